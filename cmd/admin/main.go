@@ -0,0 +1,96 @@
+// Command admin runs moderation actions against the MyGram database without
+// going through the HTTP API, e.g. to lock out an account when no admin can
+// sign in.
+//
+//	admin suspend -user 42 -duration 72h -reason "spam"
+//	admin ban -user 42 -reason "repeated abuse"
+//	admin reinstate -user 42
+//	admin restore -user 42
+//	admin force-reset -user 42
+//	admin revoke-sessions -user 42
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/service"
+)
+
+const usage = `usage: admin <command> -user <id> [flags]
+
+commands:
+  suspend          suspend a user, requires -duration
+  ban              ban a user
+  reinstate        lift a suspension or ban
+  restore          undo the soft delete of a user
+  force-reset      require a password change on next login, prints the
+                   one-time token that completes it
+  revoke-sessions  invalidate every token issued to the user`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd := os.Args[1]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	userID := fs.Uint64("user", 0, "id of the user to moderate")
+	reason := fs.String("reason", "", "reason shown to the user")
+	duration := fs.Duration("duration", 0, "suspension length, e.g. 72h")
+	fs.Parse(os.Args[2:])
+
+	if *userID == 0 {
+		fmt.Fprintln(os.Stderr, "-user is required")
+		os.Exit(2)
+	}
+
+	gorm := infrastructure.NewGormPostgres()
+	svc := service.NewAdminService(repository.NewUserQuery(gorm), repository.NewPhotoQuery(gorm), repository.NewLikeQuery(gorm), repository.NewSaveQuery(gorm))
+	ctx := context.Background()
+
+	var (
+		user  model.User
+		token string
+		err   error
+	)
+	switch cmd {
+	case "suspend":
+		user, err = svc.SuspendUser(ctx, *userID, *duration, *reason)
+	case "ban":
+		user, err = svc.BanUser(ctx, *userID, *reason)
+	case "reinstate":
+		user, err = svc.ReinstateUser(ctx, *userID)
+	case "restore":
+		user, err = svc.RestoreUser(ctx, *userID)
+	case "force-reset":
+		token, err = svc.ForcePasswordReset(ctx, *userID)
+	case "revoke-sessions":
+		err = svc.RevokeSessions(ctx, *userID)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s: user %d done\n", cmd, *userID)
+	if user.SuspendedUntil != nil {
+		fmt.Printf("suspended until %s\n", user.SuspendedUntil.Format(time.RFC3339))
+	}
+	if user.BannedAt != nil {
+		fmt.Printf("banned at %s\n", user.BannedAt.Format(time.RFC3339))
+	}
+	if token != "" {
+		fmt.Printf("reset token: %s\n", token)
+	}
+}
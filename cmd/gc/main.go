@@ -0,0 +1,54 @@
+// Command gc deletes stored blobs that no photo references anymore, along
+// with their rendered variants. Blobs of photos deleted within the grace
+// period are kept so the photos can still be restored.
+//
+//	gc -dry-run
+//	gc -grace 72h -limit 1000
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would be deleted without deleting")
+	grace := flag.Duration("grace", service.DefaultGCGrace, "how long a blob must have been unreferenced")
+	limit := flag.Int("limit", 0, "stop after this many blobs, 0 for no limit")
+	verbose := flag.Bool("v", false, "list every blob")
+	flag.Parse()
+
+	gc := service.NewBlobGC(repository.NewBlobQuery(infrastructure.NewGormPostgres()), storage.NewStorage())
+	report, err := gc.Collect(context.Background(), service.GCOptions{Grace: *grace, DryRun: *dryRun, Limit: *limit})
+
+	verb := "deleted"
+	if report.DryRun {
+		verb = "would delete"
+	}
+	if *verbose {
+		for _, blob := range report.Collected {
+			fmt.Printf("%s %s (%d bytes, ref_count %d)\n", verb, blob.Key, blob.Size, blob.RefCount)
+		}
+	}
+	fmt.Printf("%s %d blobs, %d bytes\n", verb, len(report.Collected), report.Bytes)
+	if report.Drifted > 0 {
+		fmt.Printf("%d of them had a non-zero ref_count without a referencing photo\n", report.Drifted)
+	}
+	for _, msg := range report.Errors {
+		fmt.Fprintln(os.Stderr, "error:", msg)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
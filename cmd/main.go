@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
+	"log"
+	"os"
+	"runtime"
+
 	"github.com/MidnightHelix/MyGram/internal/handler"
 	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/media"
 	"github.com/MidnightHelix/MyGram/internal/middleware"
 	"github.com/MidnightHelix/MyGram/internal/repository"
 	"github.com/MidnightHelix/MyGram/internal/router"
 	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/internal/storage"
 	"github.com/MidnightHelix/MyGram/pkg/validator"
 	"github.com/gin-gonic/gin"
 
@@ -32,9 +39,29 @@ func main() {
 	g := gin.Default()
 	v1 := g.Group("/api/v1")
 	usersGroup := v1.Group("/users")
+	followsGroup := v1.Group("/users")
+	blocksGroup := v1.Group("/users")
+	closeFriendsGroup := v1.Group("/users")
 	photosGroup := v1.Group("/photos")
+	userPhotosGroup := v1.Group("/users")
+	likesGroup := v1.Group("/photos")
 	commentsGroup := v1.Group("/comments")
 	socialMediasGroup := v1.Group("/socialmedias")
+	adminGroup := v1.Group("/admin")
+	mediaGroup := v1.Group("/media")
+	feedGroup := v1.Group("/feed")
+	exploreGroup := v1.Group("/explore")
+	tagsGroup := v1.Group("/tags")
+	notificationsGroup := v1.Group("/notifications")
+	albumsGroup := v1.Group("/albums")
+	userAlbumsGroup := v1.Group("/users")
+	savedGroup := v1.Group("/saved")
+	savesGroup := v1.Group("/photos")
+	searchGroup := v1.Group("/search")
+	nearbyGroup := v1.Group("/photos")
+	storiesGroup := v1.Group("/stories")
+	highlightsGroup := v1.Group("/highlights")
+	userStoriesGroup := v1.Group("/users")
 
 	// dependency injection
 	// dig by uber
@@ -46,18 +73,102 @@ func main() {
 	photoRepo := repository.NewPhotoQuery(gorm)
 	commentRepo := repository.NewCommentQuery(gorm)
 	socialMediaRepo := repository.NewSocialMediaQuery(gorm)
-	authMiddleware := middleware.NewAuthMiddleware(userRepo, photoRepo, commentRepo, socialMediaRepo)
+	followRepo := repository.NewFollowQuery(gorm)
+	blockRepo := repository.NewBlockQuery(gorm)
+	blobRepo := repository.NewBlobQuery(gorm)
+	timelineRepo := repository.NewTimelineQuery(gorm)
+	exploreRepo := repository.NewExploreQuery(gorm)
+	likeRepo := repository.NewLikeQuery(gorm)
+	tagRepo := repository.NewTagQuery(gorm)
+	mentionRepo := repository.NewMentionQuery(gorm)
+	notificationRepo := repository.NewNotificationQuery(gorm)
+	albumRepo := repository.NewAlbumQuery(gorm)
+	saveRepo := repository.NewSaveQuery(gorm)
+	closeFriendRepo := repository.NewCloseFriendQuery(gorm)
+	searchRepo := repository.NewSearchQuery(gorm)
+	storyRepo := repository.NewStoryQuery(gorm)
+	store := storage.NewStorage()
+	authMiddleware := middleware.NewAuthMiddleware(userRepo, photoRepo, commentRepo, socialMediaRepo, albumRepo)
 	customValidator := validator.NewCustomValidator()
 
-	userSvc := service.NewUserService(userRepo)
-	userHdl := handler.NewUserHandler(userSvc, customValidator)
+	feedSvc := service.NewFeedService(timelineRepo, followRepo, likeRepo, saveRepo)
+	feedHdl := handler.NewFeedHandler(feedSvc)
+	feedRouter := router.NewFeedRouter(feedGroup, feedHdl, *authMiddleware)
+
+	exploreCfg, err := service.ExploreConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	exploreSvc := service.NewExploreService(exploreRepo, likeRepo, saveRepo, exploreCfg)
+	exploreSvc.Start(context.Background())
+	exploreHdl := handler.NewExploreHandler(exploreSvc)
+	exploreRouter := router.NewExploreRouter(exploreGroup, exploreHdl, *authMiddleware)
+
+	searchCfg, err := service.SearchConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	searchSvc := service.NewSearchService(searchRepo, likeRepo, saveRepo, searchCfg)
+	searchHdl := handler.NewSearchHandler(searchSvc, customValidator)
+	searchRouter := router.NewSearchRouter(searchGroup, nearbyGroup, searchHdl, *authMiddleware)
+
+	followSvc := service.NewFollowService(followRepo, userRepo, blockRepo, feedSvc)
+	followHdl := handler.NewFollowHandler(followSvc)
+	followRouter := router.NewFollowRouter(followsGroup, followHdl, *authMiddleware)
+
+	blockSvc := service.NewBlockService(blockRepo, userRepo)
+	blockHdl := handler.NewBlockHandler(blockSvc)
+	blockRouter := router.NewBlockRouter(blocksGroup, blockHdl, *authMiddleware)
+
+	closeFriendSvc := service.NewCloseFriendService(closeFriendRepo, userRepo)
+	closeFriendHdl := handler.NewCloseFriendHandler(closeFriendSvc)
+	closeFriendRouter := router.NewCloseFriendRouter(closeFriendsGroup, closeFriendHdl, *authMiddleware)
+
+	userSvc := service.NewUserService(userRepo, blockRepo)
+	userHdl := handler.NewUserHandler(userSvc, followSvc, customValidator)
 	userRouter := router.NewUserRouter(usersGroup, userHdl, *authMiddleware)
 
-	photoSvc := service.NewPhotoService(photoRepo)
+	mentionSvc := service.NewMentionService(mentionRepo, notificationRepo, userRepo, photoRepo, blockRepo)
+	notificationSvc := service.NewNotificationService(notificationRepo)
+	notificationHdl := handler.NewNotificationHandler(notificationSvc)
+	notificationRouter := router.NewNotificationRouter(notificationsGroup, notificationHdl, *authMiddleware)
+
+	photoProcessor := service.NewPhotoProcessor(photoRepo, store, feedSvc, runtime.NumCPU())
+	photoProcessor.Start(context.Background())
+	photoSvc := service.NewPhotoService(photoRepo, blobRepo, likeRepo, saveRepo, tagRepo, commentRepo, userRepo, blockRepo, mentionSvc, store, photoProcessor)
 	photoHdl := handler.NewPhotoHandler(photoSvc, customValidator)
-	photoRouter := router.NewPhotoRouter(photosGroup, photoHdl, *authMiddleware)
+	photoRouter := router.NewPhotoRouter(photosGroup, userPhotosGroup, photoHdl, *authMiddleware)
 
-	commentSvc := service.NewCommentService(commentRepo)
+	likeSvc := service.NewLikeService(likeRepo, photoRepo, userRepo, blockRepo)
+	likeHdl := handler.NewLikeHandler(likeSvc)
+	likeRouter := router.NewLikeRouter(likesGroup, likeHdl, *authMiddleware)
+
+	tagSvc := service.NewTagService(tagRepo, likeRepo, saveRepo)
+	tagHdl := handler.NewTagHandler(tagSvc, customValidator)
+	tagRouter := router.NewTagRouter(tagsGroup, tagHdl, *authMiddleware)
+
+	albumSvc := service.NewAlbumService(albumRepo, photoRepo, userRepo, blockRepo, likeRepo, saveRepo)
+	albumHdl := handler.NewAlbumHandler(albumSvc, customValidator)
+	albumRouter := router.NewAlbumRouter(albumsGroup, userAlbumsGroup, albumHdl, *authMiddleware)
+
+	storyCfg, err := service.StoryConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	storySvc := service.NewStoryService(storyRepo, blobRepo, store, storyCfg)
+	storySvc.Start(context.Background())
+	storyHdl := handler.NewStoryHandler(storySvc, customValidator)
+	storyRouter := router.NewStoryRouter(storiesGroup, highlightsGroup, userStoriesGroup, storyHdl, *authMiddleware)
+
+	saveSvc := service.NewSaveService(saveRepo, photoRepo, userRepo, blockRepo, likeRepo)
+	saveHdl := handler.NewSaveHandler(saveSvc, customValidator)
+	saveRouter := router.NewSaveRouter(savedGroup, savesGroup, saveHdl, *authMiddleware)
+
+	commentCfg, err := service.CommentConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	commentSvc := service.NewCommentService(commentRepo, photoRepo, userRepo, blockRepo, likeRepo, saveRepo, mentionSvc, commentCfg)
 	commentHdl := handler.NewCommentHandler(commentSvc, customValidator)
 	commentRouter := router.NewCommentRouter(commentsGroup, commentHdl, *authMiddleware)
 
@@ -65,11 +176,45 @@ func main() {
 	socialMediaHdl := handler.NewSocialMediaHandler(socialMediaSvc, customValidator)
 	socialMediaRouter := router.NewSocialMediaRouter(socialMediasGroup, socialMediaHdl, *authMiddleware)
 
+	adminSvc := service.NewAdminService(userRepo, photoRepo, likeRepo, saveRepo)
+	adminHdl := handler.NewAdminHandler(adminSvc, customValidator)
+	adminRouter := router.NewAdminRouter(adminGroup, adminHdl, *authMiddleware)
+
+	mediaCache, err := media.NewCache()
+	if err != nil {
+		log.Fatal(err)
+	}
+	signer, err := media.NewSigner([]byte(os.Getenv("MEDIA_SIGNING_KEY")))
+	if err != nil {
+		log.Fatal(err)
+	}
+	mediaSvc := service.NewMediaService(store, signer, mediaCache, photoRepo)
+	mediaHdl := handler.NewMediaHandler(mediaSvc, customValidator)
+	mediaRouter := router.NewMediaRouter(mediaGroup, mediaHdl, *authMiddleware)
+
 	// mount
 	userRouter.Mount()
+	followRouter.Mount()
+	blockRouter.Mount()
+	closeFriendRouter.Mount()
 	photoRouter.Mount()
+	likeRouter.Mount()
 	commentRouter.Mount()
 	socialMediaRouter.Mount()
+	adminRouter.Mount()
+	mediaRouter.Mount()
+	feedRouter.Mount()
+	exploreRouter.Mount()
+	tagRouter.Mount()
+	notificationRouter.Mount()
+	albumRouter.Mount()
+	saveRouter.Mount()
+	searchRouter.Mount()
+	storyRouter.Mount()
+	// uploads kept on local disk are served by the api itself
+	if root, ok := storage.LocalRoot(store); ok {
+		g.Static(storage.LocalBaseURL, root)
+	}
 	// swagger
 	g.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
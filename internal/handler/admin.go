@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type AdminHandler interface {
+	SuspendUser(ctx *gin.Context)
+	BanUser(ctx *gin.Context)
+	ReinstateUser(ctx *gin.Context)
+	RestoreUser(ctx *gin.Context)
+	ForcePasswordReset(ctx *gin.Context)
+	RevokeSessions(ctx *gin.Context)
+	FindNearDuplicates(ctx *gin.Context)
+}
+
+type adminHandlerImpl struct {
+	svc       service.AdminService
+	validator *validator.CustomValidator
+}
+
+func NewAdminHandler(svc service.AdminService, validator *validator.CustomValidator) AdminHandler {
+	return &adminHandlerImpl{
+		svc:       svc,
+		validator: validator,
+	}
+}
+
+//	 SuspendUser godoc
+//
+//		@Summary		Suspend a user
+//		@Description	Suspend a user for a duration such as "72h", the user is signed out and their content hidden until it ends
+//		@Tags			admin
+//		@Accept			json
+//		@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param suspension body dto.Suspension true "Suspension"
+//	@Param        id   path      int  true  "User ID"
+//	@Success		200	{object}	dto.ModeratedUser
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		403	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/admin/users/{id}/suspend [post]
+func (u *adminHandlerImpl) SuspendUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.Suspension{}
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	user, err := u.svc.SuspendUser(ctx, uint64(id), duration, req.Reason)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User has been suspended", Data: moderatedUser(user)})
+}
+
+//	 BanUser godoc
+//
+//		@Summary		Ban a user
+//		@Description	Permanently ban a user until an admin reinstates them
+//		@Tags			admin
+//		@Accept			json
+//		@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param ban body dto.Ban true "Ban"
+//	@Param        id   path      int  true  "User ID"
+//	@Success		200	{object}	dto.ModeratedUser
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		403	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/admin/users/{id}/ban [post]
+func (u *adminHandlerImpl) BanUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.Ban{}
+	if err := ctx.ShouldBindJSON(&req); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	user, err := u.svc.BanUser(ctx, uint64(id), req.Reason)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User has been banned", Data: moderatedUser(user)})
+}
+
+// ReinstateUser godoc
+//
+// @Summary		Reinstate a user
+// @Description	Lift the suspension or ban of a user
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	dto.ModeratedUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/admin/users/{id}/reinstate [post]
+func (u *adminHandlerImpl) ReinstateUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	user, err := u.svc.ReinstateUser(ctx, uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User has been reinstated", Data: moderatedUser(user)})
+}
+
+// RestoreUser godoc
+//
+// @Summary		Restore a deleted user
+// @Description	Undo the soft delete of a user account
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	dto.ModeratedUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/admin/users/{id}/restore [post]
+func (u *adminHandlerImpl) RestoreUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	user, err := u.svc.RestoreUser(ctx, uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User has been restored", Data: moderatedUser(user)})
+}
+
+// ForcePasswordReset godoc
+//
+// @Summary		Force a password reset
+// @Description	Sign the user out everywhere and require a password change on the next login, completed with the returned one-time token
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/admin/users/{id}/force-password-reset [post]
+func (u *adminHandlerImpl) ForcePasswordReset(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	token, err := u.svc.ForcePasswordReset(ctx, uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := map[string]any{
+		"reset_token": token,
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User must reset their password with the reset token on next login", Data: data})
+}
+
+// RevokeSessions godoc
+//
+// @Summary		Revoke sessions
+// @Description	Invalidate every access token issued to the user so far
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/admin/users/{id}/revoke-sessions [post]
+func (u *adminHandlerImpl) RevokeSessions(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.RevokeSessions(ctx, uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "All sessions of the user have been revoked"})
+}
+
+// FindNearDuplicates godoc
+//
+// @Summary		Find near-duplicate photos
+// @Description	List photos of any user that look like the given photo, closest first. Distance is in bits of the 64 bit perceptual hash, default 7, at most 11.
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Param        distance   query      int  false  "Max Hamming distance"
+// @Success		200	{object}	[]dto.NearDuplicate
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/admin/photos/{id}/duplicates [get]
+func (u *adminHandlerImpl) FindNearDuplicates(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	distance := 0
+	if v := ctx.Query("distance"); v != "" {
+		if distance, err = strconv.Atoi(v); err != nil {
+			ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: service.ErrInvalidDistance.Error()})
+			return
+		}
+	}
+
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+
+	duplicates, err := u.svc.FindNearDuplicates(ctx, uint64(userID), uint64(id), distance)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.NearDuplicate{}
+	for _, item := range duplicates {
+		photo := photoSummary(item.Photo)
+		// admins also see how to reach the author
+		if photo.User != nil {
+			photo.User.Email = item.Photo.User.Email
+		}
+		data = append(data, dto.NearDuplicate{Photo: photo, PHashDistance: item.PHashDistance, DHashDistance: item.DHashDistance})
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+func moderatedUser(user model.User) dto.ModeratedUser {
+	return dto.ModeratedUser{
+		ID:               user.ID,
+		Username:         user.Username,
+		SuspendedUntil:   user.SuspendedUntil,
+		BannedAt:         user.BannedAt,
+		ModerationReason: user.ModerationReason,
+	}
+}
@@ -0,0 +1,556 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type AlbumHandler interface {
+	GetAlbum(ctx *gin.Context)
+	GetUserAlbums(ctx *gin.Context)
+
+	CreateAlbum(ctx *gin.Context)
+	EditAlbum(ctx *gin.Context)
+	DeleteAlbum(ctx *gin.Context)
+
+	AddPhoto(ctx *gin.Context)
+	RemovePhoto(ctx *gin.Context)
+	ReorderPhotos(ctx *gin.Context)
+
+	AddCollaborator(ctx *gin.Context)
+	RemoveCollaborator(ctx *gin.Context)
+}
+
+type albumHandlerImpl struct {
+	svc       service.AlbumService
+	validator *validator.CustomValidator
+}
+
+func NewAlbumHandler(svc service.AlbumService, validator *validator.CustomValidator) AlbumHandler {
+	return &albumHandlerImpl{svc: svc, validator: validator}
+}
+
+// ShowAlbum godoc
+//
+// @Summary		Show album
+// @Description	Get an album and its photos in album order. Pass next_cursor from meta to get the next page.
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	dto.AlbumPage
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id} [get]
+func (u *albumHandlerImpl) GetAlbum(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	album, items, err := u.svc.GetAlbum(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.AlbumPage{Album: albumSummary(album), Photos: []dto.Photo{}}
+	for _, item := range items {
+		data.Photos = append(data.Photos, photoSummary(*item.Photo))
+	}
+	info := dto.PageInfo{}
+	if len(items) == page.Size() {
+		next := uint64(items[len(items)-1].Position)
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
+
+// ShowUserAlbums godoc
+//
+// @Summary		Show user albums
+// @Description	Get the albums of a user the caller may see, newest first. Pass next_cursor from meta to get the next page.
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Album
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/albums [get]
+func (u *albumHandlerImpl) GetUserAlbums(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	albums, err := u.svc.GetUserAlbums(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Album{}
+	for _, item := range albums {
+		data = append(data, albumSummary(item))
+	}
+	info := dto.PageInfo{}
+	if len(albums) == page.Size() {
+		next := albums[len(albums)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
+
+// CreateAlbum godoc
+//
+// @Summary		Create an album
+// @Description	Create an empty album, its cover becomes the first photo added
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param album body dto.AlbumInput true "Create Album"
+// @Success		201	{object}	dto.Album
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums [post]
+func (u *albumHandlerImpl) CreateAlbum(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.AlbumInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	album, err := u.svc.CreateAlbum(ctx, albumFromInput(req), uint64(userId))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: albumSummary(album)})
+}
+
+// UpdateAlbum godoc
+//
+// @Summary		Update an album
+// @Description	Change the title, description, visibility or cover of an album, only its owner may
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Param album body dto.AlbumInput true "Update Album"
+// @Success		200	{object}	dto.Album
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id} [put]
+func (u *albumHandlerImpl) EditAlbum(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.AlbumInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	album, err := u.svc.EditAlbum(ctx, albumFromInput(req), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: albumSummary(album)})
+}
+
+// DeleteAlbum godoc
+//
+// @Summary		Delete an album
+// @Description	Delete an album, its photos are not deleted
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id} [delete]
+func (u *albumHandlerImpl) DeleteAlbum(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.DeleteAlbum(ctx, uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your album has been successfully deleted"})
+}
+
+// AddAlbumPhoto godoc
+//
+// @Summary		Add a photo to an album
+// @Description	Append one of the caller's photos to an album they own or collaborate on
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Param photo body dto.AlbumPhotoInput true "Photo"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id}/photos [post]
+func (u *albumHandlerImpl) AddPhoto(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.AlbumPhotoInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.svc.AddPhoto(ctx, uint64(userId), uint64(id), req.PhotoID); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Photo has been added to the album"})
+}
+
+// RemoveAlbumPhoto godoc
+//
+// @Summary		Remove a photo from an album
+// @Description	Take a photo out of an album, collaborators may only remove photos they added
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Param        photo_id   path      int  true  "Photo ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id}/photos/{photo_id} [delete]
+func (u *albumHandlerImpl) RemovePhoto(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	photoID, err := strconv.Atoi(ctx.Param("photo_id"))
+	if photoID == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.RemovePhoto(ctx, uint64(userId), uint64(id), uint64(photoID)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Photo has been removed from the album"})
+}
+
+// ReorderAlbumPhotos godoc
+//
+// @Summary		Reorder album photos
+// @Description	Put the photos of an album in a new order, photo_ids must list every photo of the album once
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Param order body dto.AlbumOrder true "New order"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id}/photos [put]
+func (u *albumHandlerImpl) ReorderPhotos(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.AlbumOrder{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.svc.ReorderPhotos(ctx, uint64(id), req.PhotoIDs); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Album has been reordered"})
+}
+
+// AddAlbumCollaborator godoc
+//
+// @Summary		Add an album collaborator
+// @Description	Let another user add their own photos to the album
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Param collaborator body dto.AlbumCollaboratorInput true "Collaborator"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id}/collaborators [post]
+func (u *albumHandlerImpl) AddCollaborator(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.AlbumCollaboratorInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.svc.AddCollaborator(ctx, uint64(userId), uint64(id), req.UserID); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Collaborator has been added"})
+}
+
+// RemoveAlbumCollaborator godoc
+//
+// @Summary		Remove an album collaborator
+// @Description	The owner may remove any collaborator and a collaborator may leave, the photos they added stay
+// @Tags			albums
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Album ID"
+// @Param        user_id   path      int  true  "Collaborator user ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/albums/{id}/collaborators/{user_id} [delete]
+func (u *albumHandlerImpl) RemoveCollaborator(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	collaboratorID, err := strconv.Atoi(ctx.Param("user_id"))
+	if collaboratorID == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.RemoveCollaborator(ctx, uint64(userId), uint64(id), uint64(collaboratorID)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Collaborator has been removed"})
+}
+
+func albumFromInput(req dto.AlbumInput) model.Album {
+	return model.Album{
+		Title:        req.Title,
+		Description:  req.Description,
+		Visibility:   req.Visibility,
+		CoverPhotoID: req.CoverPhotoID,
+	}
+}
+
+func albumSummary(item model.Album) dto.Album {
+	album := dto.Album{
+		ID:          item.ID,
+		Title:       item.Title,
+		Description: item.Description,
+		Visibility:  item.Visibility,
+		UserID:      item.UserID,
+		CreatedAt:   &item.CreatedAt,
+		UpdatedAt:   &item.UpdatedAt,
+	}
+	if item.CoverPhoto != nil {
+		cover := photoSummary(*item.CoverPhoto)
+		cover.LikeCount = nil
+		cover.LikedByMe = nil
+		cover.SavedByMe = nil
+		album.CoverPhoto = &cover
+	}
+	if item.User != nil {
+		album.User = &dto.UserDefault{ID: &item.User.ID, Username: item.User.Username}
+	}
+	for _, collaborator := range item.Collaborators {
+		if collaborator.User == nil {
+			continue
+		}
+		album.Collaborators = append(album.Collaborators, dto.UserDefault{ID: &collaborator.User.ID, Username: collaborator.User.Username})
+	}
+	return album
+}
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type BlockHandler interface {
+	Block(ctx *gin.Context)
+	Unblock(ctx *gin.Context)
+	GetBlocks(ctx *gin.Context)
+
+	Mute(ctx *gin.Context)
+	Unmute(ctx *gin.Context)
+	GetMutes(ctx *gin.Context)
+}
+
+type blockHandlerImpl struct {
+	svc service.BlockService
+}
+
+func NewBlockHandler(svc service.BlockService) BlockHandler {
+	return &blockHandlerImpl{svc: svc}
+}
+
+// Block godoc
+//
+// @Summary		Block a user
+// @Description	Block a user, existing follows in both directions are removed
+// @Tags			blocks
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		201	{object}	dto.BlockedUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/block [post]
+func (u *blockHandlerImpl) Block(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	block, err := u.svc.Block(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.BlockedUser{
+		ID:        block.ID,
+		UserID:    block.BlockedID,
+		CreatedAt: &block.CreatedAt,
+	}
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: data})
+}
+
+// Unblock godoc
+//
+// @Summary		Unblock a user
+// @Description	Remove a block, follows severed by the block are not restored
+// @Tags			blocks
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/block [delete]
+func (u *blockHandlerImpl) Unblock(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.Unblock(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User has been unblocked"})
+}
+
+// ShowBlocks godoc
+//
+// @Summary		Show blocked users
+// @Description	Get the users blocked by the caller
+// @Tags			blocks
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.BlockedUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/blocks [get]
+func (u *blockHandlerImpl) GetBlocks(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	blocks, err := u.svc.GetBlocks(ctx, uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.BlockedUser{}
+	for _, item := range blocks {
+		block := dto.BlockedUser{
+			ID:        item.ID,
+			UserID:    item.BlockedID,
+			CreatedAt: &item.CreatedAt,
+		}
+		if item.Blocked != nil {
+			block.Username = item.Blocked.Username
+		}
+		data = append(data, block)
+	}
+
+	meta := dto.PageInfo{}
+	if len(blocks) == page.Size() {
+		meta.NextCursor = &blocks[len(blocks)-1].ID
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: meta})
+}
+
+// Mute godoc
+//
+// @Summary		Mute a user
+// @Description	Hide a user's content from the caller's feeds, the muted user is not told
+// @Tags			blocks
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		201	{object}	dto.BlockedUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/mute [post]
+func (u *blockHandlerImpl) Mute(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	mute, err := u.svc.Mute(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.BlockedUser{
+		ID:        mute.ID,
+		UserID:    mute.MutedID,
+		CreatedAt: &mute.CreatedAt,
+	}
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: data})
+}
+
+// Unmute godoc
+//
+// @Summary		Unmute a user
+// @Description	Show a muted user's content again
+// @Tags			blocks
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/mute [delete]
+func (u *blockHandlerImpl) Unmute(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.Unmute(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User has been unmuted"})
+}
+
+// ShowMutes godoc
+//
+// @Summary		Show muted users
+// @Description	Get the users muted by the caller
+// @Tags			blocks
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.BlockedUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/mutes [get]
+func (u *blockHandlerImpl) GetMutes(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	mutes, err := u.svc.GetMutes(ctx, uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.BlockedUser{}
+	for _, item := range mutes {
+		mute := dto.BlockedUser{
+			ID:        item.ID,
+			UserID:    item.MutedID,
+			CreatedAt: &item.CreatedAt,
+		}
+		if item.Muted != nil {
+			mute.Username = item.Muted.Username
+		}
+		data = append(data, mute)
+	}
+
+	meta := dto.PageInfo{}
+	if len(mutes) == page.Size() {
+		meta.NextCursor = &mutes[len(mutes)-1].ID
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: meta})
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type CloseFriendHandler interface {
+	AddCloseFriend(ctx *gin.Context)
+	RemoveCloseFriend(ctx *gin.Context)
+	GetCloseFriends(ctx *gin.Context)
+}
+
+type closeFriendHandlerImpl struct {
+	svc service.CloseFriendService
+}
+
+func NewCloseFriendHandler(svc service.CloseFriendService) CloseFriendHandler {
+	return &closeFriendHandlerImpl{svc: svc}
+}
+
+// AddCloseFriend godoc
+//
+// @Summary		Add a close friend
+// @Description	Add a user to the caller's close friends, they see the caller's close friends photos. Nobody else sees the list
+// @Tags			close friends
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		201	{object}	dto.CloseFriend
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/close-friend [post]
+func (u *closeFriendHandlerImpl) AddCloseFriend(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	closeFriend, err := u.svc.AddCloseFriend(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.CloseFriend{
+		ID:        closeFriend.ID,
+		UserID:    closeFriend.FriendID,
+		CreatedAt: &closeFriend.CreatedAt,
+	}
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: data})
+}
+
+// RemoveCloseFriend godoc
+//
+// @Summary		Remove a close friend
+// @Description	Take a user off the caller's close friends, they stop seeing the caller's close friends photos
+// @Tags			close friends
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/close-friend [delete]
+func (u *closeFriendHandlerImpl) RemoveCloseFriend(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.RemoveCloseFriend(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "User has been removed from your close friends"})
+}
+
+// ShowCloseFriends godoc
+//
+// @Summary		Show close friends
+// @Description	Get the caller's close friends, newest first
+// @Tags			close friends
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.CloseFriend
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/close-friends [get]
+func (u *closeFriendHandlerImpl) GetCloseFriends(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	closeFriends, err := u.svc.GetCloseFriends(ctx, uint64(userId), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.CloseFriend{}
+	for _, item := range closeFriends {
+		closeFriend := dto.CloseFriend{
+			ID:        item.ID,
+			UserID:    item.FriendID,
+			CreatedAt: &item.CreatedAt,
+		}
+		if item.Friend != nil {
+			closeFriend.Username = item.Friend.Username
+		}
+		data = append(data, closeFriend)
+	}
+	info := dto.PageInfo{}
+	if len(closeFriends) == page.Size() {
+		next := closeFriends[len(closeFriends)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
@@ -17,6 +17,7 @@ import (
 type CommentHandler interface {
 	GetComments(ctx *gin.Context)
 	EditComment(ctx *gin.Context)
+	GetCommentRevisions(ctx *gin.Context)
 	DeleteComment(ctx *gin.Context)
 
 	PostComment(ctx *gin.Context)
@@ -73,20 +74,20 @@ func (u *commentHandlerImpl) GetComments(ctx *gin.Context) {
 			Message:   item.Message,
 			PhotoID:   item.PhotoID,
 			UserID:    item.UserID,
+			Mentions:  mentionEntities(item.Mentions),
 			CreatedAt: &item.CreatedAt,
 			UpdatedAt: &item.UpdatedAt,
+			Edited:    item.EditedAt != nil,
+			EditedAt:  item.EditedAt,
 			User: &dto.UserDefault{
 				ID:       &item.UserID,
 				Email:    item.User.Email,
 				Username: item.User.Username,
 			},
-			Photo: &dto.Photo{
-				ID:      item.Photo.ID,
-				Title:   item.Photo.Title,
-				Caption: item.Photo.Caption,
-				Url:     item.Photo.Url,
-				UserID:  item.Photo.UserID,
-			},
+		}
+		if item.Photo != nil {
+			photo := photoSummary(*item.Photo)
+			comment.Photo = &photo
 		}
 		data = append(data, comment)
 	}
@@ -155,7 +156,7 @@ func (u *commentHandlerImpl) PostComment(ctx *gin.Context) {
 
 	comment, err := u.svc.PostComment(ctx, comment, uint64(userID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
@@ -164,6 +165,7 @@ func (u *commentHandlerImpl) PostComment(ctx *gin.Context) {
 		Message:   comment.Message,
 		PhotoID:   comment.PhotoID,
 		UserID:    comment.UserID,
+		Mentions:  mentionEntities(comment.Mentions),
 		CreatedAt: &comment.CreatedAt,
 	}
 	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: data})
@@ -230,9 +232,9 @@ func (u *commentHandlerImpl) EditComment(ctx *gin.Context) {
 		return
 	}
 
-	comment, err := u.svc.EditComment(ctx, req, uint64(id))
+	comment, err := u.svc.EditComment(ctx, req, uint64(id), uint64(userId))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
@@ -241,11 +243,63 @@ func (u *commentHandlerImpl) EditComment(ctx *gin.Context) {
 		Message:   comment.Message,
 		PhotoID:   comment.PhotoID,
 		UserID:    comment.UserID,
+		Mentions:  mentionEntities(comment.Mentions),
 		UpdatedAt: &comment.UpdatedAt,
+		Edited:    comment.EditedAt != nil,
+		EditedAt:  comment.EditedAt,
 	}
 	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
 }
 
+// ShowCommentRevisions godoc
+//
+// @Summary		Show comment revisions
+// @Description	Get the messages a comment had before each of its edits newest first, with who made the edit and when. Only the author and admins may see them. Pass next_cursor from meta to get the next page.
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param        id   path      int  true  "Comment ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Revision
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/comments/{id}/revisions [get]
+func (u *commentHandlerImpl) GetCommentRevisions(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	revisions, err := u.svc.GetCommentRevisions(ctx, uint64(userId), isAdmin(claims.(jwt.MapClaims)), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: revisionSummaries(revisions), Meta: revisionPageInfo(revisions, page.Size())})
+}
+
 // DeleteComment godoc
 //
 // @Summary		Delete a comment
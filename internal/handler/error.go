@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MidnightHelix/MyGram/internal/service"
+)
+
+// errorStatus maps service errors to the http status returned to clients,
+// anything unknown is treated as an internal error.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrFollowRequestNotFound),
+		errors.Is(err, service.ErrPhotoNotFound),
+		errors.Is(err, service.ErrMediaNotFound),
+		errors.Is(err, service.ErrTagNotFound),
+		errors.Is(err, service.ErrAlbumNotFound),
+		errors.Is(err, service.ErrPhotoNotInAlbum),
+		errors.Is(err, service.ErrCollectionNotFound),
+		errors.Is(err, service.ErrMediaItemNotFound),
+		errors.Is(err, service.ErrStoryNotFound),
+		errors.Is(err, service.ErrHighlightNotFound),
+		errors.Is(err, service.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrPrivateAccount),
+		errors.Is(err, service.ErrAccountBanned),
+		errors.Is(err, service.ErrAccountSuspended),
+		errors.Is(err, service.ErrPasswordResetRequired),
+		errors.Is(err, service.ErrInvalidResetToken),
+		errors.Is(err, service.ErrInvalidSignature),
+		errors.Is(err, service.ErrPhotoNotOwned),
+		errors.Is(err, service.ErrAlbumPermission),
+		errors.Is(err, service.ErrStoryNotOwned),
+		errors.Is(err, service.ErrCommentEditWindow):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrFollowSelf),
+		errors.Is(err, service.ErrBlockSelf),
+		errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidDuration),
+		errors.Is(err, service.ErrUserNotDeleted),
+		errors.Is(err, service.ErrInvalidUsername),
+		errors.Is(err, service.ErrUsernameReserved),
+		errors.Is(err, service.ErrInvalidTransform),
+		errors.Is(err, service.ErrInvalidDistance),
+		errors.Is(err, service.ErrInvalidAlbumOrder),
+		errors.Is(err, service.ErrCollaboratorSelf),
+		errors.Is(err, service.ErrCloseFriendSelf),
+		errors.Is(err, service.ErrTooManyMediaItems),
+		errors.Is(err, service.ErrLastMediaItem),
+		errors.Is(err, service.ErrInvalidMediaOrder),
+		errors.Is(err, service.ErrInvalidHighlightOrder),
+		errors.Is(err, service.ErrInvalidLocation),
+		errors.Is(err, service.ErrInvalidRadius):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrUsernameTaken),
+		errors.Is(err, service.ErrEmailTaken):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrUsernameCooldown):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrPhotoTooLarge),
+		errors.Is(err, service.ErrPhotoTooManyPixels):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type ExploreHandler interface {
+	GetExplore(ctx *gin.Context)
+}
+
+type exploreHandlerImpl struct {
+	svc service.ExploreService
+}
+
+func NewExploreHandler(svc service.ExploreService) ExploreHandler {
+	return &exploreHandlerImpl{svc: svc}
+}
+
+// ShowExplore godoc
+//
+// @Summary		Show explore page
+// @Description	Get recent public photos ranked by engagement, fading with age. Pass next_cursor from meta to get the next page.
+// @Tags			explore
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        cursor   query      string  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Photo
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/explore [get]
+func (u *exploreHandlerImpl) GetExplore(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.CursorPage{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	photos, next, err := u.svc.GetExplore(ctx, uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Photo{}
+	for _, item := range photos {
+		data = append(data, photoSummary(item))
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: dto.CursorInfo{NextCursor: next}})
+}
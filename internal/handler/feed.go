@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type FeedHandler interface {
+	GetFeed(ctx *gin.Context)
+}
+
+type feedHandlerImpl struct {
+	svc service.FeedService
+}
+
+func NewFeedHandler(svc service.FeedService) FeedHandler {
+	return &feedHandlerImpl{svc: svc}
+}
+
+// ShowFeed godoc
+//
+// @Summary		Show home feed
+// @Description	Get photos of the accounts the caller follows, newest first. Pass next_cursor from meta to get the next page.
+// @Tags			feed
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Photo
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/feed [get]
+func (u *feedHandlerImpl) GetFeed(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	photos, err := u.svc.GetFeed(ctx, uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Photo{}
+	for _, item := range photos {
+		data = append(data, photoSummary(item))
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: photoPageInfo(photos, page.Size())})
+}
+
+// photoSummary is a photo as listed to other users, the author is shown
+// without private fields.
+func photoSummary(item model.Photo) dto.Photo {
+	photo := photoCounts(photoFields(item), item)
+	if item.User != nil {
+		photo.User = &dto.UserDefault{ID: &item.User.ID, Username: item.User.Username}
+	}
+	return photo
+}
+
+// ownPhoto is a photo as shown to its author, with its status, visibility
+// and the author's email.
+func ownPhoto(item model.Photo) dto.Photo {
+	photo := photoFields(item)
+	photo.Status = item.Status
+	photo.Visibility = item.Visibility
+	photo.UpdatedAt = &item.UpdatedAt
+	if item.User != nil {
+		photo.User = &dto.UserDefault{Email: item.User.Email, Username: item.User.Username}
+	}
+	return photo
+}
+
+// photoFields are the fields of a photo every view shares.
+func photoFields(item model.Photo) dto.Photo {
+	return dto.Photo{
+		ID:        item.ID,
+		Title:     item.Title,
+		Caption:   item.Caption,
+		Url:       item.Url,
+		Width:     item.Width,
+		Height:    item.Height,
+		Camera:    item.CameraModel,
+		TakenAt:   item.TakenAt,
+		Location:  photoLocation(item),
+		Variants:  photoVariants(item.Variants),
+		Media:     mediaItems(item.Media),
+		Mentions:  mentionEntities(item.Mentions),
+		UserID:    item.UserID,
+		CreatedAt: &item.CreatedAt,
+		Edited:    item.EditedAt != nil,
+		EditedAt:  item.EditedAt,
+	}
+}
+
+// photoCounts adds the counts only photo listings show.
+func photoCounts(photo dto.Photo, item model.Photo) dto.Photo {
+	photo.LikeCount = &item.LikeCount
+	photo.CommentCount = &item.CommentCount
+	photo.LikedByMe = &item.LikedByMe
+	photo.SavedByMe = &item.SavedByMe
+	return photo
+}
+
+func photoPageInfo(photos []model.Photo, limit int) dto.PageInfo {
+	info := dto.PageInfo{}
+	if len(photos) < limit {
+		return info
+	}
+	next := photos[len(photos)-1].ID
+	info.NextCursor = &next
+	return info
+}
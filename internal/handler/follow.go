@@ -0,0 +1,422 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type FollowHandler interface {
+	Follow(ctx *gin.Context)
+	Unfollow(ctx *gin.Context)
+	GetFollowers(ctx *gin.Context)
+	GetFollowing(ctx *gin.Context)
+	GetFollowStatus(ctx *gin.Context)
+
+	GetFollowRequests(ctx *gin.Context)
+	AcceptFollowRequest(ctx *gin.Context)
+	RejectFollowRequest(ctx *gin.Context)
+}
+
+type followHandlerImpl struct {
+	svc service.FollowService
+}
+
+func NewFollowHandler(svc service.FollowService) FollowHandler {
+	return &followHandlerImpl{svc: svc}
+}
+
+//	 Follow godoc
+//
+//		@Summary		Follow a user
+//		@Description	Follow a user, a follow request is created when the account is private
+//		@Tags			follows
+//		@Accept			json
+//		@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param        id   path      int  true  "User ID"
+//	@Success		201	{object}	dto.Follow
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/users/{id}/follow [post]
+func (u *followHandlerImpl) Follow(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	follow, err := u.svc.Follow(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.Follow{
+		ID:          follow.ID,
+		FollowerID:  follow.FollowerID,
+		FollowingID: follow.FollowingID,
+		Status:      follow.Status,
+		CreatedAt:   &follow.CreatedAt,
+	}
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: data})
+}
+
+// Unfollow godoc
+//
+// @Summary		Unfollow a user
+// @Description	Unfollow a user or cancel a pending follow request
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/follow [delete]
+func (u *followHandlerImpl) Unfollow(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	err = u.svc.Unfollow(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "You have unfollowed this user"})
+}
+
+// ShowFollowers godoc
+//
+// @Summary		Show followers
+// @Description	Get the paginated followers of a user
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.FollowUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/followers [get]
+func (u *followHandlerImpl) GetFollowers(ctx *gin.Context) {
+	u.listFollows(ctx, u.svc.GetFollowers, func(follow model.Follow) *model.User { return follow.Follower })
+}
+
+// ShowFollowing godoc
+//
+// @Summary		Show following
+// @Description	Get the paginated accounts a user follows
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.FollowUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/following [get]
+func (u *followHandlerImpl) GetFollowing(ctx *gin.Context) {
+	u.listFollows(ctx, u.svc.GetFollowing, func(follow model.Follow) *model.User { return follow.Following })
+}
+
+func (u *followHandlerImpl) listFollows(ctx *gin.Context,
+	list func(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error),
+	other func(follow model.Follow) *model.User) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	follows, err := list(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: followUsers(follows, other), Meta: followPageInfo(follows, page.Size())})
+}
+
+// ShowFollowStatus godoc
+//
+// @Summary		Show follow status
+// @Description	Get whether the caller and a user follow each other
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	dto.FollowStatus
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/follow-status [get]
+func (u *followHandlerImpl) GetFollowStatus(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	following, followedBy, requested, err := u.svc.GetFollowStatus(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.FollowStatus{
+		Following:  following,
+		FollowedBy: followedBy,
+		Mutual:     following && followedBy,
+		Requested:  requested,
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+// ShowFollowRequests godoc
+//
+// @Summary		Show follow requests
+// @Description	Get pending follow requests addressed to the caller
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.FollowUser
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/follow-requests [get]
+func (u *followHandlerImpl) GetFollowRequests(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	follows, err := u.svc.GetFollowRequests(ctx, uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := followUsers(follows, func(follow model.Follow) *model.User { return follow.Follower })
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: followPageInfo(follows, page.Size())})
+}
+
+// AcceptFollowRequest godoc
+//
+// @Summary		Accept a follow request
+// @Description	Accept a pending follow request addressed to the caller
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Follow request ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/follow-requests/{id} [put]
+func (u *followHandlerImpl) AcceptFollowRequest(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	err = u.svc.AcceptFollowRequest(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Follow request accepted"})
+}
+
+// RejectFollowRequest godoc
+//
+// @Summary		Reject a follow request
+// @Description	Reject a pending follow request addressed to the caller
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Follow request ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/follow-requests/{id} [delete]
+func (u *followHandlerImpl) RejectFollowRequest(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	err = u.svc.RejectFollowRequest(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Follow request rejected"})
+}
+
+func followUsers(follows []model.Follow, other func(follow model.Follow) *model.User) []dto.FollowUser {
+	data := []dto.FollowUser{}
+	for _, item := range follows {
+		follow := dto.FollowUser{
+			FollowID:   item.ID,
+			FollowedAt: &item.CreatedAt,
+		}
+		if user := other(item); user != nil {
+			follow.UserID = user.ID
+			follow.Username = user.Username
+		}
+		data = append(data, follow)
+	}
+	return data
+}
+
+// followPageInfo points the next page at the last follow id, a short page
+// means there is nothing left to fetch.
+func followPageInfo(follows []model.Follow, limit int) dto.PageInfo {
+	info := dto.PageInfo{}
+	if len(follows) < limit {
+		return info
+	}
+	next := follows[len(follows)-1].ID
+	info.NextCursor = &next
+	return info
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type LikeHandler interface {
+	Like(ctx *gin.Context)
+	Unlike(ctx *gin.Context)
+	GetLikers(ctx *gin.Context)
+}
+
+type likeHandlerImpl struct {
+	svc service.LikeService
+}
+
+func NewLikeHandler(svc service.LikeService) LikeHandler {
+	return &likeHandlerImpl{svc: svc}
+}
+
+// Like godoc
+//
+// @Summary		Like a photo
+// @Description	Like a photo, liking a photo again changes nothing
+// @Tags			likes
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Success		200	{object}	dto.PhotoLikes
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/{id}/like [post]
+func (u *likeHandlerImpl) Like(ctx *gin.Context) {
+	u.setLike(ctx, u.svc.Like)
+}
+
+// Unlike godoc
+//
+// @Summary		Unlike a photo
+// @Description	Take back a like, unliking a photo that is not liked changes nothing
+// @Tags			likes
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Success		200	{object}	dto.PhotoLikes
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/{id}/like [delete]
+func (u *likeHandlerImpl) Unlike(ctx *gin.Context) {
+	u.setLike(ctx, u.svc.Unlike)
+}
+
+func (u *likeHandlerImpl) setLike(ctx *gin.Context, set func(ctx context.Context, userID uint64, photoID uint64) (model.Photo, error)) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	photo, err := set(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.PhotoLikes{
+		PhotoID:   photo.ID,
+		LikeCount: photo.LikeCount,
+		LikedByMe: photo.LikedByMe,
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+// ShowLikers godoc
+//
+// @Summary		Show likers
+// @Description	Get the paginated users who liked a photo, newest first
+// @Tags			likes
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Liker
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/{id}/likes [get]
+func (u *likeHandlerImpl) GetLikers(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	likes, err := u.svc.GetLikers(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Liker{}
+	for _, like := range likes {
+		liker := dto.Liker{LikeID: like.ID, UserID: like.UserID, LikedAt: &like.CreatedAt}
+		if like.User != nil {
+			liker.Username = like.User.Username
+		}
+		data = append(data, liker)
+	}
+	info := dto.PageInfo{}
+	if len(likes) == page.Size() {
+		next := likes[len(likes)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type MediaHandler interface {
+	GetMedia(ctx *gin.Context)
+	SignMedia(ctx *gin.Context)
+}
+
+type mediaHandlerImpl struct {
+	svc       service.MediaService
+	validator *validator.CustomValidator
+}
+
+func NewMediaHandler(svc service.MediaService, validator *validator.CustomValidator) MediaHandler {
+	return &mediaHandlerImpl{
+		svc:       svc,
+		validator: validator,
+	}
+}
+
+// GetMedia godoc
+//
+// @Summary		Get a transformed image
+// @Description	Render a stored image at the requested size and format. The parameters must be signed, get signed urls from POST /media/sign. Signed urls expire after an hour.
+// @Tags			media
+// @Produce		jpeg
+// @Produce		png
+// @Param        key   path      string  true  "Object key"
+// @Param        w   query      int  false  "Width"
+// @Param        h   query      int  false  "Height"
+// @Param        fit   query      string  false  "contain, cover or fill"
+// @Param        fm   query      string  false  "jpeg or png"
+// @Param        q   query      int  false  "JPEG quality"
+// @Param        exp   query      int  true  "Expiry, unix seconds"
+// @Param        s   query      string  true  "Signature"
+// @Success		200
+// @Success		304
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/media/{key} [get]
+func (u *mediaHandlerImpl) GetMedia(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	req := dto.MediaTransform{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// an unreadable expiry fails the signature check like a wrong one
+	exp, _ := strconv.ParseInt(ctx.Query("exp"), 10, 64)
+	expires := time.Unix(exp, 0)
+	media, err := u.svc.Render(ctx, key, transformOptions(req), expires, ctx.Query("s"))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	// only the holder of the url may keep the image, and only as long as the
+	// signature lasts, a photo hidden since must not live on in caches
+	ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", max(int(time.Until(expires).Seconds()), 0)))
+	ctx.Header("ETag", media.ETag)
+	if ctx.GetHeader("If-None-Match") == media.ETag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, media.ContentType, media.Data)
+}
+
+//	 SignMedia godoc
+//
+//		@Summary		Sign a media url
+//		@Description	Get the signed GET /media url of an image of a photo the caller may see, rendered with the given parameters
+//		@Tags			media
+//		@Accept			json
+//		@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param transform body dto.MediaSignRequest true "Transform"
+//	@Success		200	{object}	dto.SignedMedia
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/media/sign [post]
+func (u *mediaHandlerImpl) SignMedia(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+
+	req := dto.MediaSignRequest{}
+	if err := ctx.Bind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	url, err := u.svc.SignURL(ctx, uint64(userID), req.Key, transformOptions(req.MediaTransform))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: dto.SignedMedia{Url: url}})
+}
+
+func transformOptions(req dto.MediaTransform) imaging.Options {
+	return imaging.Options{
+		Width:   req.Width,
+		Height:  req.Height,
+		Fit:     req.Fit,
+		Format:  req.Format,
+		Quality: req.Quality,
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type NotificationHandler interface {
+	GetNotifications(ctx *gin.Context)
+	MarkRead(ctx *gin.Context)
+}
+
+type notificationHandlerImpl struct {
+	svc service.NotificationService
+}
+
+func NewNotificationHandler(svc service.NotificationService) NotificationHandler {
+	return &notificationHandlerImpl{svc: svc}
+}
+
+// ShowNotifications godoc
+//
+// @Summary		Show notifications
+// @Description	Get the caller's notifications, newest first. Pass next_cursor from meta to get the next page.
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Notification
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/notifications [get]
+func (u *notificationHandlerImpl) GetNotifications(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	notifications, err := u.svc.GetNotifications(ctx, uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Notification{}
+	for _, item := range notifications {
+		notification := dto.Notification{
+			ID:        item.ID,
+			Type:      item.Type,
+			PhotoID:   item.PhotoID,
+			CommentID: item.CommentID,
+			Read:      item.ReadAt != nil,
+			CreatedAt: &item.CreatedAt,
+		}
+		if item.Actor != nil {
+			notification.Actor = &dto.UserDefault{ID: &item.Actor.ID, Username: item.Actor.Username}
+		}
+		data = append(data, notification)
+	}
+
+	info := dto.PageInfo{}
+	if len(notifications) == page.Size() {
+		next := notifications[len(notifications)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
+
+// MarkNotificationsRead godoc
+//
+// @Summary		Mark notifications read
+// @Description	Mark all of the caller's notifications as read
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/notifications/read [post]
+func (u *notificationHandlerImpl) MarkRead(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.MarkRead(ctx, uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Notifications have been marked as read"})
+}
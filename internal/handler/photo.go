@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -16,10 +18,19 @@ import (
 
 type PhotoHandler interface {
 	GetPhotos(ctx *gin.Context)
+	GetPhoto(ctx *gin.Context)
+	GetUserPhotos(ctx *gin.Context)
+	GetPhotoRevisions(ctx *gin.Context)
 	EditPhoto(ctx *gin.Context)
+	SetPhotoLocation(ctx *gin.Context)
 	DeletePhoto(ctx *gin.Context)
 
 	PostPhoto(ctx *gin.Context)
+
+	AddMediaItem(ctx *gin.Context)
+	EditMediaItem(ctx *gin.Context)
+	DeleteMediaItem(ctx *gin.Context)
+	ReorderMediaItems(ctx *gin.Context)
 }
 
 type photoHandlerImpl struct {
@@ -72,20 +83,7 @@ func (u *photoHandlerImpl) GetPhotos(ctx *gin.Context) {
 
 	var data []dto.Photo
 	for _, item := range photos {
-		photo := dto.Photo{
-			ID:        item.ID,
-			Title:     item.Title,
-			Caption:   item.Caption,
-			Url:       item.Url,
-			UserID:    item.UserID,
-			CreatedAt: &item.CreatedAt,
-			UpdatedAt: &item.UpdatedAt,
-			User: &dto.UserDefault{
-				Email:    item.User.Email,
-				Username: item.User.Username,
-			},
-		}
-		data = append(data, photo)
+		data = append(data, photoCounts(ownPhoto(item), item))
 	}
 
 	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
@@ -106,21 +104,212 @@ func (u *photoHandlerImpl) GetPhotos(ctx *gin.Context) {
 // 	ctx.JSON(http.StatusOK, user)
 // }
 
+// ShowPhoto godoc
+//
+// @Summary		Show a photo
+// @Description	Get a photo the caller may see with its author, like and comment counts, whether the caller liked and saved it, and its comments oldest first. Pass next_cursor from meta to get the next page of comments.
+// @Tags			photos
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page of comments"
+// @Param        limit   query      int  false  "Comments per page"
+// @Success		200	{object}	dto.Photo
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/{id} [get]
+func (u *photoHandlerImpl) GetPhoto(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	photo, err := u.svc.GetPhoto(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := photoSummary(photo)
+	data.UpdatedAt = &photo.UpdatedAt
+	if photo.UserID == uint64(userId) {
+		data.Status = photo.Status
+		data.Visibility = photo.Visibility
+	}
+	data.Comments = []dto.Comment{}
+	for _, item := range photo.Comments {
+		data.Comments = append(data.Comments, commentSummary(item))
+	}
+	info := dto.PageInfo{}
+	if len(photo.Comments) == page.Size() {
+		next := photo.Comments[len(photo.Comments)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
+
+// ShowUserPhotos godoc
+//
+// @Summary		Show user photos
+// @Description	Get the profile grid of a user, the photos the caller may see newest first. A private account shows none to callers who do not follow it. Pass next_cursor from meta to get the next page.
+// @Tags			photos
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Photo
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/photos [get]
+func (u *photoHandlerImpl) GetUserPhotos(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	photos, err := u.svc.GetUserPhotos(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Photo{}
+	for _, item := range photos {
+		photo := photoSummary(item)
+		if item.UserID == uint64(userId) {
+			photo.Status = item.Status
+			photo.Visibility = item.Visibility
+		}
+		data = append(data, photo)
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: photoPageInfo(photos, page.Size())})
+}
+
+// ShowPhotoRevisions godoc
+//
+// @Summary		Show photo revisions
+// @Description	Get the titles and captions a photo had before each of its edits newest first, with who made the edit and when. Only the author and admins may see them. Pass next_cursor from meta to get the next page.
+// @Tags			photos
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Revision
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/{id}/revisions [get]
+func (u *photoHandlerImpl) GetPhotoRevisions(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	revisions, err := u.svc.GetPhotoRevisions(ctx, uint64(userId), isAdmin(claims.(jwt.MapClaims)), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: revisionSummaries(revisions), Meta: revisionPageInfo(revisions, page.Size())})
+}
+
 //	 PostPhoto godoc
 //
 //		@Summary		Post a photo
-//		@Description	Create photo with input payload
+//		@Description	Upload a jpeg, png, gif or webp image of up to 10 MB as multipart form data. The photo is returned with status "processing" until its resized variants are ready. Earlier photos of the user that look the same are listed in duplicate_of.
 //		@Tags			photos
-//		@Accept			json
+//		@Accept			multipart/form-data
 //		@Produce		json
 //
 // @Security ApiKeyAuth
 // @param Authorization header string true "Authorization"
 //
-//	@Param photo body Photo true "Create Photo"
+//	@Param title formData string true "Title"
+//	@Param caption formData string false "Caption"
+//	@Param share_metadata formData bool false "Keep camera model and capture time from EXIF"
+//	@Param visibility formData string false "Who may see the photo: public, followers, close_friends or private"
+//	@Param alt_text formData []string false "Alt text of each image, in order" collectionFormat(multi)
+//	@Param latitude formData number false "Latitude where the photo was taken"
+//	@Param longitude formData number false "Longitude where the photo was taken"
+//	@Param place_name formData string false "Name of the place"
+//	@Param location_precision formData string false "How finely the location is kept: exact, approximate or city, approximate by default"
+//	@Param photo formData file true "Images, repeat the field for a carousel"
 //	@Success		201	{object}	dto.Photo
 //	@Failure		400	{object}	pkg.ErrorResponse
 //	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		413	{object}	pkg.ErrorResponse
+//	@Failure		415	{object}	pkg.ErrorResponse
 //	@Failure		500	{object}	pkg.ErrorResponse
 //	@Router			/photos [post]
 func (u *photoHandlerImpl) PostPhoto(ctx *gin.Context) {
@@ -137,39 +326,164 @@ func (u *photoHandlerImpl) PostPhoto(ctx *gin.Context) {
 		return
 	}
 
-	// binding sign-up body
-	photo := model.Photo{}
-	if err := ctx.Bind(&photo); err != nil {
+	// leave room for the other form fields next to the images
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.MaxMediaItems*service.MaxPhotoSize+1<<20)
+
+	req := dto.PhotoUpload{}
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(uploadErrorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
 		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	// if err := userSignUp.Validate(); err != nil {
-	// 	ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
-	// 	return
-	// }
-
-	if err := u.validator.ValidateStruct(photo); err != nil {
-		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+	form, err := ctx.MultipartForm()
+	if err != nil || len(form.File["photo"]) == 0 {
+		ctx.JSON(uploadErrorStatus(err), pkg.ErrorResponse{Message: "photo file is required"})
+		return
+	}
+	headers := form.File["photo"]
+	if len(headers) > service.MaxMediaItems {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: service.ErrTooManyMediaItems.Error()})
 		return
 	}
+	files := []io.Reader{}
+	for _, header := range headers {
+		if header.Size > service.MaxPhotoSize {
+			ctx.JSON(http.StatusRequestEntityTooLarge, pkg.ErrorResponse{Message: service.ErrPhotoTooLarge.Error()})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+			return
+		}
+		defer file.Close()
+		files = append(files, file)
+	}
 
-	photo, err := u.svc.PostPhoto(ctx, photo, uint64(userID))
+	photo, duplicates, err := u.svc.PostPhoto(ctx, req, files, uint64(userID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	data := dto.Photo{
-		ID:        photo.ID,
-		Title:     photo.Title,
-		Caption:   photo.Caption,
-		Url:       photo.Url,
-		UserID:    photo.UserID,
-		CreatedAt: &photo.CreatedAt,
+	data := ownPhoto(photo)
+	message := ""
+	for _, dup := range duplicates {
+		data.DuplicateOf = append(data.DuplicateOf, dup.ID)
+		message = "You have posted this photo before"
+	}
+
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Message: message, Data: data})
+}
+
+func photoVariants(variants []model.PhotoVariant) []dto.PhotoVariant {
+	res := []dto.PhotoVariant{}
+	for _, item := range variants {
+		res = append(res, dto.PhotoVariant{
+			Name:   item.Name,
+			Format: item.Format,
+			Url:    item.Url,
+			Width:  item.Width,
+			Height: item.Height,
+		})
 	}
+	return res
+}
 
-	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: data})
+func mediaItems(items []model.MediaItem) []dto.MediaItem {
+	res := []dto.MediaItem{}
+	for _, item := range items {
+		res = append(res, mediaItem(item))
+	}
+	return res
+}
+
+func mediaItem(item model.MediaItem) dto.MediaItem {
+	return dto.MediaItem{
+		ID:       item.ID,
+		Position: item.Position,
+		Url:      item.Url,
+		Width:    item.Width,
+		Height:   item.Height,
+		AltText:  item.AltText,
+	}
+}
+
+// commentSummary shows a comment with the public part of its author.
+func commentSummary(item model.Comment) dto.Comment {
+	comment := dto.Comment{
+		ID:        item.ID,
+		Message:   item.Message,
+		PhotoID:   item.PhotoID,
+		UserID:    item.UserID,
+		Mentions:  mentionEntities(item.Mentions),
+		CreatedAt: &item.CreatedAt,
+		UpdatedAt: &item.UpdatedAt,
+		Edited:    item.EditedAt != nil,
+		EditedAt:  item.EditedAt,
+	}
+	if item.User != nil {
+		comment.User = &dto.UserDefault{ID: &item.User.ID, Username: item.User.Username}
+	}
+	return comment
+}
+
+func revisionSummaries(revisions []model.Revision) []dto.Revision {
+	data := []dto.Revision{}
+	for _, item := range revisions {
+		revision := dto.Revision{
+			ID:       item.ID,
+			Title:    item.Title,
+			Text:     item.Text,
+			EditorID: item.EditorID,
+			EditedAt: item.CreatedAt,
+		}
+		if item.Editor != nil {
+			revision.Editor = &dto.UserDefault{ID: &item.Editor.ID, Username: item.Editor.Username}
+		}
+		data = append(data, revision)
+	}
+	return data
+}
+
+func revisionPageInfo(revisions []model.Revision, limit int) dto.PageInfo {
+	info := dto.PageInfo{}
+	if len(revisions) < limit {
+		return info
+	}
+	next := revisions[len(revisions)-1].ID
+	info.NextCursor = &next
+	return info
+}
+
+// photoLocation is nil for photos without a location.
+func photoLocation(photo model.Photo) *dto.PhotoLocation {
+	if photo.Latitude == nil && photo.PlaceName == "" {
+		return nil
+	}
+	return &dto.PhotoLocation{Latitude: photo.Latitude, Longitude: photo.Longitude, PlaceName: photo.PlaceName}
+}
+
+func mentionEntities(mentions []model.Mention) []dto.Mention {
+	res := []dto.Mention{}
+	for _, item := range mentions {
+		res = append(res, dto.Mention{UserID: item.UserID, Offset: item.Offset, Length: item.Length})
+	}
+	return res
+}
+
+// uploadErrorStatus tells a request body over the size limit apart from a
+// malformed one.
+func uploadErrorStatus(err error) int {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
 }
 
 //	 UpdatePhoto godoc
@@ -235,21 +549,59 @@ func (u *photoHandlerImpl) EditPhoto(ctx *gin.Context) {
 		return
 	}
 
-	photo, err := u.svc.EditPhoto(ctx, req, uint64(id))
+	photo, err := u.svc.EditPhoto(ctx, req, uint64(id), uint64(userId))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := ownPhoto(photo)
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+// SetPhotoLocation godoc
+//
+//	@Summary		Set the location of a photo
+//	@Description	Replace where a photo was taken. The coordinates are rounded to the precision asked for before they are stored: exact keeps about 11 m, approximate about 1 km and city about 11 km. Leaving out both the coordinates and the place name removes the location.
+//	@Tags			photos
+//	@Accept			json
+//	@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param        id   path      int  true  "Photo ID"
+//	@Param location body dto.PhotoLocationInput true "Location"
+//	@Success		200	{object}	dto.Photo
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/location [put]
+func (u *photoHandlerImpl) SetPhotoLocation(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
 		return
 	}
 
-	data := dto.Photo{
-		ID:        photo.ID,
-		Title:     photo.Title,
-		Caption:   photo.Caption,
-		Url:       photo.Url,
-		UserID:    photo.UserID,
-		UpdatedAt: &photo.UpdatedAt,
+	req := dto.PhotoLocationInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	photo, err := u.svc.SetPhotoLocation(ctx, uint64(id), req)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
 	}
 
+	data := ownPhoto(photo)
 	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
 }
 
@@ -306,3 +658,191 @@ func (u *photoHandlerImpl) DeletePhoto(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your photo has been successfully deleted"})
 }
+
+// AddMediaItem godoc
+//
+//	@Summary		Add an image to a post
+//	@Description	Append a jpeg, png, gif or webp image of up to 10 MB to the carousel of a post. A post holds at most 10 images.
+//	@Tags			photos
+//	@Accept			multipart/form-data
+//	@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param        id   path      int  true  "Photo ID"
+//	@Param alt_text formData string false "Alt text"
+//	@Param photo formData file true "Image"
+//	@Success		201	{object}	dto.MediaItem
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		413	{object}	pkg.ErrorResponse
+//	@Failure		415	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/media [post]
+func (u *photoHandlerImpl) AddMediaItem(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.MaxPhotoSize+1<<20)
+
+	req := dto.MediaItemInput{AltText: ctx.PostForm("alt_text")}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("photo")
+	if err != nil {
+		ctx.JSON(uploadErrorStatus(err), pkg.ErrorResponse{Message: "photo file is required"})
+		return
+	}
+	defer file.Close()
+	if header.Size > service.MaxPhotoSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, pkg.ErrorResponse{Message: service.ErrPhotoTooLarge.Error()})
+		return
+	}
+
+	item, err := u.svc.AddMediaItem(ctx, uint64(id), file, req.AltText)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: mediaItem(item)})
+}
+
+// EditMediaItem godoc
+//
+//	@Summary		Edit an image of a post
+//	@Description	Change the alt text of one image of a post
+//	@Tags			photos
+//	@Accept			json
+//	@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param        id   path      int  true  "Photo ID"
+//	@Param        media_id   path      int  true  "Media item ID"
+//	@Param item body dto.MediaItemInput true "Alt text"
+//	@Success		200	{object}	dto.MediaItem
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/media/{media_id} [put]
+func (u *photoHandlerImpl) EditMediaItem(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	mediaID, err := strconv.Atoi(ctx.Param("media_id"))
+	if mediaID == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.MediaItemInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	item, err := u.svc.EditMediaItem(ctx, uint64(id), uint64(mediaID), req.AltText)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: mediaItem(item)})
+}
+
+// DeleteMediaItem godoc
+//
+//	@Summary		Remove an image from a post
+//	@Description	Remove one image of a carousel, the images after it move up. The last image of a post cannot be removed, delete the photo instead.
+//	@Tags			photos
+//	@Accept			json
+//	@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param        id   path      int  true  "Photo ID"
+//	@Param        media_id   path      int  true  "Media item ID"
+//	@Success		200	{object}	pkg.SuccessResponse
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/media/{media_id} [delete]
+func (u *photoHandlerImpl) DeleteMediaItem(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	mediaID, err := strconv.Atoi(ctx.Param("media_id"))
+	if mediaID == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.DeleteMediaItem(ctx, uint64(id), uint64(mediaID)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "The image has been removed from your post"})
+}
+
+// ReorderMediaItems godoc
+//
+//	@Summary		Reorder the images of a post
+//	@Description	Put the images of a carousel in the order of media_ids, which lists every image of the post once. The first image becomes the cover.
+//	@Tags			photos
+//	@Accept			json
+//	@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param        id   path      int  true  "Photo ID"
+//	@Param order body dto.MediaOrder true "New order"
+//	@Success		200	{object}	[]dto.MediaItem
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/photos/{id}/media [put]
+func (u *photoHandlerImpl) ReorderMediaItems(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.MediaOrder{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	items, err := u.svc.ReorderMediaItems(ctx, uint64(id), req.MediaIDs)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: mediaItems(items)})
+}
@@ -0,0 +1,451 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type SaveHandler interface {
+	Save(ctx *gin.Context)
+	Unsave(ctx *gin.Context)
+	GetSaved(ctx *gin.Context)
+
+	GetCollections(ctx *gin.Context)
+	CreateCollection(ctx *gin.Context)
+	RenameCollection(ctx *gin.Context)
+	DeleteCollection(ctx *gin.Context)
+	AddToCollection(ctx *gin.Context)
+	RemoveFromCollection(ctx *gin.Context)
+}
+
+type saveHandlerImpl struct {
+	svc       service.SaveService
+	validator *validator.CustomValidator
+}
+
+func NewSaveHandler(svc service.SaveService, validator *validator.CustomValidator) SaveHandler {
+	return &saveHandlerImpl{svc: svc, validator: validator}
+}
+
+// Save godoc
+//
+// @Summary		Save a photo
+// @Description	Save a photo for later, only the caller sees what they saved. Saving a photo again changes nothing
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Success		200	{object}	dto.PhotoSaved
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/{id}/save [post]
+func (u *saveHandlerImpl) Save(ctx *gin.Context) {
+	u.setSave(ctx, u.svc.Save, true)
+}
+
+// Unsave godoc
+//
+// @Summary		Unsave a photo
+// @Description	Remove a photo from the saved photos and from every collection
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Photo ID"
+// @Success		200	{object}	dto.PhotoSaved
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/{id}/save [delete]
+func (u *saveHandlerImpl) Unsave(ctx *gin.Context) {
+	u.setSave(ctx, u.svc.Unsave, false)
+}
+
+func (u *saveHandlerImpl) setSave(ctx *gin.Context, set func(ctx context.Context, userID uint64, photoID uint64) error, saved bool) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := set(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: dto.PhotoSaved{PhotoID: uint64(id), SavedByMe: saved}})
+}
+
+// ShowSaved godoc
+//
+// @Summary		Show saved photos
+// @Description	Get the caller's saved photos, newest save first. Deleted photos and photos the caller may no longer see are left out. Pass next_cursor from meta to get the next page.
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        collection_id   query      int  false  "Only photos of this collection"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.Photo
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/saved [get]
+func (u *saveHandlerImpl) GetSaved(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.SavedPage{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	saves, err := u.svc.GetSaved(ctx, uint64(userId), page.CollectionID, page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Photo{}
+	for _, save := range saves {
+		data = append(data, photoSummary(*save.Photo))
+	}
+	info := dto.PageInfo{}
+	if len(saves) == page.Size() {
+		next := saves[len(saves)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
+
+// ShowCollections godoc
+//
+// @Summary		Show collections
+// @Description	Get the caller's collections of saved photos by name
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success		200	{object}	[]dto.SaveCollection
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/saved/collections [get]
+func (u *saveHandlerImpl) GetCollections(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	collections, err := u.svc.GetCollections(ctx, uint64(userId))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.SaveCollection{}
+	for _, item := range collections {
+		data = append(data, collectionSummary(item))
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+// CreateCollection godoc
+//
+// @Summary		Create a collection
+// @Description	Create a private collection of saved photos
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param collection body dto.SaveCollectionInput true "Create Collection"
+// @Success		201	{object}	dto.SaveCollection
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/saved/collections [post]
+func (u *saveHandlerImpl) CreateCollection(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.SaveCollectionInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	collection, err := u.svc.CreateCollection(ctx, uint64(userId), req.Name)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: collectionSummary(collection)})
+}
+
+// RenameCollection godoc
+//
+// @Summary		Rename a collection
+// @Description	Rename one of the caller's collections
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Collection ID"
+// @Param collection body dto.SaveCollectionInput true "Rename Collection"
+// @Success		200	{object}	dto.SaveCollection
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/saved/collections/{id} [put]
+func (u *saveHandlerImpl) RenameCollection(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.SaveCollectionInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	collection, err := u.svc.RenameCollection(ctx, uint64(userId), uint64(id), req.Name)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: collectionSummary(collection)})
+}
+
+// DeleteCollection godoc
+//
+// @Summary		Delete a collection
+// @Description	Delete one of the caller's collections, its photos stay saved
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Collection ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/saved/collections/{id} [delete]
+func (u *saveHandlerImpl) DeleteCollection(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.DeleteCollection(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your collection has been successfully deleted"})
+}
+
+// AddToCollection godoc
+//
+// @Summary		Add a photo to a collection
+// @Description	Add a photo to one of the caller's collections, saving it if it is not saved yet
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Collection ID"
+// @Param photo body dto.SaveCollectionPhotoInput true "Add Photo"
+// @Success		200	{object}	dto.PhotoSaved
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/saved/collections/{id}/photos [post]
+func (u *saveHandlerImpl) AddToCollection(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.SaveCollectionPhotoInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.svc.AddToCollection(ctx, uint64(userId), uint64(id), req.PhotoID); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: dto.PhotoSaved{PhotoID: req.PhotoID, SavedByMe: true}})
+}
+
+// RemoveFromCollection godoc
+//
+// @Summary		Remove a photo from a collection
+// @Description	Take a photo out of one of the caller's collections, it stays saved
+// @Tags			saved
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Collection ID"
+// @Param        photo_id   path      int  true  "Photo ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/saved/collections/{id}/photos/{photo_id} [delete]
+func (u *saveHandlerImpl) RemoveFromCollection(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	photoID, err := strconv.Atoi(ctx.Param("photo_id"))
+	if photoID == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.RemoveFromCollection(ctx, uint64(userId), uint64(id), uint64(photoID)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "The photo has been removed from your collection"})
+}
+
+func collectionSummary(item model.SaveCollection) dto.SaveCollection {
+	return dto.SaveCollection{
+		ID:        item.ID,
+		Name:      item.Name,
+		CreatedAt: &item.CreatedAt,
+		UpdatedAt: &item.UpdatedAt,
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type SearchHandler interface {
+	SearchPhotos(ctx *gin.Context)
+	NearbyPhotos(ctx *gin.Context)
+}
+
+type searchHandlerImpl struct {
+	svc       service.SearchService
+	validator *validator.CustomValidator
+}
+
+func NewSearchHandler(svc service.SearchService, validator *validator.CustomValidator) SearchHandler {
+	return &searchHandlerImpl{svc: svc, validator: validator}
+}
+
+// SearchPhotos godoc
+//
+// @Summary		Search photos
+// @Description	Find photos by the words of their title and caption, most relevant and recent first, with the matched words highlighted. q takes quoted phrases, "or" and "-" before a word to exclude it. Pass next_cursor from meta to get the next page.
+// @Tags			search
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        q   query      string  true  "Search query"
+// @Param        cursor   query      string  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.PhotoMatch
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/search/photos [get]
+func (u *searchHandlerImpl) SearchPhotos(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	search := dto.PhotoSearch{}
+	if err := ctx.ShouldBindQuery(&search); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(search); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	matches, next, err := u.svc.SearchPhotos(ctx, uint64(id), search.Query, search.Cursor, search.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.PhotoMatch{}
+	for _, match := range matches {
+		data = append(data, dto.PhotoMatch{
+			Photo:             photoSummary(*match.Photo),
+			TitleHighlights:   highlights(match.TitleHighlights),
+			CaptionHighlights: highlights(match.CaptionHighlights),
+		})
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: dto.CursorInfo{NextCursor: next}})
+}
+
+// NearbyPhotos godoc
+//
+// @Summary		Find photos nearby
+// @Description	Find photos taken within radius km of a point, nearest first. The radius is 5 km when left out and at most 50 km. Locations are as precise as their authors chose to share them. Pass next_cursor from meta to get the next page.
+// @Tags			search
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        lat   query      number  true  "Latitude"
+// @Param        lng   query      number  true  "Longitude"
+// @Param        radius   query      number  false  "Radius in km"
+// @Param        cursor   query      string  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.NearbyPhoto
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/photos/nearby [get]
+func (u *searchHandlerImpl) NearbyPhotos(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	search := dto.PhotoNearby{}
+	if err := ctx.ShouldBindQuery(&search); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(search); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	photos, next, err := u.svc.NearbyPhotos(ctx, uint64(id), *search.Latitude, *search.Longitude, search.Radius, search.Cursor, search.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.NearbyPhoto{}
+	for _, item := range photos {
+		data = append(data, dto.NearbyPhoto{Photo: photoSummary(*item.Photo), DistanceKm: item.DistanceKm})
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: dto.CursorInfo{NextCursor: next}})
+}
+
+func highlights(items []model.Highlight) []dto.Highlight {
+	res := []dto.Highlight{}
+	for _, item := range items {
+		res = append(res, dto.Highlight{Offset: item.Offset, Length: item.Length})
+	}
+	return res
+}
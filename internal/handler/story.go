@@ -0,0 +1,816 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type StoryHandler interface {
+	PostStory(ctx *gin.Context)
+	GetStoryTray(ctx *gin.Context)
+	GetUserStories(ctx *gin.Context)
+	MarkSeen(ctx *gin.Context)
+	GetStoryViewers(ctx *gin.Context)
+	DeleteStory(ctx *gin.Context)
+
+	GetHighlight(ctx *gin.Context)
+	GetUserHighlights(ctx *gin.Context)
+	CreateHighlight(ctx *gin.Context)
+	EditHighlight(ctx *gin.Context)
+	DeleteHighlight(ctx *gin.Context)
+	AddHighlightStory(ctx *gin.Context)
+	RemoveHighlightStory(ctx *gin.Context)
+	ReorderHighlightStories(ctx *gin.Context)
+}
+
+type storyHandlerImpl struct {
+	svc       service.StoryService
+	validator *validator.CustomValidator
+}
+
+func NewStoryHandler(svc service.StoryService, validator *validator.CustomValidator) StoryHandler {
+	return &storyHandlerImpl{svc: svc, validator: validator}
+}
+
+// PostStory godoc
+//
+// @Summary		Post a story
+// @Description	Upload a jpeg, png, gif or webp image of up to 10 MB as multipart form data. The story is shown to your followers for 24 hours, add it to a highlight to keep it on your profile.
+// @Tags			stories
+// @Accept			multipart/form-data
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param caption formData string false "Caption"
+// @Param alt_text formData string false "Alt text"
+// @Param photo formData file true "Image"
+// @Success		201	{object}	dto.Story
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		413	{object}	pkg.ErrorResponse
+// @Failure		415	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/stories [post]
+func (u *storyHandlerImpl) PostStory(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	// leave room for the other form fields next to the image
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.MaxPhotoSize+1<<20)
+
+	req := dto.StoryUpload{}
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(uploadErrorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("photo")
+	if err != nil {
+		ctx.JSON(uploadErrorStatus(err), pkg.ErrorResponse{Message: "photo file is required"})
+		return
+	}
+	defer file.Close()
+	if header.Size > service.MaxPhotoSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, pkg.ErrorResponse{Message: service.ErrPhotoTooLarge.Error()})
+		return
+	}
+
+	story, err := u.svc.PostStory(ctx, req, file, uint64(userId))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: storySummary(story, uint64(userId))})
+}
+
+// ShowStoryTray godoc
+//
+// @Summary		Show story tray
+// @Description	Get the live stories of the caller and the accounts they follow grouped by account. The caller's own come first, then accounts with stories the caller has not seen.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Success		200	{object}	[]dto.StoryTray
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/stories [get]
+func (u *storyHandlerImpl) GetStoryTray(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	trays, err := u.svc.GetStoryTray(ctx, uint64(userId))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.StoryTray{}
+	for _, tray := range trays {
+		item := dto.StoryTray{
+			User:    dto.UserDefault{ID: &tray.User.ID, Username: tray.User.Username},
+			Seen:    tray.Seen,
+			Stories: []dto.Story{},
+		}
+		for _, story := range tray.Stories {
+			item.Stories = append(item.Stories, seenStorySummary(story, uint64(userId)))
+		}
+		data = append(data, item)
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+// ShowUserStories godoc
+//
+// @Summary		Show user stories
+// @Description	Get the live stories of a user, oldest first. Only the user and their followers see them.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Success		200	{object}	[]dto.Story
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/stories [get]
+func (u *storyHandlerImpl) GetUserStories(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	stories, err := u.svc.GetUserStories(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Story{}
+	for _, story := range stories {
+		data = append(data, seenStorySummary(story, uint64(userId)))
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+// MarkStorySeen godoc
+//
+// @Summary		Mark a story seen
+// @Description	Record that the caller has seen a live story. Only the first time counts as a view.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Story ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/stories/{id}/seen [post]
+func (u *storyHandlerImpl) MarkSeen(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.MarkSeen(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Story seen"})
+}
+
+// ShowStoryViewers godoc
+//
+// @Summary		Show story viewers
+// @Description	Get who has seen one of the caller's stories, latest first. Pass next_cursor from meta to get the next page.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Story ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.StoryViewer
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/stories/{id}/viewers [get]
+func (u *storyHandlerImpl) GetStoryViewers(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	views, err := u.svc.GetStoryViewers(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.StoryViewer{}
+	for _, view := range views {
+		viewer := dto.StoryViewer{ID: view.ID, UserID: view.ViewerID, ViewedAt: view.CreatedAt}
+		if view.Viewer != nil {
+			viewer.Username = view.Viewer.Username
+		}
+		data = append(data, viewer)
+	}
+	info := dto.PageInfo{}
+	if len(views) == page.Size() {
+		next := views[len(views)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
+
+// DeleteStory godoc
+//
+// @Summary		Delete a story
+// @Description	Delete one of the caller's stories, it is taken out of every highlight too.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Story ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/stories/{id} [delete]
+func (u *storyHandlerImpl) DeleteStory(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.DeleteStory(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your story has been successfully deleted"})
+}
+
+// ShowHighlight godoc
+//
+// @Summary		Show highlight
+// @Description	Get a highlight and all its stories in highlight order. Highlights are seen by everyone who sees the owner's profile.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Highlight ID"
+// @Success		200	{object}	dto.StoryHighlightPage
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/highlights/{id} [get]
+func (u *storyHandlerImpl) GetHighlight(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	highlight, items, err := u.svc.GetHighlight(ctx, uint64(userId), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.StoryHighlightPage{Highlight: highlightSummary(highlight, uint64(userId)), Stories: []dto.Story{}}
+	for _, item := range items {
+		if item.Story == nil {
+			continue
+		}
+		data.Stories = append(data.Stories, storySummary(*item.Story, uint64(userId)))
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
+
+// ShowUserHighlights godoc
+//
+// @Summary		Show user highlights
+// @Description	Get the highlights of a user with their covers, newest first. Pass next_cursor from meta to get the next page.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "User ID"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	[]dto.StoryHighlight
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/users/{id}/highlights [get]
+func (u *storyHandlerImpl) GetUserHighlights(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	highlights, err := u.svc.GetUserHighlights(ctx, uint64(userId), uint64(id), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.StoryHighlight{}
+	for _, highlight := range highlights {
+		data = append(data, highlightSummary(highlight, uint64(userId)))
+	}
+	info := dto.PageInfo{}
+	if len(highlights) == page.Size() {
+		next := highlights[len(highlights)-1].ID
+		info.NextCursor = &next
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: info})
+}
+
+// CreateHighlight godoc
+//
+// @Summary		Create a highlight
+// @Description	Create a highlight of the caller's stories in the order given. Stories in a highlight stay on the profile after they expire.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param highlight body dto.StoryHighlightInput true "Highlight"
+// @Success		201	{object}	dto.StoryHighlight
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/highlights [post]
+func (u *storyHandlerImpl) CreateHighlight(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.StoryHighlightInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	highlight, err := u.svc.CreateHighlight(ctx, uint64(userId), req.Title, req.StoryIDs)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, pkg.SuccessResponse{Data: highlightSummary(highlight, uint64(userId))})
+}
+
+// UpdateHighlight godoc
+//
+// @Summary		Update a highlight
+// @Description	Rename one of the caller's highlights
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Highlight ID"
+// @Param highlight body dto.StoryHighlightInput true "Highlight, story_ids is ignored"
+// @Success		200	{object}	dto.StoryHighlight
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/highlights/{id} [put]
+func (u *storyHandlerImpl) EditHighlight(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.StoryHighlightInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	highlight, err := u.svc.EditHighlight(ctx, uint64(userId), uint64(id), req.Title)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: highlightSummary(highlight, uint64(userId))})
+}
+
+// DeleteHighlight godoc
+//
+// @Summary		Delete a highlight
+// @Description	Delete one of the caller's highlights. Its expired stories are deleted unless another highlight keeps them.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Highlight ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/highlights/{id} [delete]
+func (u *storyHandlerImpl) DeleteHighlight(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.DeleteHighlight(ctx, uint64(userId), uint64(id)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your highlight has been successfully deleted"})
+}
+
+// AddHighlightStory godoc
+//
+// @Summary		Add a story to a highlight
+// @Description	Append one of the caller's stories to one of their highlights, adding a story that is already in it does nothing.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Highlight ID"
+// @Param story body dto.StoryHighlightItemInput true "Story"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		403	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/highlights/{id}/stories [post]
+func (u *storyHandlerImpl) AddHighlightStory(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.StoryHighlightItemInput{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.svc.AddHighlightStory(ctx, uint64(userId), uint64(id), req.StoryID); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "The story has been added to your highlight"})
+}
+
+// RemoveHighlightStory godoc
+//
+// @Summary		Remove a story from a highlight
+// @Description	Take a story out of one of the caller's highlights. An expired story is deleted unless another highlight keeps it.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Highlight ID"
+// @Param        story_id   path      int  true  "Story ID"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/highlights/{id}/stories/{story_id} [delete]
+func (u *storyHandlerImpl) RemoveHighlightStory(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+	storyID, err := strconv.Atoi(ctx.Param("story_id"))
+	if storyID == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	if err := u.svc.RemoveHighlightStory(ctx, uint64(userId), uint64(id), uint64(storyID)); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "The story has been removed from your highlight"})
+}
+
+// ReorderHighlightStories godoc
+//
+// @Summary		Reorder a highlight
+// @Description	Put the stories of one of the caller's highlights in the order of story_ids, which lists every story of the highlight once.
+// @Tags			stories
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        id   path      int  true  "Highlight ID"
+// @Param order body dto.StoryHighlightOrder true "New order"
+// @Success		200	{object}	pkg.SuccessResponse
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/highlights/{id}/stories [put]
+func (u *storyHandlerImpl) ReorderHighlightStories(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.StoryHighlightOrder{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.svc.ReorderHighlightStories(ctx, uint64(userId), uint64(id), req.StoryIDs); err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your highlight has been reordered"})
+}
+
+// storySummary shows the view count to the author only.
+func storySummary(item model.Story, viewerID uint64) dto.Story {
+	story := dto.Story{
+		ID:        item.ID,
+		Url:       item.Url,
+		Width:     item.Width,
+		Height:    item.Height,
+		Caption:   item.Caption,
+		AltText:   item.AltText,
+		UserID:    item.UserID,
+		ExpiresAt: item.ExpiresAt,
+		CreatedAt: &item.CreatedAt,
+	}
+	if item.UserID == viewerID {
+		story.ViewCount = &item.ViewCount
+	}
+	if item.User != nil {
+		story.User = &dto.UserDefault{ID: &item.User.ID, Username: item.User.Username}
+	}
+	return story
+}
+
+// seenStorySummary adds the seen state, for listings that look it up.
+func seenStorySummary(item model.Story, viewerID uint64) dto.Story {
+	story := storySummary(item, viewerID)
+	story.Seen = &item.SeenByMe
+	return story
+}
+
+func highlightSummary(item model.StoryHighlight, viewerID uint64) dto.StoryHighlight {
+	highlight := dto.StoryHighlight{
+		ID:        item.ID,
+		Title:     item.Title,
+		UserID:    item.UserID,
+		CreatedAt: &item.CreatedAt,
+		UpdatedAt: &item.UpdatedAt,
+	}
+	if item.CoverStory != nil {
+		cover := storySummary(*item.CoverStory, viewerID)
+		highlight.CoverStory = &cover
+	}
+	if item.User != nil {
+		highlight.User = &dto.UserDefault{ID: &item.User.ID, Username: item.User.Username}
+	}
+	return highlight
+}
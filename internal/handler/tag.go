@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MidnightHelix/MyGram/internal/service"
+	"github.com/MidnightHelix/MyGram/pkg"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type TagHandler interface {
+	GetTag(ctx *gin.Context)
+	SearchTags(ctx *gin.Context)
+}
+
+type tagHandlerImpl struct {
+	svc       service.TagService
+	validator *validator.CustomValidator
+}
+
+func NewTagHandler(svc service.TagService, validator *validator.CustomValidator) TagHandler {
+	return &tagHandlerImpl{svc: svc, validator: validator}
+}
+
+// ShowTag godoc
+//
+// @Summary		Show tag
+// @Description	Get a hashtag and its photos, newest first. Pass next_cursor from meta to get the next page.
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        name   path      string  true  "Tag name, with or without #"
+// @Param        cursor   query      int  false  "Cursor from the previous page"
+// @Param        limit   query      int  false  "Page size"
+// @Success		200	{object}	dto.TagPage
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		404	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/tags/{name} [get]
+func (u *tagHandlerImpl) GetTag(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	page := dto.Page{}
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	tag, photos, err := u.svc.GetTagPhotos(ctx, uint64(id), ctx.Param("name"), page.Cursor, page.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := dto.TagPage{
+		Tag:    dto.Tag{Name: tag.Name, PhotoCount: tag.PhotoCount},
+		Photos: []dto.Photo{},
+	}
+	for _, item := range photos {
+		data.Photos = append(data.Photos, photoSummary(item))
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: photoPageInfo(photos, page.Size())})
+}
+
+// SearchTags godoc
+//
+// @Summary		Search tags
+// @Description	Autocomplete hashtags starting with q, most used first
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+// @Param        q   query      string  true  "Tag prefix, with or without #"
+// @Param        limit   query      int  false  "Number of tags"
+// @Success		200	{object}	[]dto.Tag
+// @Failure		400	{object}	pkg.ErrorResponse
+// @Failure		500	{object}	pkg.ErrorResponse
+// @Router			/tags [get]
+func (u *tagHandlerImpl) SearchTags(ctx *gin.Context) {
+	search := dto.TagSearch{}
+	if err := ctx.ShouldBindQuery(&search); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(search); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	tags, err := u.svc.SearchTags(ctx, search.Query, search.Limit)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := []dto.Tag{}
+	for _, tag := range tags {
+		data = append(data, dto.Tag{Name: tag.Name, PhotoCount: tag.PhotoCount})
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
+}
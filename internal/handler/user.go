@@ -3,7 +3,9 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/MidnightHelix/MyGram/internal/model"
 	"github.com/MidnightHelix/MyGram/internal/service"
@@ -17,20 +19,27 @@ import (
 type UserHandler interface {
 	GetUsers(ctx *gin.Context)
 	GetUsersById(ctx *gin.Context)
+	GetUserByUsername(ctx *gin.Context)
+	CheckUsername(ctx *gin.Context)
+	ChangeUsername(ctx *gin.Context)
 	UserSignUp(ctx *gin.Context)
 	UserLogin(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
 	EditUser(ctx *gin.Context)
+	UpdatePrivacy(ctx *gin.Context)
 	DeleteUser(ctx *gin.Context)
 }
 
 type userHandlerImpl struct {
 	svc       service.UserService
+	followSvc service.FollowService
 	validator *validator.CustomValidator
 }
 
-func NewUserHandler(svc service.UserService, validator *validator.CustomValidator) UserHandler {
+func NewUserHandler(svc service.UserService, followSvc service.FollowService, validator *validator.CustomValidator) UserHandler {
 	return &userHandlerImpl{
 		svc:       svc,
+		followSvc: followSvc,
 		validator: validator,
 	}
 }
@@ -38,22 +47,59 @@ func NewUserHandler(svc service.UserService, validator *validator.CustomValidato
 // ShowUsers godoc
 //
 //	@Summary		Show users list
-//	@Description	will fetch 3rd party server to get users data
+//	@Description	Page through the user directory, q narrows it to usernames or display names starting with q
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	[]model.User
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param			q	query		string	false	"Username or display name prefix"
+//	@Param			sort	query		string	false	"username (default) or newest"
+//	@Param			cursor	query		string	false	"Cursor from the previous page"
+//	@Param			limit	query		int	false	"Page size"
+//	@Success		200	{object}	[]dto.User
 //	@Failure		400	{object}	pkg.ErrorResponse
 //	@Failure		404	{object}	pkg.ErrorResponse
 //	@Failure		500	{object}	pkg.ErrorResponse
 //	@Router			/users [get]
 func (u *userHandlerImpl) GetUsers(ctx *gin.Context) {
-	users, err := u.svc.GetUsers(ctx)
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	viewerID := int(userID)
+	if viewerID == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	directory := dto.UserDirectory{}
+	if err := ctx.ShouldBindQuery(&directory); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(directory); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	users, next, err := u.svc.GetUsers(ctx, uint64(viewerID), directory)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, users)
+
+	admin := isAdmin(claims.(jwt.MapClaims))
+	data := []dto.User{}
+	for _, item := range users {
+		data = append(data, userProjection(item, uint64(viewerID), admin))
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data, Meta: dto.CursorInfo{NextCursor: next}})
 }
 
 // ShowUsersById godoc
@@ -64,24 +110,179 @@ func (u *userHandlerImpl) GetUsers(ctx *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		int	true	"User ID"
-//	@Success		200	{object}	model.User
+//	@Success		200	{object}	dto.User
 //	@Failure		400	{object}	pkg.ErrorResponse
 //	@Failure		404	{object}	pkg.ErrorResponse
 //	@Failure		500	{object}	pkg.ErrorResponse
 //	@Router			/users/{id} [get]
 func (u *userHandlerImpl) GetUsersById(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	viewerID := int(userID)
+	if viewerID == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
 	// get id user
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if id == 0 || err != nil {
 		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
 		return
 	}
-	user, err := u.svc.GetUsersById(ctx, uint64(id))
+	user, err := u.svc.GetProfile(ctx, uint64(viewerID), uint64(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	followers, following, err := u.followSvc.CountFollows(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := userProjection(user, uint64(viewerID), isAdmin(claims.(jwt.MapClaims)))
+	data.Followers = &followers
+	data.Following = &following
+	ctx.JSON(http.StatusOK, data)
+}
+
+// ShowUserByUsername godoc
+//
+//	@Summary		Show user by username
+//	@Description	Look a user up by username, case and look-alike characters are ignored. An old username redirects to the current one.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Success		200	{object}	dto.User
+//	@Success		301	{object}	dto.User
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		404	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/users/by-username/{username} [get]
+func (u *userHandlerImpl) GetUserByUsername(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	viewerID := int(userID)
+	if viewerID == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	username := ctx.Param("username")
+	user, redirected, err := u.svc.GetProfileByUsername(ctx, uint64(viewerID), username)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if redirected {
+		location := strings.TrimSuffix(ctx.Request.URL.Path, username) + url.PathEscape(user.Username)
+		ctx.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	followers, following, err := u.followSvc.CountFollows(ctx, user.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, user)
+
+	data := userProjection(user, uint64(viewerID), isAdmin(claims.(jwt.MapClaims)))
+	data.Followers = &followers
+	data.Following = &following
+	ctx.JSON(http.StatusOK, data)
+}
+
+// CheckUsername godoc
+//
+//	@Summary		Check username availability
+//	@Description	Tell whether a username can be registered, and why not
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Success		200	{object}	dto.UsernameAvailability
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/users/availability [get]
+func (u *userHandlerImpl) CheckUsername(ctx *gin.Context) {
+	username := ctx.Query("username")
+	if username == "" {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	res, err := u.svc.CheckUsername(ctx, username)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: res})
+}
+
+//	 ChangeUsername godoc
+//
+//		@Summary		Change username
+//		@Description	Change the caller's username, allowed once every 30 days. The old username redirects to the new one.
+//		@Tags			users
+//		@Accept			json
+//		@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param username body dto.UsernameChange true "New Username"
+//	@Success		200	{object}	dto.User
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		409	{object}	pkg.ErrorResponse
+//	@Failure		429	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/users/username [put]
+func (u *userHandlerImpl) ChangeUsername(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.UsernameChange{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if err := u.validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	user, err := u.svc.ChangeUsername(ctx, uint64(id), req.Username)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your username has been changed", Data: userProjection(user, uint64(id), false)})
 }
 
 //	 RegisterUser godoc
@@ -117,7 +318,7 @@ func (u *userHandlerImpl) UserSignUp(ctx *gin.Context) {
 
 	user, err := u.svc.SignUp(ctx, userSignUp)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
@@ -164,7 +365,7 @@ func (u *userHandlerImpl) UserLogin(ctx *gin.Context) {
 
 	user, err := u.svc.Login(ctx, userLogin)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
@@ -179,6 +380,49 @@ func (u *userHandlerImpl) UserLogin(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
 }
 
+//	 ResetPassword godoc
+//
+//		@Summary		Reset password
+//		@Description	Change the password with the current one, or with the one-time token after an admin forced a reset. Every existing session is signed out.
+//		@Tags			users
+//		@Accept			json
+//		@Produce		json
+//		@Param reset body dto.PasswordReset true "Password Reset"
+//		@Success		200	{object}	pkg.SuccessResponse
+//		@Failure		400	{object}	pkg.ErrorResponse
+//		@Failure		403	{object}	pkg.ErrorResponse
+//		@Failure		500	{object}	pkg.ErrorResponse
+//		@Router			/users/password [post]
+func (u *userHandlerImpl) ResetPassword(ctx *gin.Context) {
+	reset := dto.PasswordReset{}
+	if err := ctx.Bind(&reset); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.validator.ValidateStruct(reset); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	user, err := u.svc.ResetPassword(ctx, reset)
+	if err != nil {
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	token, err := u.svc.GenerateUserAccessToken(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	data := map[string]any{
+		"token": token,
+	}
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your password has been changed", Data: data})
+}
+
 //	 UpdateUser godoc
 //
 //		@Summary		Update user
@@ -227,20 +471,72 @@ func (u *userHandlerImpl) EditUser(ctx *gin.Context) {
 
 	user, err = u.svc.EditUser(ctx, req, uint64(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		ctx.JSON(errorStatus(err), pkg.ErrorResponse{Message: err.Error()})
 		return
 	}
 
 	data := dto.User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Age:       &user.Age,
-		UpdatedAt: &user.UpdatedAt,
+		ID:          user.ID,
+		Email:       user.Email,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Age:         &user.Age,
+		UpdatedAt:   &user.UpdatedAt,
 	}
 	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Data: data})
 }
 
+//	 UpdatePrivacy godoc
+//
+//		@Summary		Update account privacy
+//		@Description	Make the caller's account private or public, private accounts approve their followers and going public accepts every pending request
+//		@Tags			users
+//		@Accept			json
+//		@Produce		json
+//
+// @Security ApiKeyAuth
+// @param Authorization header string true "Authorization"
+//
+//	@Param privacy body dto.UserPrivacy true "Account Privacy"
+//	@Success		200	{object}	pkg.SuccessResponse
+//	@Failure		400	{object}	pkg.ErrorResponse
+//	@Failure		500	{object}	pkg.ErrorResponse
+//	@Router			/users/privacy [put]
+func (u *userHandlerImpl) UpdatePrivacy(ctx *gin.Context) {
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		fmt.Println("Failed to Retrieve Claims")
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	id := int(userID)
+	if id == 0 {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	req := dto.UserPrivacy{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := u.svc.UpdatePrivacy(ctx, uint64(id), *req.IsPrivate); err != nil {
+		ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+		return
+	}
+	// a public account has no use for requests, whoever asked now follows it
+	if !*req.IsPrivate {
+		if err := u.followSvc.AcceptAllFollowRequests(ctx, uint64(id)); err != nil {
+			ctx.JSON(http.StatusInternalServerError, pkg.ErrorResponse{Message: err.Error()})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your privacy settings have been updated"})
+}
+
 //	 DeleteUser godoc
 //
 //		@Summary		Delete user
@@ -289,3 +585,25 @@ func (u *userHandlerImpl) DeleteUser(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, pkg.SuccessResponse{Message: "Your account has been successfully deleted"})
 }
+
+// userProjection is the public view of a user. Email and age are only shown
+// to the user themselves and to admins.
+func userProjection(user model.User, viewerID uint64, admin bool) dto.User {
+	data := dto.User{
+		ID:          user.ID,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		IsPrivate:   user.IsPrivate,
+		CreatedAt:   &user.CreatedAt,
+	}
+	if user.ID == viewerID || admin {
+		data.Email = user.Email
+		data.Age = &user.Age
+	}
+	return data
+}
+
+func isAdmin(claims jwt.MapClaims) bool {
+	role, _ := claims["role"].(string)
+	return role == model.RoleAdmin
+}
@@ -0,0 +1,154 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExif = errors.New("invalid exif data")
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// Metadata is what is read from an image's EXIF block. Only Orientation is
+// acted on, the rest is kept on the photo when its owner opts in.
+type Metadata struct {
+	Orientation int
+	CameraMake  string
+	CameraModel string
+	TakenAt     *time.Time
+	// HasGPS reports that the file carried location data, it is always
+	// stripped and never read.
+	HasGPS bool
+}
+
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagOrientation        = 0x0112
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+
+	typeASCII = 2
+	typeShort = 3
+	typeLong  = 4
+)
+
+// parseExif reads a TIFF structured EXIF block, the part after "Exif\0\0".
+func parseExif(tiff []byte) (Metadata, error) {
+	meta := Metadata{Orientation: 1}
+	if len(tiff) < 8 {
+		return meta, ErrInvalidExif
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return meta, ErrInvalidExif
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return meta, ErrInvalidExif
+	}
+
+	ifd0, err := readIFD(tiff, order, order.Uint32(tiff[4:]))
+	if err != nil {
+		return meta, err
+	}
+	if e, ok := ifd0[tagOrientation]; ok {
+		if o := int(e.uint(tiff, order)); o >= 1 && o <= 8 {
+			meta.Orientation = o
+		}
+	}
+	meta.CameraMake = ifd0[tagMake].string(tiff, order)
+	meta.CameraModel = ifd0[tagModel].string(tiff, order)
+	_, meta.HasGPS = ifd0[tagGPSIFD]
+
+	if e, ok := ifd0[tagExifIFD]; ok {
+		sub, err := readIFD(tiff, order, e.uint(tiff, order))
+		if err != nil {
+			return meta, err
+		}
+		meta.TakenAt = parseExifTime(sub[tagDateTimeOriginal].string(tiff, order), sub[tagOffsetTimeOriginal].string(tiff, order))
+	}
+	return meta, nil
+}
+
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	// value holds the raw 4 byte value or offset field
+	value []byte
+}
+
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) (map[uint16]ifdEntry, error) {
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return nil, ErrInvalidExif
+	}
+	n := int(order.Uint16(tiff[offset:]))
+	start := int(offset) + 2
+	if start+n*12 > len(tiff) {
+		return nil, ErrInvalidExif
+	}
+
+	entries := make(map[uint16]ifdEntry, n)
+	for i := 0; i < n; i++ {
+		b := tiff[start+i*12:]
+		entries[order.Uint16(b)] = ifdEntry{typ: order.Uint16(b[2:]), count: order.Uint32(b[4:]), value: b[8:12]}
+	}
+	return entries, nil
+}
+
+func (e ifdEntry) uint(tiff []byte, order binary.ByteOrder) uint32 {
+	switch e.typ {
+	case typeShort:
+		return uint32(order.Uint16(e.value))
+	case typeLong:
+		return order.Uint32(e.value)
+	}
+	return 0
+}
+
+func (e ifdEntry) string(tiff []byte, order binary.ByteOrder) string {
+	if e.typ != typeASCII || e.count == 0 {
+		return ""
+	}
+	data := e.value
+	if e.count > 4 {
+		offset := uint64(order.Uint32(e.value))
+		if offset+uint64(e.count) > uint64(len(tiff)) {
+			return ""
+		}
+		data = tiff[offset : offset+uint64(e.count)]
+	} else {
+		data = data[:e.count]
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		data = data[:i]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseExifTime reads "2006:01:02 15:04:05", in the recorded offset when
+// there is one and UTC otherwise since EXIF has no zone by default.
+func parseExifTime(value string, offset string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	layout, value := "2006:01:02 15:04:05", strings.TrimSpace(value)
+	if offset != "" {
+		layout, value = layout+"-07:00", value+offset
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
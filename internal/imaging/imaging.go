@@ -0,0 +1,154 @@
+// Package imaging turns an uploaded image into the renditions clients are
+// served: a square thumbnail and bounded medium and large sizes, each in
+// JPEG and PNG.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+
+	jpegQuality = 85
+)
+
+// MaxPixels is the largest width x height Decode accepts. Headers are
+// cheap to forge, a file of a few kilobytes can claim dimensions that take
+// gigabytes to decode.
+const MaxPixels = 40_000_000
+
+var (
+	ErrDecode        = errors.New("image could not be decoded")
+	ErrTooManyPixels = errors.New("image is larger than 40 megapixels")
+)
+
+// Size describes one rendition. Square sizes are center-cropped to exactly
+// Max x Max, the others are scaled to fit within Max on their longest side.
+// Images are never scaled up.
+type Size struct {
+	Name   string
+	Max    int
+	Square bool
+}
+
+var Sizes = []Size{
+	{Name: "thumb", Max: 150, Square: true},
+	{Name: "medium", Max: 640},
+	{Name: "large", Max: 1280},
+}
+
+var Formats = []string{FormatJPEG, FormatPNG}
+
+type Rendition struct {
+	Name        string
+	Format      string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+type Result struct {
+	Width      int
+	Height     int
+	Renditions []Rendition
+}
+
+// Decode decodes data once the dimensions its header declares are known to
+// be within MaxPixels, every image read from an upload or from storage goes
+// through it.
+func Decode(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Join(ErrDecode, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Join(ErrDecode, err)
+	}
+	return img, nil
+}
+
+// Process decodes data and renders every size in every format.
+func Process(data []byte) (Result, error) {
+	src, err := Decode(data)
+	if err != nil {
+		return Result{}, err
+	}
+	return Render(src)
+}
+
+// Render is Process for an already decoded image.
+func Render(src image.Image) (Result, error) {
+	bounds := src.Bounds()
+	res := Result{Width: bounds.Dx(), Height: bounds.Dy()}
+	for _, size := range Sizes {
+		img := resize(src, size)
+		for _, format := range Formats {
+			data, err := encode(img, format)
+			if err != nil {
+				return Result{}, err
+			}
+			res.Renditions = append(res.Renditions, Rendition{
+				Name:        size.Name,
+				Format:      format,
+				ContentType: "image/" + format,
+				Width:       img.Bounds().Dx(),
+				Height:      img.Bounds().Dy(),
+				Data:        data,
+			})
+		}
+	}
+	return res, nil
+}
+
+func resize(src image.Image, size Size) image.Image {
+	bounds := src.Bounds()
+	if size.Square {
+		side := min(bounds.Dx(), bounds.Dy())
+		x := bounds.Min.X + (bounds.Dx()-side)/2
+		y := bounds.Min.Y + (bounds.Dy()-side)/2
+		bounds = image.Rect(x, y, x+side, y+side)
+	}
+
+	w, h := Fit(bounds.Dx(), bounds.Dy(), size.Max, size.Max)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+// Fit scales w x h down to fit within maxW x maxH keeping the aspect ratio,
+// a zero bound is unconstrained.
+func Fit(w, h, maxW, maxH int) (int, int) {
+	scale := 1.0
+	if maxW > 0 && w > maxW {
+		scale = float64(maxW) / float64(w)
+	}
+	if maxH > 0 && float64(h)*scale > float64(maxH) {
+		scale = float64(maxH) / float64(h)
+	}
+	return max(1, int(float64(w)*scale+0.5)), max(1, int(float64(h)*scale+0.5))
+}
+
+func encode(img image.Image, format string) ([]byte, error) {
+	return Encode(img, Options{Format: format, Quality: jpegQuality})
+}
+
+func flatten(img image.Image) image.Image {
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
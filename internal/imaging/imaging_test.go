@@ -0,0 +1,85 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFit(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		w, h       int
+		maxW, maxH int
+		outW, outH int
+	}{
+		{desc: "landscape", w: 4000, h: 3000, maxW: 1280, maxH: 1280, outW: 1280, outH: 960},
+		{desc: "portrait", w: 3000, h: 4000, maxW: 1280, maxH: 1280, outW: 960, outH: 1280},
+		{desc: "smaller is kept", w: 300, h: 200, maxW: 640, maxH: 640, outW: 300, outH: 200},
+		{desc: "width only", w: 1000, h: 500, maxW: 100, maxH: 0, outW: 100, outH: 50},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			w, h := Fit(tC.w, tC.h, tC.maxW, tC.maxH)
+			assert.Equal(t, tC.outW, w)
+			assert.Equal(t, tC.outH, h)
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	for x := 0; x < 800; x++ {
+		for y := 0; y < 400; y++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	buf := bytes.Buffer{}
+	assert.Nil(t, png.Encode(&buf, src))
+
+	res, err := Process(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, 800, res.Width)
+	assert.Equal(t, 400, res.Height)
+	assert.Equal(t, len(Sizes)*len(Formats), len(res.Renditions))
+
+	dims := map[string][2]int{}
+	for _, r := range res.Renditions {
+		img, format, err := image.Decode(bytes.NewReader(r.Data))
+		assert.Nil(t, err)
+		assert.Equal(t, r.Format, format)
+		assert.Equal(t, r.Width, img.Bounds().Dx())
+		dims[r.Name] = [2]int{r.Width, r.Height}
+	}
+	assert.Equal(t, [2]int{150, 150}, dims["thumb"])
+	assert.Equal(t, [2]int{640, 320}, dims["medium"])
+	assert.Equal(t, [2]int{800, 400}, dims["large"])
+
+	_, err = Process([]byte("not an image"))
+	assert.ErrorIs(t, err, ErrDecode)
+}
+
+func TestDecode(t *testing.T) {
+	buf := bytes.Buffer{}
+	assert.Nil(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2))))
+	img, err := Decode(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, img.Bounds().Dx())
+
+	// a tiny file whose header claims 100000 x 100000 pixels
+	bomb := append([]byte{}, buf.Bytes()...)
+	binary.BigEndian.PutUint32(bomb[16:], 100000)
+	binary.BigEndian.PutUint32(bomb[20:], 100000)
+	binary.BigEndian.PutUint32(bomb[29:], crc32.ChecksumIEEE(bomb[12:29]))
+	_, err = Decode(bomb)
+	assert.Equal(t, ErrTooManyPixels, err)
+
+	_, err = Process(bomb)
+	assert.Equal(t, ErrTooManyPixels, err)
+}
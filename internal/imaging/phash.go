@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+// HashBands is how many 16 bit bands a perceptual hash is split into for
+// indexing. Two hashes d bits apart have at least one band that differs in
+// no more than d/HashBands bits, so probing every band for values that
+// close finds all of them.
+const HashBands = 4
+
+// MaxProbeDistance bounds BandProbes, each extra bit of band radius
+// multiplies the number of values probed.
+const MaxProbeDistance = 3*HashBands - 1
+
+// Hashes are the perceptual hashes of an image, visually similar images
+// have hashes a small Hamming distance apart.
+type Hashes struct {
+	PHash uint64
+	DHash uint64
+}
+
+func Hash(img image.Image) Hashes {
+	return Hashes{PHash: PHash(img), DHash: DHash(img)}
+}
+
+// PHash is the DCT hash: the lowest 8x8 frequencies of a 32x32 grayscale
+// copy, one bit per coefficient above their median. It survives resizing,
+// recompression and small color changes.
+func PHash(img image.Image) uint64 {
+	const size = 32
+	px := grayscale(img, size, size)
+
+	// separable 2D DCT-II, only the 8x8 low frequencies are needed
+	rows := make([][8]float64, size)
+	for y := 0; y < size; y++ {
+		for u := 0; u < 8; u++ {
+			sum := 0.0
+			for x := 0; x < size; x++ {
+				sum += px[y*size+x] * math.Cos(float64((2*x+1)*u)*math.Pi/(2*size))
+			}
+			rows[y][u] = sum
+		}
+	}
+	coeffs := make([]float64, 0, 64)
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			sum := 0.0
+			for y := 0; y < size; y++ {
+				sum += rows[y][u] * math.Cos(float64((2*y+1)*v)*math.Pi/(2*size))
+			}
+			coeffs = append(coeffs, sum)
+		}
+	}
+
+	// the DC term is the average brightness and would skew the median
+	sorted := append([]float64{}, coeffs[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(63-i)
+		}
+	}
+	return hash
+}
+
+// DHash is the gradient hash: one bit per horizontally adjacent pixel pair
+// of a 9x8 grayscale copy, set when brightness increases.
+func DHash(img image.Image) uint64 {
+	px := grayscale(img, 9, 8)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if px[y*9+x] < px[y*9+x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Distance is the number of bits two hashes differ in.
+func Distance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Bands splits a hash into HashBands 16 bit values, most significant first.
+func Bands(hash uint64) [HashBands]uint16 {
+	res := [HashBands]uint16{}
+	for i := range res {
+		res[i] = uint16(hash >> uint(16*(HashBands-1-i)))
+	}
+	return res
+}
+
+// BandProbes returns, for each band of hash, the band values a hash within
+// maxDistance bits must match at least one of.
+func BandProbes(hash uint64, maxDistance int) [HashBands][]uint16 {
+	radius := min(maxDistance, MaxProbeDistance) / HashBands
+	res := [HashBands][]uint16{}
+	for i, band := range Bands(hash) {
+		res[i] = flips(band, 0, radius)
+	}
+	return res
+}
+
+// flips returns v and every value that differs from it in up to radius of
+// the bits from bit on.
+func flips(v uint16, bit int, radius int) []uint16 {
+	res := []uint16{v}
+	if radius == 0 {
+		return res
+	}
+	for i := bit; i < 16; i++ {
+		res = append(res, flips(v^1<<uint(i), i+1, radius-1)...)
+	}
+	return res
+}
+
+func grayscale(img image.Image, w int, h int) []float64 {
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(dst, dst.Bounds(), flatten(img), img.Bounds(), draw.Src, nil)
+	px := make([]float64, w*h)
+	for i, v := range dst.Pix {
+		px[i] = float64(v)
+	}
+	return px
+}
@@ -0,0 +1,74 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pattern is a smooth, photo-like image, flip mirrors it horizontally.
+func pattern(w int, h int, flip bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			fx := float64(x) / float64(w)
+			if flip {
+				fx = 1 - fx
+			}
+			fy := float64(y) / float64(h)
+			v := 128 + 100*math.Sin(fx*7)*math.Cos(fy*5+fx*2)
+			img.Set(x, y, color.RGBA{R: uint8(v), G: uint8(255 * fy), B: 64, A: 255})
+		}
+	}
+	return img
+}
+
+func TestHash(t *testing.T) {
+	src := pattern(640, 480, false)
+	hashes := Hash(src)
+
+	t.Run("resized and recompressed copies are near", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		assert.Nil(t, jpeg.Encode(&buf, Transform(src, Options{Width: 200, Fit: FitContain}), &jpeg.Options{Quality: 40}))
+		copied, _, err := image.Decode(&buf)
+		assert.Nil(t, err)
+
+		other := Hash(copied)
+		assert.LessOrEqual(t, Distance(hashes.PHash, other.PHash), 7)
+		assert.LessOrEqual(t, Distance(hashes.DHash, other.DHash), 10)
+	})
+
+	t.Run("different images are far", func(t *testing.T) {
+		other := Hash(pattern(640, 480, true))
+		assert.Greater(t, Distance(hashes.PHash, other.PHash), 10)
+		assert.Greater(t, Distance(hashes.DHash, other.DHash), 10)
+	})
+}
+
+func TestBands(t *testing.T) {
+	assert.Equal(t, [HashBands]uint16{0x0123, 0x4567, 0x89ab, 0xcdef}, Bands(0x0123456789abcdef))
+	assert.Equal(t, 2, Distance(0b1010, 0b0110))
+}
+
+func TestBandProbes(t *testing.T) {
+	hash := uint64(0x0123456789abcdef)
+	probes := BandProbes(hash, 7)
+	for _, values := range probes {
+		assert.Len(t, values, 17)
+	}
+
+	// any hash within 7 bits matches a probe of at least one band
+	near := hash ^ 0b11 ^ 0b11<<16 ^ 0b11<<32 ^ 0b1<<48
+	matched := false
+	for i, band := range Bands(near) {
+		for _, v := range probes[i] {
+			matched = matched || v == band
+		}
+	}
+	assert.True(t, matched)
+}
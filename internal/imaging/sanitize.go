@@ -0,0 +1,257 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// originalJPEGQuality is used when an original has to be re-encoded to
+// apply its orientation, higher than the variants since it is the master.
+const originalJPEGQuality = 92
+
+var ErrMalformed = errors.New("malformed image file")
+
+// Sanitize removes EXIF, XMP, IPTC and text metadata from an uploaded
+// image so location, serial numbers and the like are never stored. JPEG and
+// PNG files with a non-default EXIF orientation are re-encoded upright,
+// because dropping the tag would otherwise display them sideways.
+//
+// The returned Metadata is what the file carried before it was stripped.
+func Sanitize(data []byte, contentType string) ([]byte, Metadata, error) {
+	meta := Metadata{Orientation: 1}
+	var (
+		clean []byte
+		err   error
+	)
+	switch contentType {
+	case "image/jpeg":
+		clean, meta, err = stripJPEG(data)
+	case "image/png":
+		clean, meta, err = stripPNG(data)
+	case "image/webp":
+		clean, meta, err = stripWebP(data)
+	default:
+		return data, meta, nil
+	}
+	if err != nil {
+		return nil, meta, err
+	}
+	if meta.Orientation == 1 || contentType == "image/webp" {
+		return clean, meta, nil
+	}
+
+	img, err := Decode(clean)
+	if err != nil {
+		return nil, meta, err
+	}
+	img = Orient(img, meta.Orientation)
+
+	buf := bytes.Buffer{}
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: originalJPEGQuality})
+	}
+	if err != nil {
+		return nil, meta, err
+	}
+	return buf.Bytes(), meta, nil
+}
+
+// stripJPEG drops APP1 (EXIF, XMP), APP13 (IPTC) and comment segments. The
+// JFIF, ICC profile and Adobe segments are kept since decoding needs them.
+func stripJPEG(data []byte) ([]byte, Metadata, error) {
+	meta := Metadata{Orientation: 1}
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, meta, ErrMalformed
+	}
+
+	out := bytes.Buffer{}
+	out.Grow(len(data))
+	out.Write(data[:2])
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, meta, ErrMalformed
+		}
+		// markers may be padded with any number of 0xFF
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, meta, ErrMalformed
+		}
+		marker := data[i]
+		i++
+
+		// start of scan, the entropy coded data runs to the end
+		if marker == 0xDA || marker == 0xD9 {
+			out.Write([]byte{0xFF, marker})
+			out.Write(data[i:])
+			return out.Bytes(), meta, nil
+		}
+		// markers without a payload
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write([]byte{0xFF, marker})
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, meta, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, meta, ErrMalformed
+		}
+		payload := data[i+2 : i+length]
+		segment := data[i-2 : i+length]
+		i += length
+
+		switch marker {
+		case 0xE1:
+			if bytes.HasPrefix(payload, exifHeader) {
+				if m, err := parseExif(payload[len(exifHeader):]); err == nil {
+					meta = m
+				}
+			}
+		case 0xED, 0xFE:
+		default:
+			out.Write(segment)
+		}
+	}
+	return nil, meta, ErrMalformed
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// stripPNG drops the eXIf chunk and the free-form text and time chunks.
+func stripPNG(data []byte) ([]byte, Metadata, error) {
+	meta := Metadata{Orientation: 1}
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, meta, ErrMalformed
+	}
+
+	out := bytes.Buffer{}
+	out.Grow(len(data))
+	out.Write(pngSignature)
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, meta, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		typ := string(data[i+4 : i+8])
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, meta, ErrMalformed
+		}
+
+		switch typ {
+		case "eXIf":
+			if m, err := parseExif(data[i+8 : i+8+length]); err == nil {
+				meta = m
+			}
+		case "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+		if typ == "IEND" {
+			break
+		}
+	}
+	return out.Bytes(), meta, nil
+}
+
+// stripWebP drops the EXIF and XMP chunks and clears their VP8X flags.
+func stripWebP(data []byte) ([]byte, Metadata, error) {
+	meta := Metadata{Orientation: 1}
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, meta, ErrMalformed
+	}
+
+	out := bytes.Buffer{}
+	out.Grow(len(data))
+	out.Write(data[:12])
+	i := 12
+	for i+8 <= len(data) {
+		fourcc := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2
+		if size < 0 || i+8+size > len(data) {
+			return nil, meta, ErrMalformed
+		}
+		if end > len(data) {
+			end = len(data)
+		}
+
+		switch fourcc {
+		case "EXIF":
+			payload := bytes.TrimPrefix(data[i+8:i+8+size], exifHeader)
+			if m, err := parseExif(payload); err == nil {
+				meta = m
+			}
+		case "XMP ":
+		case "VP8X":
+			chunk := append([]byte{}, data[i:end]...)
+			if size > 0 {
+				chunk[8] &^= 0x08 | 0x04
+			}
+			out.Write(chunk)
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+
+	clean := out.Bytes()
+	binary.LittleEndian.PutUint32(clean[4:], uint32(len(clean)-8))
+	return clean, meta, nil
+}
+
+// Orient transforms img so it displays upright for the given EXIF
+// orientation, 1 returns it unchanged.
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return dst
+}
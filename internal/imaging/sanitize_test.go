@@ -0,0 +1,154 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testExif builds a big-endian EXIF block with a camera, orientation, a
+// capture time and an (empty) GPS directory.
+func testExif(orientation uint16) []byte {
+	o := binary.BigEndian
+	b := make([]byte, 152)
+	copy(b, "MM")
+	o.PutUint16(b[2:], 42)
+	o.PutUint32(b[4:], 8)
+	entry := func(at int, tag uint16, typ uint16, count uint32, value uint32) {
+		o.PutUint16(b[at:], tag)
+		o.PutUint16(b[at+2:], typ)
+		o.PutUint32(b[at+4:], count)
+		o.PutUint32(b[at+8:], value)
+	}
+
+	o.PutUint16(b[8:], 5)
+	entry(10, tagMake, typeASCII, 6, 74)
+	entry(22, tagModel, typeASCII, 7, 80)
+	entry(34, tagOrientation, typeShort, 1, uint32(orientation)<<16)
+	entry(46, tagExifIFD, typeLong, 1, 88)
+	entry(58, tagGPSIFD, typeLong, 1, 146)
+	copy(b[74:], "Canon\x00")
+	copy(b[80:], "EOS 5D\x00")
+
+	o.PutUint16(b[88:], 2)
+	entry(90, tagDateTimeOriginal, typeASCII, 20, 118)
+	entry(102, tagOffsetTimeOriginal, typeASCII, 7, 138)
+	copy(b[118:], "2024:05:01 10:20:30\x00")
+	copy(b[138:], "+07:00\x00")
+	return b
+}
+
+// testImage is red on the left half and blue on the right.
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 20; y++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 20 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func withExifSegment(jpg []byte, tiff []byte) []byte {
+	payload := append(append([]byte{}, exifHeader...), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, segment...)
+	out = append(out, payload...)
+	return append(out, jpg[2:]...)
+}
+
+func pngChunk(typ string, data []byte) []byte {
+	b := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(b, uint32(len(data)))
+	copy(b[4:], typ)
+	b = append(b, data...)
+	return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b[4:]))
+}
+
+func TestSanitizeJPEG(t *testing.T) {
+	plain := bytes.Buffer{}
+	assert.Nil(t, jpeg.Encode(&plain, testImage(), nil))
+
+	t.Run("upright file is stripped losslessly", func(t *testing.T) {
+		clean, meta, err := Sanitize(withExifSegment(plain.Bytes(), testExif(1)), "image/jpeg")
+		assert.Nil(t, err)
+		assert.Equal(t, plain.Bytes(), clean)
+		assert.True(t, meta.HasGPS)
+		assert.Equal(t, "Canon", meta.CameraMake)
+		assert.Equal(t, "EOS 5D", meta.CameraModel)
+		assert.Equal(t, time.Date(2024, 5, 1, 3, 20, 30, 0, time.UTC), meta.TakenAt.UTC())
+	})
+
+	t.Run("rotated file is re-encoded upright", func(t *testing.T) {
+		clean, meta, err := Sanitize(withExifSegment(plain.Bytes(), testExif(6)), "image/jpeg")
+		assert.Nil(t, err)
+		assert.Equal(t, 6, meta.Orientation)
+		assert.False(t, bytes.Contains(clean, exifHeader))
+
+		img, err := jpeg.Decode(bytes.NewReader(clean))
+		assert.Nil(t, err)
+		assert.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+		// rotated clockwise, the red left half is now on top
+		r, _, b, _ := img.At(10, 5).RGBA()
+		assert.Greater(t, r, b)
+		r, _, b, _ = img.At(10, 35).RGBA()
+		assert.Greater(t, b, r)
+	})
+}
+
+func TestSanitizePNG(t *testing.T) {
+	plain := bytes.Buffer{}
+	assert.Nil(t, png.Encode(&plain, testImage()))
+	data := plain.Bytes()
+	// insert metadata chunks right after IHDR (8 byte signature + 25 byte chunk)
+	withMeta := append([]byte{}, data[:33]...)
+	withMeta = append(withMeta, pngChunk("eXIf", testExif(1))...)
+	withMeta = append(withMeta, pngChunk("tEXt", []byte("Comment\x00secret"))...)
+	withMeta = append(withMeta, data[33:]...)
+
+	clean, meta, err := Sanitize(withMeta, "image/png")
+	assert.Nil(t, err)
+	assert.Equal(t, data, clean)
+	assert.Equal(t, "EOS 5D", meta.CameraModel)
+}
+
+func TestSanitizeWebP(t *testing.T) {
+	chunk := func(fourcc string, data []byte) []byte {
+		b := append([]byte(fourcc), 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(b[4:], uint32(len(data)))
+		b = append(b, data...)
+		if len(data)%2 == 1 {
+			b = append(b, 0)
+		}
+		return b
+	}
+	body := []byte("WEBP")
+	body = append(body, chunk("VP8X", []byte{0x08 | 0x04 | 0x10, 0, 0, 0, 0, 0, 0, 0, 0, 0})...)
+	body = append(body, chunk("VP8L", []byte{1, 2, 3})...)
+	body = append(body, chunk("EXIF", testExif(1))...)
+	body = append(body, chunk("XMP ", []byte("<x/>"))...)
+	file := append([]byte("RIFF\x00\x00\x00\x00"), body...)
+	binary.LittleEndian.PutUint32(file[4:], uint32(len(file)-8))
+
+	clean, meta, err := Sanitize(file, "image/webp")
+	assert.Nil(t, err)
+	assert.True(t, meta.HasGPS)
+	assert.False(t, bytes.Contains(clean, []byte("EXIF")))
+	assert.False(t, bytes.Contains(clean, []byte("XMP ")))
+	assert.Equal(t, byte(0x10), clean[20])
+	assert.Equal(t, uint32(len(clean)-8), binary.LittleEndian.Uint32(clean[4:]))
+}
@@ -0,0 +1,113 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	FitContain = "contain"
+	FitCover   = "cover"
+	FitFill    = "fill"
+
+	// MaxDimension bounds requested sizes so a single request cannot make
+	// the server allocate huge canvases.
+	MaxDimension = 2048
+)
+
+var ErrInvalidOptions = errors.New("invalid transform options")
+
+// Options describe an on-the-fly rendition. A zero Width or Height leaves
+// that side to the aspect ratio.
+type Options struct {
+	Width   int
+	Height  int
+	Fit     string
+	Format  string
+	Quality int
+}
+
+// Normalize fills defaults and rejects out of range values, so equal
+// renditions always have equal options.
+func (o Options) Normalize() (Options, error) {
+	if o.Fit == "" {
+		o.Fit = FitContain
+	}
+	if o.Format == "" {
+		o.Format = FormatJPEG
+	}
+	if o.Quality == 0 {
+		o.Quality = jpegQuality
+	}
+	if o.Format == FormatPNG {
+		// png is lossless, quality would only fragment the cache
+		o.Quality = 0
+	}
+
+	switch {
+	case o.Width < 0 || o.Height < 0 || o.Width > MaxDimension || o.Height > MaxDimension,
+		o.Fit != FitContain && o.Fit != FitCover && o.Fit != FitFill,
+		o.Format != FormatJPEG && o.Format != FormatPNG,
+		o.Format == FormatJPEG && (o.Quality < 1 || o.Quality > 100):
+		return Options{}, ErrInvalidOptions
+	}
+	// cover and fill need both sides, with one they behave like contain
+	if o.Width == 0 || o.Height == 0 {
+		o.Fit = FitContain
+	}
+	return o, nil
+}
+
+// Transform resizes src according to opts, which must be normalized.
+// Images are never scaled up.
+func Transform(src image.Image, opts Options) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	switch opts.Fit {
+	case FitFill:
+		return scale(src, bounds, min(opts.Width, w), min(opts.Height, h))
+	case FitCover:
+		// crop the source to the target aspect ratio, then scale
+		cw, ch := w, w*opts.Height/opts.Width
+		if ch > h {
+			cw, ch = h*opts.Width/opts.Height, h
+		}
+		x := bounds.Min.X + (w-cw)/2
+		y := bounds.Min.Y + (h-ch)/2
+		crop := image.Rect(x, y, x+cw, y+ch)
+		dw, dh := Fit(cw, ch, opts.Width, opts.Height)
+		return scale(src, crop, dw, dh)
+	}
+	if opts.Width == 0 && opts.Height == 0 {
+		return src
+	}
+	dw, dh := Fit(w, h, opts.Width, opts.Height)
+	return scale(src, bounds, dw, dh)
+}
+
+func scale(src image.Image, from image.Rectangle, w int, h int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, max(1, w), max(1, h)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, from, draw.Src, nil)
+	return dst
+}
+
+// Encode writes img in the format and quality of opts.
+func Encode(img image.Image, opts Options) ([]byte, error) {
+	buf := bytes.Buffer{}
+	var err error
+	if opts.Format == FormatPNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: opts.Quality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
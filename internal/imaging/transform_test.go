@@ -0,0 +1,51 @@
+package imaging
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	opts, err := Options{Width: 100}.Normalize()
+	assert.Nil(t, err)
+	assert.Equal(t, Options{Width: 100, Fit: FitContain, Format: FormatJPEG, Quality: jpegQuality}, opts)
+
+	opts, err = Options{Width: 100, Height: 100, Fit: FitCover, Format: FormatPNG, Quality: 50}.Normalize()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, opts.Quality)
+
+	for _, invalid := range []Options{
+		{Width: -1},
+		{Width: MaxDimension + 1},
+		{Fit: "stretch"},
+		{Format: "gif"},
+		{Quality: 101},
+	} {
+		_, err := invalid.Normalize()
+		assert.ErrorIs(t, err, ErrInvalidOptions)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	testCases := []struct {
+		desc       string
+		opts       Options
+		outW, outH int
+	}{
+		{desc: "contain", opts: Options{Width: 200, Height: 200, Fit: FitContain}, outW: 200, outH: 100},
+		{desc: "cover", opts: Options{Width: 200, Height: 200, Fit: FitCover}, outW: 200, outH: 200},
+		{desc: "fill", opts: Options{Width: 200, Height: 300, Fit: FitFill}, outW: 200, outH: 300},
+		{desc: "height only", opts: Options{Height: 100, Fit: FitContain}, outW: 200, outH: 100},
+		{desc: "no upscaling", opts: Options{Width: 1600, Fit: FitContain}, outW: 800, outH: 400},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			bounds := Transform(src, tC.opts).Bounds()
+			assert.Equal(t, tC.outW, bounds.Dx())
+			assert.Equal(t, tC.outH, bounds.Dy())
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Code generated by mockery v2.42.1. DO NOT EDIT.
+// Code generated by mockery v2.53.3. DO NOT EDIT.
 
 package mocks
 
@@ -13,7 +13,7 @@ type GormPostgres struct {
 	mock.Mock
 }
 
-// GetConnection provides a mock function with given fields:
+// GetConnection provides a mock function with no fields
 func (_m *GormPostgres) GetConnection() *gorm.DB {
 	ret := _m.Called()
 
@@ -33,6 +33,24 @@ func (_m *GormPostgres) GetConnection() *gorm.DB {
 	return r0
 }
 
+// SearchLanguage provides a mock function with no fields
+func (_m *GormPostgres) SearchLanguage() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchLanguage")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // NewGormPostgres creates a new instance of GormPostgres. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewGormPostgres(t interface {
@@ -2,27 +2,59 @@ package infrastructure
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 type GormPostgres interface {
 	GetConnection() *gorm.DB
+	// SearchLanguage is the text search configuration the photo search
+	// index was built with, queries must use the same one to hit it.
+	SearchLanguage() string
 }
 
 type gormPostgresImpl struct {
-	master *gorm.DB
+	master         *gorm.DB
+	searchLanguage string
 }
 
+// DefaultSearchLanguage is used when SEARCH_LANGUAGE is not set.
+const DefaultSearchLanguage = "english"
+
+// searchLanguagePattern keeps SEARCH_LANGUAGE to a plain configuration name,
+// it ends up in index definitions and cannot be passed as a parameter.
+var searchLanguagePattern = regexp.MustCompile(`^[a-z_]+$`)
+
 func NewGormPostgres() GormPostgres {
+	language := os.Getenv("SEARCH_LANGUAGE")
+	if language == "" {
+		language = DefaultSearchLanguage
+	}
+	if !searchLanguagePattern.MatchString(language) {
+		panic(fmt.Sprintf("SEARCH_LANGUAGE: invalid text search configuration %q", language))
+	}
 	return &gormPostgresImpl{
-		master: connect(),
+		master:         connect(language),
+		searchLanguage: language,
 	}
 }
 
-func connect() *gorm.DB {
+// PhotoSearchDocument is the text photo search matches against in language.
+// The search index is built on exactly this expression, queries have to use
+// it verbatim for the planner to pick the index.
+func PhotoSearchDocument(language string) string {
+	return fmt.Sprintf("to_tsvector('%s'::regconfig, photos.title || ' ' || COALESCE(photos.caption, ''))", language)
+}
+
+func connect(searchLanguage string) *gorm.DB {
 	host := "127.0.0.1"
 	port := "5432"
 	user := "midnight"
@@ -35,10 +67,146 @@ func connect() *gorm.DB {
 		panic(err)
 	}
 
-	db.AutoMigrate(&model.User{}, &model.SocialMedia{}, &model.Comment{}, &model.Photo{})
+	db.AutoMigrate(&model.User{}, &model.SocialMedia{}, &model.Comment{}, &model.Photo{}, &model.PhotoVariant{}, &model.PhotoHashBand{}, &model.Follow{}, &model.Block{}, &model.Mute{}, &model.UsernameRedirect{}, &model.Blob{}, &model.TimelineEntry{}, &model.ExploreScore{}, &model.Like{}, &model.Tag{}, &model.PhotoTag{}, &model.Mention{}, &model.Notification{}, &model.Album{}, &model.AlbumPhoto{}, &model.AlbumCollaborator{}, &model.Save{}, &model.SaveCollection{}, &model.SaveCollectionPhoto{}, &model.CloseFriend{}, &model.MediaItem{}, &model.Story{}, &model.StoryView{}, &model.StoryHighlight{}, &model.StoryHighlightItem{}, &model.Revision{})
+	backfillIdentityKeys(db)
+	backfillBlobs(db)
+	backfillMediaItems(db)
+	backfillCommentCounts(db)
+	// feeds read an account's photos newest first
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_photos_user_id_id ON photos (user_id, id DESC)")
+	// nearby search scans a box of latitudes and filters longitudes from the
+	// index, plain btree so no PostGIS is needed
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_photos_location ON photos (latitude, longitude) WHERE latitude IS NOT NULL AND deleted_at IS NULL")
+	// explore pages through scores highest first
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_explore_scores_score_photo_id ON explore_scores (score DESC, photo_id DESC)")
+	// prefix search in the user directory filters on lower-cased names
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users (LOWER(username) text_pattern_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_display_name_lower ON users (LOWER(display_name) text_pattern_ops)")
+	// tag autocomplete matches name prefixes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags (name text_pattern_ops)")
+	// albums list their photos in the owner's order
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_album_photos_album_id_position ON album_photos (album_id, position)")
+	// the story tray reads the live stories of followed accounts
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_stories_user_id_expires_at ON stories (user_id, expires_at) WHERE deleted_at IS NULL")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_story_highlight_items_highlight_id_position ON story_highlight_items (highlight_id, position)")
+	migrateSearchIndex(db, searchLanguage)
 	return db
 }
 
+// migrateSearchIndex builds the full text index of photos for language and
+// drops the ones built for other languages, so changing SEARCH_LANGUAGE
+// takes effect on the next start.
+func migrateSearchIndex(db *gorm.DB, language string) {
+	name := "idx_photos_search_" + language
+	if err := db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON photos USING GIN ((%s))", name, PhotoSearchDocument(language))).Error; err != nil {
+		fmt.Println("migrate search index:", err)
+		return
+	}
+	stale := []string{}
+	if err := db.Raw(`SELECT indexname FROM pg_indexes WHERE tablename = 'photos' AND indexname LIKE 'idx\_photos\_search\_%' AND indexname <> ?`, name).
+		Scan(&stale).Error; err != nil {
+		fmt.Println("migrate search index:", err)
+		return
+	}
+	for _, index := range stale {
+		db.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s", index))
+	}
+}
+
+// backfillIdentityKeys fills the canonical username and email keys of users
+// created before the keys existed. Login looks users up by these keys, so
+// when two rows normalize to the same key the migration stops and lists
+// them instead of leaving one of the users without a key to log in with.
+func backfillIdentityKeys(db *gorm.DB) {
+	pending := int64(0)
+	if err := db.Unscoped().Model(&model.User{}).Where("username_key IS NULL OR email_key IS NULL").Count(&pending).Error; err != nil {
+		panic(fmt.Sprintf("backfill identity keys: %v", err))
+	}
+	if pending == 0 {
+		return
+	}
+
+	users := []model.User{}
+	if err := db.Unscoped().Select("id", "username", "email").Order("id").Find(&users).Error; err != nil {
+		panic(fmt.Sprintf("backfill identity keys: %v", err))
+	}
+	if collisions := identityKeyCollisions(users); len(collisions) > 0 {
+		panic("backfill identity keys: rename these users before starting again:\n" + strings.Join(collisions, "\n"))
+	}
+
+	for _, user := range users {
+		if err := db.Unscoped().Model(&model.User{}).Where("id = ? AND (username_key IS NULL OR email_key IS NULL)", user.ID).Updates(map[string]any{
+			"username_key": helper.UsernameKey(user.Username),
+			"email_key":    helper.EmailKey(user.Email),
+		}).Error; err != nil {
+			panic(fmt.Sprintf("backfill identity keys of user %d: %v", user.ID, err))
+		}
+	}
+}
+
+// identityKeyCollisions lists the username and email keys more than one of
+// users normalizes to, with the ids of those users.
+func identityKeyCollisions(users []model.User) []string {
+	usernames := map[string][]string{}
+	emails := map[string][]string{}
+	for _, user := range users {
+		id := strconv.FormatUint(user.ID, 10)
+		key := helper.UsernameKey(user.Username)
+		usernames[key] = append(usernames[key], id)
+		key = helper.EmailKey(user.Email)
+		emails[key] = append(emails[key], id)
+	}
+
+	collisions := []string{}
+	for key, ids := range usernames {
+		if len(ids) > 1 {
+			collisions = append(collisions, fmt.Sprintf("username %q: users %s", key, strings.Join(ids, ", ")))
+		}
+	}
+	for key, ids := range emails {
+		if len(ids) > 1 {
+			collisions = append(collisions, fmt.Sprintf("email %q: users %s", key, strings.Join(ids, ", ")))
+		}
+	}
+	sort.Strings(collisions)
+	return collisions
+}
+
+// backfillBlobs registers objects uploaded before blobs were tracked so the
+// garbage collector knows them. They have no hash and are never shared.
+func backfillBlobs(db *gorm.DB) {
+	if err := db.Exec(`INSERT INTO blobs (key, content_type, size, ref_count, uploaded, created_at, updated_at)
+		SELECT object_key, MAX(content_type), MAX(size), COUNT(*) FILTER (WHERE deleted_at IS NULL), TRUE, NOW(), NOW()
+		FROM photos WHERE object_key <> '' GROUP BY object_key
+		ON CONFLICT (key) DO NOTHING`).Error; err != nil {
+		fmt.Println("backfill blobs:", err)
+	}
+}
+
+// backfillMediaItems gives photos from before carousels their single image
+// as the first media item. The item takes over the photo's blob reference.
+func backfillMediaItems(db *gorm.DB) {
+	if err := db.Exec(`INSERT INTO media_items (photo_id, position, url, object_key, content_type, size, width, height, alt_text, created_at)
+		SELECT id, 1, url, object_key, content_type, size, width, height, '', created_at FROM photos
+		WHERE NOT EXISTS (SELECT 1 FROM media_items WHERE media_items.photo_id = photos.id)`).Error; err != nil {
+		fmt.Println("backfill media items:", err)
+	}
+}
+
+// backfillCommentCounts counts the comments of photos from before
+// comment_count was kept, photos that already have a count are left alone.
+func backfillCommentCounts(db *gorm.DB) {
+	if err := db.Exec(`UPDATE photos SET comment_count =
+		(SELECT COUNT(*) FROM comments WHERE comments.photo_id = photos.id AND comments.deleted_at IS NULL)
+		WHERE comment_count = 0 AND EXISTS (SELECT 1 FROM comments WHERE comments.photo_id = photos.id AND comments.deleted_at IS NULL)`).Error; err != nil {
+		fmt.Println("backfill comment counts:", err)
+	}
+}
+
 func (g *gormPostgresImpl) GetConnection() *gorm.DB {
 	return g.master
 }
+
+func (g *gormPostgresImpl) SearchLanguage() string {
+	return g.searchLanguage
+}
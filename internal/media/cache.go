@@ -0,0 +1,145 @@
+package media
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DiskCache keeps generated renditions as files under dir and evicts the
+// least recently used ones once their total size exceeds maxBytes.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	size  int64
+	order *list.List // front is most recently used
+	items map[string]*list.Element
+}
+
+type cacheEntry struct {
+	name string
+	size int64
+}
+
+// NewDiskCache opens the cache in dir, files left by a previous run are
+// kept and ordered by modification time.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	c := &DiskCache{dir: dir, maxBytes: maxBytes, order: list.New(), items: map[string]*list.Element{}}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	infos := []os.FileInfo{}
+	for _, f := range files {
+		if info, err := f.Info(); err == nil && info.Mode().IsRegular() {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().After(infos[j].ModTime()) })
+	for _, info := range infos {
+		c.items[info.Name()] = c.order.PushBack(&cacheEntry{name: info.Name(), size: info.Size()})
+		c.size += info.Size()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict()
+	return c, nil
+}
+
+// Get returns the cached data for name, which must be a plain file name.
+func (c *DiskCache) Get(name string) ([]byte, bool) {
+	c.mu.Lock()
+	elem, ok := c.items[name]
+	if ok {
+		c.order.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		c.remove(name)
+		return nil, false
+	}
+	return data, true
+}
+
+func (c *DiskCache) Put(name string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, name)); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[name]; ok {
+		c.size -= elem.Value.(*cacheEntry).size
+		c.order.Remove(elem)
+	}
+	c.items[name] = c.order.PushFront(&cacheEntry{name: name, size: int64(len(data))})
+	c.size += int64(len(data))
+	c.evict()
+	return nil
+}
+
+func (c *DiskCache) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[name]; ok {
+		c.size -= elem.Value.(*cacheEntry).size
+		c.order.Remove(elem)
+		delete(c.items, name)
+	}
+}
+
+// evict must be called with mu held.
+func (c *DiskCache) evict() {
+	for c.size > c.maxBytes && c.order.Len() > 0 {
+		entry := c.order.Remove(c.order.Back()).(*cacheEntry)
+		delete(c.items, entry.name)
+		c.size -= entry.size
+		os.Remove(filepath.Join(c.dir, entry.name))
+	}
+}
+
+// NewCache opens the rendition cache configured by MEDIA_CACHE_DIR (default
+// "cache/media") and MEDIA_CACHE_BYTES (default 1 GiB).
+func NewCache() (*DiskCache, error) {
+	dir := os.Getenv("MEDIA_CACHE_DIR")
+	if dir == "" {
+		dir = "cache/media"
+	}
+	maxBytes := int64(1 << 30)
+	if v := os.Getenv("MEDIA_CACHE_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MEDIA_CACHE_BYTES: %w", err)
+		}
+		maxBytes = n
+	}
+	return NewDiskCache(dir, maxBytes)
+}
@@ -0,0 +1,48 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 10)
+	assert.Nil(t, err)
+
+	assert.Nil(t, cache.Put("a", []byte("aaaa")))
+	assert.Nil(t, cache.Put("b", []byte("bbbb")))
+	// touch a so b is the least recently used
+	data, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("aaaa"), data)
+
+	assert.Nil(t, cache.Put("c", []byte("cccc")))
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	_, err = os.Stat(filepath.Join(dir, "b"))
+	assert.True(t, os.IsNotExist(err))
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+
+	t.Run("reopen keeps files", func(t *testing.T) {
+		reopened, err := NewDiskCache(dir, 10)
+		assert.Nil(t, err)
+		data, ok := reopened.Get("c")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("cccc"), data)
+	})
+
+	t.Run("reopen with a smaller limit evicts", func(t *testing.T) {
+		reopened, err := NewDiskCache(dir, 4)
+		assert.Nil(t, err)
+		entries, _ := os.ReadDir(dir)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, int64(4), reopened.size)
+	})
+}
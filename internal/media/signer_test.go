@@ -0,0 +1,42 @@
+package media
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	_, err := NewSigner(nil)
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	signer, err := NewSigner([]byte("secret"))
+	assert.Nil(t, err)
+	opts := imaging.Options{Width: 100, Fit: imaging.FitContain, Format: imaging.FormatJPEG, Quality: 85}
+	now := time.Unix(1700000000, 0)
+	signer.now = func() time.Time { return now }
+	expires := now.Add(URLLifetime)
+	sig := signer.Sign("photos/1/a.jpg", opts, expires)
+
+	assert.True(t, signer.Verify("photos/1/a.jpg", opts, expires, sig))
+	assert.False(t, signer.Verify("photos/1/b.jpg", opts, expires, sig))
+	assert.False(t, signer.Verify("photos/1/a.jpg", imaging.Options{Width: 2000, Fit: imaging.FitContain, Format: imaging.FormatJPEG, Quality: 85}, expires, sig))
+	assert.False(t, signer.Verify("photos/1/a.jpg", opts, expires.Add(time.Hour), sig))
+	other, _ := NewSigner([]byte("other"))
+	assert.False(t, other.Verify("photos/1/a.jpg", opts, expires, sig))
+
+	u, err := url.Parse(signer.URL("/api/v1/media", "photos/1/a.jpg", opts))
+	assert.Nil(t, err)
+	assert.Equal(t, "/api/v1/media/photos/1/a.jpg", u.Path)
+	assert.Equal(t, sig, u.Query().Get("s"))
+	assert.Equal(t, "1700003600", u.Query().Get("exp"))
+	assert.Equal(t, "100", u.Query().Get("w"))
+	assert.False(t, strings.Contains(u.RawQuery, "h="))
+
+	now = expires
+	assert.False(t, signer.Verify("photos/1/a.jpg", opts, expires, sig))
+}
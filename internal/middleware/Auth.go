@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/MidnightHelix/MyGram/internal/model"
 	"github.com/MidnightHelix/MyGram/internal/repository"
 	"github.com/MidnightHelix/MyGram/pkg"
 	"github.com/MidnightHelix/MyGram/pkg/helper"
@@ -19,17 +21,20 @@ type AuthorizationMiddleware struct {
 	PhotoRepository       repository.PhotoQuery
 	CommentRepository     repository.CommentQuery
 	SocialMediaRepository repository.SocialMediaQuery
+	AlbumRepository       repository.AlbumQuery
 }
 
 func NewAuthMiddleware(userRepository repository.UserQuery,
 	photoRepository repository.PhotoQuery,
 	commentRepository repository.CommentQuery,
-	socialMediaRepository repository.SocialMediaQuery) *AuthorizationMiddleware {
+	socialMediaRepository repository.SocialMediaQuery,
+	albumRepository repository.AlbumQuery) *AuthorizationMiddleware {
 	return &AuthorizationMiddleware{
 		UserRepository:        userRepository,
 		PhotoRepository:       photoRepository,
 		CommentRepository:     commentRepository,
 		SocialMediaRepository: socialMediaRepository,
+		AlbumRepository:       albumRepository,
 	}
 }
 
@@ -62,10 +67,77 @@ func (m *AuthorizationMiddleware) Authentication(ctx *gin.Context) {
 		return
 	}
 
+	// the token alone cannot tell whether the account was sanctioned or
+	// signed out since it was issued, so check the current user row
+	userID, _ := claims["user_id"].(float64)
+	user, err := m.UserRepository.GetUsersByID(ctx, uint64(userID))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, pkg.ErrorResponse{
+			Message: "Internal Server Error",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+	if user.ID == 0 {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+			Message: "unauthorized",
+			Errors:  []string{"account not found"},
+		})
+		return
+	}
+	if user.IsBanned() {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, pkg.ErrorResponse{
+			Message: "account banned",
+			Errors:  withReason("your account has been banned", user.ModerationReason),
+		})
+		return
+	}
+	if now := time.Now(); user.IsSuspended(now) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, pkg.ErrorResponse{
+			Message: "account suspended",
+			Errors:  withReason(fmt.Sprintf("your account is suspended until %s", user.SuspendedUntil.Format(time.RFC3339)), user.ModerationReason),
+		})
+		return
+	}
+	// iat only has whole seconds, a token from the second of the revocation
+	// may predate it and is rejected too
+	issuedAt, _ := claims["iat"].(float64)
+	if user.TokensValidAfter != nil && int64(issuedAt) <= user.TokensValidAfter.Unix() {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+			Message: "unauthorized",
+			Errors:  []string{"session has been revoked"},
+		})
+		return
+	}
+	claims["role"] = user.Role
+
 	ctx.Set("claims", claims)
 	ctx.Next()
 }
 
+func (m *AuthorizationMiddleware) AdminAuthorization(ctx *gin.Context) {
+
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+			Message: "Unauthorized",
+			Errors:  []string{"Missing claims in context"},
+		})
+		return
+	}
+
+	role, _ := claims.(jwt.MapClaims)["role"].(string)
+	if role != model.RoleAdmin {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, pkg.ErrorResponse{
+			Message: "Forbidden",
+			Errors:  []string{"Admin access required"},
+		})
+		return
+	}
+
+	ctx.Next()
+}
+
 func (m *AuthorizationMiddleware) UserAuthorization(ctx *gin.Context) {
 
 	claims, ok := ctx.Get("claims")
@@ -258,3 +330,83 @@ func (m *AuthorizationMiddleware) SocialMediaAuthorization(ctx *gin.Context) {
 
 	ctx.Next()
 }
+
+// AlbumAuthorization lets only the owner of the album in the id param
+// through.
+func (m *AuthorizationMiddleware) AlbumAuthorization(ctx *gin.Context) {
+	m.albumAuthorization(ctx, false)
+}
+
+// AlbumContributorAuthorization lets the owner and the collaborators of the
+// album in the id param through.
+func (m *AuthorizationMiddleware) AlbumContributorAuthorization(ctx *gin.Context) {
+	m.albumAuthorization(ctx, true)
+}
+
+func (m *AuthorizationMiddleware) albumAuthorization(ctx *gin.Context, collaborators bool) {
+
+	claims, ok := ctx.Get("claims")
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, pkg.ErrorResponse{
+			Message: "Unauthorized",
+			Errors:  []string{"Missing claims in context"},
+		})
+		return
+	}
+
+	userID := claims.(jwt.MapClaims)["user_id"].(float64)
+	userId := int(userID)
+	if userId == 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if id == 0 || err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, pkg.ErrorResponse{Message: "invalid required param"})
+		return
+	}
+
+	album, err := m.AlbumRepository.GetAlbumByID(ctx, uint64(id))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, pkg.ErrorResponse{
+			Message: "Internal Server Error",
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
+	if album.ID == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, pkg.ErrorResponse{Message: "Album Not Found"})
+		return
+	}
+
+	if album.UserID == uint64(userId) {
+		ctx.Next()
+		return
+	}
+	if collaborators {
+		member, err := m.AlbumRepository.IsCollaborator(ctx, album.ID, uint64(userId))
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, pkg.ErrorResponse{
+				Message: "Internal Server Error",
+				Errors:  []string{err.Error()},
+			})
+			return
+		}
+		if member {
+			ctx.Next()
+			return
+		}
+	}
+	ctx.AbortWithStatusJSON(http.StatusForbidden, pkg.ErrorResponse{
+		Message: "Forbidden",
+		Errors:  []string{"You are not authorized to modify this album"},
+	})
+}
+
+func withReason(message string, reason string) []string {
+	if reason == "" {
+		return []string{message}
+	}
+	return []string{message, "reason: " + reason}
+}
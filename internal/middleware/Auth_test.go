@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newToken(t *testing.T, userID uint64, issuedAt time.Time) string {
+	token, err := helper.GenerateToken(model.AccessClaim{
+		StandardClaim: model.StandardClaim{
+			Exp: uint64(time.Now().Add(time.Hour).Unix()),
+			Iat: uint64(issuedAt.Unix()),
+		},
+		UserID: userID,
+	})
+	assert.Nil(t, err)
+	return token
+}
+
+func authenticate(t *testing.T, user model.User, token string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	userMock := mocks.NewUserQuery(t)
+	userMock.On("GetUsersByID", mock.Anything, user.ID).Return(user, nil)
+	m := NewAuthMiddleware(userMock, nil, nil, nil, nil)
+
+	r := gin.New()
+	r.GET("/", m.Authentication, func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	later := now.Add(time.Hour)
+
+	testCases := []struct {
+		desc     string
+		user     model.User
+		issuedAt time.Time
+		status   int
+	}{
+		{desc: "active user passes", user: model.User{ID: 1}, issuedAt: now, status: http.StatusOK},
+		{desc: "banned user is rejected", user: model.User{ID: 1, BannedAt: &past}, issuedAt: now, status: http.StatusForbidden},
+		{desc: "suspended user is rejected", user: model.User{ID: 1, SuspendedUntil: &later}, issuedAt: now, status: http.StatusForbidden},
+		{desc: "expired suspension passes", user: model.User{ID: 1, SuspendedUntil: &past}, issuedAt: now, status: http.StatusOK},
+		{desc: "token issued before revocation is rejected", user: model.User{ID: 1, TokensValidAfter: &now}, issuedAt: past, status: http.StatusUnauthorized},
+		{desc: "token issued in the second of revocation is rejected", user: model.User{ID: 1, TokensValidAfter: &now}, issuedAt: now, status: http.StatusUnauthorized},
+		{desc: "token issued after revocation passes", user: model.User{ID: 1, TokensValidAfter: &past}, issuedAt: now, status: http.StatusOK},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			rec := authenticate(t, tC.user, newToken(t, tC.user.ID, tC.issuedAt))
+			assert.Equal(t, tC.status, rec.Code)
+		})
+	}
+}
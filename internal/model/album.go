@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AlbumVisibilityPublic    = "public"
+	AlbumVisibilityFollowers = "followers"
+	AlbumVisibilityPrivate   = "private"
+)
+
+// Album groups photos in an order its owner picks, a photo may be in any
+// number of albums. CoverPhotoID is set to the first photo added until the
+// owner picks another one. Collaborators may add their own photos.
+type Album struct {
+	ID            uint64  `json:"id" gorm:"primaryKey"`
+	UserID        uint64  `json:"user_id" gorm:"not null;index"`
+	Title         string  `json:"title" gorm:"not null"`
+	Description   string  `json:"description"`
+	CoverPhotoID  *uint64 `json:"cover_photo_id,omitempty"`
+	Visibility    string  `json:"visibility" gorm:"not null;default:public"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt      `json:"deleted_at,omitempty"`
+	User          *User               `json:"user,omitempty" validate:"-"`
+	CoverPhoto    *Photo              `json:"cover_photo,omitempty" gorm:"foreignKey:CoverPhotoID" validate:"-"`
+	Collaborators []AlbumCollaborator `json:"collaborators,omitempty"`
+}
+
+// AlbumPhoto places a photo in an album. Position orders the album from 1
+// up, AddedBy is the owner or the collaborator who added it.
+type AlbumPhoto struct {
+	AlbumID   uint64 `json:"album_id" gorm:"primaryKey;autoIncrement:false"`
+	PhotoID   uint64 `json:"photo_id" gorm:"primaryKey;autoIncrement:false;index"`
+	Position  int    `json:"position" gorm:"not null"`
+	AddedBy   uint64 `json:"added_by" gorm:"not null"`
+	CreatedAt time.Time
+	Photo     *Photo `json:"photo,omitempty" validate:"-"`
+}
+
+// AlbumCollaborator lets UserID add photos to an album they do not own.
+type AlbumCollaborator struct {
+	AlbumID   uint64 `json:"album_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID    uint64 `json:"user_id" gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt time.Time
+	User      *User `json:"user,omitempty" validate:"-"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Blob is a stored object. Uploads are keyed by the sha256 of their content
+// so identical uploads share one object, RefCount counts the live photos
+// using it. Objects stored before content addressing have no Hash.
+// Uploaded is set once the object was stored, until then a reference only
+// means someone is uploading it.
+type Blob struct {
+	ID          uint64  `json:"id" gorm:"primaryKey"`
+	Key         string  `json:"key" gorm:"not null;uniqueIndex"`
+	Hash        *string `json:"hash,omitempty" gorm:"index"`
+	ContentType string  `json:"content_type"`
+	Size        int64   `json:"size"`
+	RefCount    int     `json:"ref_count" gorm:"not null;default:0"`
+	Uploaded    bool    `json:"uploaded" gorm:"not null;default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time `gorm:"index"`
+}
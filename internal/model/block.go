@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type Block struct {
+	ID        uint64 `json:"id" gorm:"primaryKey"`
+	BlockerID uint64 `json:"blocker_id" gorm:"not null;uniqueIndex:idx_blocks_pair"`
+	BlockedID uint64 `json:"blocked_id" gorm:"not null;uniqueIndex:idx_blocks_pair;index"`
+	CreatedAt time.Time
+	Blocked   *User `json:"blocked,omitempty" validate:"-"`
+}
+
+type Mute struct {
+	ID        uint64 `json:"id" gorm:"primaryKey"`
+	MuterID   uint64 `json:"muter_id" gorm:"not null;uniqueIndex:idx_mutes_pair"`
+	MutedID   uint64 `json:"muted_id" gorm:"not null;uniqueIndex:idx_mutes_pair"`
+	CreatedAt time.Time
+	Muted     *User `json:"muted,omitempty" validate:"-"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// CloseFriend puts FriendID on the close friends list of UserID, they see
+// the photos UserID shares with close friends only.
+type CloseFriend struct {
+	ID        uint64 `json:"id" gorm:"primaryKey"`
+	UserID    uint64 `json:"user_id" gorm:"not null;uniqueIndex:idx_close_friends_pair"`
+	FriendID  uint64 `json:"friend_id" gorm:"not null;uniqueIndex:idx_close_friends_pair;index"`
+	CreatedAt time.Time
+	Friend    *User `json:"friend,omitempty" validate:"-"`
+}
@@ -13,7 +13,10 @@ type Comment struct {
 	Message   string `json:"message" gorm:"not null" binding:"required" validate:"required"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// EditedAt is when the message last changed, nil when it never did.
+	EditedAt  *time.Time     `json:"edited_at,omitempty"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
+	Mentions  []Mention      `json:"mentions,omitempty" gorm:"polymorphic:Source;polymorphicValue:comments"`
 	User      *User          `json:"user,omitempty" validate:"-"`
 	Photo     *Photo         `json:"photo,omitempty" validate:"-"`
 }
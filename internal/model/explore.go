@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// ExploreScore ranks a recent public photo on the explore page. Scores are
+// recomputed in bulk by a background job, AuthorRank is the photo's place
+// among its author's scored photos and keeps one account from filling the
+// page.
+type ExploreScore struct {
+	PhotoID    uint64  `gorm:"primaryKey;autoIncrement:false"`
+	UserID     uint64  `gorm:"not null;index"`
+	Score      float64 `gorm:"not null"`
+	AuthorRank int     `gorm:"not null"`
+	ComputedAt time.Time
+	Photo      *Photo
+}
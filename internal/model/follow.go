@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+const (
+	FollowStatusPending  = "pending"
+	FollowStatusAccepted = "accepted"
+)
+
+type Follow struct {
+	ID          uint64 `json:"id" gorm:"primaryKey"`
+	FollowerID  uint64 `json:"follower_id" gorm:"not null;uniqueIndex:idx_follows_pair;index"`
+	FollowingID uint64 `json:"following_id" gorm:"not null;uniqueIndex:idx_follows_pair;index"`
+	Status      string `json:"status" gorm:"not null;default:accepted;index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Follower    *User `json:"follower,omitempty" validate:"-"`
+	Following   *User `json:"following,omitempty" validate:"-"`
+}
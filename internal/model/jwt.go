@@ -24,5 +24,6 @@ type AccessClaim struct {
 	StandardClaim
 	UserID   uint64    `json:"user_id"`
 	Username string    `json:"username"`
+	Role     string    `json:"role"`
 	Dob      time.Time `json:"dob"`
 }
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Like is a user liking a photo. The pair is unique so a like counts once
+// no matter how often it is sent.
+type Like struct {
+	ID        uint64 `json:"id" gorm:"primaryKey"`
+	UserID    uint64 `json:"user_id" gorm:"not null;uniqueIndex:idx_likes_pair"`
+	PhotoID   uint64 `json:"photo_id" gorm:"not null;uniqueIndex:idx_likes_pair;index"`
+	CreatedAt time.Time
+	User      *User `json:"user,omitempty" validate:"-"`
+}
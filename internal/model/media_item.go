@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// MediaItem is one image of a post, shown as a carousel in Position order
+// starting at 1. Every item is a stored object of its own, shared with
+// other uploads of the same content through its blob.
+type MediaItem struct {
+	ID          uint64 `json:"id" gorm:"primaryKey"`
+	PhotoID     uint64 `json:"photo_id" gorm:"not null;index:idx_media_items_photo_position,priority:1"`
+	Position    int    `json:"position" gorm:"not null;index:idx_media_items_photo_position,priority:2"`
+	Url         string `json:"url" gorm:"not null"`
+	ObjectKey   string `json:"object_key,omitempty" gorm:"index"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	AltText     string `json:"alt_text"`
+	CreatedAt   time.Time
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	MentionSourcePhoto   = "photos"
+	MentionSourceComment = "comments"
+)
+
+// Mention is an @username in a photo caption or comment resolved to the
+// user it named when it was written, so it keeps pointing at them after a
+// username change. Offset and Length count runes in the text and include
+// the '@'.
+type Mention struct {
+	ID         uint64 `json:"-" gorm:"primaryKey"`
+	SourceType string `json:"-" gorm:"not null;index:idx_mentions_source,priority:1"`
+	SourceID   uint64 `json:"-" gorm:"not null;index:idx_mentions_source,priority:2"`
+	UserID     uint64 `json:"user_id" gorm:"not null;index"`
+	Offset     int    `json:"offset" gorm:"column:text_offset;not null"`
+	Length     int    `json:"length" gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+// MentionSource is the photo caption or comment a text belongs to. Whoever
+// may see PhotoID may see the text.
+type MentionSource struct {
+	Type      string
+	ID        uint64
+	AuthorID  uint64
+	PhotoID   uint64
+	CommentID *uint64
+}
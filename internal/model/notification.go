@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+const NotificationTypeMention = "mention"
+
+// Notification tells UserID that ActorID did something involving them.
+// CommentID is set when it happened in a comment on PhotoID.
+type Notification struct {
+	ID        uint64     `json:"id" gorm:"primaryKey"`
+	UserID    uint64     `json:"user_id" gorm:"not null;index"`
+	ActorID   uint64     `json:"actor_id" gorm:"not null"`
+	Type      string     `json:"type" gorm:"not null"`
+	PhotoID   uint64     `json:"photo_id" gorm:"not null"`
+	CommentID *uint64    `json:"comment_id,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time
+	Actor     *User `json:"actor,omitempty" validate:"-"`
+}
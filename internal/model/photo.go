@@ -6,15 +6,100 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	PhotoStatusProcessing = "processing"
+	PhotoStatusReady      = "ready"
+	PhotoStatusFailed     = "failed"
+)
+
+// Who may see a photo besides its author. Public photos follow the account:
+// anyone for a public account, accepted followers for a private one.
+const (
+	PhotoVisibilityPublic       = "public"
+	PhotoVisibilityFollowers    = "followers"
+	PhotoVisibilityCloseFriends = "close_friends"
+	PhotoVisibilityPrivate      = "private"
+)
+
+// How finely the location of a photo is kept, coordinates are rounded to
+// the precision the author picks before they are stored.
+const (
+	LocationPrecisionExact       = "exact"
+	LocationPrecisionApproximate = "approximate"
+	LocationPrecisionCity        = "city"
+)
+
+// Photo is a post of one or more uploaded images. Url is derived from
+// ObjectKey by the storage backend at upload time and is never taken from
+// the client. The images are the post's Media, Url, ObjectKey, the
+// dimensions and Variants belong to the first of them so clients that only
+// know single image posts keep working.
 type Photo struct {
+	ID          uint64 `json:"id" gorm:"primaryKey"`
+	Title       string `json:"title" gorm:"not null" binding:"required" validate:"required"`
+	Caption     string `json:"caption"`
+	Url         string `json:"photo_url" gorm:"not null"`
+	ObjectKey   string `json:"object_key,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	Status      string `json:"status" gorm:"not null;default:ready;index"`
+	Visibility  string `json:"visibility" gorm:"not null;default:public" validate:"omitempty,oneof=public followers close_friends private"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	// CameraModel and TakenAt come from EXIF and are only kept when the
+	// uploader opts in, everything else in EXIF is discarded.
+	CameraModel string     `json:"camera_model,omitempty"`
+	TakenAt     *time.Time `json:"taken_at,omitempty"`
+	// Latitude and Longitude are only set together and are already rounded,
+	// the position the author gave is never stored. PlaceName is free text
+	// and may be set without them.
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	PlaceName string   `json:"place_name,omitempty"`
+	// PHash and DHash are the perceptual hashes of the image, stored as the
+	// bits of the uint64 hash. Photos from before hashing have none.
+	PHash *int64 `json:"-"`
+	DHash *int64 `json:"-"`
+	// LikeCount and CommentCount are kept in step with the likes and
+	// comments tables by every like, unlike, comment and deleted comment.
+	// LikedByMe and SavedByMe are filled in per viewer and never stored.
+	LikeCount    int64  `json:"like_count" gorm:"not null;default:0"`
+	CommentCount int64  `json:"comment_count" gorm:"not null;default:0"`
+	LikedByMe    bool   `json:"-" gorm:"-"`
+	SavedByMe    bool   `json:"-" gorm:"-"`
+	UserID       uint64 `json:"user_id" gorm:"column:user_id"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	// EditedAt is when the title or caption last changed, nil when they
+	// never did. UpdatedAt also moves on other changes.
+	EditedAt  *time.Time      `json:"edited_at,omitempty"`
+	DeletedAt gorm.DeletedAt  `json:"deleted_at,omitempty"`
+	Variants  []PhotoVariant  `json:"variants,omitempty"`
+	Media     []MediaItem     `json:"media,omitempty"`
+	HashBands []PhotoHashBand `json:"-"`
+	Mentions  []Mention       `json:"mentions,omitempty" gorm:"polymorphic:Source;polymorphicValue:photos"`
+	Comments  []Comment       `json:"comments,omitempty"`
+	User      *User           `json:"user,omitempty" validate:"-"`
+}
+
+// PhotoVariant is a resized rendition of a photo, produced after upload.
+type PhotoVariant struct {
 	ID        uint64 `json:"id" gorm:"primaryKey"`
-	Title     string `json:"title" gorm:"not null" binding:"required" validate:"required"`
-	Caption   string `json:"caption"`
-	Url       string `json:"photo_url" gorm:"not null" binding:"required" validate:"required"`
-	UserID    uint64 `json:"user_id" gorm:"column:user_id"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
-	Comments  []Comment      `json:"comments,omitempty"`
-	User      *User          `json:"user,omitempty" validate:"-"`
+	PhotoID   uint64 `json:"photo_id" gorm:"not null;index"`
+	Name      string `json:"name" gorm:"not null"`
+	Format    string `json:"format" gorm:"not null"`
+	ObjectKey string `json:"object_key" gorm:"not null"`
+	Url       string `json:"url" gorm:"not null"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Size      int64  `json:"size"`
+}
+
+// PhotoHashBand indexes one 16 bit band of a photo's PHash, near-duplicates
+// are found by looking up bands instead of comparing every hash.
+type PhotoHashBand struct {
+	ID      uint64 `gorm:"primaryKey"`
+	PhotoID uint64 `gorm:"not null;index"`
+	Band    int16  `gorm:"not null;index:idx_photo_hash_bands_band_value,priority:1"`
+	Value   int32  `gorm:"not null;index:idx_photo_hash_bands_band_value,priority:2"`
 }
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	RevisionSourcePhoto   = "photos"
+	RevisionSourceComment = "comments"
+)
+
+// Revision keeps the text a photo or comment had before one of its edits,
+// who made the edit and when. Title is only set for photos, Text is the
+// caption of a photo or the message of a comment.
+type Revision struct {
+	ID         uint64 `json:"id" gorm:"primaryKey"`
+	SourceType string `json:"-" gorm:"not null;index:idx_revisions_source,priority:1"`
+	SourceID   uint64 `json:"-" gorm:"not null;index:idx_revisions_source,priority:2"`
+	EditorID   uint64 `json:"editor_id" gorm:"not null"`
+	Title      string `json:"title,omitempty"`
+	Text       string `json:"text"`
+	CreatedAt  time.Time
+	Editor     *User `json:"editor,omitempty" gorm:"foreignKey:EditorID"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Save is a user bookmarking a photo for later. Saves are only ever shown
+// to the user who made them.
+type Save struct {
+	ID        uint64 `json:"id" gorm:"primaryKey"`
+	UserID    uint64 `json:"user_id" gorm:"not null;uniqueIndex:idx_saves_pair"`
+	PhotoID   uint64 `json:"photo_id" gorm:"not null;uniqueIndex:idx_saves_pair;index"`
+	CreatedAt time.Time
+	Photo     *Photo `json:"photo,omitempty" validate:"-"`
+}
+
+// SaveCollection is a private, named group of a user's saved photos. A
+// saved photo may be in any number of collections, or none.
+type SaveCollection struct {
+	ID        uint64 `json:"id" gorm:"primaryKey"`
+	UserID    uint64 `json:"user_id" gorm:"not null;index"`
+	Name      string `json:"name" gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SaveCollectionPhoto puts a saved photo in a collection.
+type SaveCollectionPhoto struct {
+	CollectionID uint64 `gorm:"primaryKey;autoIncrement:false"`
+	PhotoID      uint64 `gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt    time.Time
+}
@@ -0,0 +1,33 @@
+package model
+
+// PhotoMatch is a photo found by search. Rank is the photo's relevance to
+// the query decayed by its age, results are ordered by it.
+type PhotoMatch struct {
+	PhotoID uint64
+	Rank    float64
+	// TitleHeadline and CaptionHeadline are the title and caption with the
+	// matched words marked by the database, they are turned into
+	// TitleHighlights and CaptionHighlights and never shown as they are.
+	TitleHeadline     string
+	CaptionHeadline   string
+	TitleHighlights   []Highlight `gorm:"-"`
+	CaptionHighlights []Highlight `gorm:"-"`
+	Photo             *Photo
+}
+
+// Highlight marks a matched word of a text. Offset and Length count runes
+// of the text like the offsets of a Mention.
+type Highlight struct {
+	Offset int
+	Length int
+}
+
+// NearbyPhoto is a photo found near a point. Distance is the square of its
+// distance in km on a plane touching the earth at that point, results are
+// ordered by it. DistanceKm is the great circle distance shown to clients.
+type NearbyPhoto struct {
+	PhotoID    uint64
+	Distance   float64
+	DistanceKm float64 `gorm:"-"`
+	Photo      *Photo
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Story is an image shown to the author and their followers until ExpiresAt.
+// The reaper deletes expired stories unless they are kept in a highlight,
+// HighlightCount counts the highlights holding it. Kept stories leave the
+// story tray but stay on the author's profile. SeenByMe is filled in per
+// viewer and never stored.
+type Story struct {
+	ID             uint64    `json:"id" gorm:"primaryKey"`
+	UserID         uint64    `json:"user_id" gorm:"not null;index"`
+	Url            string    `json:"url" gorm:"not null"`
+	ObjectKey      string    `json:"-" gorm:"not null;index"`
+	ContentType    string    `json:"content_type,omitempty"`
+	Size           int64     `json:"size,omitempty"`
+	Width          int       `json:"width,omitempty"`
+	Height         int       `json:"height,omitempty"`
+	Caption        string    `json:"caption"`
+	AltText        string    `json:"alt_text"`
+	ViewCount      int64     `json:"view_count" gorm:"not null;default:0"`
+	HighlightCount int       `json:"-" gorm:"not null;default:0"`
+	SeenByMe       bool      `json:"-" gorm:"-"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty"`
+	User           *User          `json:"user,omitempty" validate:"-"`
+}
+
+// StoryView records that ViewerID has seen a story, the first time counts.
+type StoryView struct {
+	ID        uint64 `json:"id" gorm:"primaryKey"`
+	StoryID   uint64 `json:"story_id" gorm:"not null;uniqueIndex:idx_story_views_pair"`
+	ViewerID  uint64 `json:"viewer_id" gorm:"not null;uniqueIndex:idx_story_views_pair;index"`
+	CreatedAt time.Time
+	Viewer    *User `json:"viewer,omitempty" gorm:"foreignKey:ViewerID" validate:"-"`
+}
+
+// StoryHighlight keeps stories of its owner on their profile after they
+// expire, in an order the owner picks. CoverStoryID is the first story until
+// that one is removed.
+type StoryHighlight struct {
+	ID           uint64  `json:"id" gorm:"primaryKey"`
+	UserID       uint64  `json:"user_id" gorm:"not null;index"`
+	Title        string  `json:"title" gorm:"not null"`
+	CoverStoryID *uint64 `json:"cover_story_id,omitempty"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	User         *User  `json:"user,omitempty" validate:"-"`
+	CoverStory   *Story `json:"cover_story,omitempty" gorm:"foreignKey:CoverStoryID" validate:"-"`
+}
+
+// StoryHighlightItem places a story in a highlight. Position orders the
+// highlight from 1 up.
+type StoryHighlightItem struct {
+	HighlightID uint64 `json:"highlight_id" gorm:"primaryKey;autoIncrement:false"`
+	StoryID     uint64 `json:"story_id" gorm:"primaryKey;autoIncrement:false;index"`
+	Position    int    `json:"position" gorm:"not null"`
+	CreatedAt   time.Time
+	Story       *Story `json:"story,omitempty" validate:"-"`
+}
+
+// StoryTray is the live stories of one account as the viewer's story tray
+// shows them, Seen is set once the viewer has seen all of them.
+type StoryTray struct {
+	User    User
+	Seen    bool
+	Stories []Story
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Tag is a hashtag used in photo captions. Name is normalized, see
+// helper.NormalizeHashtag, and PhotoCount is recounted whenever a photo
+// gains or loses the tag.
+type Tag struct {
+	ID         uint64 `json:"id" gorm:"primaryKey"`
+	Name       string `json:"name" gorm:"not null;uniqueIndex"`
+	PhotoCount int64  `json:"photo_count" gorm:"not null;default:0"`
+	CreatedAt  time.Time
+}
+
+// PhotoTag links a photo to a tag in its caption.
+type PhotoTag struct {
+	PhotoID uint64 `gorm:"primaryKey;autoIncrement:false"`
+	TagID   uint64 `gorm:"primaryKey;autoIncrement:false;index"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// TimelineEntry is a photo pushed into a follower's precomputed home feed
+// when it was posted. Accounts with FanoutOnRead set get no entries, their
+// photos are merged into the feed when it is read.
+type TimelineEntry struct {
+	UserID    uint64 `gorm:"primaryKey;autoIncrement:false"`
+	PhotoID   uint64 `gorm:"primaryKey;autoIncrement:false;index"`
+	AuthorID  uint64 `gorm:"not null;index"`
+	CreatedAt time.Time
+}
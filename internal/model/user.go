@@ -6,13 +6,35 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
-	ID           uint64         `json:"id,omitempty" gorm:"primaryKey"`
-	Username     string         `json:"username,omitempty" gorm:"not null;unique;uniqueIndex" binding:"required" validate:"required,min=3,max=50"`
-	Email        string         `json:"email,omitempty" gorm:"not null;unique;uniqueIndex" binding:"required" validate:"required,email"`
-	Password     string         `json:"password,omitempty" gorm:"not null"`
-	DoB          time.Time      `json:"dob,omitempty" gorm:"not null"`
-	Age          uint8          `json:"age,omitempty" gorm:"not null" binding:"required" validate:"required,min=9"`
+	ID                uint64     `json:"id,omitempty" gorm:"primaryKey"`
+	Username          string     `json:"username,omitempty" gorm:"not null;unique;uniqueIndex" binding:"required" validate:"required,min=3,max=50"`
+	Email             string     `json:"email,omitempty" gorm:"not null;unique;uniqueIndex" binding:"required" validate:"required,email"`
+	UsernameKey       string     `json:"-" gorm:"uniqueIndex;default:null"`
+	EmailKey          string     `json:"-" gorm:"uniqueIndex;default:null"`
+	UsernameChangedAt *time.Time `json:"username_changed_at,omitempty"`
+	DisplayName       string     `json:"display_name,omitempty" validate:"max=50"`
+	Role              string     `json:"role,omitempty" gorm:"not null;default:user"`
+	Password          string     `json:"password,omitempty" gorm:"not null"`
+	DoB               time.Time  `json:"dob,omitempty" gorm:"not null"`
+	Age               uint8      `json:"age,omitempty" gorm:"not null" binding:"required" validate:"required,min=9"`
+	IsPrivate         bool       `json:"is_private" gorm:"not null;default:false"`
+	SuspendedUntil    *time.Time `json:"suspended_until,omitempty"`
+	BannedAt          *time.Time `json:"banned_at,omitempty"`
+	ModerationReason  string     `json:"moderation_reason,omitempty"`
+	MustResetPassword bool       `json:"must_reset_password,omitempty" gorm:"not null;default:false"`
+	// PasswordResetToken holds the sha256 of the one-time token that
+	// completes a forced reset, it is cleared once used.
+	PasswordResetToken string     `json:"-" gorm:"default:null"`
+	TokensValidAfter   *time.Time `json:"-"`
+	// FanoutOnRead marks accounts with too many followers to push every
+	// photo into each follower's timeline, it is never cleared again.
+	FanoutOnRead bool           `json:"-" gorm:"not null;default:false"`
 	CreatedAt    time.Time      `json:"created_at,omitempty"`
 	UpdatedAt    time.Time      `json:"updated_at,omitempty"`
 	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty"`
@@ -20,3 +42,22 @@ type User struct {
 	SocialMedias []SocialMedia  `json:"social_medias,omitempty"`
 	Comments     []Comment      `json:"comments,omitempty"`
 }
+
+func (u User) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+// IsSuspended reports whether a temporary suspension is still running at t.
+func (u User) IsSuspended(t time.Time) bool {
+	return u.SuspendedUntil != nil && u.SuspendedUntil.After(t)
+}
+
+// UsernameRedirect keeps a previous username pointing at its owner so old
+// links and mentions keep working, and so nobody else can take the handle.
+type UsernameRedirect struct {
+	ID          uint64    `json:"id" gorm:"primaryKey"`
+	UsernameKey string    `json:"-" gorm:"not null;uniqueIndex"`
+	Username    string    `json:"username" gorm:"not null"`
+	UserID      uint64    `json:"user_id" gorm:"not null;index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
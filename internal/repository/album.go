@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlbumQuery interface {
+	// GetAlbumByID returns an album whoever asks, it is meant for ownership
+	// checks. Everything shown to users goes through GetAlbum.
+	GetAlbumByID(ctx context.Context, id uint64) (model.Album, error)
+	// GetAlbum returns an album with its owner, cover and collaborators, or
+	// an empty album when the viewer may not see it.
+	GetAlbum(ctx context.Context, viewerID uint64, id uint64) (model.Album, error)
+	// GetUserAlbums lists the albums of userID the viewer may see, newest
+	// first, starting below the album id cursor when it is not zero.
+	GetUserAlbums(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Album, error)
+	// GetAlbumPhotos lists the photos of an album the viewer may see in album
+	// order, starting after the position cursor when it is not zero.
+	GetAlbumPhotos(ctx context.Context, viewerID uint64, albumID uint64, cursor uint64, limit int) ([]model.AlbumPhoto, error)
+	GetAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64) (model.AlbumPhoto, error)
+	// GetAlbumPhotoIDs returns every photo in an album in album order.
+	GetAlbumPhotoIDs(ctx context.Context, albumID uint64) ([]uint64, error)
+	IsCollaborator(ctx context.Context, albumID uint64, userID uint64) (bool, error)
+
+	CreateAlbum(ctx context.Context, album model.Album) (model.Album, error)
+	// UpdateAlbum saves the title, description, visibility and cover.
+	UpdateAlbum(ctx context.Context, album model.Album) (model.Album, error)
+	DeleteAlbum(ctx context.Context, id uint64) error
+	// AddAlbumPhoto appends a photo to the end of an album and reports
+	// whether it was added, a photo already in the album stays where it is.
+	AddAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64, addedBy uint64) (bool, error)
+	// RemoveAlbumPhoto takes a photo out of an album and reports whether it
+	// was in it. Removing the cover makes the first remaining photo the
+	// cover.
+	RemoveAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64) (bool, error)
+	// ReorderAlbumPhotos numbers the photos of an album in the order of
+	// photoIDs.
+	ReorderAlbumPhotos(ctx context.Context, albumID uint64, photoIDs []uint64) error
+	AddCollaborator(ctx context.Context, albumID uint64, userID uint64) error
+	RemoveCollaborator(ctx context.Context, albumID uint64, userID uint64) error
+}
+
+type albumQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewAlbumQuery(db infrastructure.GormPostgres) AlbumQuery {
+	return &albumQueryImpl{db: db}
+}
+
+func (u *albumQueryImpl) GetAlbumByID(ctx context.Context, id uint64) (model.Album, error) {
+	db := u.db.GetConnection()
+	album := model.Album{}
+	if err := db.
+		WithContext(ctx).
+		Table("albums").
+		Where("id = ? AND deleted_at IS NULL", id).
+		Find(&album).Error; err != nil {
+		return model.Album{}, err
+	}
+	return album, nil
+}
+
+func (u *albumQueryImpl) GetAlbum(ctx context.Context, viewerID uint64, id uint64) (model.Album, error) {
+	db := u.db.GetConnection()
+	album := model.Album{}
+	if err := db.
+		WithContext(ctx).
+		Table("albums").
+		Where("albums.id = ? AND albums.deleted_at IS NULL", id).
+		Scopes(visibleAlbums(viewerID), notBlocked(viewerID, "albums.user_id"), activeUsers("albums.user_id")).
+		Preload("Collaborators.User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username")
+		}).
+		Preload("CoverPhoto", albumCover(viewerID)).
+		Preload("CoverPhoto.Variants").
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username")
+		}).
+		Find(&album).Error; err != nil {
+		return model.Album{}, err
+	}
+	return album, nil
+}
+
+func (u *albumQueryImpl) GetUserAlbums(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Album, error) {
+	db := u.db.GetConnection()
+	albums := []model.Album{}
+	query := db.
+		WithContext(ctx).
+		Table("albums").
+		Where("albums.user_id = ? AND albums.deleted_at IS NULL", userID).
+		Scopes(visibleAlbums(viewerID), notBlocked(viewerID, "albums.user_id"), activeUsers("albums.user_id"))
+	if cursor > 0 {
+		query = query.Where("albums.id < ?", cursor)
+	}
+	if err := query.
+		Preload("CoverPhoto", albumCover(viewerID)).
+		Preload("CoverPhoto.Variants").
+		Order("albums.id DESC").
+		Limit(limit).
+		Find(&albums).Error; err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+// albumCover leaves out a cover the viewer may not see, a collaborator's
+// photo can be from an account that is private to them or blocks them, or
+// be shared with fewer people than the album.
+func albumCover(viewerID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("status = ?", model.PhotoStatusReady).
+			Scopes(visiblePhotos(viewerID, "photos"))
+	}
+}
+
+func (u *albumQueryImpl) GetAlbumPhotos(ctx context.Context, viewerID uint64, albumID uint64, cursor uint64, limit int) ([]model.AlbumPhoto, error) {
+	db := u.db.GetConnection()
+	items := []model.AlbumPhoto{}
+	if err := db.
+		WithContext(ctx).
+		Table("album_photos").
+		Select("album_photos.*").
+		Joins("JOIN photos ON photos.id = album_photos.photo_id AND photos.deleted_at IS NULL").
+		Where("album_photos.album_id = ? AND album_photos.position > ? AND photos.status = ?", albumID, cursor, model.PhotoStatusReady).
+		Scopes(visiblePhotos(viewerID, "photos")).
+		Preload("Photo").
+		Preload("Photo.Mentions").
+		Preload("Photo.User").
+		Preload("Photo.Media", mediaInOrder).
+		Preload("Photo.Variants").
+		Order("album_photos.position").
+		Limit(limit).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (u *albumQueryImpl) GetAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64) (model.AlbumPhoto, error) {
+	db := u.db.GetConnection()
+	item := model.AlbumPhoto{}
+	if err := db.
+		WithContext(ctx).
+		Table("album_photos").
+		Where("album_id = ? AND photo_id = ?", albumID, photoID).
+		Find(&item).Error; err != nil {
+		return model.AlbumPhoto{}, err
+	}
+	return item, nil
+}
+
+func (u *albumQueryImpl) GetAlbumPhotoIDs(ctx context.Context, albumID uint64) ([]uint64, error) {
+	db := u.db.GetConnection()
+	ids := []uint64{}
+	if err := db.
+		WithContext(ctx).
+		Table("album_photos").
+		Where("album_id = ?", albumID).
+		Order("position").
+		Pluck("photo_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (u *albumQueryImpl) IsCollaborator(ctx context.Context, albumID uint64, userID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Table("album_collaborators").
+		Where("album_id = ? AND user_id = ?", albumID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (u *albumQueryImpl) CreateAlbum(ctx context.Context, album model.Album) (model.Album, error) {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("albums").
+		Create(&album).Error; err != nil {
+		return model.Album{}, err
+	}
+	return album, nil
+}
+
+func (u *albumQueryImpl) UpdateAlbum(ctx context.Context, album model.Album) (model.Album, error) {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Model(&model.Album{ID: album.ID}).
+		Select("title", "description", "visibility", "cover_photo_id").
+		Updates(album).Error; err != nil {
+		return model.Album{}, err
+	}
+	return album, nil
+}
+
+func (u *albumQueryImpl) DeleteAlbum(ctx context.Context, id uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Where("id = ?", id).
+		Delete(&model.Album{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *albumQueryImpl) AddAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64, addedBy uint64) (bool, error) {
+	db := u.db.GetConnection()
+	added := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// adds to the same album wait for each other so positions stay unique
+		if err := lockAlbum(tx, albumID); err != nil {
+			return err
+		}
+		res := tx.Exec(`INSERT INTO album_photos (album_id, photo_id, position, added_by, created_at)
+			SELECT ?, ?, COALESCE(MAX(position), 0) + 1, ?, NOW() FROM album_photos WHERE album_id = ?
+			ON CONFLICT (album_id, photo_id) DO NOTHING`, albumID, photoID, addedBy, albumID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return tx.Exec("UPDATE albums SET cover_photo_id = ? WHERE id = ? AND cover_photo_id IS NULL", photoID, albumID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return added, nil
+}
+
+func (u *albumQueryImpl) RemoveAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	removed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAlbum(tx, albumID); err != nil {
+			return err
+		}
+		res := tx.Exec("DELETE FROM album_photos WHERE album_id = ? AND photo_id = ?", albumID, photoID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return tx.Exec(`UPDATE albums SET cover_photo_id =
+			(SELECT photo_id FROM album_photos WHERE album_id = ? ORDER BY position LIMIT 1)
+			WHERE id = ? AND cover_photo_id = ?`, albumID, albumID, photoID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return removed, nil
+}
+
+func (u *albumQueryImpl) ReorderAlbumPhotos(ctx context.Context, albumID uint64, photoIDs []uint64) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAlbum(tx, albumID); err != nil {
+			return err
+		}
+		for i, photoID := range photoIDs {
+			if err := tx.
+				Table("album_photos").
+				Where("album_id = ? AND photo_id = ?", albumID, photoID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lockAlbum holds the album row until the transaction ends, changes to the
+// order of an album run one at a time.
+func lockAlbum(tx *gorm.DB, albumID uint64) error {
+	album := model.Album{}
+	return tx.
+		Table("albums").
+		Select("id").
+		Where("id = ?", albumID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&album).Error
+}
+
+func (u *albumQueryImpl) AddCollaborator(ctx context.Context, albumID uint64, userID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.AlbumCollaborator{AlbumID: albumID, UserID: userID}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *albumQueryImpl) RemoveCollaborator(ctx context.Context, albumID uint64, userID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Where("album_id = ? AND user_id = ?", albumID, userID).
+		Delete(&model.AlbumCollaborator{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAlbumPhoto(t *testing.T) {
+	t.Run("first photo becomes the cover", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "albums" WHERE id = $1`) + ".*" + regexp.QuoteMeta(`FOR UPDATE`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO album_photos (album_id, photo_id, position, added_by, created_at) SELECT $1, $2, COALESCE(MAX(position), 0) + 1, $3, NOW() FROM album_photos WHERE album_id = $4 ON CONFLICT (album_id, photo_id) DO NOTHING`)).
+			WithArgs(4, 7, 1, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE albums SET cover_photo_id = $1 WHERE id = $2 AND cover_photo_id IS NULL`)).
+			WithArgs(7, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		albumRepo := albumQueryImpl{db: postgresMock}
+		added, err := albumRepo.AddAlbumPhoto(context.Background(), 4, 7, 1)
+		assert.Nil(t, err)
+		assert.True(t, added)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("photo already in the album stays", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "albums"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO album_photos`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		albumRepo := albumQueryImpl{db: postgresMock}
+		added, err := albumRepo.AddAlbumPhoto(context.Background(), 4, 7, 1)
+		assert.Nil(t, err)
+		assert.False(t, added)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRemoveAlbumPhoto(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "albums"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM album_photos WHERE album_id = $1 AND photo_id = $2`)).
+		WithArgs(4, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE albums SET cover_photo_id = (SELECT photo_id FROM album_photos WHERE album_id = $1 ORDER BY position LIMIT 1) WHERE id = $2 AND cover_photo_id = $3`)).
+		WithArgs(4, 4, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	albumRepo := albumQueryImpl{db: postgresMock}
+	removed, err := albumRepo.RemoveAlbumPhoto(context.Background(), 4, 7)
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestReorderAlbumPhotos(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "albums"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "album_photos" SET "position"=$1 WHERE album_id = $2 AND photo_id = $3`)).
+		WithArgs(1, 4, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "album_photos" SET "position"=$1 WHERE album_id = $2 AND photo_id = $3`)).
+		WithArgs(2, 4, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	albumRepo := albumQueryImpl{db: postgresMock}
+	assert.Nil(t, albumRepo.ReorderAlbumPhotos(context.Background(), 4, []uint64{9, 7}))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAlbum(t *testing.T) {
+	t.Run("not visible to the viewer", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "albums" WHERE (albums.id = $1 AND albums.deleted_at IS NULL) AND `+
+			`(albums.user_id = $2 OR albums.id IN (SELECT album_id FROM album_collaborators WHERE user_id = $3) OR `+
+			`(albums.visibility = $4 AND albums.user_id IN (SELECT id FROM users WHERE NOT is_private)) OR `+
+			`(albums.visibility IN ($5,$6) AND albums.user_id IN (SELECT following_id FROM follows WHERE follower_id = $7 AND status = $8)))`)).
+			WithArgs(4, 1, 1, model.AlbumVisibilityPublic, model.AlbumVisibilityPublic, model.AlbumVisibilityFollowers, 1, model.FollowStatusAccepted,
+				1, 1, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		albumRepo := albumQueryImpl{db: postgresMock}
+		album, err := albumRepo.GetAlbum(context.Background(), 1, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(0), album.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("visible with cover and collaborators", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "albums" WHERE (albums.id = $1`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "visibility", "cover_photo_id"}).AddRow(4, 2, "Trip", "public", 7))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "album_collaborators" WHERE "album_collaborators"."album_id" = $1`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"album_id", "user_id"}).AddRow(4, 3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","username" FROM "users" WHERE "users"."id" = $1`)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(3, "carol"))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos" WHERE status = $1 AND`) + ".*" + regexp.QuoteMeta(`"photos"."id" = $`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(7, 3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants" WHERE "photo_variants"."photo_id" = $1`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","username" FROM "users" WHERE "users"."id" = $1`)).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "bob"))
+
+		albumRepo := albumQueryImpl{db: postgresMock}
+		album, err := albumRepo.GetAlbum(context.Background(), 1, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(7), album.CoverPhoto.ID)
+		assert.Equal(t, "carol", album.Collaborators[0].User.Username)
+		assert.Equal(t, "bob", album.User.Username)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetAlbumPhotos(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT album_photos.* FROM "album_photos" JOIN photos ON photos.id = album_photos.photo_id AND photos.deleted_at IS NULL `+
+		`WHERE (album_photos.album_id = $1 AND album_photos.position > $2 AND photos.status = $3)`) + ".*" +
+		regexp.QuoteMeta(`ORDER BY album_photos.position LIMIT $`)).
+		WillReturnRows(sqlmock.NewRows([]string{"album_id", "photo_id", "position", "added_by"}).AddRow(4, 7, 3, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos" WHERE "photos"."id" = $1 AND "photos"."deleted_at" IS NULL`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(7, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "bob"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id"}))
+
+	albumRepo := albumQueryImpl{db: postgresMock}
+	items, err := albumRepo.GetAlbumPhotos(context.Background(), 1, 4, 2, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, "bob", items[0].Photo.User.Username)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unreferencedBlob matches blobs untouched since the cutoff that no live
+// photo or story, nor one deleted after the cutoff, points at, be it as a
+// photo's cover or any other of its media items.
+const unreferencedBlob = `blobs.updated_at < ? AND NOT EXISTS (
+	SELECT 1 FROM photos WHERE photos.object_key = blobs.key AND (photos.deleted_at IS NULL OR photos.deleted_at > ?)) AND NOT EXISTS (
+	SELECT 1 FROM media_items JOIN photos ON photos.id = media_items.photo_id
+	WHERE media_items.object_key = blobs.key AND (photos.deleted_at IS NULL OR photos.deleted_at > ?)) AND NOT EXISTS (
+	SELECT 1 FROM stories WHERE stories.object_key = blobs.key AND (stories.deleted_at IS NULL OR stories.deleted_at > ?))`
+
+type BlobQuery interface {
+	// AcquireBlob creates the blob or takes another reference to it and
+	// returns the stored row, the object exists only when it is Uploaded.
+	AcquireBlob(ctx context.Context, blob model.Blob) (model.Blob, error)
+	// MarkBlobUploaded records that the object of key was stored.
+	MarkBlobUploaded(ctx context.Context, key string) error
+	ReleaseBlob(ctx context.Context, key string) error
+
+	// garbage collection
+	GetUnreferencedBlobs(ctx context.Context, cutoff time.Time, afterID uint64, limit int) ([]model.Blob, error)
+	DeleteUnreferencedBlob(ctx context.Context, id uint64, cutoff time.Time, deleteObjects func(blob model.Blob) error) (bool, error)
+}
+
+type blobQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewBlobQuery(db infrastructure.GormPostgres) BlobQuery {
+	return &blobQueryImpl{db: db}
+}
+
+func (u *blobQueryImpl) AcquireBlob(ctx context.Context, blob model.Blob) (model.Blob, error) {
+	db := u.db.GetConnection()
+	blob.RefCount = 1
+	if err := db.
+		WithContext(ctx).
+		Table("blobs").
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"ref_count":  gorm.Expr("blobs.ref_count + 1"),
+				"updated_at": time.Now(),
+			}),
+		}, clause.Returning{}).
+		Create(&blob).Error; err != nil {
+		return model.Blob{}, err
+	}
+	return blob, nil
+}
+
+func (u *blobQueryImpl) MarkBlobUploaded(ctx context.Context, key string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("blobs").
+		Where("key = ?", key).
+		Update("uploaded", true).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReleaseBlob drops a reference, the object stays until the garbage
+// collector finds it unreferenced past the grace period.
+func (u *blobQueryImpl) ReleaseBlob(ctx context.Context, key string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("blobs").
+		Where("key = ? AND ref_count > 0", key).
+		Updates(map[string]any{
+			"ref_count":  gorm.Expr("ref_count - 1"),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *blobQueryImpl) GetUnreferencedBlobs(ctx context.Context, cutoff time.Time, afterID uint64, limit int) ([]model.Blob, error) {
+	db := u.db.GetConnection()
+	blobs := []model.Blob{}
+	if err := db.
+		WithContext(ctx).
+		Table("blobs").
+		Where("id > ?", afterID).
+		Where(unreferencedBlob, cutoff, cutoff, cutoff, cutoff).
+		Order("id").
+		Limit(limit).
+		Find(&blobs).Error; err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+// DeleteUnreferencedBlob locks the blob if it is still unreferenced, calls
+// deleteObjects and removes the row. An upload of the same content waits
+// on the lock and stores the object again once the row is gone.
+func (u *blobQueryImpl) DeleteUnreferencedBlob(ctx context.Context, id uint64, cutoff time.Time, deleteObjects func(blob model.Blob) error) (bool, error) {
+	db := u.db.GetConnection()
+	deleted := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		blob := model.Blob{}
+		if err := tx.
+			Table("blobs").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			Where(unreferencedBlob, cutoff, cutoff, cutoff, cutoff).
+			Find(&blob).Error; err != nil {
+			return err
+		}
+		if blob.ID == 0 {
+			return nil
+		}
+		if err := deleteObjects(blob); err != nil {
+			return err
+		}
+		deleted = true
+		return tx.
+			Table("blobs").
+			Where("id = ?", id).
+			Delete(&model.Blob{}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireBlob(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT ("key") DO UPDATE SET "ref_count"=blobs.ref_count + 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key", "ref_count"}).AddRow(4, "blobs/aa/aa.png", 3))
+	mock.ExpectCommit()
+
+	blobRepo := blobQueryImpl{db: postgresMock}
+	res, err := blobRepo.AcquireBlob(context.Background(), model.Blob{Key: "blobs/aa/aa.png", Size: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), res.ID)
+	assert.Equal(t, 3, res.RefCount)
+}
+
+func TestMarkBlobUploaded(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "blobs" SET "uploaded"=$1 WHERE key = $2`)).
+		WithArgs(true, "blobs/aa/aa.png").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	blobRepo := blobQueryImpl{db: postgresMock}
+	err := blobRepo.MarkBlobUploaded(context.Background(), "blobs/aa/aa.png")
+	assert.Nil(t, err)
+}
+
+func TestGetUnreferencedBlobs(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+	cutoff := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`NOT EXISTS (
+	SELECT 1 FROM photos WHERE photos.object_key = blobs.key AND (photos.deleted_at IS NULL OR photos.deleted_at > $3)) AND NOT EXISTS (
+	SELECT 1 FROM media_items JOIN photos ON photos.id = media_items.photo_id
+	WHERE media_items.object_key = blobs.key AND (photos.deleted_at IS NULL OR photos.deleted_at > $4)) AND NOT EXISTS (
+	SELECT 1 FROM stories WHERE stories.object_key = blobs.key AND (stories.deleted_at IS NULL OR stories.deleted_at > $5))`)).
+		WithArgs(7, cutoff, cutoff, cutoff, cutoff, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key"}).AddRow(8, "blobs/aa/aa.png"))
+
+	blobRepo := blobQueryImpl{db: postgresMock}
+	res, err := blobRepo.GetUnreferencedBlobs(context.Background(), cutoff, 7, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+type BlockQuery interface {
+	IsBlocked(ctx context.Context, userID uint64, otherID uint64) (bool, error)
+	GetBlocks(ctx context.Context, blockerID uint64, cursor uint64, limit int) ([]model.Block, error)
+	GetMutes(ctx context.Context, muterID uint64, cursor uint64, limit int) ([]model.Mute, error)
+
+	CreateBlock(ctx context.Context, block model.Block) (model.Block, error)
+	DeleteBlock(ctx context.Context, blockerID uint64, blockedID uint64) error
+	CreateMute(ctx context.Context, mute model.Mute) (model.Mute, error)
+	DeleteMute(ctx context.Context, muterID uint64, mutedID uint64) error
+}
+
+type blockQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewBlockQuery(db infrastructure.GormPostgres) BlockQuery {
+	return &blockQueryImpl{db: db}
+}
+
+// IsBlocked reports whether either user has blocked the other.
+func (u *blockQueryImpl) IsBlocked(ctx context.Context, userID uint64, otherID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Table("blocks").
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (u *blockQueryImpl) GetBlocks(ctx context.Context, blockerID uint64, cursor uint64, limit int) ([]model.Block, error) {
+	db := u.db.GetConnection()
+	blocks := []model.Block{}
+	query := db.
+		WithContext(ctx).
+		Table("blocks").
+		Where("blocker_id = ?", blockerID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Blocked", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (u *blockQueryImpl) GetMutes(ctx context.Context, muterID uint64, cursor uint64, limit int) ([]model.Mute, error) {
+	db := u.db.GetConnection()
+	mutes := []model.Mute{}
+	query := db.
+		WithContext(ctx).
+		Table("mutes").
+		Where("muter_id = ?", muterID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Muted", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&mutes).Error; err != nil {
+		return nil, err
+	}
+	return mutes, nil
+}
+
+// CreateBlock stores the block and severs any follow edge (or pending
+// request) between the two users in the same transaction.
+func (u *blockQueryImpl) CreateBlock(ctx context.Context, block model.Block) (model.Block, error) {
+	db := u.db.GetConnection()
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Table("follows").
+			Where("(follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)",
+				block.BlockerID, block.BlockedID, block.BlockedID, block.BlockerID).
+			Delete(&model.Follow{}).Error; err != nil {
+			return err
+		}
+		return tx.
+			Table("blocks").
+			Where(model.Block{BlockerID: block.BlockerID, BlockedID: block.BlockedID}).
+			FirstOrCreate(&block).Error
+	})
+	if err != nil {
+		return model.Block{}, err
+	}
+	return block, nil
+}
+
+func (u *blockQueryImpl) DeleteBlock(ctx context.Context, blockerID uint64, blockedID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("blocks").
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&model.Block{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *blockQueryImpl) CreateMute(ctx context.Context, mute model.Mute) (model.Mute, error) {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("mutes").
+		Where(model.Mute{MuterID: mute.MuterID, MutedID: mute.MutedID}).
+		FirstOrCreate(&mute).Error; err != nil {
+		return model.Mute{}, err
+	}
+	return mute, nil
+}
+
+func (u *blockQueryImpl) DeleteMute(ctx context.Context, muterID uint64, mutedID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("mutes").
+		Where("muter_id = ? AND muted_id = ?", muterID, mutedID).
+		Delete(&model.Mute{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
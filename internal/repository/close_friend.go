@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+type CloseFriendQuery interface {
+	GetCloseFriends(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.CloseFriend, error)
+
+	// CreateCloseFriend and DeleteCloseFriend are idempotent.
+	CreateCloseFriend(ctx context.Context, closeFriend model.CloseFriend) (model.CloseFriend, error)
+	DeleteCloseFriend(ctx context.Context, userID uint64, friendID uint64) error
+}
+
+type closeFriendQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewCloseFriendQuery(db infrastructure.GormPostgres) CloseFriendQuery {
+	return &closeFriendQueryImpl{db: db}
+}
+
+func (u *closeFriendQueryImpl) GetCloseFriends(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.CloseFriend, error) {
+	db := u.db.GetConnection()
+	closeFriends := []model.CloseFriend{}
+	query := db.
+		WithContext(ctx).
+		Table("close_friends").
+		Where("user_id = ?", userID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Friend", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&closeFriends).Error; err != nil {
+		return nil, err
+	}
+	return closeFriends, nil
+}
+
+func (u *closeFriendQueryImpl) CreateCloseFriend(ctx context.Context, closeFriend model.CloseFriend) (model.CloseFriend, error) {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("close_friends").
+		Where(model.CloseFriend{UserID: closeFriend.UserID, FriendID: closeFriend.FriendID}).
+		FirstOrCreate(&closeFriend).Error; err != nil {
+		return model.CloseFriend{}, err
+	}
+	return closeFriend, nil
+}
+
+func (u *closeFriendQueryImpl) DeleteCloseFriend(ctx context.Context, userID uint64, friendID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("close_friends").
+		Where("user_id = ? AND friend_id = ?", userID, friendID).
+		Delete(&model.CloseFriend{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
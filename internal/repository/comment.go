@@ -6,14 +6,29 @@ import (
 	"github.com/MidnightHelix/MyGram/internal/infrastructure"
 	"github.com/MidnightHelix/MyGram/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CommentQuery interface {
+	// GetComments lists the comments of userID on photos they may still
+	// see, leaving out those on photos of authors they muted.
 	GetComments(ctx context.Context, userID uint64) ([]model.Comment, error)
 	GetCommentsByID(ctx context.Context, id uint64) (model.Comment, error)
+	// GetPhotoComments lists the comments of a photo oldest first with
+	// their authors, leaving out authors blocked from or by the viewer and
+	// sanctioned ones. cursor is the id of the last comment of the previous
+	// page, 0 starts from the beginning.
+	GetPhotoComments(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Comment, error)
 
+	// CreateComment and DeleteComment keep the photo's comment_count in
+	// step.
 	CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error)
-	EditComment(ctx context.Context, comment model.Comment, id uint64) (model.Comment, error)
+	// EditComment replaces the message of a comment, a changed one is
+	// recorded as a revision by editorID.
+	EditComment(ctx context.Context, comment model.Comment, id uint64, editorID uint64) (model.Comment, error)
+	// GetCommentRevisions lists the earlier messages of a comment newest
+	// first.
+	GetCommentRevisions(ctx context.Context, id uint64, cursor uint64, limit int) ([]model.Revision, error)
 	DeleteComment(ctx context.Context, id uint64) error
 }
 
@@ -36,12 +51,12 @@ func (u *commentQueryImpl) GetComments(ctx context.Context, userID uint64) ([]mo
 		WithContext(ctx).
 		Table("comments").
 		Where("user_id = ?", userID).
+		Where("photo_id IN (?)", visiblePhotoIDs(db, userID).Scopes(notMuted(userID, "photos.user_id"))).
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("ID", "Username", "Email")
 		}).
-		Preload("Photo", func(db *gorm.DB) *gorm.DB {
-			return db.Select("ID", "Title", "Caption", "Url", "UserID")
-		}).
+		Preload("Photo").
+		Preload("Mentions").
 		//Preload("User").
 		Find(&photos).Error; err != nil {
 		return nil, err
@@ -62,37 +77,101 @@ func (u *commentQueryImpl) GetCommentsByID(ctx context.Context, id uint64) (mode
 	return comment, nil
 }
 
-func (u *commentQueryImpl) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+func (u *commentQueryImpl) GetPhotoComments(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Comment, error) {
 	db := u.db.GetConnection()
-	if err := db.
+	comments := []model.Comment{}
+	query := db.
 		WithContext(ctx).
 		Table("comments").
-		Save(&comment).Error; err != nil {
+		Where("comments.photo_id = ? AND comments.deleted_at IS NULL", photoID).
+		Scopes(notBlocked(viewerID, "comments.user_id"), activeUsers("comments.user_id"))
+	if cursor > 0 {
+		query = query.Where("comments.id > ?", cursor)
+	}
+	if err := query.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username", "DisplayName")
+		}).
+		Preload("Mentions").
+		Order("comments.id").
+		Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (u *commentQueryImpl) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	db := u.db.GetConnection()
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("comments").Save(&comment).Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE photos SET comment_count = comment_count + 1 WHERE id = ?", comment.PhotoID).Error
+	})
+	if err != nil {
 		return model.Comment{}, err
 	}
 	return comment, nil
 }
 
-func (u *commentQueryImpl) EditComment(ctx context.Context, comment model.Comment, id uint64) (model.Comment, error) {
+// EditComment keeps the message the comment had as a revision when it
+// changes, see photoQueryImpl.EditPhoto.
+func (u *commentQueryImpl) EditComment(ctx context.Context, comment model.Comment, id uint64, editorID uint64) (model.Comment, error) {
 	db := u.db.GetConnection()
-	if err := db.
-		WithContext(ctx).
-		Table("comments").
-		Where("id = ?", id).
-		Select("message").
-		Updates(model.Comment{Message: comment.Message}).Error; err != nil {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		previous := model.Comment{}
+		if err := tx.
+			Table("comments").
+			Select("id", "message").
+			Where("id = ?", id).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&previous).Error; err != nil {
+			return err
+		}
+		if previous.ID == 0 || previous.Message == comment.Message {
+			return nil
+		}
+
+		revision := model.Revision{
+			SourceType: model.RevisionSourceComment,
+			SourceID:   id,
+			EditorID:   editorID,
+			Text:       previous.Message,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		comment.EditedAt = &revision.CreatedAt
+		return tx.
+			Table("comments").
+			Where("id = ?", id).
+			Select("message", "edited_at").
+			Updates(model.Comment{Message: comment.Message, EditedAt: comment.EditedAt}).Error
+	})
+	if err != nil {
 		return model.Comment{}, err
 	}
 	return comment, nil
 }
 
+func (u *commentQueryImpl) GetCommentRevisions(ctx context.Context, id uint64, cursor uint64, limit int) ([]model.Revision, error) {
+	db := u.db.GetConnection()
+	return getRevisions(db.WithContext(ctx), model.RevisionSourceComment, id, cursor, limit)
+}
+
+// DeleteComment only lowers the count when the comment was still there, of
+// two concurrent deletes only one does.
 func (u *commentQueryImpl) DeleteComment(ctx context.Context, id uint64) error {
 	db := u.db.GetConnection()
-	if err := db.
-		WithContext(ctx).
-		Table("comments").
-		Where("id = ?", id).Delete(&model.Comment{}).Error; err != nil {
-		return err
-	}
-	return nil
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Table("comments").Where("id = ?", id).Delete(&model.Comment{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Exec("UPDATE photos SET comment_count = GREATEST(comment_count - 1, 0) WHERE id = (SELECT photo_id FROM comments WHERE id = ?)", id).Error
+	})
 }
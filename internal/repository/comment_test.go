@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPhotoComments(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "comments" WHERE (comments.photo_id = $1 AND comments.deleted_at IS NULL) AND comments.id > $2 `+
+		`AND comments.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $3) `+
+		`AND comments.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $4) `+
+		`AND (comments.user_id NOT IN (SELECT id FROM users WHERE banned_at IS NOT NULL OR suspended_until > $5)) `+
+		`AND "comments"."deleted_at" IS NULL ORDER BY comments.id LIMIT $6`)).
+		WithArgs(7, 10, 1, 1, sqlmock.AnyArg(), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id", "user_id", "message"}).AddRow(11, 7, 2, "nice"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","username","display_name" FROM "users" WHERE "users"."id" = $1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "display_name"}).AddRow(2, "bob", "Bob"))
+
+	commentRepo := commentQueryImpl{db: postgresMock}
+	res, err := commentRepo.GetPhotoComments(context.Background(), 1, 7, 10, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, "bob", res[0].User.Username)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateComment(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "comments"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE photos SET comment_count = comment_count + 1 WHERE id = $1`)).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	commentRepo := commentQueryImpl{db: postgresMock}
+	res, err := commentRepo.CreateComment(context.Background(), model.Comment{PhotoID: 7, UserID: 1, Message: "nice"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(11), res.ID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteComment(t *testing.T) {
+	t.Run("delete lowers the count", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "comments" SET "deleted_at"=$1 WHERE id = $2 AND "comments"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), 11).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE photos SET comment_count = GREATEST(comment_count - 1, 0) WHERE id = (SELECT photo_id FROM comments WHERE id = $1)`)).
+			WithArgs(11).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		commentRepo := commentQueryImpl{db: postgresMock}
+		assert.Nil(t, commentRepo.DeleteComment(context.Background(), 11))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("repeated delete leaves the count", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "comments" SET "deleted_at"=$1`)).
+			WithArgs(sqlmock.AnyArg(), 11).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		commentRepo := commentQueryImpl{db: postgresMock}
+		assert.Nil(t, commentRepo.DeleteComment(context.Background(), 11))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestEditComment(t *testing.T) {
+	t.Run("changed message keeps a revision", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","message" FROM "comments" WHERE id = $1 AND "comments"."deleted_at" IS NULL FOR UPDATE`)).
+			WithArgs(11).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "message"}).AddRow(11, "nice"))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "revisions" ("source_type","source_id","editor_id","title","text","created_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+			WithArgs("comments", 11, 1, "", "nice", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "comments" SET "message"=$1,"updated_at"=$2,"edited_at"=$3 WHERE id = $4`)).
+			WithArgs("very nice", sqlmock.AnyArg(), sqlmock.AnyArg(), 11).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		commentRepo := commentQueryImpl{db: postgresMock}
+		res, err := commentRepo.EditComment(context.Background(), model.Comment{Message: "very nice"}, 11, 1)
+		assert.Nil(t, err)
+		assert.NotNil(t, res.EditedAt)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("same message leaves the comment", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","message" FROM "comments"`)).
+			WithArgs(11).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "message"}).AddRow(11, "nice"))
+		mock.ExpectCommit()
+
+		commentRepo := commentQueryImpl{db: postgresMock}
+		res, err := commentRepo.EditComment(context.Background(), model.Comment{Message: "nice"}, 11, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, res.EditedAt)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetCommentRevisions(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "revisions" WHERE (source_type = $1 AND source_id = $2) AND id < $3 ORDER BY id DESC LIMIT $4`)).
+		WithArgs("comments", 11, 9, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source_type", "source_id", "editor_id", "text"}).AddRow(8, "comments", 11, 1, "nice"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","username","display_name" FROM "users" WHERE "users"."id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "display_name"}).AddRow(1, "ann", "Ann"))
+
+	commentRepo := commentQueryImpl{db: postgresMock}
+	res, err := commentRepo.GetCommentRevisions(context.Background(), 11, 9, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, "ann", res[0].Editor.Username)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
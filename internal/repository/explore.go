@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+// ExploreWeights configures how engagement is turned into an explore
+// score: the weighted engagement plus one, divided by the photo's age in
+// hours plus two raised to Gravity. A higher Gravity makes photos fall off
+// faster.
+type ExploreWeights struct {
+	Comments float64
+	Likes    float64
+	Saves    float64
+	Gravity  float64
+}
+
+// ExplorePage selects a page of the explore ranking below the keyset of the
+// previous page, AfterID is zero on the first page. Authors contribute at
+// most MaxPerAuthor photos to the whole ranking.
+type ExplorePage struct {
+	AfterScore   float64
+	AfterID      uint64
+	MaxPerAuthor int
+	Limit        int
+}
+
+type ExploreQuery interface {
+	// RecomputeScores replaces every explore score with fresh ones for the
+	// public photos posted since since, as of now.
+	RecomputeScores(ctx context.Context, weights ExploreWeights, since time.Time, now time.Time) error
+	GetExplore(ctx context.Context, viewerID uint64, page ExplorePage) ([]model.ExploreScore, error)
+}
+
+type exploreQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewExploreQuery(db infrastructure.GormPostgres) ExploreQuery {
+	return &exploreQueryImpl{db: db}
+}
+
+func (u *exploreQueryImpl) RecomputeScores(ctx context.Context, weights ExploreWeights, since time.Time, now time.Time) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// engagement by the author themselves does not count
+		scored := tx.
+			Table("photos").
+			Select(`photos.id AS photo_id, photos.user_id,
+				(1 + ? * (SELECT COUNT(*) FROM comments WHERE comments.photo_id = photos.id AND comments.user_id <> photos.user_id AND comments.deleted_at IS NULL)
+				+ ? * (SELECT COUNT(*) FROM likes WHERE likes.photo_id = photos.id AND likes.user_id <> photos.user_id)
+				+ ? * (SELECT COUNT(*) FROM saves WHERE saves.photo_id = photos.id AND saves.user_id <> photos.user_id))
+				/ POWER(GREATEST(EXTRACT(EPOCH FROM ? - photos.created_at), 0) / 3600 + 2, ?) AS score`,
+				weights.Comments, weights.Likes, weights.Saves, now, weights.Gravity).
+			Where("photos.deleted_at IS NULL AND photos.status = ? AND photos.visibility = ? AND photos.created_at > ?",
+				model.PhotoStatusReady, model.PhotoVisibilityPublic, since).
+			Where("photos.user_id IN (SELECT id FROM users WHERE NOT is_private AND deleted_at IS NULL)").
+			Scopes(activeUsers("photos.user_id"))
+
+		if err := tx.Exec("DELETE FROM explore_scores").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`INSERT INTO explore_scores (photo_id, user_id, score, author_rank, computed_at)
+			SELECT photo_id, user_id, score, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY score DESC, photo_id DESC), ?
+			FROM (?) AS scored`, now, scored).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// GetExplore reads scores highest first. Photos deleted or no longer visible
+// to the viewer and authors muted since the last recompute are left out.
+func (u *exploreQueryImpl) GetExplore(ctx context.Context, viewerID uint64, page ExplorePage) ([]model.ExploreScore, error) {
+	db := u.db.GetConnection()
+	scores := []model.ExploreScore{}
+	query := db.
+		WithContext(ctx).
+		Table("explore_scores").
+		Where("explore_scores.author_rank <= ? AND explore_scores.user_id <> ?", page.MaxPerAuthor, viewerID).
+		Where("explore_scores.photo_id IN (?)", visiblePhotoIDs(db, viewerID)).
+		Scopes(notMuted(viewerID, "explore_scores.user_id"))
+	if page.AfterID > 0 {
+		query = query.Where("(explore_scores.score, explore_scores.photo_id) < (?, ?)", page.AfterScore, page.AfterID)
+	}
+	if err := query.
+		Preload("Photo").
+		Preload("Photo.User").
+		Preload("Photo.Media", mediaInOrder).
+		Preload("Photo.Variants").
+		Preload("Photo.Mentions").
+		Order("explore_scores.score DESC, explore_scores.photo_id DESC").
+		Limit(page.Limit).
+		Find(&scores).Error; err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecomputeScores(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+	now := time.Now()
+	since := now.Add(-72 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM explore_scores`)).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO explore_scores (photo_id, user_id, score, author_rank, computed_at)`)+".*"+regexp.QuoteMeta(`FROM (SELECT photos.id AS photo_id`)+
+		// every save by someone else adds the save weight to the score
+		".*"+regexp.QuoteMeta(`+ $4 * (SELECT COUNT(*) FROM saves WHERE saves.photo_id = photos.id AND saves.user_id <> photos.user_id))`)).
+		WithArgs(now, 2.0, 1.0, 3.0, now, 1.5, "ready", "public", since, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	exploreRepo := exploreQueryImpl{db: postgresMock}
+	err := exploreRepo.RecomputeScores(context.Background(), ExploreWeights{Comments: 2, Likes: 1, Saves: 3, Gravity: 1.5}, since, now)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExplore(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "explore_scores" WHERE (explore_scores.author_rank <= $1 AND explore_scores.user_id <> $2) AND explore_scores.photo_id IN (SELECT photos.id FROM "photos" WHERE photos.deleted_at IS NULL AND (photos.user_id = $3 OR`)).
+		WithArgs(3, 1, 1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg(), 0.5, 40, 1, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"photo_id", "user_id", "score"}).AddRow(9, 2, 0.4))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(9, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	exploreRepo := exploreQueryImpl{db: postgresMock}
+	res, err := exploreRepo.GetExplore(context.Background(), 1, ExplorePage{AfterScore: 0.5, AfterID: 40, MaxPerAuthor: 3, Limit: 20})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, uint64(2), res[0].Photo.User.ID)
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowQuery interface {
+	GetFollow(ctx context.Context, followerID uint64, followingID uint64) (model.Follow, error)
+	GetFollowByID(ctx context.Context, id uint64) (model.Follow, error)
+	GetFollowers(ctx context.Context, viewerID uint64, userID uint64, status string, cursor uint64, limit int) ([]model.Follow, error)
+	GetFollowing(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error)
+	CountFollowers(ctx context.Context, userID uint64) (int64, error)
+	CountFollowing(ctx context.Context, userID uint64) (int64, error)
+
+	// CreateFollow reports whether the edge was inserted, when the pair
+	// already has one that edge is returned instead.
+	CreateFollow(ctx context.Context, follow model.Follow) (model.Follow, bool, error)
+	UpdateFollowStatus(ctx context.Context, id uint64, status string) error
+	AcceptFollowRequests(ctx context.Context, userID uint64) ([]model.Follow, error)
+	DeleteFollow(ctx context.Context, followerID uint64, followingID uint64) error
+}
+
+type followQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewFollowQuery(db infrastructure.GormPostgres) FollowQuery {
+	return &followQueryImpl{db: db}
+}
+
+func (u *followQueryImpl) GetFollow(ctx context.Context, followerID uint64, followingID uint64) (model.Follow, error) {
+	db := u.db.GetConnection()
+	follow := model.Follow{}
+	if err := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("follower_id = ? AND following_id = ?", followerID, followingID).
+		Find(&follow).Error; err != nil {
+		return model.Follow{}, err
+	}
+	return follow, nil
+}
+
+func (u *followQueryImpl) GetFollowByID(ctx context.Context, id uint64) (model.Follow, error) {
+	db := u.db.GetConnection()
+	follow := model.Follow{}
+	if err := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("id = ?", id).
+		Find(&follow).Error; err != nil {
+		return model.Follow{}, err
+	}
+	return follow, nil
+}
+
+// GetFollowers returns follows pointing at userID, newest first, leaving out
+// users blocked from or by the viewer. cursor is the id of the last row of the
+// previous page, 0 starts from the beginning.
+func (u *followQueryImpl) GetFollowers(ctx context.Context, viewerID uint64, userID uint64, status string, cursor uint64, limit int) ([]model.Follow, error) {
+	db := u.db.GetConnection()
+	follows := []model.Follow{}
+	query := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("following_id = ? AND status = ?", userID, status).
+		Scopes(notBlocked(viewerID, "follower_id"), activeUsers("follower_id"))
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Follower", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username", "IsPrivate")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&follows).Error; err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+// GetFollowing returns accepted follows made by userID, newest first.
+func (u *followQueryImpl) GetFollowing(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error) {
+	db := u.db.GetConnection()
+	follows := []model.Follow{}
+	query := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("follower_id = ? AND status = ?", userID, model.FollowStatusAccepted).
+		Scopes(notBlocked(viewerID, "following_id"), activeUsers("following_id"))
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Following", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username", "IsPrivate")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&follows).Error; err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+func (u *followQueryImpl) CountFollowers(ctx context.Context, userID uint64) (int64, error) {
+	db := u.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("following_id = ? AND status = ?", userID, model.FollowStatusAccepted).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (u *followQueryImpl) CountFollowing(ctx context.Context, userID uint64) (int64, error) {
+	db := u.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("follower_id = ? AND status = ?", userID, model.FollowStatusAccepted).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateFollow relies on the unique pair, of two concurrent follows only one
+// inserts a row and the other reads it back.
+func (u *followQueryImpl) CreateFollow(ctx context.Context, follow model.Follow) (model.Follow, bool, error) {
+	db := u.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("follows").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "follower_id"}, {Name: "following_id"}},
+			DoNothing: true,
+		}).
+		Create(&follow)
+	if res.Error != nil {
+		return model.Follow{}, false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return follow, true, nil
+	}
+	existing, err := u.GetFollow(ctx, follow.FollowerID, follow.FollowingID)
+	if err != nil {
+		return model.Follow{}, false, err
+	}
+	return existing, false, nil
+}
+
+func (u *followQueryImpl) UpdateFollowStatus(ctx context.Context, id uint64, status string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("id = ?", id).
+		Update("status", status).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// AcceptFollowRequests accepts every pending request to userID at once and
+// returns the accepted edges.
+func (u *followQueryImpl) AcceptFollowRequests(ctx context.Context, userID uint64) ([]model.Follow, error) {
+	db := u.db.GetConnection()
+	follows := []model.Follow{}
+	if err := db.
+		WithContext(ctx).
+		Model(&follows).
+		Clauses(clause.Returning{}).
+		Where("following_id = ? AND status = ?", userID, model.FollowStatusPending).
+		Update("status", model.FollowStatusAccepted).Error; err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+func (u *followQueryImpl) DeleteFollow(ctx context.Context, followerID uint64, followingID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("follows").
+		Where("follower_id = ? AND following_id = ?", followerID, followingID).
+		Delete(&model.Follow{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFollow(t *testing.T) {
+	insert := regexp.QuoteMeta(`INSERT INTO "follows" ("follower_id","following_id","status","created_at","updated_at") ` +
+		`VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("follower_id","following_id") DO NOTHING RETURNING "id"`)
+
+	t.Run("new follow is inserted", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insert).
+			WithArgs(1, 2, model.FollowStatusAccepted, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectCommit()
+
+		followRepo := followQueryImpl{db: postgresMock}
+		res, created, err := followRepo.CreateFollow(context.Background(), model.Follow{FollowerID: 1, FollowingID: 2, Status: model.FollowStatusAccepted})
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.Equal(t, uint64(10), res.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("concurrent follow reads the existing row", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insert).
+			WithArgs(1, 2, model.FollowStatusPending, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "follows" WHERE follower_id = $1 AND following_id = $2`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "follower_id", "following_id", "status"}).
+				AddRow(5, 1, 2, model.FollowStatusPending))
+
+		followRepo := followQueryImpl{db: postgresMock}
+		res, created, err := followRepo.CreateFollow(context.Background(), model.Follow{FollowerID: 1, FollowingID: 2, Status: model.FollowStatusPending})
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, uint64(5), res.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAcceptFollowRequests(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "follows" SET "status"=$1,"updated_at"=$2 WHERE following_id = $3 AND status = $4 RETURNING *`)).
+		WithArgs(model.FollowStatusAccepted, sqlmock.AnyArg(), 2, model.FollowStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "follower_id", "following_id", "status"}).
+			AddRow(3, 4, 2, model.FollowStatusAccepted))
+	mock.ExpectCommit()
+
+	followRepo := followQueryImpl{db: postgresMock}
+	res, err := followRepo.AcceptFollowRequests(context.Background(), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, uint64(4), res[0].FollowerID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+type LikeQuery interface {
+	GetLikes(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Like, error)
+	// GetLikedPhotoIDs returns which of photoIDs the user has liked.
+	GetLikedPhotoIDs(ctx context.Context, userID uint64, photoIDs []uint64) ([]uint64, error)
+
+	// CreateLike and DeleteLike report whether a like was actually added or
+	// removed, the photo's like_count only moves when it was.
+	CreateLike(ctx context.Context, userID uint64, photoID uint64) (bool, error)
+	DeleteLike(ctx context.Context, userID uint64, photoID uint64) (bool, error)
+}
+
+type likeQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewLikeQuery(db infrastructure.GormPostgres) LikeQuery {
+	return &likeQueryImpl{db: db}
+}
+
+// GetLikes returns the likes of a photo, newest first, leaving out users
+// blocked from or by the viewer. cursor is the id of the last like of the
+// previous page, 0 starts from the beginning.
+func (u *likeQueryImpl) GetLikes(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Like, error) {
+	db := u.db.GetConnection()
+	likes := []model.Like{}
+	query := db.
+		WithContext(ctx).
+		Table("likes").
+		Where("photo_id = ?", photoID).
+		Scopes(notBlocked(viewerID, "user_id"), activeUsers("user_id"))
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username", "IsPrivate")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&likes).Error; err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
+
+func (u *likeQueryImpl) GetLikedPhotoIDs(ctx context.Context, userID uint64, photoIDs []uint64) ([]uint64, error) {
+	ids := []uint64{}
+	if len(photoIDs) == 0 {
+		return ids, nil
+	}
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("likes").
+		Where("user_id = ? AND photo_id IN ?", userID, photoIDs).
+		Pluck("photo_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CreateLike relies on the unique pair, of two concurrent likes only one
+// inserts a row and bumps the count.
+func (u *likeQueryImpl) CreateLike(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	created := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec("INSERT INTO likes (user_id, photo_id, created_at) VALUES (?, ?, NOW()) ON CONFLICT (user_id, photo_id) DO NOTHING", userID, photoID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return tx.Exec("UPDATE photos SET like_count = like_count + 1 WHERE id = ?", photoID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+func (u *likeQueryImpl) DeleteLike(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	deleted := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec("DELETE FROM likes WHERE user_id = ? AND photo_id = ?", userID, photoID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		deleted = true
+		return tx.Exec("UPDATE photos SET like_count = GREATEST(like_count - 1, 0) WHERE id = ?", photoID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLike(t *testing.T) {
+	t.Run("new like bumps the count", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO likes (user_id, photo_id, created_at) VALUES ($1, $2, NOW()) ON CONFLICT (user_id, photo_id) DO NOTHING`)).
+			WithArgs(1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE photos SET like_count = like_count + 1 WHERE id = $1`)).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		likeRepo := likeQueryImpl{db: postgresMock}
+		created, err := likeRepo.CreateLike(context.Background(), 1, 7)
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("repeated like leaves the count", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO likes`)).
+			WithArgs(1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		likeRepo := likeQueryImpl{db: postgresMock}
+		created, err := likeRepo.CreateLike(context.Background(), 1, 7)
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteLike(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM likes WHERE user_id = $1 AND photo_id = $2`)).
+		WithArgs(1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE photos SET like_count = GREATEST(like_count - 1, 0) WHERE id = $1`)).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	likeRepo := likeQueryImpl{db: postgresMock}
+	deleted, err := likeRepo.DeleteLike(context.Background(), 1, 7)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLikedPhotoIDs(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "photo_id" FROM "likes" WHERE user_id = $1 AND photo_id IN ($2,$3)`)).
+		WithArgs(1, 7, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"photo_id"}).AddRow(8))
+
+	likeRepo := likeQueryImpl{db: postgresMock}
+	res, err := likeRepo.GetLikedPhotoIDs(context.Background(), 1, []uint64{7, 8})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{8}, res)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+type MentionQuery interface {
+	// ReplaceMentions makes mentions the only ones of a photo or comment and
+	// returns the users it mentioned before, so only new ones get notified.
+	ReplaceMentions(ctx context.Context, sourceType string, sourceID uint64, mentions []model.Mention) ([]uint64, error)
+}
+
+type mentionQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewMentionQuery(db infrastructure.GormPostgres) MentionQuery {
+	return &mentionQueryImpl{db: db}
+}
+
+func (u *mentionQueryImpl) ReplaceMentions(ctx context.Context, sourceType string, sourceID uint64, mentions []model.Mention) ([]uint64, error) {
+	db := u.db.GetConnection()
+	previous := []uint64{}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Table("mentions").
+			Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+			Pluck("user_id", &previous).Error; err != nil {
+			return err
+		}
+		if err := tx.
+			Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+			Delete(&model.Mention{}).Error; err != nil {
+			return err
+		}
+		if len(mentions) == 0 {
+			return nil
+		}
+		for i := range mentions {
+			mentions[i].SourceType = sourceType
+			mentions[i].SourceID = sourceID
+		}
+		return tx.Create(&mentions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceMentions(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "user_id" FROM "mentions" WHERE source_type = $1 AND source_id = $2`)).
+		WithArgs(model.MentionSourceComment, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "mentions" WHERE source_type = $1 AND source_id = $2`)).
+		WithArgs(model.MentionSourceComment, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "mentions" ("source_type","source_id","user_id","text_offset","length","created_at")`)).
+		WithArgs(model.MentionSourceComment, 3, 6, 0, 4, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	mentionRepo := mentionQueryImpl{db: postgresMock}
+	previous, err := mentionRepo.ReplaceMentions(context.Background(), model.MentionSourceComment, 3, []model.Mention{{UserID: 6, Offset: 0, Length: 4}})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{5}, previous)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,415 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AlbumQuery is an autogenerated mock type for the AlbumQuery type
+type AlbumQuery struct {
+	mock.Mock
+}
+
+// AddAlbumPhoto provides a mock function with given fields: ctx, albumID, photoID, addedBy
+func (_m *AlbumQuery) AddAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64, addedBy uint64) (bool, error) {
+	ret := _m.Called(ctx, albumID, photoID, addedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAlbumPhoto")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, albumID, photoID, addedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) bool); ok {
+		r0 = rf(ctx, albumID, photoID, addedBy)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64) error); ok {
+		r1 = rf(ctx, albumID, photoID, addedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddCollaborator provides a mock function with given fields: ctx, albumID, userID
+func (_m *AlbumQuery) AddCollaborator(ctx context.Context, albumID uint64, userID uint64) error {
+	ret := _m.Called(ctx, albumID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCollaborator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, albumID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateAlbum provides a mock function with given fields: ctx, album
+func (_m *AlbumQuery) CreateAlbum(ctx context.Context, album model.Album) (model.Album, error) {
+	ret := _m.Called(ctx, album)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAlbum")
+	}
+
+	var r0 model.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Album) (model.Album, error)); ok {
+		return rf(ctx, album)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Album) model.Album); ok {
+		r0 = rf(ctx, album)
+	} else {
+		r0 = ret.Get(0).(model.Album)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Album) error); ok {
+		r1 = rf(ctx, album)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAlbum provides a mock function with given fields: ctx, id
+func (_m *AlbumQuery) DeleteAlbum(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAlbum")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAlbum provides a mock function with given fields: ctx, viewerID, id
+func (_m *AlbumQuery) GetAlbum(ctx context.Context, viewerID uint64, id uint64) (model.Album, error) {
+	ret := _m.Called(ctx, viewerID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbum")
+	}
+
+	var r0 model.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.Album, error)); ok {
+		return rf(ctx, viewerID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.Album); ok {
+		r0 = rf(ctx, viewerID, id)
+	} else {
+		r0 = ret.Get(0).(model.Album)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, viewerID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlbumByID provides a mock function with given fields: ctx, id
+func (_m *AlbumQuery) GetAlbumByID(ctx context.Context, id uint64) (model.Album, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbumByID")
+	}
+
+	var r0 model.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.Album, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.Album); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.Album)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlbumPhoto provides a mock function with given fields: ctx, albumID, photoID
+func (_m *AlbumQuery) GetAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64) (model.AlbumPhoto, error) {
+	ret := _m.Called(ctx, albumID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbumPhoto")
+	}
+
+	var r0 model.AlbumPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.AlbumPhoto, error)); ok {
+		return rf(ctx, albumID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.AlbumPhoto); ok {
+		r0 = rf(ctx, albumID, photoID)
+	} else {
+		r0 = ret.Get(0).(model.AlbumPhoto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, albumID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlbumPhotoIDs provides a mock function with given fields: ctx, albumID
+func (_m *AlbumQuery) GetAlbumPhotoIDs(ctx context.Context, albumID uint64) ([]uint64, error) {
+	ret := _m.Called(ctx, albumID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbumPhotoIDs")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]uint64, error)); ok {
+		return rf(ctx, albumID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []uint64); ok {
+		r0 = rf(ctx, albumID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, albumID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlbumPhotos provides a mock function with given fields: ctx, viewerID, albumID, cursor, limit
+func (_m *AlbumQuery) GetAlbumPhotos(ctx context.Context, viewerID uint64, albumID uint64, cursor uint64, limit int) ([]model.AlbumPhoto, error) {
+	ret := _m.Called(ctx, viewerID, albumID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlbumPhotos")
+	}
+
+	var r0 []model.AlbumPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.AlbumPhoto, error)); ok {
+		return rf(ctx, viewerID, albumID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.AlbumPhoto); ok {
+		r0 = rf(ctx, viewerID, albumID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.AlbumPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, albumID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserAlbums provides a mock function with given fields: ctx, viewerID, userID, cursor, limit
+func (_m *AlbumQuery) GetUserAlbums(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Album, error) {
+	ret := _m.Called(ctx, viewerID, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserAlbums")
+	}
+
+	var r0 []model.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.Album, error)); ok {
+		return rf(ctx, viewerID, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.Album); ok {
+		r0 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Album)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsCollaborator provides a mock function with given fields: ctx, albumID, userID
+func (_m *AlbumQuery) IsCollaborator(ctx context.Context, albumID uint64, userID uint64) (bool, error) {
+	ret := _m.Called(ctx, albumID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsCollaborator")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, albumID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, albumID, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, albumID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveAlbumPhoto provides a mock function with given fields: ctx, albumID, photoID
+func (_m *AlbumQuery) RemoveAlbumPhoto(ctx context.Context, albumID uint64, photoID uint64) (bool, error) {
+	ret := _m.Called(ctx, albumID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAlbumPhoto")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, albumID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, albumID, photoID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, albumID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveCollaborator provides a mock function with given fields: ctx, albumID, userID
+func (_m *AlbumQuery) RemoveCollaborator(ctx context.Context, albumID uint64, userID uint64) error {
+	ret := _m.Called(ctx, albumID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveCollaborator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, albumID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorderAlbumPhotos provides a mock function with given fields: ctx, albumID, photoIDs
+func (_m *AlbumQuery) ReorderAlbumPhotos(ctx context.Context, albumID uint64, photoIDs []uint64) error {
+	ret := _m.Called(ctx, albumID, photoIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderAlbumPhotos")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) error); ok {
+		r0 = rf(ctx, albumID, photoIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAlbum provides a mock function with given fields: ctx, album
+func (_m *AlbumQuery) UpdateAlbum(ctx context.Context, album model.Album) (model.Album, error) {
+	ret := _m.Called(ctx, album)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAlbum")
+	}
+
+	var r0 model.Album
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Album) (model.Album, error)); ok {
+		return rf(ctx, album)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Album) model.Album); ok {
+		r0 = rf(ctx, album)
+	} else {
+		r0 = ret.Get(0).(model.Album)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Album) error); ok {
+		r1 = rf(ctx, album)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAlbumQuery creates a new instance of AlbumQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAlbumQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AlbumQuery {
+	mock := &AlbumQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
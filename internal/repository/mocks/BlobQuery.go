@@ -0,0 +1,153 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BlobQuery is an autogenerated mock type for the BlobQuery type
+type BlobQuery struct {
+	mock.Mock
+}
+
+// AcquireBlob provides a mock function with given fields: ctx, blob
+func (_m *BlobQuery) AcquireBlob(ctx context.Context, blob model.Blob) (model.Blob, error) {
+	ret := _m.Called(ctx, blob)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireBlob")
+	}
+
+	var r0 model.Blob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Blob) (model.Blob, error)); ok {
+		return rf(ctx, blob)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Blob) model.Blob); ok {
+		r0 = rf(ctx, blob)
+	} else {
+		r0 = ret.Get(0).(model.Blob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Blob) error); ok {
+		r1 = rf(ctx, blob)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUnreferencedBlob provides a mock function with given fields: ctx, id, cutoff, deleteObjects
+func (_m *BlobQuery) DeleteUnreferencedBlob(ctx context.Context, id uint64, cutoff time.Time, deleteObjects func(model.Blob) error) (bool, error) {
+	ret := _m.Called(ctx, id, cutoff, deleteObjects)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUnreferencedBlob")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time, func(model.Blob) error) (bool, error)); ok {
+		return rf(ctx, id, cutoff, deleteObjects)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time, func(model.Blob) error) bool); ok {
+		r0 = rf(ctx, id, cutoff, deleteObjects)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, time.Time, func(model.Blob) error) error); ok {
+		r1 = rf(ctx, id, cutoff, deleteObjects)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnreferencedBlobs provides a mock function with given fields: ctx, cutoff, afterID, limit
+func (_m *BlobQuery) GetUnreferencedBlobs(ctx context.Context, cutoff time.Time, afterID uint64, limit int) ([]model.Blob, error) {
+	ret := _m.Called(ctx, cutoff, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnreferencedBlobs")
+	}
+
+	var r0 []model.Blob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, uint64, int) ([]model.Blob, error)); ok {
+		return rf(ctx, cutoff, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, uint64, int) []model.Blob); ok {
+		r0 = rf(ctx, cutoff, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Blob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, uint64, int) error); ok {
+		r1 = rf(ctx, cutoff, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkBlobUploaded provides a mock function with given fields: ctx, key
+func (_m *BlobQuery) MarkBlobUploaded(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkBlobUploaded")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseBlob provides a mock function with given fields: ctx, key
+func (_m *BlobQuery) ReleaseBlob(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseBlob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBlobQuery creates a new instance of BlobQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBlobQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BlobQuery {
+	mock := &BlobQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,209 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BlockQuery is an autogenerated mock type for the BlockQuery type
+type BlockQuery struct {
+	mock.Mock
+}
+
+// CreateBlock provides a mock function with given fields: ctx, block
+func (_m *BlockQuery) CreateBlock(ctx context.Context, block model.Block) (model.Block, error) {
+	ret := _m.Called(ctx, block)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBlock")
+	}
+
+	var r0 model.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Block) (model.Block, error)); ok {
+		return rf(ctx, block)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Block) model.Block); ok {
+		r0 = rf(ctx, block)
+	} else {
+		r0 = ret.Get(0).(model.Block)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Block) error); ok {
+		r1 = rf(ctx, block)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateMute provides a mock function with given fields: ctx, mute
+func (_m *BlockQuery) CreateMute(ctx context.Context, mute model.Mute) (model.Mute, error) {
+	ret := _m.Called(ctx, mute)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMute")
+	}
+
+	var r0 model.Mute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Mute) (model.Mute, error)); ok {
+		return rf(ctx, mute)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Mute) model.Mute); ok {
+		r0 = rf(ctx, mute)
+	} else {
+		r0 = ret.Get(0).(model.Mute)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Mute) error); ok {
+		r1 = rf(ctx, mute)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteBlock provides a mock function with given fields: ctx, blockerID, blockedID
+func (_m *BlockQuery) DeleteBlock(ctx context.Context, blockerID uint64, blockedID uint64) error {
+	ret := _m.Called(ctx, blockerID, blockedID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, blockerID, blockedID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteMute provides a mock function with given fields: ctx, muterID, mutedID
+func (_m *BlockQuery) DeleteMute(ctx context.Context, muterID uint64, mutedID uint64) error {
+	ret := _m.Called(ctx, muterID, mutedID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, muterID, mutedID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBlocks provides a mock function with given fields: ctx, blockerID, cursor, limit
+func (_m *BlockQuery) GetBlocks(ctx context.Context, blockerID uint64, cursor uint64, limit int) ([]model.Block, error) {
+	ret := _m.Called(ctx, blockerID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlocks")
+	}
+
+	var r0 []model.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Block, error)); ok {
+		return rf(ctx, blockerID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Block); ok {
+		r0 = rf(ctx, blockerID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, blockerID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMutes provides a mock function with given fields: ctx, muterID, cursor, limit
+func (_m *BlockQuery) GetMutes(ctx context.Context, muterID uint64, cursor uint64, limit int) ([]model.Mute, error) {
+	ret := _m.Called(ctx, muterID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMutes")
+	}
+
+	var r0 []model.Mute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Mute, error)); ok {
+		return rf(ctx, muterID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Mute); ok {
+		r0 = rf(ctx, muterID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Mute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, muterID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsBlocked provides a mock function with given fields: ctx, userID, otherID
+func (_m *BlockQuery) IsBlocked(ctx context.Context, userID uint64, otherID uint64) (bool, error) {
+	ret := _m.Called(ctx, userID, otherID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsBlocked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, userID, otherID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, userID, otherID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, otherID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBlockQuery creates a new instance of BlockQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBlockQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BlockQuery {
+	mock := &BlockQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CloseFriendQuery is an autogenerated mock type for the CloseFriendQuery type
+type CloseFriendQuery struct {
+	mock.Mock
+}
+
+// CreateCloseFriend provides a mock function with given fields: ctx, closeFriend
+func (_m *CloseFriendQuery) CreateCloseFriend(ctx context.Context, closeFriend model.CloseFriend) (model.CloseFriend, error) {
+	ret := _m.Called(ctx, closeFriend)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCloseFriend")
+	}
+
+	var r0 model.CloseFriend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.CloseFriend) (model.CloseFriend, error)); ok {
+		return rf(ctx, closeFriend)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.CloseFriend) model.CloseFriend); ok {
+		r0 = rf(ctx, closeFriend)
+	} else {
+		r0 = ret.Get(0).(model.CloseFriend)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.CloseFriend) error); ok {
+		r1 = rf(ctx, closeFriend)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteCloseFriend provides a mock function with given fields: ctx, userID, friendID
+func (_m *CloseFriendQuery) DeleteCloseFriend(ctx context.Context, userID uint64, friendID uint64) error {
+	ret := _m.Called(ctx, userID, friendID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCloseFriend")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, userID, friendID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCloseFriends provides a mock function with given fields: ctx, userID, cursor, limit
+func (_m *CloseFriendQuery) GetCloseFriends(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.CloseFriend, error) {
+	ret := _m.Called(ctx, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCloseFriends")
+	}
+
+	var r0 []model.CloseFriend
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.CloseFriend, error)); ok {
+		return rf(ctx, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.CloseFriend); ok {
+		r0 = rf(ctx, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.CloseFriend)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCloseFriendQuery creates a new instance of CloseFriendQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCloseFriendQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CloseFriendQuery {
+	mock := &CloseFriendQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,221 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentQuery is an autogenerated mock type for the CommentQuery type
+type CommentQuery struct {
+	mock.Mock
+}
+
+// CreateComment provides a mock function with given fields: ctx, comment
+func (_m *CommentQuery) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	ret := _m.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComment")
+	}
+
+	var r0 model.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Comment) (model.Comment, error)); ok {
+		return rf(ctx, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Comment) model.Comment); ok {
+		r0 = rf(ctx, comment)
+	} else {
+		r0 = ret.Get(0).(model.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Comment) error); ok {
+		r1 = rf(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteComment provides a mock function with given fields: ctx, id
+func (_m *CommentQuery) DeleteComment(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EditComment provides a mock function with given fields: ctx, comment, id, editorID
+func (_m *CommentQuery) EditComment(ctx context.Context, comment model.Comment, id uint64, editorID uint64) (model.Comment, error) {
+	ret := _m.Called(ctx, comment, id, editorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditComment")
+	}
+
+	var r0 model.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Comment, uint64, uint64) (model.Comment, error)); ok {
+		return rf(ctx, comment, id, editorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Comment, uint64, uint64) model.Comment); ok {
+		r0 = rf(ctx, comment, id, editorID)
+	} else {
+		r0 = ret.Get(0).(model.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Comment, uint64, uint64) error); ok {
+		r1 = rf(ctx, comment, id, editorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentRevisions provides a mock function with given fields: ctx, id, cursor, limit
+func (_m *CommentQuery) GetCommentRevisions(ctx context.Context, id uint64, cursor uint64, limit int) ([]model.Revision, error) {
+	ret := _m.Called(ctx, id, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentRevisions")
+	}
+
+	var r0 []model.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Revision, error)); ok {
+		return rf(ctx, id, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Revision); ok {
+		r0 = rf(ctx, id, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, id, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: ctx, userID
+func (_m *CommentQuery) GetComments(ctx context.Context, userID uint64) ([]model.Comment, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetComments")
+	}
+
+	var r0 []model.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.Comment, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.Comment); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCommentsByID provides a mock function with given fields: ctx, id
+func (_m *CommentQuery) GetCommentsByID(ctx context.Context, id uint64) (model.Comment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentsByID")
+	}
+
+	var r0 model.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.Comment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.Comment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotoComments provides a mock function with given fields: ctx, viewerID, photoID, cursor, limit
+func (_m *CommentQuery) GetPhotoComments(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Comment, error) {
+	ret := _m.Called(ctx, viewerID, photoID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotoComments")
+	}
+
+	var r0 []model.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.Comment, error)); ok {
+		return rf(ctx, viewerID, photoID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.Comment); ok {
+		r0 = rf(ctx, viewerID, photoID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, photoID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCommentQuery creates a new instance of CommentQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCommentQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CommentQuery {
+	mock := &CommentQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,81 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/MidnightHelix/MyGram/internal/repository"
+
+	time "time"
+)
+
+// ExploreQuery is an autogenerated mock type for the ExploreQuery type
+type ExploreQuery struct {
+	mock.Mock
+}
+
+// GetExplore provides a mock function with given fields: ctx, viewerID, page
+func (_m *ExploreQuery) GetExplore(ctx context.Context, viewerID uint64, page repository.ExplorePage) ([]model.ExploreScore, error) {
+	ret := _m.Called(ctx, viewerID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExplore")
+	}
+
+	var r0 []model.ExploreScore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.ExplorePage) ([]model.ExploreScore, error)); ok {
+		return rf(ctx, viewerID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.ExplorePage) []model.ExploreScore); ok {
+		r0 = rf(ctx, viewerID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ExploreScore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, repository.ExplorePage) error); ok {
+		r1 = rf(ctx, viewerID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecomputeScores provides a mock function with given fields: ctx, weights, since, now
+func (_m *ExploreQuery) RecomputeScores(ctx context.Context, weights repository.ExploreWeights, since time.Time, now time.Time) error {
+	ret := _m.Called(ctx, weights, since, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecomputeScores")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ExploreWeights, time.Time, time.Time) error); ok {
+		r0 = rf(ctx, weights, since, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewExploreQuery creates a new instance of ExploreQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExploreQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExploreQuery {
+	mock := &ExploreQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
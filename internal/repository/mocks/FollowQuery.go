@@ -0,0 +1,302 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FollowQuery is an autogenerated mock type for the FollowQuery type
+type FollowQuery struct {
+	mock.Mock
+}
+
+// AcceptFollowRequests provides a mock function with given fields: ctx, userID
+func (_m *FollowQuery) AcceptFollowRequests(ctx context.Context, userID uint64) ([]model.Follow, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptFollowRequests")
+	}
+
+	var r0 []model.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.Follow, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.Follow); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountFollowers provides a mock function with given fields: ctx, userID
+func (_m *FollowQuery) CountFollowers(ctx context.Context, userID uint64) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountFollowers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountFollowing provides a mock function with given fields: ctx, userID
+func (_m *FollowQuery) CountFollowing(ctx context.Context, userID uint64) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountFollowing")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateFollow provides a mock function with given fields: ctx, follow
+func (_m *FollowQuery) CreateFollow(ctx context.Context, follow model.Follow) (model.Follow, bool, error) {
+	ret := _m.Called(ctx, follow)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFollow")
+	}
+
+	var r0 model.Follow
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Follow) (model.Follow, bool, error)); ok {
+		return rf(ctx, follow)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Follow) model.Follow); ok {
+		r0 = rf(ctx, follow)
+	} else {
+		r0 = ret.Get(0).(model.Follow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Follow) bool); ok {
+		r1 = rf(ctx, follow)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, model.Follow) error); ok {
+		r2 = rf(ctx, follow)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteFollow provides a mock function with given fields: ctx, followerID, followingID
+func (_m *FollowQuery) DeleteFollow(ctx context.Context, followerID uint64, followingID uint64) error {
+	ret := _m.Called(ctx, followerID, followingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFollow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, followerID, followingID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFollow provides a mock function with given fields: ctx, followerID, followingID
+func (_m *FollowQuery) GetFollow(ctx context.Context, followerID uint64, followingID uint64) (model.Follow, error) {
+	ret := _m.Called(ctx, followerID, followingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollow")
+	}
+
+	var r0 model.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.Follow, error)); ok {
+		return rf(ctx, followerID, followingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.Follow); ok {
+		r0 = rf(ctx, followerID, followingID)
+	} else {
+		r0 = ret.Get(0).(model.Follow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, followerID, followingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFollowByID provides a mock function with given fields: ctx, id
+func (_m *FollowQuery) GetFollowByID(ctx context.Context, id uint64) (model.Follow, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowByID")
+	}
+
+	var r0 model.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.Follow, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.Follow); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.Follow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFollowers provides a mock function with given fields: ctx, viewerID, userID, status, cursor, limit
+func (_m *FollowQuery) GetFollowers(ctx context.Context, viewerID uint64, userID uint64, status string, cursor uint64, limit int) ([]model.Follow, error) {
+	ret := _m.Called(ctx, viewerID, userID, status, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowers")
+	}
+
+	var r0 []model.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, string, uint64, int) ([]model.Follow, error)); ok {
+		return rf(ctx, viewerID, userID, status, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, string, uint64, int) []model.Follow); ok {
+		r0 = rf(ctx, viewerID, userID, status, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, string, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, userID, status, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFollowing provides a mock function with given fields: ctx, viewerID, userID, cursor, limit
+func (_m *FollowQuery) GetFollowing(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error) {
+	ret := _m.Called(ctx, viewerID, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowing")
+	}
+
+	var r0 []model.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.Follow, error)); ok {
+		return rf(ctx, viewerID, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.Follow); ok {
+		r0 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFollowStatus provides a mock function with given fields: ctx, id, status
+func (_m *FollowQuery) UpdateFollowStatus(ctx context.Context, id uint64, status string) error {
+	ret := _m.Called(ctx, id, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFollowStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFollowQuery creates a new instance of FollowQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFollowQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FollowQuery {
+	mock := &FollowQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
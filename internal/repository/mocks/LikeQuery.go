@@ -0,0 +1,145 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LikeQuery is an autogenerated mock type for the LikeQuery type
+type LikeQuery struct {
+	mock.Mock
+}
+
+// CreateLike provides a mock function with given fields: ctx, userID, photoID
+func (_m *LikeQuery) CreateLike(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	ret := _m.Called(ctx, userID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLike")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, userID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, userID, photoID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteLike provides a mock function with given fields: ctx, userID, photoID
+func (_m *LikeQuery) DeleteLike(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	ret := _m.Called(ctx, userID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLike")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, userID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, userID, photoID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLikedPhotoIDs provides a mock function with given fields: ctx, userID, photoIDs
+func (_m *LikeQuery) GetLikedPhotoIDs(ctx context.Context, userID uint64, photoIDs []uint64) ([]uint64, error) {
+	ret := _m.Called(ctx, userID, photoIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLikedPhotoIDs")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) ([]uint64, error)); ok {
+		return rf(ctx, userID, photoIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) []uint64); ok {
+		r0 = rf(ctx, userID, photoIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, []uint64) error); ok {
+		r1 = rf(ctx, userID, photoIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLikes provides a mock function with given fields: ctx, viewerID, photoID, cursor, limit
+func (_m *LikeQuery) GetLikes(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Like, error) {
+	ret := _m.Called(ctx, viewerID, photoID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLikes")
+	}
+
+	var r0 []model.Like
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.Like, error)); ok {
+		return rf(ctx, viewerID, photoID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.Like); ok {
+		r0 = rf(ctx, viewerID, photoID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Like)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, photoID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLikeQuery creates a new instance of LikeQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLikeQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LikeQuery {
+	mock := &LikeQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MentionQuery is an autogenerated mock type for the MentionQuery type
+type MentionQuery struct {
+	mock.Mock
+}
+
+// ReplaceMentions provides a mock function with given fields: ctx, sourceType, sourceID, mentions
+func (_m *MentionQuery) ReplaceMentions(ctx context.Context, sourceType string, sourceID uint64, mentions []model.Mention) ([]uint64, error) {
+	ret := _m.Called(ctx, sourceType, sourceID, mentions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceMentions")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, []model.Mention) ([]uint64, error)); ok {
+		return rf(ctx, sourceType, sourceID, mentions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, []model.Mention) []uint64); ok {
+		r0 = rf(ctx, sourceType, sourceID, mentions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, []model.Mention) error); ok {
+		r1 = rf(ctx, sourceType, sourceID, mentions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMentionQuery creates a new instance of MentionQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMentionQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MentionQuery {
+	mock := &MentionQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
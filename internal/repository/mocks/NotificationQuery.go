@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// NotificationQuery is an autogenerated mock type for the NotificationQuery type
+type NotificationQuery struct {
+	mock.Mock
+}
+
+// CreateNotifications provides a mock function with given fields: ctx, notifications
+func (_m *NotificationQuery) CreateNotifications(ctx context.Context, notifications []model.Notification) error {
+	ret := _m.Called(ctx, notifications)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotifications")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.Notification) error); ok {
+		r0 = rf(ctx, notifications)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetNotifications provides a mock function with given fields: ctx, userID, cursor, limit
+func (_m *NotificationQuery) GetNotifications(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Notification, error) {
+	ret := _m.Called(ctx, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotifications")
+	}
+
+	var r0 []model.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Notification, error)); ok {
+		return rf(ctx, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Notification); ok {
+		r0 = rf(ctx, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkNotificationsRead provides a mock function with given fields: ctx, userID, at
+func (_m *NotificationQuery) MarkNotificationsRead(ctx context.Context, userID uint64, at time.Time) error {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkNotificationsRead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) error); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNotificationQuery creates a new instance of NotificationQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationQuery {
+	mock := &NotificationQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,556 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhotoQuery is an autogenerated mock type for the PhotoQuery type
+type PhotoQuery struct {
+	mock.Mock
+}
+
+// AddMediaItem provides a mock function with given fields: ctx, item, maxItems
+func (_m *PhotoQuery) AddMediaItem(ctx context.Context, item model.MediaItem, maxItems int) (model.MediaItem, bool, error) {
+	ret := _m.Called(ctx, item, maxItems)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddMediaItem")
+	}
+
+	var r0 model.MediaItem
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.MediaItem, int) (model.MediaItem, bool, error)); ok {
+		return rf(ctx, item, maxItems)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.MediaItem, int) model.MediaItem); ok {
+		r0 = rf(ctx, item, maxItems)
+	} else {
+		r0 = ret.Get(0).(model.MediaItem)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.MediaItem, int) bool); ok {
+		r1 = rf(ctx, item, maxItems)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, model.MediaItem, int) error); ok {
+		r2 = rf(ctx, item, maxItems)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreatePhoto provides a mock function with given fields: ctx, photo
+func (_m *PhotoQuery) CreatePhoto(ctx context.Context, photo model.Photo) (model.Photo, error) {
+	ret := _m.Called(ctx, photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePhoto")
+	}
+
+	var r0 model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Photo) (model.Photo, error)); ok {
+		return rf(ctx, photo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Photo) model.Photo); ok {
+		r0 = rf(ctx, photo)
+	} else {
+		r0 = ret.Get(0).(model.Photo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Photo) error); ok {
+		r1 = rf(ctx, photo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteMediaItem provides a mock function with given fields: ctx, photoID, id
+func (_m *PhotoQuery) DeleteMediaItem(ctx context.Context, photoID uint64, id uint64) (bool, error) {
+	ret := _m.Called(ctx, photoID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMediaItem")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, photoID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, photoID, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, photoID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePhoto provides a mock function with given fields: ctx, id
+func (_m *PhotoQuery) DeletePhoto(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePhoto")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EditMediaItem provides a mock function with given fields: ctx, id, altText
+func (_m *PhotoQuery) EditMediaItem(ctx context.Context, id uint64, altText string) error {
+	ret := _m.Called(ctx, id, altText)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditMediaItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, altText)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EditPhoto provides a mock function with given fields: ctx, photo, id, editorID
+func (_m *PhotoQuery) EditPhoto(ctx context.Context, photo model.Photo, id uint64, editorID uint64) (model.Photo, error) {
+	ret := _m.Called(ctx, photo, id, editorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditPhoto")
+	}
+
+	var r0 model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Photo, uint64, uint64) (model.Photo, error)); ok {
+		return rf(ctx, photo, id, editorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Photo, uint64, uint64) model.Photo); ok {
+		r0 = rf(ctx, photo, id, editorID)
+	} else {
+		r0 = ret.Get(0).(model.Photo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Photo, uint64, uint64) error); ok {
+		r1 = rf(ctx, photo, id, editorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByHashBands provides a mock function with given fields: ctx, probes, userID, excludeID, limit
+func (_m *PhotoQuery) FindByHashBands(ctx context.Context, probes [][]int32, userID uint64, excludeID uint64, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, probes, userID, excludeID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByHashBands")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, [][]int32, uint64, uint64, int) ([]model.Photo, error)); ok {
+		return rf(ctx, probes, userID, excludeID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, [][]int32, uint64, uint64, int) []model.Photo); ok {
+		r0 = rf(ctx, probes, userID, excludeID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, [][]int32, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, probes, userID, excludeID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMediaItem provides a mock function with given fields: ctx, photoID, id
+func (_m *PhotoQuery) GetMediaItem(ctx context.Context, photoID uint64, id uint64) (model.MediaItem, error) {
+	ret := _m.Called(ctx, photoID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMediaItem")
+	}
+
+	var r0 model.MediaItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.MediaItem, error)); ok {
+		return rf(ctx, photoID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.MediaItem); ok {
+		r0 = rf(ctx, photoID, id)
+	} else {
+		r0 = ret.Get(0).(model.MediaItem)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, photoID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhoto provides a mock function with given fields: ctx, viewerID, id
+func (_m *PhotoQuery) GetPhoto(ctx context.Context, viewerID uint64, id uint64) (model.Photo, error) {
+	ret := _m.Called(ctx, viewerID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhoto")
+	}
+
+	var r0 model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.Photo, error)); ok {
+		return rf(ctx, viewerID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.Photo); ok {
+		r0 = rf(ctx, viewerID, id)
+	} else {
+		r0 = ret.Get(0).(model.Photo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, viewerID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotoRevisions provides a mock function with given fields: ctx, id, cursor, limit
+func (_m *PhotoQuery) GetPhotoRevisions(ctx context.Context, id uint64, cursor uint64, limit int) ([]model.Revision, error) {
+	ret := _m.Called(ctx, id, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotoRevisions")
+	}
+
+	var r0 []model.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Revision, error)); ok {
+		return rf(ctx, id, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Revision); ok {
+		r0 = rf(ctx, id, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, id, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotos provides a mock function with given fields: ctx, userID
+func (_m *PhotoQuery) GetPhotos(ctx context.Context, userID uint64) ([]model.Photo, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotos")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.Photo, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.Photo); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotosByID provides a mock function with given fields: ctx, id
+func (_m *PhotoQuery) GetPhotosByID(ctx context.Context, id uint64) (model.Photo, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotosByID")
+	}
+
+	var r0 model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.Photo, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.Photo); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.Photo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPhotosByStatus provides a mock function with given fields: ctx, status, afterID, limit
+func (_m *PhotoQuery) GetPhotosByStatus(ctx context.Context, status string, afterID uint64, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, status, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhotosByStatus")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, int) ([]model.Photo, error)); ok {
+		return rf(ctx, status, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, int) []model.Photo); ok {
+		r0 = rf(ctx, status, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, int) error); ok {
+		r1 = rf(ctx, status, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserPhotos provides a mock function with given fields: ctx, viewerID, userID, cursor, limit
+func (_m *PhotoQuery) GetUserPhotos(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, viewerID, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserPhotos")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.Photo, error)); ok {
+		return rf(ctx, viewerID, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.Photo); ok {
+		r0 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsObjectVisible provides a mock function with given fields: ctx, viewerID, key
+func (_m *PhotoQuery) IsObjectVisible(ctx context.Context, viewerID uint64, key string) (bool, error) {
+	ret := _m.Called(ctx, viewerID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsObjectVisible")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) (bool, error)); ok {
+		return rf(ctx, viewerID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) bool); ok {
+		r0 = rf(ctx, viewerID, key)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string) error); ok {
+		r1 = rf(ctx, viewerID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPhotoVisible provides a mock function with given fields: ctx, viewerID, id
+func (_m *PhotoQuery) IsPhotoVisible(ctx context.Context, viewerID uint64, id uint64) (bool, error) {
+	ret := _m.Called(ctx, viewerID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsPhotoVisible")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, viewerID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, viewerID, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, viewerID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkPhotoFailed provides a mock function with given fields: ctx, id
+func (_m *PhotoQuery) MarkPhotoFailed(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPhotoFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorderMediaItems provides a mock function with given fields: ctx, photoID, ids
+func (_m *PhotoQuery) ReorderMediaItems(ctx context.Context, photoID uint64, ids []uint64) (bool, error) {
+	ret := _m.Called(ctx, photoID, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderMediaItems")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) (bool, error)); ok {
+		return rf(ctx, photoID, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) bool); ok {
+		r0 = rf(ctx, photoID, ids)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, []uint64) error); ok {
+		r1 = rf(ctx, photoID, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveVariants provides a mock function with given fields: ctx, id, width, height, variants
+func (_m *PhotoQuery) SaveVariants(ctx context.Context, id uint64, width int, height int, variants []model.PhotoVariant) error {
+	ret := _m.Called(ctx, id, width, height, variants)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveVariants")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int, []model.PhotoVariant) error); ok {
+		r0 = rf(ctx, id, width, height, variants)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPhotoLocation provides a mock function with given fields: ctx, id, latitude, longitude, placeName
+func (_m *PhotoQuery) SetPhotoLocation(ctx context.Context, id uint64, latitude *float64, longitude *float64, placeName string) error {
+	ret := _m.Called(ctx, id, latitude, longitude, placeName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPhotoLocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *float64, *float64, string) error); ok {
+		r0 = rf(ctx, id, latitude, longitude, placeName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPhotoQuery creates a new instance of PhotoQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhotoQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhotoQuery {
+	mock := &PhotoQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
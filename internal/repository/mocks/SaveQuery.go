@@ -0,0 +1,303 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SaveQuery is an autogenerated mock type for the SaveQuery type
+type SaveQuery struct {
+	mock.Mock
+}
+
+// AddToCollection provides a mock function with given fields: ctx, id, photoID
+func (_m *SaveQuery) AddToCollection(ctx context.Context, id uint64, photoID uint64) error {
+	ret := _m.Called(ctx, id, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToCollection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, id, photoID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateCollection provides a mock function with given fields: ctx, collection
+func (_m *SaveQuery) CreateCollection(ctx context.Context, collection model.SaveCollection) (model.SaveCollection, error) {
+	ret := _m.Called(ctx, collection)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCollection")
+	}
+
+	var r0 model.SaveCollection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.SaveCollection) (model.SaveCollection, error)); ok {
+		return rf(ctx, collection)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.SaveCollection) model.SaveCollection); ok {
+		r0 = rf(ctx, collection)
+	} else {
+		r0 = ret.Get(0).(model.SaveCollection)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.SaveCollection) error); ok {
+		r1 = rf(ctx, collection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSave provides a mock function with given fields: ctx, userID, photoID
+func (_m *SaveQuery) CreateSave(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	ret := _m.Called(ctx, userID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSave")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, userID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, userID, photoID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteCollection provides a mock function with given fields: ctx, id
+func (_m *SaveQuery) DeleteCollection(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCollection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSave provides a mock function with given fields: ctx, userID, photoID
+func (_m *SaveQuery) DeleteSave(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	ret := _m.Called(ctx, userID, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSave")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, userID, photoID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, userID, photoID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, photoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCollection provides a mock function with given fields: ctx, userID, id
+func (_m *SaveQuery) GetCollection(ctx context.Context, userID uint64, id uint64) (model.SaveCollection, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollection")
+	}
+
+	var r0 model.SaveCollection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.SaveCollection, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.SaveCollection); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Get(0).(model.SaveCollection)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCollections provides a mock function with given fields: ctx, userID
+func (_m *SaveQuery) GetCollections(ctx context.Context, userID uint64) ([]model.SaveCollection, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollections")
+	}
+
+	var r0 []model.SaveCollection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.SaveCollection, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.SaveCollection); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.SaveCollection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSavedPhotoIDs provides a mock function with given fields: ctx, userID, photoIDs
+func (_m *SaveQuery) GetSavedPhotoIDs(ctx context.Context, userID uint64, photoIDs []uint64) ([]uint64, error) {
+	ret := _m.Called(ctx, userID, photoIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSavedPhotoIDs")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) ([]uint64, error)); ok {
+		return rf(ctx, userID, photoIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) []uint64); ok {
+		r0 = rf(ctx, userID, photoIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, []uint64) error); ok {
+		r1 = rf(ctx, userID, photoIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSaves provides a mock function with given fields: ctx, userID, collectionID, cursor, limit
+func (_m *SaveQuery) GetSaves(ctx context.Context, userID uint64, collectionID uint64, cursor uint64, limit int) ([]model.Save, error) {
+	ret := _m.Called(ctx, userID, collectionID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSaves")
+	}
+
+	var r0 []model.Save
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.Save, error)); ok {
+		return rf(ctx, userID, collectionID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.Save); ok {
+		r0 = rf(ctx, userID, collectionID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Save)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, userID, collectionID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveFromCollection provides a mock function with given fields: ctx, id, photoID
+func (_m *SaveQuery) RemoveFromCollection(ctx context.Context, id uint64, photoID uint64) error {
+	ret := _m.Called(ctx, id, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFromCollection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, id, photoID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenameCollection provides a mock function with given fields: ctx, id, name
+func (_m *SaveQuery) RenameCollection(ctx context.Context, id uint64, name string) error {
+	ret := _m.Called(ctx, id, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameCollection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSaveQuery creates a new instance of SaveQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSaveQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SaveQuery {
+	mock := &SaveQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
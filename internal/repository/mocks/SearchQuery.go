@@ -0,0 +1,91 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+// SearchQuery is an autogenerated mock type for the SearchQuery type
+type SearchQuery struct {
+	mock.Mock
+}
+
+// NearbyPhotos provides a mock function with given fields: ctx, viewerID, search
+func (_m *SearchQuery) NearbyPhotos(ctx context.Context, viewerID uint64, search repository.PhotoNearby) ([]model.NearbyPhoto, error) {
+	ret := _m.Called(ctx, viewerID, search)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NearbyPhotos")
+	}
+
+	var r0 []model.NearbyPhoto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.PhotoNearby) ([]model.NearbyPhoto, error)); ok {
+		return rf(ctx, viewerID, search)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.PhotoNearby) []model.NearbyPhoto); ok {
+		r0 = rf(ctx, viewerID, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.NearbyPhoto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, repository.PhotoNearby) error); ok {
+		r1 = rf(ctx, viewerID, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchPhotos provides a mock function with given fields: ctx, viewerID, search
+func (_m *SearchQuery) SearchPhotos(ctx context.Context, viewerID uint64, search repository.PhotoSearch) ([]model.PhotoMatch, error) {
+	ret := _m.Called(ctx, viewerID, search)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchPhotos")
+	}
+
+	var r0 []model.PhotoMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.PhotoSearch) ([]model.PhotoMatch, error)); ok {
+		return rf(ctx, viewerID, search)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.PhotoSearch) []model.PhotoMatch); ok {
+		r0 = rf(ctx, viewerID, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.PhotoMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, repository.PhotoSearch) error); ok {
+		r1 = rf(ctx, viewerID, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSearchQuery creates a new instance of SearchQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSearchQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SearchQuery {
+	mock := &SearchQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
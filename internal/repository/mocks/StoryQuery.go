@@ -0,0 +1,565 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// StoryQuery is an autogenerated mock type for the StoryQuery type
+type StoryQuery struct {
+	mock.Mock
+}
+
+// AddHighlightStory provides a mock function with given fields: ctx, highlightID, storyID
+func (_m *StoryQuery) AddHighlightStory(ctx context.Context, highlightID uint64, storyID uint64) (bool, error) {
+	ret := _m.Called(ctx, highlightID, storyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddHighlightStory")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, highlightID, storyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, highlightID, storyID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, highlightID, storyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateHighlight provides a mock function with given fields: ctx, highlight
+func (_m *StoryQuery) CreateHighlight(ctx context.Context, highlight model.StoryHighlight) (model.StoryHighlight, error) {
+	ret := _m.Called(ctx, highlight)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHighlight")
+	}
+
+	var r0 model.StoryHighlight
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.StoryHighlight) (model.StoryHighlight, error)); ok {
+		return rf(ctx, highlight)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.StoryHighlight) model.StoryHighlight); ok {
+		r0 = rf(ctx, highlight)
+	} else {
+		r0 = ret.Get(0).(model.StoryHighlight)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.StoryHighlight) error); ok {
+		r1 = rf(ctx, highlight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateStory provides a mock function with given fields: ctx, story
+func (_m *StoryQuery) CreateStory(ctx context.Context, story model.Story) (model.Story, error) {
+	ret := _m.Called(ctx, story)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateStory")
+	}
+
+	var r0 model.Story
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Story) (model.Story, error)); ok {
+		return rf(ctx, story)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Story) model.Story); ok {
+		r0 = rf(ctx, story)
+	} else {
+		r0 = ret.Get(0).(model.Story)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Story) error); ok {
+		r1 = rf(ctx, story)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteHighlight provides a mock function with given fields: ctx, id
+func (_m *StoryQuery) DeleteHighlight(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHighlight")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteStory provides a mock function with given fields: ctx, id
+func (_m *StoryQuery) DeleteStory(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireStories provides a mock function with given fields: ctx, now, limit
+func (_m *StoryQuery) ExpireStories(ctx context.Context, now time.Time, limit int) ([]model.Story, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireStories")
+	}
+
+	var r0 []model.Story
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]model.Story, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []model.Story); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Story)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHighlight provides a mock function with given fields: ctx, viewerID, id
+func (_m *StoryQuery) GetHighlight(ctx context.Context, viewerID uint64, id uint64) (model.StoryHighlight, error) {
+	ret := _m.Called(ctx, viewerID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHighlight")
+	}
+
+	var r0 model.StoryHighlight
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.StoryHighlight, error)); ok {
+		return rf(ctx, viewerID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.StoryHighlight); ok {
+		r0 = rf(ctx, viewerID, id)
+	} else {
+		r0 = ret.Get(0).(model.StoryHighlight)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, viewerID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHighlightByID provides a mock function with given fields: ctx, id
+func (_m *StoryQuery) GetHighlightByID(ctx context.Context, id uint64) (model.StoryHighlight, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHighlightByID")
+	}
+
+	var r0 model.StoryHighlight
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.StoryHighlight, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.StoryHighlight); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.StoryHighlight)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHighlightItems provides a mock function with given fields: ctx, highlightID
+func (_m *StoryQuery) GetHighlightItems(ctx context.Context, highlightID uint64) ([]model.StoryHighlightItem, error) {
+	ret := _m.Called(ctx, highlightID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHighlightItems")
+	}
+
+	var r0 []model.StoryHighlightItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.StoryHighlightItem, error)); ok {
+		return rf(ctx, highlightID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.StoryHighlightItem); ok {
+		r0 = rf(ctx, highlightID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StoryHighlightItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, highlightID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeenStoryIDs provides a mock function with given fields: ctx, viewerID, storyIDs
+func (_m *StoryQuery) GetSeenStoryIDs(ctx context.Context, viewerID uint64, storyIDs []uint64) ([]uint64, error) {
+	ret := _m.Called(ctx, viewerID, storyIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeenStoryIDs")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) ([]uint64, error)); ok {
+		return rf(ctx, viewerID, storyIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) []uint64); ok {
+		r0 = rf(ctx, viewerID, storyIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, []uint64) error); ok {
+		r1 = rf(ctx, viewerID, storyIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStory provides a mock function with given fields: ctx, viewerID, id, now
+func (_m *StoryQuery) GetStory(ctx context.Context, viewerID uint64, id uint64, now time.Time) (model.Story, error) {
+	ret := _m.Called(ctx, viewerID, id, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStory")
+	}
+
+	var r0 model.Story
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, time.Time) (model.Story, error)); ok {
+		return rf(ctx, viewerID, id, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, time.Time) model.Story); ok {
+		r0 = rf(ctx, viewerID, id, now)
+	} else {
+		r0 = ret.Get(0).(model.Story)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, time.Time) error); ok {
+		r1 = rf(ctx, viewerID, id, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoryByID provides a mock function with given fields: ctx, id
+func (_m *StoryQuery) GetStoryByID(ctx context.Context, id uint64) (model.Story, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoryByID")
+	}
+
+	var r0 model.Story
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.Story, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.Story); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.Story)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoryTray provides a mock function with given fields: ctx, viewerID, now
+func (_m *StoryQuery) GetStoryTray(ctx context.Context, viewerID uint64, now time.Time) ([]model.Story, error) {
+	ret := _m.Called(ctx, viewerID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoryTray")
+	}
+
+	var r0 []model.Story
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) ([]model.Story, error)); ok {
+		return rf(ctx, viewerID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) []model.Story); ok {
+		r0 = rf(ctx, viewerID, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Story)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, time.Time) error); ok {
+		r1 = rf(ctx, viewerID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoryViewers provides a mock function with given fields: ctx, storyID, cursor, limit
+func (_m *StoryQuery) GetStoryViewers(ctx context.Context, storyID uint64, cursor uint64, limit int) ([]model.StoryView, error) {
+	ret := _m.Called(ctx, storyID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoryViewers")
+	}
+
+	var r0 []model.StoryView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.StoryView, error)); ok {
+		return rf(ctx, storyID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.StoryView); ok {
+		r0 = rf(ctx, storyID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StoryView)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, storyID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserHighlights provides a mock function with given fields: ctx, viewerID, userID, cursor, limit
+func (_m *StoryQuery) GetUserHighlights(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.StoryHighlight, error) {
+	ret := _m.Called(ctx, viewerID, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserHighlights")
+	}
+
+	var r0 []model.StoryHighlight
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.StoryHighlight, error)); ok {
+		return rf(ctx, viewerID, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.StoryHighlight); ok {
+		r0 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StoryHighlight)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserStories provides a mock function with given fields: ctx, viewerID, userID, now
+func (_m *StoryQuery) GetUserStories(ctx context.Context, viewerID uint64, userID uint64, now time.Time) ([]model.Story, error) {
+	ret := _m.Called(ctx, viewerID, userID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserStories")
+	}
+
+	var r0 []model.Story
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, time.Time) ([]model.Story, error)); ok {
+		return rf(ctx, viewerID, userID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, time.Time) []model.Story); ok {
+		r0 = rf(ctx, viewerID, userID, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Story)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, time.Time) error); ok {
+		r1 = rf(ctx, viewerID, userID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkSeen provides a mock function with given fields: ctx, storyID, viewerID
+func (_m *StoryQuery) MarkSeen(ctx context.Context, storyID uint64, viewerID uint64) (bool, error) {
+	ret := _m.Called(ctx, storyID, viewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSeen")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, storyID, viewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, storyID, viewerID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, storyID, viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveHighlightStory provides a mock function with given fields: ctx, highlightID, storyID
+func (_m *StoryQuery) RemoveHighlightStory(ctx context.Context, highlightID uint64, storyID uint64) (bool, error) {
+	ret := _m.Called(ctx, highlightID, storyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveHighlightStory")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (bool, error)); ok {
+		return rf(ctx, highlightID, storyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) bool); ok {
+		r0 = rf(ctx, highlightID, storyID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, highlightID, storyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReorderHighlightStories provides a mock function with given fields: ctx, highlightID, storyIDs
+func (_m *StoryQuery) ReorderHighlightStories(ctx context.Context, highlightID uint64, storyIDs []uint64) error {
+	ret := _m.Called(ctx, highlightID, storyIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderHighlightStories")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) error); ok {
+		r0 = rf(ctx, highlightID, storyIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateHighlight provides a mock function with given fields: ctx, id, title
+func (_m *StoryQuery) UpdateHighlight(ctx context.Context, id uint64, title string) error {
+	ret := _m.Called(ctx, id, title)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateHighlight")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, title)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStoryQuery creates a new instance of StoryQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStoryQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StoryQuery {
+	mock := &StoryQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
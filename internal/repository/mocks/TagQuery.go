@@ -0,0 +1,135 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TagQuery is an autogenerated mock type for the TagQuery type
+type TagQuery struct {
+	mock.Mock
+}
+
+// GetTag provides a mock function with given fields: ctx, name
+func (_m *TagQuery) GetTag(ctx context.Context, name string) (model.Tag, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTag")
+	}
+
+	var r0 model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Tag, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Tag); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(model.Tag)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTagPhotos provides a mock function with given fields: ctx, viewerID, tagID, cursor, limit
+func (_m *TagQuery) GetTagPhotos(ctx context.Context, viewerID uint64, tagID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, viewerID, tagID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTagPhotos")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) ([]model.Photo, error)); ok {
+		return rf(ctx, viewerID, tagID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64, int) []model.Photo); ok {
+		r0 = rf(ctx, viewerID, tagID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, viewerID, tagID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTags provides a mock function with given fields: ctx, prefix, limit
+func (_m *TagQuery) SearchTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error) {
+	ret := _m.Called(ctx, prefix, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTags")
+	}
+
+	var r0 []model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]model.Tag, error)); ok {
+		return rf(ctx, prefix, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []model.Tag); ok {
+		r0 = rf(ctx, prefix, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, prefix, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPhotoTags provides a mock function with given fields: ctx, photoID, names
+func (_m *TagQuery) SetPhotoTags(ctx context.Context, photoID uint64, names []string) error {
+	ret := _m.Called(ctx, photoID, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPhotoTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []string) error); ok {
+		r0 = rf(ctx, photoID, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTagQuery creates a new instance of TagQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTagQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TagQuery {
+	mock := &TagQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,161 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TimelineQuery is an autogenerated mock type for the TimelineQuery type
+type TimelineQuery struct {
+	mock.Mock
+}
+
+// Backfill provides a mock function with given fields: ctx, userID, authorID, limit
+func (_m *TimelineQuery) Backfill(ctx context.Context, userID uint64, authorID uint64, limit int) error {
+	ret := _m.Called(ctx, userID, authorID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Backfill")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) error); ok {
+		r0 = rf(ctx, userID, authorID, limit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FanOut provides a mock function with given fields: ctx, photo
+func (_m *TimelineQuery) FanOut(ctx context.Context, photo model.Photo) error {
+	ret := _m.Called(ctx, photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FanOut")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Photo) error); ok {
+		r0 = rf(ctx, photo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFanoutOnReadPhotos provides a mock function with given fields: ctx, userID, cursor, limit
+func (_m *TimelineQuery) GetFanoutOnReadPhotos(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFanoutOnReadPhotos")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Photo, error)); ok {
+		return rf(ctx, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Photo); ok {
+		r0 = rf(ctx, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTimeline provides a mock function with given fields: ctx, userID, cursor, limit
+func (_m *TimelineQuery) GetTimeline(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimeline")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Photo, error)); ok {
+		return rf(ctx, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Photo); ok {
+		r0 = rf(ctx, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveAuthor provides a mock function with given fields: ctx, userID, authorID
+func (_m *TimelineQuery) RemoveAuthor(ctx context.Context, userID uint64, authorID uint64) error {
+	ret := _m.Called(ctx, userID, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAuthor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, userID, authorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetFanoutOnRead provides a mock function with given fields: ctx, userID
+func (_m *TimelineQuery) SetFanoutOnRead(ctx context.Context, userID uint64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetFanoutOnRead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTimelineQuery creates a new instance of TimelineQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTimelineQuery(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TimelineQuery {
+	mock := &TimelineQuery{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,14 +1,16 @@
-// Code generated by mockery v2.42.0. DO NOT EDIT.
+// Code generated by mockery v2.53.3. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
 
-	// model "github.com/Calmantara/go-kominfo-2024/go-middleware/internal/model"
-	// mock "github.com/stretchr/testify/mock"
 	model "github.com/MidnightHelix/MyGram/internal/model"
 	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/MidnightHelix/MyGram/internal/repository"
+
+	time "time"
 )
 
 // UserQuery is an autogenerated mock type for the UserQuery type
@@ -16,6 +18,24 @@ type UserQuery struct {
 	mock.Mock
 }
 
+// ChangeUsername provides a mock function with given fields: ctx, id, username, at
+func (_m *UserQuery) ChangeUsername(ctx context.Context, id uint64, username string, at time.Time) error {
+	ret := _m.Called(ctx, id, username, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeUsername")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, time.Time) error); ok {
+		r0 = rf(ctx, id, username, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateUser provides a mock function with given fields: ctx, user
 func (_m *UserQuery) CreateUser(ctx context.Context, user model.User) (model.User, error) {
 	ret := _m.Called(ctx, user)
@@ -44,9 +64,139 @@ func (_m *UserQuery) CreateUser(ctx context.Context, user model.User) (model.Use
 	return r0, r1
 }
 
-// GetUsers provides a mock function with given fields: ctx
-func (_m *UserQuery) GetUsers(ctx context.Context) ([]model.User, error) {
-	ret := _m.Called(ctx)
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *UserQuery) DeleteUser(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EditUser provides a mock function with given fields: ctx, editUser, id
+func (_m *UserQuery) EditUser(ctx context.Context, editUser model.User, id uint64) (model.User, error) {
+	ret := _m.Called(ctx, editUser, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditUser")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.User, uint64) (model.User, error)); ok {
+		return rf(ctx, editUser, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.User, uint64) model.User); ok {
+		r0 = rf(ctx, editUser, id)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.User, uint64) error); ok {
+		r1 = rf(ctx, editUser, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByEmail provides a mock function with given fields: ctx, email
+func (_m *UserQuery) FindByEmail(ctx context.Context, email string) (model.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByEmail")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUsername provides a mock function with given fields: ctx, username
+func (_m *UserQuery) FindByUsername(ctx context.Context, username string) (model.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByUsername")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsernameRedirect provides a mock function with given fields: ctx, username
+func (_m *UserQuery) GetUsernameRedirect(ctx context.Context, username string) (model.UsernameRedirect, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsernameRedirect")
+	}
+
+	var r0 model.UsernameRedirect
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.UsernameRedirect, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.UsernameRedirect); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(model.UsernameRedirect)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields: ctx, viewerID, search
+func (_m *UserQuery) GetUsers(ctx context.Context, viewerID uint64, search repository.UserSearch) ([]model.User, error) {
+	ret := _m.Called(ctx, viewerID, search)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUsers")
@@ -54,19 +204,19 @@ func (_m *UserQuery) GetUsers(ctx context.Context) ([]model.User, error) {
 
 	var r0 []model.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]model.User, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.UserSearch) ([]model.User, error)); ok {
+		return rf(ctx, viewerID, search)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []model.User); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, repository.UserSearch) []model.User); ok {
+		r0 = rf(ctx, viewerID, search)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, repository.UserSearch) error); ok {
+		r1 = rf(ctx, viewerID, search)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -102,6 +252,244 @@ func (_m *UserQuery) GetUsersByID(ctx context.Context, id uint64) (model.User, e
 	return r0, r1
 }
 
+// GetUsersByIDUnscoped provides a mock function with given fields: ctx, id
+func (_m *UserQuery) GetUsersByIDUnscoped(ctx context.Context, id uint64) (model.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByIDUnscoped")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsEmailTaken provides a mock function with given fields: ctx, email, exceptUserID
+func (_m *UserQuery) IsEmailTaken(ctx context.Context, email string, exceptUserID uint64) (bool, error) {
+	ret := _m.Called(ctx, email, exceptUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsEmailTaken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) (bool, error)); ok {
+		return rf(ctx, email, exceptUserID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) bool); ok {
+		r0 = rf(ctx, email, exceptUserID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64) error); ok {
+		r1 = rf(ctx, email, exceptUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsUsernameTaken provides a mock function with given fields: ctx, username, exceptUserID
+func (_m *UserQuery) IsUsernameTaken(ctx context.Context, username string, exceptUserID uint64) (bool, error) {
+	ret := _m.Called(ctx, username, exceptUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsUsernameTaken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) (bool, error)); ok {
+		return rf(ctx, username, exceptUserID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) bool); ok {
+		r0 = rf(ctx, username, exceptUserID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64) error); ok {
+		r1 = rf(ctx, username, exceptUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPasswordWithToken provides a mock function with given fields: ctx, id, tokenHash, password, at
+func (_m *UserQuery) ResetPasswordWithToken(ctx context.Context, id uint64, tokenHash string, password string, at time.Time) (bool, error) {
+	ret := _m.Called(ctx, id, tokenHash, password, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPasswordWithToken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string, time.Time) (bool, error)); ok {
+		return rf(ctx, id, tokenHash, password, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, string, time.Time) bool); ok {
+		r0 = rf(ctx, id, tokenHash, password, at)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string, string, time.Time) error); ok {
+		r1 = rf(ctx, id, tokenHash, password, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreUser provides a mock function with given fields: ctx, id
+func (_m *UserQuery) RestoreUser(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeTokens provides a mock function with given fields: ctx, id, at
+func (_m *UserQuery) RevokeTokens(ctx context.Context, id uint64, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetBan provides a mock function with given fields: ctx, id, bannedAt, reason
+func (_m *UserQuery) SetBan(ctx context.Context, id uint64, bannedAt *time.Time, reason string) error {
+	ret := _m.Called(ctx, id, bannedAt, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *time.Time, string) error); ok {
+		r0 = rf(ctx, id, bannedAt, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPasswordResetToken provides a mock function with given fields: ctx, id, tokenHash
+func (_m *UserQuery) SetPasswordResetToken(ctx context.Context, id uint64, tokenHash string) error {
+	ret := _m.Called(ctx, id, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPasswordResetToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = rf(ctx, id, tokenHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSuspension provides a mock function with given fields: ctx, id, until, reason
+func (_m *UserQuery) SetSuspension(ctx context.Context, id uint64, until *time.Time, reason string) error {
+	ret := _m.Called(ctx, id, until, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSuspension")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *time.Time, string) error); ok {
+		r0 = rf(ctx, id, until, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, id, password, at
+func (_m *UserQuery) UpdatePassword(ctx context.Context, id uint64, password string, at time.Time) error {
+	ret := _m.Called(ctx, id, password, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, time.Time) error); ok {
+		r0 = rf(ctx, id, password, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePrivacy provides a mock function with given fields: ctx, id, isPrivate
+func (_m *UserQuery) UpdatePrivacy(ctx context.Context, id uint64, isPrivate bool) error {
+	ret := _m.Called(ctx, id, isPrivate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePrivacy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, bool) error); ok {
+		r0 = rf(ctx, id, isPrivate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserQuery creates a new instance of UserQuery. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserQuery(t interface {
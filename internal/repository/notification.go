@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+type NotificationQuery interface {
+	// GetNotifications returns a user's notifications, newest first, leaving
+	// out ones whose actor is blocked or sanctioned or whose photo or
+	// comment is gone. cursor is the id of the last notification of the
+	// previous page, 0 starts from the beginning.
+	GetNotifications(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Notification, error)
+
+	CreateNotifications(ctx context.Context, notifications []model.Notification) error
+	MarkNotificationsRead(ctx context.Context, userID uint64, at time.Time) error
+}
+
+type notificationQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewNotificationQuery(db infrastructure.GormPostgres) NotificationQuery {
+	return &notificationQueryImpl{db: db}
+}
+
+func (u *notificationQueryImpl) GetNotifications(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Notification, error) {
+	db := u.db.GetConnection()
+	notifications := []model.Notification{}
+	query := db.
+		WithContext(ctx).
+		Table("notifications").
+		Where("user_id = ?", userID).
+		Where("photo_id IN (?)", visiblePhotoIDs(db, userID)).
+		Where("comment_id IS NULL OR comment_id IN (SELECT id FROM comments WHERE deleted_at IS NULL)").
+		Scopes(notBlocked(userID, "actor_id"), activeUsers("actor_id"))
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Actor", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (u *notificationQueryImpl) CreateNotifications(ctx context.Context, notifications []model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("notifications").
+		Create(&notifications).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *notificationQueryImpl) MarkNotificationsRead(ctx context.Context, userID uint64, at time.Time) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("notifications").
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at).Error; err != nil {
+		return err
+	}
+	return nil
+}
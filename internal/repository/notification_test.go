@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotifications(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "notifications" WHERE user_id = $1 AND photo_id IN (SELECT photos.id FROM "photos" WHERE photos.deleted_at IS NULL AND (photos.user_id = $2 OR`) + ".*" +
+		regexp.QuoteMeta(`AND id < $`) + ".*" + regexp.QuoteMeta(`ORDER BY id DESC LIMIT $`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "actor_id", "type", "photo_id"}).AddRow(9, 1, 2, "mention", 7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","username" FROM "users" WHERE "users"."id" = $1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "bob"))
+
+	notificationRepo := notificationQueryImpl{db: postgresMock}
+	res, err := notificationRepo.GetNotifications(context.Background(), 1, 10, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, "bob", res[0].Actor.Username)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -5,15 +5,62 @@ import (
 
 	"github.com/MidnightHelix/MyGram/internal/infrastructure"
 	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PhotoQuery interface {
 	GetPhotos(ctx context.Context, userID uint64) ([]model.Photo, error)
 	GetPhotosByID(ctx context.Context, id uint64) (model.Photo, error)
+	// IsPhotoVisible reports whether the viewer may see the photo.
+	IsPhotoVisible(ctx context.Context, viewerID uint64, id uint64) (bool, error)
+	// IsObjectVisible reports whether key is an image or rendition of a
+	// photo the viewer may see. Uploads share objects by content, one of
+	// the photos using key is enough.
+	IsObjectVisible(ctx context.Context, viewerID uint64, key string) (bool, error)
+	// GetPhoto loads a photo the viewer may see with its author and images,
+	// the id is zero when there is none. Photos still processing or failed
+	// are only shown to their author.
+	GetPhoto(ctx context.Context, viewerID uint64, id uint64) (model.Photo, error)
+	// GetUserPhotos lists the photos of userID the viewer may see for their
+	// profile grid, newest first, starting below the photo id cursor when
+	// it is not zero.
+	GetUserPhotos(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Photo, error)
 
 	CreatePhoto(ctx context.Context, photo model.Photo) (model.Photo, error)
-	EditPhoto(ctx context.Context, photo model.Photo, id uint64) (model.Photo, error)
+	// EditPhoto replaces the title, caption and visibility of a photo. A
+	// changed title or caption is recorded as a revision by editorID.
+	EditPhoto(ctx context.Context, photo model.Photo, id uint64, editorID uint64) (model.Photo, error)
+	// GetPhotoRevisions lists the earlier titles and captions of a photo
+	// newest first.
+	GetPhotoRevisions(ctx context.Context, id uint64, cursor uint64, limit int) ([]model.Revision, error)
+	// SetPhotoLocation stores the location of photo as given, nil
+	// coordinates and an empty place name remove it.
+	SetPhotoLocation(ctx context.Context, id uint64, latitude *float64, longitude *float64, placeName string) error
 	DeletePhoto(ctx context.Context, id uint64) error
+
+	// processing
+	GetPhotosByStatus(ctx context.Context, status string, afterID uint64, limit int) ([]model.Photo, error)
+	MarkPhotoFailed(ctx context.Context, id uint64) error
+	SaveVariants(ctx context.Context, id uint64, width int, height int, variants []model.PhotoVariant) error
+
+	// near-duplicates
+	FindByHashBands(ctx context.Context, probes [][]int32, userID uint64, excludeID uint64, limit int) ([]model.Photo, error)
+
+	// media items
+	GetMediaItem(ctx context.Context, photoID uint64, id uint64) (model.MediaItem, error)
+	// AddMediaItem appends an item to a post holding fewer than maxItems and
+	// reports whether it was added.
+	AddMediaItem(ctx context.Context, item model.MediaItem, maxItems int) (model.MediaItem, bool, error)
+	EditMediaItem(ctx context.Context, id uint64, altText string) error
+	// DeleteMediaItem and ReorderMediaItems keep the positions of a post's
+	// items numbered from 1 and copy a new first item onto the photo. They
+	// report whether that happened, the old cover's variants are dropped
+	// then and new ones have to be rendered. The last item of a post is
+	// never deleted.
+	DeleteMediaItem(ctx context.Context, photoID uint64, id uint64) (bool, error)
+	// ReorderMediaItems numbers the items of a post in the order of ids.
+	ReorderMediaItems(ctx context.Context, photoID uint64, ids []uint64) (bool, error)
 }
 
 type PhotoCommand interface {
@@ -39,6 +86,9 @@ func (u *photoQueryImpl) GetPhotos(ctx context.Context, userID uint64) ([]model.
 		// 	return db.Select("ID", "Username", "Email")
 		// }).
 		Preload("User").
+		Preload("Media", mediaInOrder).
+		Preload("Variants").
+		Preload("Mentions").
 		Find(&photos).Error; err != nil {
 		return nil, err
 	}
@@ -52,12 +102,95 @@ func (u *photoQueryImpl) GetPhotosByID(ctx context.Context, id uint64) (model.Ph
 		WithContext(ctx).
 		Table("photos").
 		Where("id = ?", id).
+		Preload("Media", mediaInOrder).
+		Preload("Variants").
 		Find(&photo).Error; err != nil {
 		return model.Photo{}, err
 	}
 	return photo, nil
 }
 
+func (u *photoQueryImpl) IsPhotoVisible(ctx context.Context, viewerID uint64, id uint64) (bool, error) {
+	db := u.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Table("photos").
+		Where("photos.id = ? AND photos.deleted_at IS NULL", id).
+		Scopes(visiblePhotos(viewerID, "photos")).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (u *photoQueryImpl) IsObjectVisible(ctx context.Context, viewerID uint64, key string) (bool, error) {
+	db := u.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Table("photos").
+		Where("photos.deleted_at IS NULL").
+		Where("photos.object_key = ? OR "+
+			"photos.id IN (SELECT photo_id FROM media_items WHERE object_key = ?) OR "+
+			"photos.id IN (SELECT photo_id FROM photo_variants WHERE object_key = ?)", key, key, key).
+		Scopes(visiblePhotos(viewerID, "photos")).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (u *photoQueryImpl) GetPhoto(ctx context.Context, viewerID uint64, id uint64) (model.Photo, error) {
+	db := u.db.GetConnection()
+	photo := model.Photo{}
+	if err := db.
+		WithContext(ctx).
+		Table("photos").
+		Where("photos.id = ? AND photos.deleted_at IS NULL", id).
+		Scopes(visiblePhotos(viewerID, "photos"), readyPhotos(viewerID)).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username", "DisplayName", "IsPrivate")
+		}).
+		Preload("Media", mediaInOrder).
+		Preload("Variants").
+		Preload("Mentions").
+		Find(&photo).Error; err != nil {
+		return model.Photo{}, err
+	}
+	return photo, nil
+}
+
+func (u *photoQueryImpl) GetUserPhotos(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	db := u.db.GetConnection()
+	photos := []model.Photo{}
+	query := db.
+		WithContext(ctx).
+		Table("photos").
+		Where("photos.user_id = ? AND photos.deleted_at IS NULL", userID).
+		Scopes(visiblePhotos(viewerID, "photos"), readyPhotos(viewerID))
+	if cursor > 0 {
+		query = query.Where("photos.id < ?", cursor)
+	}
+	if err := query.
+		Preload("Media", mediaInOrder).
+		Preload("Variants").
+		Order("photos.id DESC").
+		Limit(limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// readyPhotos hides photos that are still processing or failed from
+// everyone but their author.
+func readyPhotos(viewerID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("photos.status = ? OR photos.user_id = ?", model.PhotoStatusReady, viewerID)
+	}
+}
+
 func (u *photoQueryImpl) CreatePhoto(ctx context.Context, photo model.Photo) (model.Photo, error) {
 	db := u.db.GetConnection()
 	if err := db.
@@ -69,17 +202,67 @@ func (u *photoQueryImpl) CreatePhoto(ctx context.Context, photo model.Photo) (mo
 	return photo, nil
 }
 
-func (u *photoQueryImpl) EditPhoto(ctx context.Context, photo model.Photo, id uint64) (model.Photo, error) {
+// EditPhoto keeps the title and caption the photo had as a revision when
+// either of them changes and marks the photo edited. The row is locked so
+// concurrent edits each keep the text the other one replaced.
+func (u *photoQueryImpl) EditPhoto(ctx context.Context, photo model.Photo, id uint64, editorID uint64) (model.Photo, error) {
+	db := u.db.GetConnection()
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		previous := model.Photo{}
+		if err := tx.
+			Table("photos").
+			Select("id", "title", "caption").
+			Where("id = ?", id).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&previous).Error; err != nil {
+			return err
+		}
+
+		columns := []string{"title", "caption"}
+		if photo.Visibility != "" {
+			columns = append(columns, "visibility")
+		}
+		if previous.ID != 0 && (previous.Title != photo.Title || previous.Caption != photo.Caption) {
+			revision := model.Revision{
+				SourceType: model.RevisionSourcePhoto,
+				SourceID:   id,
+				EditorID:   editorID,
+				Title:      previous.Title,
+				Text:       previous.Caption,
+			}
+			if err := tx.Create(&revision).Error; err != nil {
+				return err
+			}
+			photo.EditedAt = &revision.CreatedAt
+			columns = append(columns, "edited_at")
+		}
+		return tx.
+			Table("photos").
+			Where("id = ?", id).
+			Select(columns).
+			Updates(model.Photo{Title: photo.Title, Caption: photo.Caption, Visibility: photo.Visibility, EditedAt: photo.EditedAt}).Error
+	})
+	if err != nil {
+		return model.Photo{}, err
+	}
+	return photo, nil
+}
+
+func (u *photoQueryImpl) GetPhotoRevisions(ctx context.Context, id uint64, cursor uint64, limit int) ([]model.Revision, error) {
+	db := u.db.GetConnection()
+	return getRevisions(db.WithContext(ctx), model.RevisionSourcePhoto, id, cursor, limit)
+}
+
+func (u *photoQueryImpl) SetPhotoLocation(ctx context.Context, id uint64, latitude *float64, longitude *float64, placeName string) error {
 	db := u.db.GetConnection()
 	if err := db.
 		WithContext(ctx).
 		Table("photos").
 		Where("id = ?", id).
-		Select("title", "caption", "url").
-		Updates(model.Photo{Title: photo.Title, Caption: photo.Caption, Url: photo.Url}).Error; err != nil {
-		return model.Photo{}, err
+		Updates(map[string]interface{}{"latitude": latitude, "longitude": longitude, "place_name": placeName}).Error; err != nil {
+		return err
 	}
-	return photo, nil
+	return nil
 }
 
 func (u *photoQueryImpl) DeletePhoto(ctx context.Context, id uint64) error {
@@ -92,3 +275,254 @@ func (u *photoQueryImpl) DeletePhoto(ctx context.Context, id uint64) error {
 	}
 	return nil
 }
+
+// GetPhotosByStatus returns photos with status in id order, afterID is the
+// id of the last photo of the previous page, 0 starts from the beginning.
+func (u *photoQueryImpl) GetPhotosByStatus(ctx context.Context, status string, afterID uint64, limit int) ([]model.Photo, error) {
+	db := u.db.GetConnection()
+	photos := []model.Photo{}
+	if err := db.
+		WithContext(ctx).
+		Table("photos").
+		Where("status = ? AND id > ?", status, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// MarkPhotoFailed gives up on a photo still processing, a ready photo
+// whose re-render failed keeps serving its previous variants.
+func (u *photoQueryImpl) MarkPhotoFailed(ctx context.Context, id uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("photos").
+		Where("id = ? AND status = ?", id, model.PhotoStatusProcessing).
+		Update("status", model.PhotoStatusFailed).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// SaveVariants replaces the renditions of a photo, records the original
+// dimensions and marks it ready.
+func (u *photoQueryImpl) SaveVariants(ctx context.Context, id uint64, width int, height int, variants []model.PhotoVariant) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Table("photo_variants").
+			Where("photo_id = ?", id).
+			Delete(&model.PhotoVariant{}).Error; err != nil {
+			return err
+		}
+		if len(variants) > 0 {
+			if err := tx.
+				Table("photo_variants").
+				Create(&variants).Error; err != nil {
+				return err
+			}
+		}
+		return tx.
+			Table("photos").
+			Where("id = ?", id).
+			Updates(map[string]any{"width": width, "height": height, "status": model.PhotoStatusReady}).Error
+	})
+}
+
+// FindByHashBands returns photos with a PHash band equal to one of the
+// probed values, probes[i] being the values of band i. A zero userID
+// searches every user's photos. The caller checks the actual distance.
+func (u *photoQueryImpl) FindByHashBands(ctx context.Context, probes [][]int32, userID uint64, excludeID uint64, limit int) ([]model.Photo, error) {
+	db := u.db.GetConnection()
+	photos := []model.Photo{}
+
+	bands := db.Table("photo_hash_bands").Select("photo_id")
+	match := db
+	for band, values := range probes {
+		match = match.Or("band = ? AND value IN ?", band, values)
+	}
+	bands = bands.Where(match)
+
+	query := db.
+		WithContext(ctx).
+		Table("photos").
+		Where("id IN (?)", bands).
+		Where("id <> ?", excludeID)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.
+		Preload("User").
+		Order("id DESC").
+		Limit(limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// mediaInOrder preloads the media items of a post in carousel order.
+func mediaInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("media_items.position")
+}
+
+func (u *photoQueryImpl) GetMediaItem(ctx context.Context, photoID uint64, id uint64) (model.MediaItem, error) {
+	db := u.db.GetConnection()
+	item := model.MediaItem{}
+	if err := db.
+		WithContext(ctx).
+		Table("media_items").
+		Where("id = ? AND photo_id = ?", id, photoID).
+		Find(&item).Error; err != nil {
+		return model.MediaItem{}, err
+	}
+	return item, nil
+}
+
+func (u *photoQueryImpl) AddMediaItem(ctx context.Context, item model.MediaItem, maxItems int) (model.MediaItem, bool, error) {
+	db := u.db.GetConnection()
+	added := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// adds to the same post wait for each other so the limit holds
+		if err := lockPhoto(tx, item.PhotoID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Table("media_items").Where("photo_id = ?", item.PhotoID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxItems) {
+			return nil
+		}
+		item.Position = int(count) + 1
+		if err := tx.Table("media_items").Create(&item).Error; err != nil {
+			return err
+		}
+		added = true
+		return nil
+	})
+	if err != nil {
+		return model.MediaItem{}, false, err
+	}
+	return item, added, nil
+}
+
+func (u *photoQueryImpl) EditMediaItem(ctx context.Context, id uint64, altText string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("media_items").
+		Where("id = ?", id).
+		Update("alt_text", altText).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *photoQueryImpl) DeleteMediaItem(ctx context.Context, photoID uint64, id uint64) (bool, error) {
+	db := u.db.GetConnection()
+	changed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPhoto(tx, photoID); err != nil {
+			return err
+		}
+		item := model.MediaItem{}
+		if err := tx.Table("media_items").Where("id = ? AND photo_id = ?", id, photoID).Find(&item).Error; err != nil {
+			return err
+		}
+		if item.ID == 0 {
+			return nil
+		}
+		// a post keeps at least one item, even against concurrent deletes
+		res := tx.Exec("DELETE FROM media_items WHERE id = ? AND (SELECT COUNT(*) FROM media_items WHERE photo_id = ?) > 1", id, photoID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Exec("UPDATE media_items SET position = position - 1 WHERE photo_id = ? AND position > ?", photoID, item.Position).Error; err != nil {
+			return err
+		}
+		var err error
+		changed, err = syncCover(tx, photoID)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+func (u *photoQueryImpl) ReorderMediaItems(ctx context.Context, photoID uint64, ids []uint64) (bool, error) {
+	db := u.db.GetConnection()
+	changed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPhoto(tx, photoID); err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := tx.
+				Table("media_items").
+				Where("id = ? AND photo_id = ?", id, photoID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		var err error
+		changed, err = syncCover(tx, photoID)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// lockPhoto holds the photo row until the transaction ends, changes to the
+// items of a post run one at a time.
+func lockPhoto(tx *gorm.DB, photoID uint64) error {
+	photo := model.Photo{}
+	return tx.
+		Table("photos").
+		Select("id").
+		Where("id = ?", photoID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&photo).Error
+}
+
+// syncCover copies the first item of a post onto the photo when it is not
+// the one there already and drops the variants rendered from the old one.
+func syncCover(tx *gorm.DB, photoID uint64) (bool, error) {
+	first := model.MediaItem{}
+	if err := tx.Table("media_items").Where("photo_id = ?", photoID).Order("position").Limit(1).Find(&first).Error; err != nil {
+		return false, err
+	}
+	if first.ID == 0 {
+		return false, nil
+	}
+	res := tx.
+		Table("photos").
+		Where("id = ? AND object_key <> ?", photoID, first.ObjectKey).
+		Updates(map[string]any{
+			"url":          first.Url,
+			"object_key":   first.ObjectKey,
+			"content_type": first.ContentType,
+			"size":         first.Size,
+			"width":        first.Width,
+			"height":       first.Height,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	if err := tx.Table("photo_variants").Where("photo_id = ?", photoID).Delete(&model.PhotoVariant{}).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
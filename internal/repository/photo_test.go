@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindByHashBands(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+	row := sqlmock.
+		NewRows([]string{"id", "user_id"}).
+		AddRow(3, 1)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id IN (SELECT photo_id FROM "photo_hash_bands" WHERE (band = $1 AND value IN ($2,$3)) OR (band = $4 AND value IN ($5))) AND id <> $6 AND user_id = $7`)).
+		WithArgs(0, 1, 2, 1, 3, 5, 1, 50).
+		WillReturnRows(row)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	photoRepo := photoQueryImpl{db: postgresMock}
+	res, err := photoRepo.FindByHashBands(context.Background(), [][]int32{{1, 2}, {3}}, 1, 5, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+}
+
+func TestGetPhotosByStatus(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos" WHERE (status = $1 AND id > $2) AND "photos"."deleted_at" IS NULL ORDER BY id LIMIT $3`)).
+		WithArgs(model.PhotoStatusProcessing, 500, 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(501))
+
+	photoRepo := photoQueryImpl{db: postgresMock}
+	res, err := photoRepo.GetPhotosByStatus(context.Background(), model.PhotoStatusProcessing, 500, 500)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkPhotoFailed(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	// a ready photo is left alone, only one still processing fails
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photos" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(model.PhotoStatusFailed, 7, model.PhotoStatusProcessing).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	photoRepo := photoQueryImpl{db: postgresMock}
+	err := photoRepo.MarkPhotoFailed(context.Background(), 7)
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMediaItem(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "photos" WHERE id = $1 AND "photos"."deleted_at" IS NULL FOR UPDATE`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items" WHERE id = $1 AND photo_id = $2`)).
+		WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id", "position"}).AddRow(1, 7, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM media_items WHERE id = $1 AND (SELECT COUNT(*) FROM media_items WHERE photo_id = $2) > 1`)).
+		WithArgs(1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE media_items SET position = position - 1 WHERE photo_id = $1 AND position > $2`)).
+		WithArgs(7, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items" WHERE photo_id = $1 ORDER BY position LIMIT $2`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id", "position", "url", "object_key"}).AddRow(2, 7, 1, "/uploads/b", "b"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photos" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "photo_variants" WHERE photo_id = $1`)).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	photoRepo := photoQueryImpl{db: postgresMock}
+	changed, err := photoRepo.DeleteMediaItem(context.Background(), 7, 1)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestReorderMediaItems(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "photos" WHERE id = $1 AND "photos"."deleted_at" IS NULL FOR UPDATE`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "media_items" SET "position"=$1 WHERE id = $2 AND photo_id = $3`)).
+		WithArgs(1, 2, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "media_items" SET "position"=$1 WHERE id = $2 AND photo_id = $3`)).
+		WithArgs(2, 1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items" WHERE photo_id = $1 ORDER BY position LIMIT $2`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id", "position", "object_key"}).AddRow(2, 7, 1, "b"))
+	// the cover did not change, its variants stay
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photos" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	photoRepo := photoQueryImpl{db: postgresMock}
+	changed, err := photoRepo.ReorderMediaItems(context.Background(), 7, []uint64{2, 1})
+	assert.Nil(t, err)
+	assert.False(t, changed)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserPhotos(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos" WHERE (photos.user_id = $1 AND photos.deleted_at IS NULL) AND photos.id < $2 AND `)+visiblePhotosSQL+
+		regexp.QuoteMeta(` AND (photos.status = $14 OR photos.user_id = $15) AND "photos"."deleted_at" IS NULL ORDER BY photos.id DESC LIMIT $16`)).
+		WithArgs(2, 40, 1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg(), "ready", 1, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(39, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items" WHERE "media_items"."photo_id" = $1 ORDER BY media_items.position`)).
+		WithArgs(39).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "photo_id", "position"}).AddRow(5, 39, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants" WHERE "photo_variants"."photo_id" = $1`)).
+		WithArgs(39).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	photoRepo := photoQueryImpl{db: postgresMock}
+	res, err := photoRepo.GetUserPhotos(context.Background(), 1, 2, 40, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, 1, len(res[0].Media))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestEditPhoto(t *testing.T) {
+	t.Run("changed caption keeps a revision", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","title","caption" FROM "photos" WHERE id = $1 AND "photos"."deleted_at" IS NULL FOR UPDATE`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "caption"}).AddRow(7, "t", "before"))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "revisions" ("source_type","source_id","editor_id","title","text","created_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+			WithArgs("photos", 7, 2, "t", "before", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photos" SET "title"=$1,"caption"=$2,"updated_at"=$3,"edited_at"=$4 WHERE id = $5`)).
+			WithArgs("t", "after", sqlmock.AnyArg(), sqlmock.AnyArg(), 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		photoRepo := photoQueryImpl{db: postgresMock}
+		res, err := photoRepo.EditPhoto(context.Background(), model.Photo{Title: "t", Caption: "after"}, 7, 2)
+		assert.Nil(t, err)
+		assert.NotNil(t, res.EditedAt)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("visibility only is not an edit", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","title","caption" FROM "photos"`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "caption"}).AddRow(7, "t", "before"))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "photos" SET "title"=$1,"caption"=$2,"visibility"=$3,"updated_at"=$4 WHERE id = $5`)).
+			WithArgs("t", "before", "followers", sqlmock.AnyArg(), 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		photoRepo := photoQueryImpl{db: postgresMock}
+		res, err := photoRepo.EditPhoto(context.Background(), model.Photo{Title: "t", Caption: "before", Visibility: "followers"}, 7, 2)
+		assert.Nil(t, err)
+		assert.Nil(t, res.EditedAt)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
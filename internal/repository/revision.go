@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+// getRevisions lists the revisions of a photo or comment newest first with
+// their editors. cursor is the id of the last revision of the previous page,
+// 0 starts from the newest.
+func getRevisions(db *gorm.DB, sourceType string, sourceID uint64, cursor uint64, limit int) ([]model.Revision, error) {
+	revisions := []model.Revision{}
+	query := db.
+		Table("revisions").
+		Where("source_type = ? AND source_id = ?", sourceType, sourceID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Editor", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Username", "DisplayName")
+		}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
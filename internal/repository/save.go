@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+type SaveQuery interface {
+	// GetSaves lists a user's saved photos, newest save first, leaving out
+	// photos that were deleted or that the user may no longer see. A
+	// collectionID other than zero lists only that collection. cursor is the
+	// save id of the last item of the previous page, 0 starts from the
+	// beginning.
+	GetSaves(ctx context.Context, userID uint64, collectionID uint64, cursor uint64, limit int) ([]model.Save, error)
+	// GetSavedPhotoIDs returns which of photoIDs the user has saved.
+	GetSavedPhotoIDs(ctx context.Context, userID uint64, photoIDs []uint64) ([]uint64, error)
+
+	// CreateSave and DeleteSave report whether anything changed, saving a
+	// photo twice or unsaving one that is not saved is not an error.
+	// DeleteSave also takes the photo out of the user's collections.
+	CreateSave(ctx context.Context, userID uint64, photoID uint64) (bool, error)
+	DeleteSave(ctx context.Context, userID uint64, photoID uint64) (bool, error)
+
+	GetCollections(ctx context.Context, userID uint64) ([]model.SaveCollection, error)
+	// GetCollection returns a collection of userID, or an empty one when it
+	// does not exist or belongs to someone else.
+	GetCollection(ctx context.Context, userID uint64, id uint64) (model.SaveCollection, error)
+	CreateCollection(ctx context.Context, collection model.SaveCollection) (model.SaveCollection, error)
+	RenameCollection(ctx context.Context, id uint64, name string) error
+	// DeleteCollection removes a collection, its photos stay saved.
+	DeleteCollection(ctx context.Context, id uint64) error
+	AddToCollection(ctx context.Context, id uint64, photoID uint64) error
+	RemoveFromCollection(ctx context.Context, id uint64, photoID uint64) error
+}
+
+type saveQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewSaveQuery(db infrastructure.GormPostgres) SaveQuery {
+	return &saveQueryImpl{db: db}
+}
+
+func (u *saveQueryImpl) GetSaves(ctx context.Context, userID uint64, collectionID uint64, cursor uint64, limit int) ([]model.Save, error) {
+	db := u.db.GetConnection()
+	saves := []model.Save{}
+	query := db.
+		WithContext(ctx).
+		Table("saves").
+		Select("saves.*").
+		Joins("JOIN photos ON photos.id = saves.photo_id AND photos.deleted_at IS NULL").
+		Where("saves.user_id = ? AND photos.status = ?", userID, model.PhotoStatusReady)
+	if collectionID > 0 {
+		query = query.Joins("JOIN save_collection_photos ON save_collection_photos.photo_id = saves.photo_id AND save_collection_photos.collection_id = ?", collectionID)
+	}
+	if cursor > 0 {
+		query = query.Where("saves.id < ?", cursor)
+	}
+	if err := query.
+		Scopes(visiblePhotos(userID, "photos")).
+		Preload("Photo").
+		Preload("Photo.Mentions").
+		Preload("Photo.User").
+		Preload("Photo.Media", mediaInOrder).
+		Preload("Photo.Variants").
+		Order("saves.id DESC").
+		Limit(limit).
+		Find(&saves).Error; err != nil {
+		return nil, err
+	}
+	return saves, nil
+}
+
+func (u *saveQueryImpl) GetSavedPhotoIDs(ctx context.Context, userID uint64, photoIDs []uint64) ([]uint64, error) {
+	ids := []uint64{}
+	if len(photoIDs) == 0 {
+		return ids, nil
+	}
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("saves").
+		Where("user_id = ? AND photo_id IN ?", userID, photoIDs).
+		Pluck("photo_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (u *saveQueryImpl) CreateSave(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Exec("INSERT INTO saves (user_id, photo_id, created_at) VALUES (?, ?, NOW()) ON CONFLICT (user_id, photo_id) DO NOTHING", userID, photoID)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (u *saveQueryImpl) DeleteSave(ctx context.Context, userID uint64, photoID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	deleted := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM save_collection_photos WHERE photo_id = ?
+			AND collection_id IN (SELECT id FROM save_collections WHERE user_id = ?)`, photoID, userID).Error; err != nil {
+			return err
+		}
+		res := tx.Exec("DELETE FROM saves WHERE user_id = ? AND photo_id = ?", userID, photoID)
+		if res.Error != nil {
+			return res.Error
+		}
+		deleted = res.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
+
+func (u *saveQueryImpl) GetCollections(ctx context.Context, userID uint64) ([]model.SaveCollection, error) {
+	db := u.db.GetConnection()
+	collections := []model.SaveCollection{}
+	if err := db.
+		WithContext(ctx).
+		Table("save_collections").
+		Where("user_id = ?", userID).
+		Order("name, id").
+		Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+func (u *saveQueryImpl) GetCollection(ctx context.Context, userID uint64, id uint64) (model.SaveCollection, error) {
+	db := u.db.GetConnection()
+	collection := model.SaveCollection{}
+	if err := db.
+		WithContext(ctx).
+		Table("save_collections").
+		Where("id = ? AND user_id = ?", id, userID).
+		Find(&collection).Error; err != nil {
+		return model.SaveCollection{}, err
+	}
+	return collection, nil
+}
+
+func (u *saveQueryImpl) CreateCollection(ctx context.Context, collection model.SaveCollection) (model.SaveCollection, error) {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("save_collections").
+		Create(&collection).Error; err != nil {
+		return model.SaveCollection{}, err
+	}
+	return collection, nil
+}
+
+func (u *saveQueryImpl) RenameCollection(ctx context.Context, id uint64, name string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Model(&model.SaveCollection{ID: id}).
+		Update("name", name).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *saveQueryImpl) DeleteCollection(ctx context.Context, id uint64) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&model.SaveCollectionPhoto{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.SaveCollection{}).Error
+	})
+}
+
+func (u *saveQueryImpl) AddToCollection(ctx context.Context, id uint64, photoID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Exec("INSERT INTO save_collection_photos (collection_id, photo_id, created_at) VALUES (?, ?, NOW()) ON CONFLICT DO NOTHING", id, photoID).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *saveQueryImpl) RemoveFromCollection(ctx context.Context, id uint64, photoID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Where("collection_id = ? AND photo_id = ?", id, photoID).
+		Delete(&model.SaveCollectionPhoto{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
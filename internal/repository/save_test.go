@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSave(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO saves (user_id, photo_id, created_at) VALUES ($1, $2, NOW()) ON CONFLICT (user_id, photo_id) DO NOTHING`)).
+		WithArgs(1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	saveRepo := saveQueryImpl{db: postgresMock}
+	created, err := saveRepo.CreateSave(context.Background(), 1, 7)
+	assert.Nil(t, err)
+	assert.False(t, created)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSave(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM save_collection_photos WHERE photo_id = $1`)+".*"+regexp.QuoteMeta(`SELECT id FROM save_collections WHERE user_id = $2`)).
+		WithArgs(7, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM saves WHERE user_id = $1 AND photo_id = $2`)).
+		WithArgs(1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	saveRepo := saveQueryImpl{db: postgresMock}
+	deleted, err := saveRepo.DeleteSave(context.Background(), 1, 7)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSaves(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT saves.* FROM "saves" JOIN photos ON photos.id = saves.photo_id AND photos.deleted_at IS NULL JOIN save_collection_photos ON save_collection_photos.photo_id = saves.photo_id AND save_collection_photos.collection_id = $1 WHERE (saves.user_id = $2 AND photos.status = $3) AND saves.id < $4`) + ".*" + regexp.QuoteMeta(`ORDER BY saves.id DESC LIMIT $`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "photo_id"}).AddRow(5, 1, 7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos" WHERE "photos"."id" = $1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(7, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "bob"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants" WHERE "photo_variants"."photo_id" = $1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"photo_id", "name"}))
+
+	saveRepo := saveQueryImpl{db: postgresMock}
+	saves, err := saveRepo.GetSaves(context.Background(), 1, 3, 9, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(saves))
+	assert.Equal(t, uint64(7), saves[0].Photo.ID)
+	assert.Equal(t, "bob", saves[0].Photo.User.Username)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
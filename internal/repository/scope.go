@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+)
+
+// notBlocked drops rows whose userColumn belongs to someone the viewer has
+// blocked or who has blocked the viewer. Every query listing other people's
+// content should go through it.
+func notBlocked(viewerID uint64, userColumn string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where(userColumn+" NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = ?)", viewerID).
+			Where(userColumn+" NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = ?)", viewerID)
+	}
+}
+
+// notMuted drops rows authored by users the viewer has muted. Unlike blocks
+// this is one-directional and never surfaces to the muted user.
+func notMuted(viewerID uint64, userColumn string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(userColumn+" NOT IN (SELECT muted_id FROM mutes WHERE muter_id = ?)", viewerID)
+	}
+}
+
+// activeUsers drops rows owned by banned or currently suspended accounts so
+// their content disappears for everyone else while the sanction lasts.
+func activeUsers(userColumn string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(userColumn+" NOT IN (SELECT id FROM users WHERE banned_at IS NOT NULL OR suspended_until > ?)", time.Now())
+	}
+}
+
+// visiblePhotos is the one rule deciding which photos a viewer may see, every
+// query returning photos, or anything hanging off a photo, goes through it.
+// table is the name or alias of the photos table in the query. Authors see
+// all their photos. Anyone else needs an active author not blocked in either
+// direction, and then public photos of public accounts, public and
+// followers-only photos of accounts they follow, or close friends photos of
+// accounts that listed them as a close friend. Private photos are the
+// author's alone.
+func visiblePhotos(viewerID uint64, table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		author, visibility := table+".user_id", table+".visibility"
+		db = db.Where(author+" = ? OR "+
+			"("+visibility+" = ? AND "+author+" IN (SELECT id FROM users WHERE NOT is_private)) OR "+
+			"("+visibility+" IN ? AND "+author+" IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?)) OR "+
+			"("+visibility+" = ? AND "+author+" IN (SELECT user_id FROM close_friends WHERE friend_id = ?))",
+			viewerID, model.PhotoVisibilityPublic,
+			[]string{model.PhotoVisibilityPublic, model.PhotoVisibilityFollowers}, viewerID, model.FollowStatusAccepted,
+			model.PhotoVisibilityCloseFriends, viewerID)
+		return activeUsers(author)(notBlocked(viewerID, author)(db))
+	}
+}
+
+// visiblePhotoIDs is the subquery of the ids of live photos the viewer may
+// see, for filtering rows that point at photos.
+func visiblePhotoIDs(db *gorm.DB, viewerID uint64) *gorm.DB {
+	return db.
+		Session(&gorm.Session{NewDB: true}).
+		Table("photos").
+		Select("photos.id").
+		Where("photos.deleted_at IS NULL").
+		Scopes(visiblePhotos(viewerID, "photos"))
+}
+
+// visibleAlbums keeps albums the viewer owns or collaborates on, public
+// albums of accounts whose photos they may see, and followers-only albums of
+// accounts they follow.
+func visibleAlbums(viewerID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("albums.user_id = ? OR albums.id IN (SELECT album_id FROM album_collaborators WHERE user_id = ?) OR "+
+			"(albums.visibility = ? AND albums.user_id IN (SELECT id FROM users WHERE NOT is_private)) OR "+
+			"(albums.visibility IN ? AND albums.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?))",
+			viewerID, viewerID, model.AlbumVisibilityPublic,
+			[]string{model.AlbumVisibilityPublic, model.AlbumVisibilityFollowers}, viewerID, model.FollowStatusAccepted)
+	}
+}
+
+// visibleStories keeps stories of the viewer and of accounts they follow,
+// stories are for followers whether the account is public or private.
+// table is the name or alias of the stories table in the query.
+func visibleStories(viewerID uint64, table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		author := table + ".user_id"
+		db = db.Where(author+" = ? OR "+author+" IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?)",
+			viewerID, viewerID, model.FollowStatusAccepted)
+		return activeUsers(author)(notBlocked(viewerID, author)(db))
+	}
+}
+
+// visibleHighlights keeps highlights of the viewer, of public accounts and of
+// private accounts they follow, the same people who see the profile.
+func visibleHighlights(viewerID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("story_highlights.user_id = ? OR "+
+			"story_highlights.user_id IN (SELECT id FROM users WHERE NOT is_private) OR "+
+			"story_highlights.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?)",
+			viewerID, viewerID, model.FollowStatusAccepted)
+		return activeUsers("story_highlights.user_id")(notBlocked(viewerID, "story_highlights.user_id")(db))
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes user input safe to embed in a LIKE pattern.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
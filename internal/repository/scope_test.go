@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// visiblePhotosSQL matches the visiblePhotos predicate on the photos table
+// wherever its placeholders end up in a query.
+var visiblePhotosSQL = strings.ReplaceAll(regexp.QuoteMeta(`(photos.user_id = $1 OR `+
+	`(photos.visibility = $1 AND photos.user_id IN (SELECT id FROM users WHERE NOT is_private)) OR `+
+	`(photos.visibility IN ($1,$1) AND photos.user_id IN (SELECT following_id FROM follows WHERE follower_id = $1 AND status = $1)) OR `+
+	`(photos.visibility = $1 AND photos.user_id IN (SELECT user_id FROM close_friends WHERE friend_id = $1))) `+
+	`AND photos.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $1) `+
+	`AND photos.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $1) `+
+	`AND (photos.user_id NOT IN (SELECT id FROM users WHERE banned_at IS NOT NULL OR suspended_until > $1))`), `\$1`, `\$\d+`)
+
+// TestVisiblePhotos runs every query that returns photos, or rows pointing
+// at photos, and fails when one of them does not go through visiblePhotos.
+func TestVisiblePhotos(t *testing.T) {
+	queries := map[string]func(db infrastructure.GormPostgres) error{
+		"timeline": func(db infrastructure.GormPostgres) error {
+			_, err := (&timelineQueryImpl{db: db}).GetTimeline(context.Background(), 1, 0, 20)
+			return err
+		},
+		"fan-out on read": func(db infrastructure.GormPostgres) error {
+			_, err := (&timelineQueryImpl{db: db}).GetFanoutOnReadPhotos(context.Background(), 1, 0, 20)
+			return err
+		},
+		"explore": func(db infrastructure.GormPostgres) error {
+			_, err := (&exploreQueryImpl{db: db}).GetExplore(context.Background(), 1, ExplorePage{MaxPerAuthor: 3, Limit: 20})
+			return err
+		},
+		"tag": func(db infrastructure.GormPostgres) error {
+			_, err := (&tagQueryImpl{db: db}).GetTagPhotos(context.Background(), 1, 3, 0, 20)
+			return err
+		},
+		"album": func(db infrastructure.GormPostgres) error {
+			_, err := (&albumQueryImpl{db: db}).GetAlbumPhotos(context.Background(), 1, 4, 0, 20)
+			return err
+		},
+		"saved": func(db infrastructure.GormPostgres) error {
+			_, err := (&saveQueryImpl{db: db}).GetSaves(context.Background(), 1, 0, 0, 20)
+			return err
+		},
+		"comments": func(db infrastructure.GormPostgres) error {
+			_, err := (&commentQueryImpl{db: db}).GetComments(context.Background(), 1)
+			return err
+		},
+		"notifications": func(db infrastructure.GormPostgres) error {
+			_, err := (&notificationQueryImpl{db: db}).GetNotifications(context.Background(), 1, 0, 20)
+			return err
+		},
+		"search": func(db infrastructure.GormPostgres) error {
+			_, err := (&searchQueryImpl{db: db}).SearchPhotos(context.Background(), 1, PhotoSearch{Query: "beach", HalfLife: time.Hour, Now: time.Now(), Limit: 20})
+			return err
+		},
+		"nearby": func(db infrastructure.GormPostgres) error {
+			_, err := (&searchQueryImpl{db: db}).NearbyPhotos(context.Background(), 1, PhotoNearby{Latitude: -6.2, Longitude: 106.8, RadiusKm: 5, Limit: 20})
+			return err
+		},
+		"photo detail": func(db infrastructure.GormPostgres) error {
+			_, err := (&photoQueryImpl{db: db}).GetPhoto(context.Background(), 1, 7)
+			return err
+		},
+		"profile grid": func(db infrastructure.GormPostgres) error {
+			_, err := (&photoQueryImpl{db: db}).GetUserPhotos(context.Background(), 1, 2, 0, 20)
+			return err
+		},
+		"media signing": func(db infrastructure.GormPostgres) error {
+			_, err := (&photoQueryImpl{db: db}).IsObjectVisible(context.Background(), 1, "photos/1/a.png")
+			return err
+		},
+	}
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			db, mock := newMockGorm()
+			postgresMock := mocks.NewGormPostgres(t)
+			postgresMock.On("GetConnection").Return(db)
+			postgresMock.On("SearchLanguage").Return("english").Maybe()
+
+			mock.ExpectQuery(visiblePhotosSQL).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			assert.Nil(t, query(postgresMock))
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIsPhotoVisible(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "photos" WHERE (photos.id = $1 AND photos.deleted_at IS NULL) AND `)+visiblePhotosSQL).
+		WithArgs(7, 1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	photoRepo := photoQueryImpl{db: postgresMock}
+	visible, err := photoRepo.IsPhotoVisible(context.Background(), 1, 7)
+	assert.Nil(t, err)
+	assert.False(t, visible)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
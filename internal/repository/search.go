@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+	"gorm.io/gorm"
+)
+
+// PhotoSearch selects a page of photo search results below the keyset of
+// the previous page, AfterID is zero on the first page. Ranks are computed
+// as of Now so every page of one search sees the same ranking. Relevance
+// halves every HalfLife of a photo's age.
+type PhotoSearch struct {
+	Query     string
+	HalfLife  time.Duration
+	Now       time.Time
+	AfterRank float64
+	AfterID   uint64
+	Limit     int
+}
+
+// PhotoNearby selects a page of the photos within RadiusKm of a point,
+// nearest first, after the keyset of the previous page. AfterID is zero on
+// the first page.
+type PhotoNearby struct {
+	Latitude      float64
+	Longitude     float64
+	RadiusKm      float64
+	AfterDistance float64
+	AfterID       uint64
+	Limit         int
+}
+
+type SearchQuery interface {
+	// SearchPhotos finds photos the viewer may see whose title or caption
+	// match the query, best ranked first, with the matched words
+	// highlighted. Query takes the web search syntax of Postgres: quoted
+	// phrases, "or" and "-" to exclude a word.
+	SearchPhotos(ctx context.Context, viewerID uint64, search PhotoSearch) ([]model.PhotoMatch, error)
+	// NearbyPhotos finds photos the viewer may see located near a point.
+	NearbyPhotos(ctx context.Context, viewerID uint64, search PhotoNearby) ([]model.NearbyPhoto, error)
+}
+
+type searchQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewSearchQuery(db infrastructure.GormPostgres) SearchQuery {
+	return &searchQueryImpl{db: db}
+}
+
+// Matched words are wrapped in control characters that cannot be confused
+// with text, and the whole text is returned instead of a fragment so the
+// marks can be turned into offsets of the original.
+const (
+	headlineStart   = '\x02'
+	headlineStop    = '\x03'
+	headlineOptions = "StartSel=\x02, StopSel=\x03, HighlightAll=true"
+)
+
+func (u *searchQueryImpl) SearchPhotos(ctx context.Context, viewerID uint64, search PhotoSearch) ([]model.PhotoMatch, error) {
+	db := u.db.GetConnection()
+	language := u.db.SearchLanguage()
+	document := infrastructure.PhotoSearchDocument(language)
+	rank := "ts_rank(" + document + ", search_query) / (1 + GREATEST(EXTRACT(EPOCH FROM ?::timestamptz - photos.created_at), 0) / ?)"
+	halfLife := search.HalfLife.Seconds()
+
+	matches := []model.PhotoMatch{}
+	query := db.
+		WithContext(ctx).
+		Table("photos").
+		Select(`photos.id AS photo_id, `+rank+` AS rank,
+			ts_headline(?::regconfig, photos.title, search_query, ?) AS title_headline,
+			ts_headline(?::regconfig, COALESCE(photos.caption, ''), search_query, ?) AS caption_headline`,
+			search.Now, halfLife, language, headlineOptions, language, headlineOptions).
+		Joins(fmt.Sprintf("CROSS JOIN websearch_to_tsquery('%s'::regconfig, ?) AS search_query", language), search.Query).
+		Where(document+" @@ search_query").
+		Where("photos.deleted_at IS NULL AND photos.status = ?", model.PhotoStatusReady).
+		Scopes(visiblePhotos(viewerID, "photos"), notMuted(viewerID, "photos.user_id"))
+	if search.AfterID > 0 {
+		query = query.Where("("+rank+", photos.id) < (?, ?)", search.Now, halfLife, search.AfterRank, search.AfterID)
+	}
+	if err := query.
+		Preload("Photo").
+		Preload("Photo.User").
+		Preload("Photo.Media", mediaInOrder).
+		Preload("Photo.Variants").
+		Preload("Photo.Mentions").
+		Order("rank DESC, photos.id DESC").
+		Limit(search.Limit).
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(search.Query)
+	for i, match := range matches {
+		if match.Photo == nil {
+			continue
+		}
+		matches[i].TitleHighlights = headlineHighlights(match.TitleHeadline, match.Photo.Title, terms)
+		matches[i].CaptionHighlights = headlineHighlights(match.CaptionHeadline, match.Photo.Caption, terms)
+	}
+	return matches, nil
+}
+
+// NearbyPhotos only uses the btree index on latitude and longitude and plain
+// arithmetic, so it needs no geo extension in Postgres. The bounding box of
+// the circle picks the candidates from the index and a flat approximation of
+// the distance, good to a fraction of a percent within the radii searched,
+// drops the corners and orders them.
+func (u *searchQueryImpl) NearbyPhotos(ctx context.Context, viewerID uint64, search PhotoNearby) ([]model.NearbyPhoto, error) {
+	db := u.db.GetConnection()
+	box := helper.NearbyBox(search.Latitude, search.Longitude, search.RadiusKm)
+	latScale := helper.KmPerDegree * helper.KmPerDegree
+	lngScale := latScale * math.Pow(math.Cos(search.Latitude*math.Pi/180), 2)
+
+	// longitudes more than half way round are measured the other way
+	candidates := db.
+		Session(&gorm.Session{NewDB: true}).
+		Table("photos").
+		Select(`id, user_id, visibility, (latitude - ?) * (latitude - ?) * ? +
+			CASE WHEN ABS(longitude - ?) > 180 THEN (360 - ABS(longitude - ?)) * (360 - ABS(longitude - ?))
+			ELSE (longitude - ?) * (longitude - ?) END * ? AS distance`,
+			search.Latitude, search.Latitude, latScale,
+			search.Longitude, search.Longitude, search.Longitude, search.Longitude, search.Longitude, lngScale).
+		Where("latitude IS NOT NULL AND deleted_at IS NULL AND status = ?", model.PhotoStatusReady).
+		Where("latitude BETWEEN ? AND ?", box.MinLat, box.MaxLat)
+	if box.CrossesAntimeridian() {
+		candidates = candidates.Where("(longitude >= ? OR longitude <= ?)", box.MinLng, box.MaxLng)
+	} else {
+		candidates = candidates.Where("longitude BETWEEN ? AND ?", box.MinLng, box.MaxLng)
+	}
+
+	photos := []model.NearbyPhoto{}
+	query := db.
+		WithContext(ctx).
+		Table("(?) AS photos", candidates).
+		Select("photos.id AS photo_id, photos.distance").
+		Where("photos.distance <= ?", search.RadiusKm*search.RadiusKm).
+		Scopes(visiblePhotos(viewerID, "photos"), notMuted(viewerID, "photos.user_id"))
+	if search.AfterID > 0 {
+		query = query.Where("(photos.distance > ? OR (photos.distance = ? AND photos.id > ?))", search.AfterDistance, search.AfterDistance, search.AfterID)
+	}
+	if err := query.
+		Preload("Photo").
+		Preload("Photo.User").
+		Preload("Photo.Media", mediaInOrder).
+		Preload("Photo.Variants").
+		Preload("Photo.Mentions").
+		Order("photos.distance, photos.id").
+		Limit(search.Limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// searchTerms lower-cases the words of a web search query, leaving out the
+// operators and the words to exclude.
+func searchTerms(query string) []string {
+	terms := []string{}
+	for _, word := range strings.Fields(query) {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		word = strings.Trim(word, `"`)
+		if word == "" || strings.EqualFold(word, "or") {
+			continue
+		}
+		terms = append(terms, string(lowerRunes(word)))
+	}
+	return terms
+}
+
+// headlineHighlights turns the marks of a headline into highlights of text.
+// A headline that does not give back text once the marks are removed, say
+// because text contains the marks itself, is ignored and text is
+// highlighted word by word instead.
+func headlineHighlights(headline string, text string, terms []string) []model.Highlight {
+	highlights := []model.Highlight{}
+	stripped := strings.Builder{}
+	offset, start := 0, -1
+	for _, r := range headline {
+		switch r {
+		case headlineStart:
+			start = offset
+		case headlineStop:
+			if start >= 0 && offset > start {
+				highlights = append(highlights, model.Highlight{Offset: start, Length: offset - start})
+			}
+			start = -1
+		default:
+			stripped.WriteRune(r)
+			offset++
+		}
+	}
+	if stripped.String() != text {
+		return highlightTerms(text, terms)
+	}
+	return highlights
+}
+
+// highlightTerms marks every occurrence of terms in text ignoring case,
+// preferring the longest term where several start at the same place.
+func highlightTerms(text string, terms []string) []model.Highlight {
+	highlights := []model.Highlight{}
+	runes := lowerRunes(text)
+	for i := 0; i < len(runes); {
+		length := 0
+		for _, term := range terms {
+			t := []rune(term)
+			if len(t) > length && len(t) <= len(runes)-i && string(runes[i:i+len(t)]) == term {
+				length = len(t)
+			}
+		}
+		if length == 0 {
+			i++
+			continue
+		}
+		highlights = append(highlights, model.Highlight{Offset: i, Length: length})
+		i += length
+	}
+	return highlights
+}
+
+// lowerRunes lower-cases s rune by rune, so offsets into the result are
+// offsets into s.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchPhotos(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+	postgresMock.On("SearchLanguage").Return("english")
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT photos.id AS photo_id, ts_rank(to_tsvector('english'::regconfig, photos.title || ' ' || COALESCE(photos.caption, '')), search_query)`)+".*"+
+		regexp.QuoteMeta(`FROM "photos" CROSS JOIN websearch_to_tsquery('english'::regconfig, $7) AS search_query WHERE to_tsvector('english'::regconfig, photos.title || ' ' || COALESCE(photos.caption, '')) @@ search_query`)+".*"+
+		regexp.QuoteMeta(`ORDER BY rank DESC, photos.id DESC LIMIT $25`)).
+		WithArgs(now, 3600.0, "english", headlineOptions, "english", headlineOptions, "sunny beach", "ready", now, 3600.0, 0.25, 40,
+			1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg(), 1, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"photo_id", "rank", "title_headline", "caption_headline"}).
+			AddRow(9, 0.2, "\x02Sunny\x03 day", "at the \x02beach\x03"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "caption"}).AddRow(9, 2, "Sunny day", "at the beach"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	searchRepo := searchQueryImpl{db: postgresMock}
+	res, err := searchRepo.SearchPhotos(context.Background(), 1, PhotoSearch{
+		Query: "sunny beach", HalfLife: time.Hour, Now: now, AfterRank: 0.25, AfterID: 40, Limit: 20,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, []model.Highlight{{Offset: 0, Length: 5}}, res[0].TitleHighlights)
+	assert.Equal(t, []model.Highlight{{Offset: 7, Length: 5}}, res[0].CaptionHighlights)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestHeadlineHighlights(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		text     string
+		want     []model.Highlight
+	}{
+		{"marks become offsets", "Café \x02Sunsets\x03 and \x02sunset\x03", "Café Sunsets and sunset", []model.Highlight{{Offset: 5, Length: 7}, {Offset: 17, Length: 6}}},
+		{"no match", "Café", "Café", []model.Highlight{}},
+		{"headline differs from text", "\x02sunset\x03 ...", "sunset at the beach", []model.Highlight{{Offset: 0, Length: 6}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, headlineHighlights(tt.headline, tt.text, []string{"sunset"}))
+		})
+	}
+}
+
+func TestNearbyPhotos(t *testing.T) {
+	t.Run("box inside the antimeridian", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT photos.id AS photo_id, photos.distance FROM (SELECT id, user_id, visibility, (latitude - $1) * (latitude - $2) * $3 +`)+".*"+
+			regexp.QuoteMeta(`AS distance FROM "photos" WHERE (latitude IS NOT NULL AND deleted_at IS NULL AND status = $10) AND (latitude BETWEEN $11 AND $12) AND (longitude BETWEEN $13 AND $14)) AS photos `+
+				`WHERE photos.distance <= $15 AND ((photos.distance > $16 OR (photos.distance = $17 AND photos.id > $18))) AND `)+visiblePhotosSQL+".*"+
+			regexp.QuoteMeta(`ORDER BY photos.distance, photos.id LIMIT $31`)).
+			WithArgs(-6.2, -6.2, sqlmock.AnyArg(), 106.8, 106.8, 106.8, 106.8, 106.8, sqlmock.AnyArg(), "ready",
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 25.0, 4.5, 4.5, 30,
+				1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg(), 1, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"photo_id", "distance"}).AddRow(31, 5.0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photos"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "latitude", "longitude"}).AddRow(31, 2, -6.21, 106.82))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		searchRepo := searchQueryImpl{db: postgresMock}
+		res, err := searchRepo.NearbyPhotos(context.Background(), 1, PhotoNearby{
+			Latitude: -6.2, Longitude: 106.8, RadiusKm: 5, AfterDistance: 4.5, AfterID: 30, Limit: 20,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, 106.82, *res[0].Photo.Longitude)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("box across the antimeridian", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`AND (latitude BETWEEN $11 AND $12) AND ((longitude >= $13 OR longitude <= $14))) AS photos`)).
+			WillReturnRows(sqlmock.NewRows([]string{"photo_id", "distance"}))
+
+		searchRepo := searchQueryImpl{db: postgresMock}
+		res, err := searchRepo.NearbyPhotos(context.Background(), 1, PhotoNearby{Latitude: -17, Longitude: 179.99, RadiusKm: 10, Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StoryQuery interface {
+	// GetStoryByID returns a story whoever asks, expired or not, it is meant
+	// for ownership checks.
+	GetStoryByID(ctx context.Context, id uint64) (model.Story, error)
+	// GetStory returns a story the viewer may see that has not expired by
+	// now, or an empty story.
+	GetStory(ctx context.Context, viewerID uint64, id uint64, now time.Time) (model.Story, error)
+	// GetStoryTray returns the stories of the viewer and of the accounts they
+	// follow that have not expired by now, by author and oldest first.
+	// Muted accounts are left out.
+	GetStoryTray(ctx context.Context, viewerID uint64, now time.Time) ([]model.Story, error)
+	// GetUserStories returns the stories of userID the viewer may see that
+	// have not expired by now, oldest first.
+	GetUserStories(ctx context.Context, viewerID uint64, userID uint64, now time.Time) ([]model.Story, error)
+	// GetSeenStoryIDs returns which of storyIDs the viewer has seen.
+	GetSeenStoryIDs(ctx context.Context, viewerID uint64, storyIDs []uint64) ([]uint64, error)
+	// GetStoryViewers lists who has seen a story, latest first, starting
+	// below the view id cursor when it is not zero.
+	GetStoryViewers(ctx context.Context, storyID uint64, cursor uint64, limit int) ([]model.StoryView, error)
+
+	CreateStory(ctx context.Context, story model.Story) (model.Story, error)
+	// MarkSeen records that the viewer has seen a story and reports whether
+	// it is the first time, the story's view_count only moves when it is.
+	MarkSeen(ctx context.Context, storyID uint64, viewerID uint64) (bool, error)
+	// DeleteStory deletes a story and takes it out of every highlight.
+	DeleteStory(ctx context.Context, id uint64) error
+	// ExpireStories deletes up to limit stories that expired by now and are
+	// in no highlight, and returns them.
+	ExpireStories(ctx context.Context, now time.Time, limit int) ([]model.Story, error)
+
+	// GetHighlightByID returns a highlight whoever asks, it is meant for
+	// ownership checks. Everything shown to users goes through GetHighlight.
+	GetHighlightByID(ctx context.Context, id uint64) (model.StoryHighlight, error)
+	// GetHighlight returns a highlight with its owner and cover, or an empty
+	// highlight when the viewer may not see it.
+	GetHighlight(ctx context.Context, viewerID uint64, id uint64) (model.StoryHighlight, error)
+	// GetUserHighlights lists the highlights of userID with their covers,
+	// newest first, starting below the highlight id cursor when it is not
+	// zero. It is empty when the viewer may not see them.
+	GetUserHighlights(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.StoryHighlight, error)
+	// GetHighlightItems returns every story of a highlight in highlight
+	// order.
+	GetHighlightItems(ctx context.Context, highlightID uint64) ([]model.StoryHighlightItem, error)
+
+	CreateHighlight(ctx context.Context, highlight model.StoryHighlight) (model.StoryHighlight, error)
+	UpdateHighlight(ctx context.Context, id uint64, title string) error
+	// DeleteHighlight deletes a highlight, its stories expire as usual
+	// unless another highlight holds them.
+	DeleteHighlight(ctx context.Context, id uint64) error
+	// AddHighlightStory appends a live story to the end of a highlight and
+	// reports whether it was added, a story already in the highlight stays
+	// where it is.
+	AddHighlightStory(ctx context.Context, highlightID uint64, storyID uint64) (bool, error)
+	// RemoveHighlightStory takes a story out of a highlight and reports
+	// whether it was in it. Removing the cover makes the first remaining
+	// story the cover.
+	RemoveHighlightStory(ctx context.Context, highlightID uint64, storyID uint64) (bool, error)
+	// ReorderHighlightStories numbers the stories of a highlight in the
+	// order of storyIDs.
+	ReorderHighlightStories(ctx context.Context, highlightID uint64, storyIDs []uint64) error
+}
+
+type storyQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewStoryQuery(db infrastructure.GormPostgres) StoryQuery {
+	return &storyQueryImpl{db: db}
+}
+
+func storyAuthor(db *gorm.DB) *gorm.DB {
+	return db.Select("ID", "Username")
+}
+
+func (u *storyQueryImpl) GetStoryByID(ctx context.Context, id uint64) (model.Story, error) {
+	db := u.db.GetConnection()
+	story := model.Story{}
+	if err := db.
+		WithContext(ctx).
+		Table("stories").
+		Where("id = ? AND deleted_at IS NULL", id).
+		Find(&story).Error; err != nil {
+		return model.Story{}, err
+	}
+	return story, nil
+}
+
+func (u *storyQueryImpl) GetStory(ctx context.Context, viewerID uint64, id uint64, now time.Time) (model.Story, error) {
+	db := u.db.GetConnection()
+	story := model.Story{}
+	if err := db.
+		WithContext(ctx).
+		Table("stories").
+		Where("stories.id = ? AND stories.deleted_at IS NULL AND stories.expires_at > ?", id, now).
+		Scopes(visibleStories(viewerID, "stories")).
+		Preload("User", storyAuthor).
+		Find(&story).Error; err != nil {
+		return model.Story{}, err
+	}
+	return story, nil
+}
+
+func (u *storyQueryImpl) GetStoryTray(ctx context.Context, viewerID uint64, now time.Time) ([]model.Story, error) {
+	db := u.db.GetConnection()
+	stories := []model.Story{}
+	if err := db.
+		WithContext(ctx).
+		Table("stories").
+		Where("stories.deleted_at IS NULL AND stories.expires_at > ?", now).
+		Scopes(visibleStories(viewerID, "stories"), notMuted(viewerID, "stories.user_id")).
+		Preload("User", storyAuthor).
+		Order("stories.user_id, stories.id").
+		Find(&stories).Error; err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+func (u *storyQueryImpl) GetUserStories(ctx context.Context, viewerID uint64, userID uint64, now time.Time) ([]model.Story, error) {
+	db := u.db.GetConnection()
+	stories := []model.Story{}
+	if err := db.
+		WithContext(ctx).
+		Table("stories").
+		Where("stories.user_id = ? AND stories.deleted_at IS NULL AND stories.expires_at > ?", userID, now).
+		Scopes(visibleStories(viewerID, "stories")).
+		Preload("User", storyAuthor).
+		Order("stories.id").
+		Find(&stories).Error; err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+func (u *storyQueryImpl) GetSeenStoryIDs(ctx context.Context, viewerID uint64, storyIDs []uint64) ([]uint64, error) {
+	ids := []uint64{}
+	if len(storyIDs) == 0 {
+		return ids, nil
+	}
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("story_views").
+		Where("viewer_id = ? AND story_id IN ?", viewerID, storyIDs).
+		Pluck("story_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (u *storyQueryImpl) GetStoryViewers(ctx context.Context, storyID uint64, cursor uint64, limit int) ([]model.StoryView, error) {
+	db := u.db.GetConnection()
+	views := []model.StoryView{}
+	query := db.
+		WithContext(ctx).
+		Table("story_views").
+		Where("story_id = ?", storyID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if err := query.
+		Preload("Viewer", storyAuthor).
+		Order("id DESC").
+		Limit(limit).
+		Find(&views).Error; err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (u *storyQueryImpl) CreateStory(ctx context.Context, story model.Story) (model.Story, error) {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("stories").
+		Create(&story).Error; err != nil {
+		return model.Story{}, err
+	}
+	return story, nil
+}
+
+func (u *storyQueryImpl) MarkSeen(ctx context.Context, storyID uint64, viewerID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	created := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec("INSERT INTO story_views (story_id, viewer_id, created_at) VALUES (?, ?, NOW()) ON CONFLICT (story_id, viewer_id) DO NOTHING", storyID, viewerID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return tx.Exec("UPDATE stories SET view_count = view_count + 1 WHERE id = ?", storyID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+func (u *storyQueryImpl) DeleteStory(ctx context.Context, id uint64) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// deleting first holds the story row, a concurrent add to a
+		// highlight waits and then finds the story gone
+		if err := tx.Where("id = ?", id).Delete(&model.Story{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM story_highlight_items WHERE story_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE story_highlights SET cover_story_id =
+			(SELECT story_id FROM story_highlight_items WHERE highlight_id = story_highlights.id ORDER BY position LIMIT 1)
+			WHERE cover_story_id = ?`, id).Error
+	})
+}
+
+func (u *storyQueryImpl) ExpireStories(ctx context.Context, now time.Time, limit int) ([]model.Story, error) {
+	db := u.db.GetConnection()
+	stories := []model.Story{}
+	// highlight_count is checked on the row itself, an add to a highlight
+	// holding the row makes this wait and then skip the story
+	if err := db.
+		WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Table("stories").
+			Select("id").
+			Where("expires_at <= ? AND highlight_count = 0 AND deleted_at IS NULL", now).
+			Order("id").
+			Limit(limit)).
+		Where("expires_at <= ? AND highlight_count = 0", now).
+		Delete(&stories).Error; err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+func (u *storyQueryImpl) GetHighlightByID(ctx context.Context, id uint64) (model.StoryHighlight, error) {
+	db := u.db.GetConnection()
+	highlight := model.StoryHighlight{}
+	if err := db.
+		WithContext(ctx).
+		Table("story_highlights").
+		Where("id = ?", id).
+		Find(&highlight).Error; err != nil {
+		return model.StoryHighlight{}, err
+	}
+	return highlight, nil
+}
+
+func (u *storyQueryImpl) GetHighlight(ctx context.Context, viewerID uint64, id uint64) (model.StoryHighlight, error) {
+	db := u.db.GetConnection()
+	highlight := model.StoryHighlight{}
+	if err := db.
+		WithContext(ctx).
+		Table("story_highlights").
+		Where("story_highlights.id = ?", id).
+		Scopes(visibleHighlights(viewerID)).
+		Preload("CoverStory").
+		Preload("User", storyAuthor).
+		Find(&highlight).Error; err != nil {
+		return model.StoryHighlight{}, err
+	}
+	return highlight, nil
+}
+
+func (u *storyQueryImpl) GetUserHighlights(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.StoryHighlight, error) {
+	db := u.db.GetConnection()
+	highlights := []model.StoryHighlight{}
+	query := db.
+		WithContext(ctx).
+		Table("story_highlights").
+		Where("story_highlights.user_id = ?", userID).
+		Scopes(visibleHighlights(viewerID))
+	if cursor > 0 {
+		query = query.Where("story_highlights.id < ?", cursor)
+	}
+	if err := query.
+		Preload("CoverStory").
+		Order("story_highlights.id DESC").
+		Limit(limit).
+		Find(&highlights).Error; err != nil {
+		return nil, err
+	}
+	return highlights, nil
+}
+
+func (u *storyQueryImpl) GetHighlightItems(ctx context.Context, highlightID uint64) ([]model.StoryHighlightItem, error) {
+	db := u.db.GetConnection()
+	items := []model.StoryHighlightItem{}
+	if err := db.
+		WithContext(ctx).
+		Table("story_highlight_items").
+		Where("highlight_id = ?", highlightID).
+		Preload("Story").
+		Order("position").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (u *storyQueryImpl) CreateHighlight(ctx context.Context, highlight model.StoryHighlight) (model.StoryHighlight, error) {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("story_highlights").
+		Create(&highlight).Error; err != nil {
+		return model.StoryHighlight{}, err
+	}
+	return highlight, nil
+}
+
+func (u *storyQueryImpl) UpdateHighlight(ctx context.Context, id uint64, title string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Model(&model.StoryHighlight{ID: id}).
+		Update("title", title).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *storyQueryImpl) DeleteHighlight(ctx context.Context, id uint64) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockHighlight(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE stories SET highlight_count = highlight_count - 1
+			WHERE id IN (SELECT story_id FROM story_highlight_items WHERE highlight_id = ?) AND highlight_count > 0`, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM story_highlight_items WHERE highlight_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.StoryHighlight{}).Error
+	})
+}
+
+func (u *storyQueryImpl) AddHighlightStory(ctx context.Context, highlightID uint64, storyID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	added := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// adds to the same highlight wait for each other so positions stay
+		// unique
+		if err := lockHighlight(tx, highlightID); err != nil {
+			return err
+		}
+		// holding the story keeps the reaper from deleting it meanwhile
+		story := model.Story{}
+		if err := tx.
+			Table("stories").
+			Select("id").
+			Where("id = ? AND deleted_at IS NULL", storyID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&story).Error; err != nil {
+			return err
+		}
+		if story.ID == 0 {
+			return nil
+		}
+		res := tx.Exec(`INSERT INTO story_highlight_items (highlight_id, story_id, position, created_at)
+			SELECT ?, ?, COALESCE(MAX(position), 0) + 1, NOW() FROM story_highlight_items WHERE highlight_id = ?
+			ON CONFLICT (highlight_id, story_id) DO NOTHING`, highlightID, storyID, highlightID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		if err := tx.Exec("UPDATE stories SET highlight_count = highlight_count + 1 WHERE id = ?", storyID).Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE story_highlights SET cover_story_id = ? WHERE id = ? AND cover_story_id IS NULL", storyID, highlightID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return added, nil
+}
+
+func (u *storyQueryImpl) RemoveHighlightStory(ctx context.Context, highlightID uint64, storyID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	removed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockHighlight(tx, highlightID); err != nil {
+			return err
+		}
+		res := tx.Exec("DELETE FROM story_highlight_items WHERE highlight_id = ? AND story_id = ?", highlightID, storyID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		// an expired story in no other highlight goes with the next reap
+		if err := tx.Exec("UPDATE stories SET highlight_count = highlight_count - 1 WHERE id = ? AND highlight_count > 0", storyID).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE story_highlights SET cover_story_id =
+			(SELECT story_id FROM story_highlight_items WHERE highlight_id = ? ORDER BY position LIMIT 1)
+			WHERE id = ? AND cover_story_id = ?`, highlightID, highlightID, storyID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return removed, nil
+}
+
+func (u *storyQueryImpl) ReorderHighlightStories(ctx context.Context, highlightID uint64, storyIDs []uint64) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockHighlight(tx, highlightID); err != nil {
+			return err
+		}
+		for i, storyID := range storyIDs {
+			if err := tx.
+				Table("story_highlight_items").
+				Where("highlight_id = ? AND story_id = ?", highlightID, storyID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lockHighlight holds the highlight row until the transaction ends, changes
+// to the stories of a highlight run one at a time.
+func lockHighlight(tx *gorm.DB, highlightID uint64) error {
+	highlight := model.StoryHighlight{}
+	return tx.
+		Table("story_highlights").
+		Select("id").
+		Where("id = ?", highlightID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&highlight).Error
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStoryTray(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stories" WHERE (stories.deleted_at IS NULL AND stories.expires_at > $1) `+
+		`AND (stories.user_id = $2 OR stories.user_id IN (SELECT following_id FROM follows WHERE follower_id = $3 AND status = $4)) `+
+		`AND stories.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $5) `+
+		`AND stories.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $6) `+
+		`AND (stories.user_id NOT IN (SELECT id FROM users WHERE banned_at IS NOT NULL OR suspended_until > $7)) `+
+		`AND stories.user_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = $8) `+
+		`AND "stories"."deleted_at" IS NULL ORDER BY stories.user_id, stories.id`)).
+		WithArgs(now, 1, 1, "accepted", 1, 1, sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(4, 2).AddRow(5, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","username" FROM "users" WHERE "users"."id" = $1 AND "users"."deleted_at" IS NULL`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "ann"))
+
+	storyRepo := storyQueryImpl{db: postgresMock}
+	res, err := storyRepo.GetStoryTray(context.Background(), 1, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, "ann", res[1].User.Username)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetHighlight(t *testing.T) {
+	highlightSQL := regexp.QuoteMeta(`SELECT * FROM "story_highlights" WHERE story_highlights.id = $1 ` +
+		`AND (story_highlights.user_id = $2 OR story_highlights.user_id IN (SELECT id FROM users WHERE NOT is_private) ` +
+		`OR story_highlights.user_id IN (SELECT following_id FROM follows WHERE follower_id = $3 AND status = $4)) ` +
+		`AND story_highlights.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = $5) ` +
+		`AND story_highlights.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = $6) ` +
+		`AND (story_highlights.user_id NOT IN (SELECT id FROM users WHERE banned_at IS NOT NULL OR suspended_until > $7))`)
+
+	t.Run("error get highlight", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectQuery(highlightSQL).
+			WithArgs(3, 1, 1, "accepted", 1, 1, sqlmock.AnyArg()).
+			WillReturnError(errors.New("some error"))
+
+		storyRepo := storyQueryImpl{db: postgresMock}
+		res, err := storyRepo.GetHighlight(context.Background(), 1, 3)
+		assert.NotNil(t, err)
+		assert.Equal(t, uint64(0), res.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success get highlight", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectQuery(highlightSQL).
+			WithArgs(3, 1, 1, "accepted", 1, 1, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).AddRow(3, 2, "trip"))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","username" FROM "users"`)).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "ann"))
+
+		storyRepo := storyQueryImpl{db: postgresMock}
+		res, err := storyRepo.GetHighlight(context.Background(), 1, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, "trip", res.Title)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExpireStories(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "stories" SET "deleted_at"=$1 WHERE id IN `+
+		`(SELECT id FROM "stories" WHERE expires_at <= $2 AND highlight_count = 0 AND deleted_at IS NULL ORDER BY id LIMIT $3) `+
+		`AND (expires_at <= $4 AND highlight_count = 0) AND "stories"."deleted_at" IS NULL RETURNING *`)).
+		WithArgs(sqlmock.AnyArg(), now, 100, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "object_key"}).AddRow(4, "blobs/aa/aa.png"))
+	mock.ExpectCommit()
+
+	storyRepo := storyQueryImpl{db: postgresMock}
+	res, err := storyRepo.ExpireStories(context.Background(), now, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, "blobs/aa/aa.png", res[0].ObjectKey)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestAddHighlightStory(t *testing.T) {
+	t.Run("success keeps the story from expiring", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "story_highlights" WHERE id = $1 FOR UPDATE`)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "stories" WHERE (id = $1 AND deleted_at IS NULL) AND "stories"."deleted_at" IS NULL FOR UPDATE`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO story_highlight_items`)).
+			WithArgs(3, 4, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE stories SET highlight_count = highlight_count + 1 WHERE id = $1`)).
+			WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE story_highlights SET cover_story_id = $1 WHERE id = $2 AND cover_story_id IS NULL`)).
+			WithArgs(4, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		storyRepo := storyQueryImpl{db: postgresMock}
+		added, err := storyRepo.AddHighlightStory(context.Background(), 3, 4)
+		assert.Nil(t, err)
+		assert.True(t, added)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success story already reaped", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "story_highlights" WHERE id = $1 FOR UPDATE`)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "stories"`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		storyRepo := storyQueryImpl{db: postgresMock}
+		added, err := storyRepo.AddHighlightStory(context.Background(), 3, 4)
+		assert.Nil(t, err)
+		assert.False(t, added)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMarkSeen(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO story_views (story_id, viewer_id, created_at) VALUES ($1, $2, NOW()) ON CONFLICT (story_id, viewer_id) DO NOTHING`)).
+		WithArgs(4, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	storyRepo := storyQueryImpl{db: postgresMock}
+	created, err := storyRepo.MarkSeen(context.Background(), 4, 1)
+	assert.Nil(t, err)
+	assert.False(t, created)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
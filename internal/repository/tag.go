@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagQuery interface {
+	GetTag(ctx context.Context, name string) (model.Tag, error)
+	// GetTagPhotos lists the photos carrying a tag that the viewer may see,
+	// newest first, starting below the photo id cursor when it is not zero.
+	GetTagPhotos(ctx context.Context, viewerID uint64, tagID uint64, cursor uint64, limit int) ([]model.Photo, error)
+	// SearchTags returns used tags starting with prefix, most used first.
+	SearchTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error)
+
+	// SetPhotoTags makes names the photo's only tags, creating tags that do
+	// not exist yet. An empty names removes all of them.
+	SetPhotoTags(ctx context.Context, photoID uint64, names []string) error
+}
+
+type tagQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewTagQuery(db infrastructure.GormPostgres) TagQuery {
+	return &tagQueryImpl{db: db}
+}
+
+func (u *tagQueryImpl) GetTag(ctx context.Context, name string) (model.Tag, error) {
+	db := u.db.GetConnection()
+	tag := model.Tag{}
+	if err := db.
+		WithContext(ctx).
+		Table("tags").
+		Where("name = ?", name).
+		Find(&tag).Error; err != nil {
+		return model.Tag{}, err
+	}
+	return tag, nil
+}
+
+func (u *tagQueryImpl) GetTagPhotos(ctx context.Context, viewerID uint64, tagID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	db := u.db.GetConnection()
+	photos := []model.Photo{}
+	query := db.
+		WithContext(ctx).
+		Table("photos").
+		Joins("JOIN photo_tags ON photo_tags.photo_id = photos.id AND photo_tags.tag_id = ?", tagID).
+		Where("photos.status = ?", model.PhotoStatusReady).
+		Scopes(visiblePhotos(viewerID, "photos"), notMuted(viewerID, "photos.user_id"))
+	if cursor > 0 {
+		query = query.Where("photos.id < ?", cursor)
+	}
+	if err := query.
+		Preload("User").
+		Preload("Media", mediaInOrder).
+		Preload("Variants").
+		Preload("Mentions").
+		Order("photos.id DESC").
+		Limit(limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (u *tagQueryImpl) SearchTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error) {
+	db := u.db.GetConnection()
+	tags := []model.Tag{}
+	if err := db.
+		WithContext(ctx).
+		Table("tags").
+		Where(`name LIKE ? ESCAPE '\' AND photo_count > 0`, escapeLike(prefix)+"%").
+		Order("photo_count DESC, name").
+		Limit(limit).
+		Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// SetPhotoTags moves photo_count by the links it actually added or removed,
+// so concurrent edits of photos sharing a tag cannot lose a count.
+func (u *tagQueryImpl) SetPhotoTags(ctx context.Context, photoID uint64, names []string) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		removed := []uint64{}
+		remove := tx.Raw("DELETE FROM photo_tags WHERE photo_id = ? RETURNING tag_id", photoID)
+		if len(names) > 0 {
+			remove = tx.Raw(`DELETE FROM photo_tags WHERE photo_id = ? AND tag_id NOT IN (SELECT id FROM tags WHERE name IN ?)
+				RETURNING tag_id`, photoID, names)
+		}
+		if err := remove.Scan(&removed).Error; err != nil {
+			return err
+		}
+		if err := adjustPhotoCount(tx, removed, -1); err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		tags := make([]model.Tag, 0, len(names))
+		for _, name := range names {
+			tags = append(tags, model.Tag{Name: name, CreatedAt: time.Now()})
+		}
+		if err := tx.Table("tags").Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+		added := []uint64{}
+		if err := tx.Raw(`INSERT INTO photo_tags (photo_id, tag_id) SELECT ?, id FROM tags WHERE name IN ?
+			ON CONFLICT DO NOTHING RETURNING tag_id`, photoID, names).Scan(&added).Error; err != nil {
+			return err
+		}
+		return adjustPhotoCount(tx, added, 1)
+	})
+}
+
+func adjustPhotoCount(tx *gorm.DB, tagIDs []uint64, delta int) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	return tx.Exec("UPDATE tags SET photo_count = GREATEST(photo_count + ?, 0) WHERE id IN ?", delta, tagIDs).Error
+}
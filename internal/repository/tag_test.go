@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPhotoTags(t *testing.T) {
+	t.Run("replaces tags and moves counts", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM photo_tags WHERE photo_id = $1 AND tag_id NOT IN (SELECT id FROM tags WHERE name IN ($2,$3)) RETURNING tag_id`)).
+			WithArgs(7, "beach", "sunset").
+			WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(3))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE tags SET photo_count = GREATEST(photo_count + $1, 0) WHERE id IN ($2)`)).
+			WithArgs(-1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" ("name","photo_count","created_at") VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT DO NOTHING RETURNING "id"`)).
+			WithArgs("beach", 0, sqlmock.AnyArg(), "sunset", 0, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO photo_tags (photo_id, tag_id) SELECT $1, id FROM tags WHERE name IN ($2,$3) ON CONFLICT DO NOTHING RETURNING tag_id`)).
+			WithArgs(7, "beach", "sunset").
+			WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(5))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE tags SET photo_count = GREATEST(photo_count + $1, 0) WHERE id IN ($2)`)).
+			WithArgs(1, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tagRepo := tagQueryImpl{db: postgresMock}
+		assert.Nil(t, tagRepo.SetPhotoTags(context.Background(), 7, []string{"beach", "sunset"}))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no tags removes all", func(t *testing.T) {
+		db, mock := newMockGorm()
+		postgresMock := mocks.NewGormPostgres(t)
+		postgresMock.On("GetConnection").Return(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM photo_tags WHERE photo_id = $1 RETURNING tag_id`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"tag_id"}))
+		mock.ExpectCommit()
+
+		tagRepo := tagQueryImpl{db: postgresMock}
+		assert.Nil(t, tagRepo.SetPhotoTags(context.Background(), 7, nil))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetTagPhotos(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`JOIN photo_tags ON photo_tags.photo_id = photos.id AND photo_tags.tag_id = $1 WHERE photos.status = $2 AND photos.id < $3 AND (photos.user_id = $4 OR (photos.visibility = $5 AND photos.user_id IN (SELECT id FROM users WHERE NOT is_private))`)).
+		WithArgs(3, "ready", 50, 1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg(), 1, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(9, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	tagRepo := tagQueryImpl{db: postgresMock}
+	res, err := tagRepo.GetTagPhotos(context.Background(), 1, 3, 50, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+}
+
+func TestSearchTags(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE name LIKE $1 ESCAPE '\' AND photo_count > 0 ORDER BY photo_count DESC, name LIMIT $2`)).
+		WithArgs(`100\%%`, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "photo_count"}).AddRow(1, "100%real", 4))
+
+	tagRepo := tagQueryImpl{db: postgresMock}
+	res, err := tagRepo.SearchTags(context.Background(), "100%", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/infrastructure"
+	"github.com/MidnightHelix/MyGram/internal/model"
+)
+
+// followedBy limits a photo query to authors the viewer currently follows,
+// timeline entries outlive unfollows and blocks until they are cleaned up.
+const followedBy = "photos.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?)"
+
+type TimelineQuery interface {
+	// GetTimeline reads the precomputed part of a home feed, newest first,
+	// starting below the photo id cursor when it is not zero.
+	GetTimeline(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error)
+	// GetFanoutOnReadPhotos reads the photos of followed FanoutOnRead
+	// accounts, which are never pushed into timelines.
+	GetFanoutOnReadPhotos(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error)
+
+	FanOut(ctx context.Context, photo model.Photo) error
+	Backfill(ctx context.Context, userID uint64, authorID uint64, limit int) error
+	RemoveAuthor(ctx context.Context, userID uint64, authorID uint64) error
+	SetFanoutOnRead(ctx context.Context, userID uint64) error
+}
+
+type timelineQueryImpl struct {
+	db infrastructure.GormPostgres
+}
+
+func NewTimelineQuery(db infrastructure.GormPostgres) TimelineQuery {
+	return &timelineQueryImpl{db: db}
+}
+
+func (u *timelineQueryImpl) GetTimeline(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	db := u.db.GetConnection()
+	photos := []model.Photo{}
+	query := db.
+		WithContext(ctx).
+		Table("photos").
+		Joins("JOIN timeline_entries ON timeline_entries.photo_id = photos.id AND timeline_entries.user_id = ?", userID).
+		Where(followedBy, userID, model.FollowStatusAccepted).
+		Where("photos.status = ?", model.PhotoStatusReady).
+		Scopes(visiblePhotos(userID, "photos"), notMuted(userID, "photos.user_id"))
+	if cursor > 0 {
+		query = query.Where("timeline_entries.photo_id < ?", cursor)
+	}
+	if err := query.
+		Preload("User").
+		Preload("Media", mediaInOrder).
+		Preload("Variants").
+		Preload("Mentions").
+		Order("timeline_entries.photo_id DESC").
+		Limit(limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (u *timelineQueryImpl) GetFanoutOnReadPhotos(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	db := u.db.GetConnection()
+	photos := []model.Photo{}
+	query := db.
+		WithContext(ctx).
+		Table("photos").
+		Where(followedBy, userID, model.FollowStatusAccepted).
+		Where("photos.user_id IN (SELECT id FROM users WHERE fanout_on_read)").
+		Where("photos.status = ?", model.PhotoStatusReady).
+		Scopes(visiblePhotos(userID, "photos"), notMuted(userID, "photos.user_id"))
+	if cursor > 0 {
+		query = query.Where("photos.id < ?", cursor)
+	}
+	if err := query.
+		Preload("User").
+		Preload("Media", mediaInOrder).
+		Preload("Variants").
+		Preload("Mentions").
+		Order("photos.id DESC").
+		Limit(limit).
+		Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// FanOut pushes a new photo into the timeline of every accepted follower
+// of its author in one statement.
+func (u *timelineQueryImpl) FanOut(ctx context.Context, photo model.Photo) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Exec(`INSERT INTO timeline_entries (user_id, photo_id, author_id, created_at)
+			SELECT follower_id, ?, ?, ? FROM follows WHERE following_id = ? AND status = ?
+			ON CONFLICT DO NOTHING`,
+			photo.ID, photo.UserID, time.Now(), photo.UserID, model.FollowStatusAccepted).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Backfill pushes the latest photos of an account the user just started
+// following, so the feed is not empty until the next post. Photos still
+// processing are left for the processor to fan out once they are ready.
+func (u *timelineQueryImpl) Backfill(ctx context.Context, userID uint64, authorID uint64, limit int) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Exec(`INSERT INTO timeline_entries (user_id, photo_id, author_id, created_at)
+			SELECT ?, id, user_id, ? FROM photos
+			WHERE user_id = ? AND deleted_at IS NULL AND status = ?
+			AND NOT EXISTS (SELECT 1 FROM users WHERE id = ? AND fanout_on_read)
+			ORDER BY id DESC LIMIT ?
+			ON CONFLICT DO NOTHING`,
+			userID, time.Now(), authorID, model.PhotoStatusReady, authorID, limit).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *timelineQueryImpl) RemoveAuthor(ctx context.Context, userID uint64, authorID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("timeline_entries").
+		Where("user_id = ? AND author_id = ?", userID, authorID).
+		Delete(&model.TimelineEntry{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *timelineQueryImpl) SetFanoutOnRead(ctx context.Context, userID uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", userID).
+		Update("fanout_on_read", true).Error; err != nil {
+		return err
+	}
+	return nil
+}
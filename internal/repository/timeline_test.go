@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/MidnightHelix/MyGram/internal/infrastructure/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTimeline(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`JOIN timeline_entries ON timeline_entries.photo_id = photos.id AND timeline_entries.user_id = $1 WHERE (photos.user_id IN (SELECT following_id FROM follows WHERE follower_id = $2 AND status = $3)) AND photos.status = $4 AND timeline_entries.photo_id < $5`)).
+		WithArgs(1, 1, "accepted", "ready", 50, 1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg(), 1, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(9, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media_items"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "photo_variants"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	timelineRepo := timelineQueryImpl{db: postgresMock}
+	res, err := timelineRepo.GetTimeline(context.Background(), 1, 50, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+}
+
+func TestGetFanoutOnReadPhotos(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`AND photos.user_id IN (SELECT id FROM users WHERE fanout_on_read) AND photos.status = $3 AND`)).
+		WithArgs(1, "accepted", "ready", 1, "public", "public", "followers", 1, "accepted", "close_friends", 1, 1, 1, sqlmock.AnyArg(), 1, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	timelineRepo := timelineQueryImpl{db: postgresMock}
+	res, err := timelineRepo.GetFanoutOnReadPhotos(context.Background(), 1, 0, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(res))
+}
+
+func TestBackfill(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE user_id = $3 AND deleted_at IS NULL AND status = $4`)).
+		WithArgs(1, sqlmock.AnyArg(), 2, "ready", 2, 20).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	timelineRepo := timelineQueryImpl{db: postgresMock}
+	err := timelineRepo.Backfill(context.Background(), 1, 2, 20)
+	assert.Nil(t, err)
+}
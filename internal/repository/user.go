@@ -2,19 +2,54 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/MidnightHelix/MyGram/internal/infrastructure"
 	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+	"gorm.io/gorm"
 )
 
+const (
+	UserSortUsername = "username"
+	UserSortNewest   = "newest"
+)
+
+// UserSearch filters and pages the user directory. AfterUsername/AfterID is
+// the keyset of the last row already returned, zero values start at the top.
+type UserSearch struct {
+	Prefix        string
+	Sort          string
+	AfterUsername string
+	AfterID       uint64
+	Limit         int
+}
+
 type UserQuery interface {
-	GetUsers(ctx context.Context) ([]model.User, error)
+	GetUsers(ctx context.Context, viewerID uint64, search UserSearch) ([]model.User, error)
 	GetUsersByID(ctx context.Context, id uint64) (model.User, error)
+	GetUsersByIDUnscoped(ctx context.Context, id uint64) (model.User, error)
 	FindByEmail(ctx context.Context, email string) (model.User, error)
+	FindByUsername(ctx context.Context, username string) (model.User, error)
+	GetUsernameRedirect(ctx context.Context, username string) (model.UsernameRedirect, error)
+	IsUsernameTaken(ctx context.Context, username string, exceptUserID uint64) (bool, error)
+	IsEmailTaken(ctx context.Context, email string, exceptUserID uint64) (bool, error)
 
 	CreateUser(ctx context.Context, user model.User) (model.User, error)
 	EditUser(ctx context.Context, editUser model.User, id uint64) (model.User, error)
+	UpdatePrivacy(ctx context.Context, id uint64, isPrivate bool) error
+	UpdatePassword(ctx context.Context, id uint64, password string, at time.Time) error
+	ResetPasswordWithToken(ctx context.Context, id uint64, tokenHash string, password string, at time.Time) (bool, error)
+	ChangeUsername(ctx context.Context, id uint64, username string, at time.Time) error
 	DeleteUser(ctx context.Context, id uint64) error
+
+	// moderation
+	SetSuspension(ctx context.Context, id uint64, until *time.Time, reason string) error
+	SetBan(ctx context.Context, id uint64, bannedAt *time.Time, reason string) error
+	SetPasswordResetToken(ctx context.Context, id uint64, tokenHash string) error
+	RevokeTokens(ctx context.Context, id uint64, at time.Time) error
+	RestoreUser(ctx context.Context, id uint64) error
 }
 
 type UserCommand interface {
@@ -29,12 +64,34 @@ func NewUserQuery(db infrastructure.GormPostgres) UserQuery {
 	return &userQueryImpl{db: db}
 }
 
-func (u *userQueryImpl) GetUsers(ctx context.Context) ([]model.User, error) {
+func (u *userQueryImpl) GetUsers(ctx context.Context, viewerID uint64, search UserSearch) ([]model.User, error) {
 	db := u.db.GetConnection()
 	users := []model.User{}
-	if err := db.
+	query := db.
 		WithContext(ctx).
 		Table("users").
+		Scopes(notBlocked(viewerID, "id"), activeUsers("id"))
+	if search.Prefix != "" {
+		prefix := escapeLike(strings.ToLower(search.Prefix)) + "%"
+		query = query.Where(`LOWER(username) LIKE ? ESCAPE '\' OR LOWER(display_name) LIKE ? ESCAPE '\'`, prefix, prefix)
+	}
+
+	switch search.Sort {
+	case UserSortNewest:
+		if search.AfterID > 0 {
+			query = query.Where("id < ?", search.AfterID)
+		}
+		query = query.Order("id DESC")
+	default:
+		if search.AfterID > 0 {
+			query = query.Where("LOWER(username) > ? OR (LOWER(username) = ? AND id > ?)",
+				search.AfterUsername, search.AfterUsername, search.AfterID)
+		}
+		query = query.Order("LOWER(username) ASC").Order("id ASC")
+	}
+
+	if err := query.
+		Limit(search.Limit).
 		Find(&users).Error; err != nil {
 		return nil, err
 	}
@@ -54,21 +111,105 @@ func (u *userQueryImpl) GetUsersByID(ctx context.Context, id uint64) (model.User
 	return users, nil
 }
 
+// GetUsersByIDUnscoped also finds soft-deleted users.
+func (u *userQueryImpl) GetUsersByIDUnscoped(ctx context.Context, id uint64) (model.User, error) {
+	db := u.db.GetConnection()
+	user := model.User{}
+	if err := db.
+		WithContext(ctx).
+		Unscoped().
+		Table("users").
+		Where("id = ?", id).
+		Find(&user).Error; err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
 func (u *userQueryImpl) FindByEmail(ctx context.Context, email string) (model.User, error) {
 	db := u.db.GetConnection()
 	user := model.User{}
 	if err := db.
 		WithContext(ctx).
 		Table("users").
-		Where("email = ?", email).
+		Where("email_key = ?", helper.EmailKey(email)).
 		First(&user).Error; err != nil {
 		return model.User{}, err
 	}
 	return user, nil
 }
 
+// FindByUsername matches on the canonical username key, so "Alice" finds
+// "alice". Soft-deleted users are included because they can be restored.
+func (u *userQueryImpl) FindByUsername(ctx context.Context, username string) (model.User, error) {
+	db := u.db.GetConnection()
+	user := model.User{}
+	if err := db.
+		WithContext(ctx).
+		Unscoped().
+		Table("users").
+		Where("username_key = ?", helper.UsernameKey(username)).
+		Find(&user).Error; err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (u *userQueryImpl) GetUsernameRedirect(ctx context.Context, username string) (model.UsernameRedirect, error) {
+	db := u.db.GetConnection()
+	redirect := model.UsernameRedirect{}
+	if err := db.
+		WithContext(ctx).
+		Table("username_redirects").
+		Where("username_key = ?", helper.UsernameKey(username)).
+		Find(&redirect).Error; err != nil {
+		return model.UsernameRedirect{}, err
+	}
+	return redirect, nil
+}
+
+// IsUsernameTaken reports whether username collides with another account's
+// current or previous username.
+func (u *userQueryImpl) IsUsernameTaken(ctx context.Context, username string, exceptUserID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	key := helper.UsernameKey(username)
+	var users, redirects int64
+	if err := db.
+		WithContext(ctx).
+		Unscoped().
+		Table("users").
+		Where("username_key = ? AND id <> ?", key, exceptUserID).
+		Count(&users).Error; err != nil {
+		return false, err
+	}
+	if err := db.
+		WithContext(ctx).
+		Table("username_redirects").
+		Where("username_key = ? AND user_id <> ?", key, exceptUserID).
+		Count(&redirects).Error; err != nil {
+		return false, err
+	}
+	return users+redirects > 0, nil
+}
+
+func (u *userQueryImpl) IsEmailTaken(ctx context.Context, email string, exceptUserID uint64) (bool, error) {
+	db := u.db.GetConnection()
+	var count int64
+	if err := db.
+		WithContext(ctx).
+		Unscoped().
+		Table("users").
+		Where("email_key = ? AND id <> ?", helper.EmailKey(email), exceptUserID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (u *userQueryImpl) CreateUser(ctx context.Context, user model.User) (model.User, error) {
 	db := u.db.GetConnection()
+	user.UsernameKey = helper.UsernameKey(user.Username)
+	user.EmailKey = helper.EmailKey(user.Email)
 	if err := db.
 		WithContext(ctx).
 		Table("users").
@@ -85,13 +226,171 @@ func (u *userQueryImpl) EditUser(ctx context.Context, user model.User, id uint64
 		Table("users").
 		// Clauses(clause.Returning{}).
 		Where("id = ?", id).
-		Select("username", "email").
-		Updates(model.User{Username: user.Username, Email: user.Email}).Error; err != nil {
+		Select("username", "username_key", "email", "email_key", "display_name").
+		Updates(model.User{
+			Username:    user.Username,
+			UsernameKey: helper.UsernameKey(user.Username),
+			Email:       user.Email,
+			EmailKey:    helper.EmailKey(user.Email),
+			DisplayName: user.DisplayName,
+		}).Error; err != nil {
 		return model.User{}, err
 	}
 	return user, nil
 }
 
+func (u *userQueryImpl) UpdatePrivacy(ctx context.Context, id uint64, isPrivate bool) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Update("is_private", isPrivate).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdatePassword stores a new password hash and invalidates every token
+// issued before at.
+func (u *userQueryImpl) UpdatePassword(ctx context.Context, id uint64, password string, at time.Time) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"password":           password,
+			"tokens_valid_after": at,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ResetPasswordWithToken completes a forced reset when tokenHash matches the
+// stored one. The token is cleared in the same statement, of two concurrent
+// uses only one reports true.
+func (u *userQueryImpl) ResetPasswordWithToken(ctx context.Context, id uint64, tokenHash string, password string, at time.Time) (bool, error) {
+	db := u.db.GetConnection()
+	res := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ? AND must_reset_password AND password_reset_token = ?", id, tokenHash).
+		Updates(map[string]any{
+			"password":             password,
+			"must_reset_password":  false,
+			"password_reset_token": nil,
+			"tokens_valid_after":   at,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ChangeUsername renames a user and keeps the old username as a redirect to
+// them. A redirect the user already held for the new name is released.
+func (u *userQueryImpl) ChangeUsername(ctx context.Context, id uint64, username string, at time.Time) error {
+	db := u.db.GetConnection()
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user := model.User{}
+		if err := tx.
+			Table("users").
+			Where("id = ?", id).
+			First(&user).Error; err != nil {
+			return err
+		}
+		if err := tx.
+			Table("username_redirects").
+			Where("username_key = ? AND user_id = ?", helper.UsernameKey(username), id).
+			Delete(&model.UsernameRedirect{}).Error; err != nil {
+			return err
+		}
+		if err := tx.
+			Table("username_redirects").
+			Create(&model.UsernameRedirect{UsernameKey: user.UsernameKey, Username: user.Username, UserID: id}).Error; err != nil {
+			return err
+		}
+		return tx.
+			Table("users").
+			Where("id = ?", id).
+			Updates(map[string]any{
+				"username":            username,
+				"username_key":        helper.UsernameKey(username),
+				"username_changed_at": at,
+			}).Error
+	})
+}
+
+func (u *userQueryImpl) SetSuspension(ctx context.Context, id uint64, until *time.Time, reason string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Updates(map[string]any{"suspended_until": until, "moderation_reason": reason}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u *userQueryImpl) SetBan(ctx context.Context, id uint64, bannedAt *time.Time, reason string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Updates(map[string]any{"banned_at": bannedAt, "moderation_reason": reason}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetPasswordResetToken forces a password reset that only the holder of the
+// token hashed to tokenHash can complete.
+func (u *userQueryImpl) SetPasswordResetToken(ctx context.Context, id uint64, tokenHash string) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"must_reset_password":  true,
+			"password_reset_token": tokenHash,
+		}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeTokens makes every access token issued before at invalid.
+func (u *userQueryImpl) RevokeTokens(ctx context.Context, id uint64, at time.Time) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Table("users").
+		Where("id = ?", id).
+		Update("tokens_valid_after", at).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreUser undoes a soft delete.
+func (u *userQueryImpl) RestoreUser(ctx context.Context, id uint64) error {
+	db := u.db.GetConnection()
+	if err := db.
+		WithContext(ctx).
+		Unscoped().
+		Table("users").
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 func (u *userQueryImpl) DeleteUser(ctx context.Context, id uint64) error {
 	db := u.db.GetConnection()
 	if err := db.
@@ -41,7 +41,7 @@ func TestGetUsers(t *testing.T) {
 		`)).WillReturnError(errors.New("some error"))
 
 		userRepo := userQueryImpl{db: postgresMock}
-		res, err := userRepo.GetUsers(context.Background())
+		res, err := userRepo.GetUsers(context.Background(), 1, UserSearch{Limit: 20})
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(res))
 	})
@@ -61,8 +61,26 @@ func TestGetUsers(t *testing.T) {
 		`)).WillReturnRows(row)
 
 		userRepo := userQueryImpl{db: postgresMock}
-		res, err := userRepo.GetUsers(context.Background())
+		res, err := userRepo.GetUsers(context.Background(), 1, UserSearch{Limit: 20})
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(res))
 	})
 }
+
+func TestGetUsersPrefixSearch(t *testing.T) {
+	db, mock := newMockGorm()
+	postgresMock := mocks.NewGormPostgres(t)
+	postgresMock.On("GetConnection").Return(db)
+	row := sqlmock.
+		NewRows([]string{"id", "username"}).
+		AddRow(1, "al_bert")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`(LOWER(username) LIKE $1 ESCAPE '\' OR LOWER(display_name) LIKE $2 ESCAPE '\')`)).
+		WithArgs(`al\_%`, `al\_%`, 1, 1, sqlmock.AnyArg(), 20).
+		WillReturnRows(row)
+
+	userRepo := userQueryImpl{db: postgresMock}
+	res, err := userRepo.GetUsers(context.Background(), 1, UserSearch{Prefix: "Al_", Limit: 20})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+}
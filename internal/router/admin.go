@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminRouter interface {
+	Mount()
+}
+
+type adminRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.AdminHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewAdminRouter(v *gin.RouterGroup, handler handler.AdminHandler, authMiddleware middleware.AuthorizationMiddleware) AdminRouter {
+	return &adminRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *adminRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication, u.authMiddleware.AdminAuthorization)
+
+	// /admin/users/:id/...
+	u.v.POST("/users/:id/suspend", u.handler.SuspendUser)
+	u.v.POST("/users/:id/ban", u.handler.BanUser)
+	u.v.POST("/users/:id/reinstate", u.handler.ReinstateUser)
+	u.v.POST("/users/:id/restore", u.handler.RestoreUser)
+	u.v.POST("/users/:id/force-password-reset", u.handler.ForcePasswordReset)
+	u.v.POST("/users/:id/revoke-sessions", u.handler.RevokeSessions)
+
+	// /admin/photos/:id/duplicates?distance=
+	u.v.GET("/photos/:id/duplicates", u.handler.FindNearDuplicates)
+}
@@ -0,0 +1,45 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type AlbumRouter interface {
+	Mount()
+}
+
+// albumRouterImpl mounts /albums on v and the album list of a user on users.
+type albumRouterImpl struct {
+	v              *gin.RouterGroup
+	users          *gin.RouterGroup
+	handler        handler.AlbumHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewAlbumRouter(v *gin.RouterGroup, users *gin.RouterGroup, handler handler.AlbumHandler, authMiddleware middleware.AuthorizationMiddleware) AlbumRouter {
+	return &albumRouterImpl{v: v, users: users, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *albumRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+	u.users.Use(u.authMiddleware.Authentication)
+
+	u.v.POST("", u.handler.CreateAlbum)
+	// /albums/:id?cursor=&limit=
+	u.v.GET("/:id", u.handler.GetAlbum)
+	u.v.PUT("/:id", u.authMiddleware.AlbumAuthorization, u.handler.EditAlbum)
+	u.v.DELETE("/:id", u.authMiddleware.AlbumAuthorization, u.handler.DeleteAlbum)
+
+	u.v.POST("/:id/photos", u.authMiddleware.AlbumContributorAuthorization, u.handler.AddPhoto)
+	u.v.PUT("/:id/photos", u.authMiddleware.AlbumAuthorization, u.handler.ReorderPhotos)
+	u.v.DELETE("/:id/photos/:photo_id", u.authMiddleware.AlbumContributorAuthorization, u.handler.RemovePhoto)
+
+	u.v.POST("/:id/collaborators", u.authMiddleware.AlbumAuthorization, u.handler.AddCollaborator)
+	u.v.DELETE("/:id/collaborators/:user_id", u.authMiddleware.AlbumContributorAuthorization, u.handler.RemoveCollaborator)
+
+	// /users/:id/albums?cursor=&limit=
+	u.users.GET("/:id/albums", u.handler.GetUserAlbums)
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type BlockRouter interface {
+	Mount()
+}
+
+type blockRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.BlockHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewBlockRouter(v *gin.RouterGroup, handler handler.BlockHandler, authMiddleware middleware.AuthorizationMiddleware) BlockRouter {
+	return &blockRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *blockRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /users/blocks
+	u.v.GET("/blocks", u.handler.GetBlocks)
+	// /users/mutes
+	u.v.GET("/mutes", u.handler.GetMutes)
+
+	u.v.POST("/:id/block", u.handler.Block)
+	u.v.DELETE("/:id/block", u.handler.Unblock)
+	u.v.POST("/:id/mute", u.handler.Mute)
+	u.v.DELETE("/:id/mute", u.handler.Unmute)
+}
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type CloseFriendRouter interface {
+	Mount()
+}
+
+type closeFriendRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.CloseFriendHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewCloseFriendRouter(v *gin.RouterGroup, handler handler.CloseFriendHandler, authMiddleware middleware.AuthorizationMiddleware) CloseFriendRouter {
+	return &closeFriendRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *closeFriendRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /users/close-friends
+	u.v.GET("/close-friends", u.handler.GetCloseFriends)
+
+	u.v.POST("/:id/close-friend", u.handler.AddCloseFriend)
+	u.v.DELETE("/:id/close-friend", u.handler.RemoveCloseFriend)
+}
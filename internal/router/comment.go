@@ -29,6 +29,8 @@ func (u *commentRouterImpl) Mount() {
 	u.v.GET("", u.handler.GetComments)
 
 	u.v.PUT("/:id", u.authMiddleware.CommentAuthorization, u.handler.EditComment)
+	// the author or an admin, checked by the service
+	u.v.GET("/:id/revisions", u.handler.GetCommentRevisions)
 
 	u.v.DELETE("/:id", u.authMiddleware.CommentAuthorization, u.handler.DeleteComment)
 }
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type ExploreRouter interface {
+	Mount()
+}
+
+type exploreRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.ExploreHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewExploreRouter(v *gin.RouterGroup, handler handler.ExploreHandler, authMiddleware middleware.AuthorizationMiddleware) ExploreRouter {
+	return &exploreRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *exploreRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /explore?cursor=&limit=
+	u.v.GET("", u.handler.GetExplore)
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedRouter interface {
+	Mount()
+}
+
+type feedRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.FeedHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewFeedRouter(v *gin.RouterGroup, handler handler.FeedHandler, authMiddleware middleware.AuthorizationMiddleware) FeedRouter {
+	return &feedRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *feedRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /feed?cursor=&limit=
+	u.v.GET("", u.handler.GetFeed)
+}
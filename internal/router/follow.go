@@ -0,0 +1,38 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type FollowRouter interface {
+	Mount()
+}
+
+type followRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.FollowHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewFollowRouter(v *gin.RouterGroup, handler handler.FollowHandler, authMiddleware middleware.AuthorizationMiddleware) FollowRouter {
+	return &followRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *followRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /users/follow-requests
+	u.v.GET("/follow-requests", u.handler.GetFollowRequests)
+	u.v.PUT("/follow-requests/:id", u.handler.AcceptFollowRequest)
+	u.v.DELETE("/follow-requests/:id", u.handler.RejectFollowRequest)
+
+	// /users/:id/...
+	u.v.POST("/:id/follow", u.handler.Follow)
+	u.v.DELETE("/:id/follow", u.handler.Unfollow)
+	u.v.GET("/:id/follow-status", u.handler.GetFollowStatus)
+	u.v.GET("/:id/followers", u.handler.GetFollowers)
+	u.v.GET("/:id/following", u.handler.GetFollowing)
+}
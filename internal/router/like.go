@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type LikeRouter interface {
+	Mount()
+}
+
+type likeRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.LikeHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewLikeRouter(v *gin.RouterGroup, handler handler.LikeHandler, authMiddleware middleware.AuthorizationMiddleware) LikeRouter {
+	return &likeRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *likeRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /photos/:id/...
+	u.v.POST("/:id/like", u.handler.Like)
+	u.v.DELETE("/:id/like", u.handler.Unlike)
+	u.v.GET("/:id/likes", u.handler.GetLikers)
+}
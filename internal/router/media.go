@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type MediaRouter interface {
+	Mount()
+}
+
+type mediaRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.MediaHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewMediaRouter(v *gin.RouterGroup, handler handler.MediaHandler, authMiddleware middleware.AuthorizationMiddleware) MediaRouter {
+	return &mediaRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *mediaRouterImpl) Mount() {
+	// public, the signature is the authorization
+	// /media/*key?w=&h=&fit=&fm=&q=&s=
+	u.v.GET("/*key", u.handler.GetMedia)
+
+	// POST /media/sign
+	u.v.POST("/sign", u.authMiddleware.Authentication, u.handler.SignMedia)
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationRouter interface {
+	Mount()
+}
+
+type notificationRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.NotificationHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewNotificationRouter(v *gin.RouterGroup, handler handler.NotificationHandler, authMiddleware middleware.AuthorizationMiddleware) NotificationRouter {
+	return &notificationRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *notificationRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /notifications?cursor=&limit=
+	u.v.GET("", u.handler.GetNotifications)
+	u.v.POST("/read", u.handler.MarkRead)
+}
@@ -10,8 +10,10 @@ type PhotoRouter interface {
 	Mount()
 }
 
+// photoRouterImpl mounts /photos on v and the profile grid on users.
 type photoRouterImpl struct {
 	v              *gin.RouterGroup
+	users          *gin.RouterGroup
 	handler        handler.PhotoHandler
 	authMiddleware middleware.AuthorizationMiddleware
 }
@@ -33,19 +35,40 @@ type photoRouterImpl struct {
 // 	u.v.DELETE("/:id", u.handler.DeletePhoto)
 // }
 
-func NewPhotoRouter(v *gin.RouterGroup, handler handler.PhotoHandler, authMiddleware middleware.AuthorizationMiddleware) PhotoRouter {
-	return &photoRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+func NewPhotoRouter(v *gin.RouterGroup, users *gin.RouterGroup, handler handler.PhotoHandler, authMiddleware middleware.AuthorizationMiddleware) PhotoRouter {
+	return &photoRouterImpl{v: v, users: users, handler: handler, authMiddleware: authMiddleware}
 }
 
 func (u *photoRouterImpl) Mount() {
 
 	u.v.Use(u.authMiddleware.Authentication)
+	u.users.Use(u.authMiddleware.Authentication)
 
 	u.v.POST("", u.handler.PostPhoto)
 
 	u.v.GET("", u.handler.GetPhotos)
 
+	// /photos/:id?cursor=&limit= pages through the comments, anyone who may
+	// see the photo can
+	u.v.GET("/:id", u.handler.GetPhoto)
+
 	u.v.PUT("/:id", u.authMiddleware.PhotoAuthorization, u.handler.EditPhoto)
 
+	// the author or an admin, checked by the service
+	u.v.GET("/:id/revisions", u.handler.GetPhotoRevisions)
+
 	u.v.DELETE("/:id", u.authMiddleware.PhotoAuthorization, u.handler.DeletePhoto)
+
+	u.v.PUT("/:id/location", u.authMiddleware.PhotoAuthorization, u.handler.SetPhotoLocation)
+
+	u.v.POST("/:id/media", u.authMiddleware.PhotoAuthorization, u.handler.AddMediaItem)
+
+	u.v.PUT("/:id/media", u.authMiddleware.PhotoAuthorization, u.handler.ReorderMediaItems)
+
+	u.v.PUT("/:id/media/:media_id", u.authMiddleware.PhotoAuthorization, u.handler.EditMediaItem)
+
+	u.v.DELETE("/:id/media/:media_id", u.authMiddleware.PhotoAuthorization, u.handler.DeleteMediaItem)
+
+	// /users/:id/photos?cursor=&limit=
+	u.users.GET("/:id/photos", u.handler.GetUserPhotos)
 }
@@ -0,0 +1,43 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type SaveRouter interface {
+	Mount()
+}
+
+// saveRouterImpl mounts saving a photo on photos and the saved photos and
+// collections on v.
+type saveRouterImpl struct {
+	v              *gin.RouterGroup
+	photos         *gin.RouterGroup
+	handler        handler.SaveHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewSaveRouter(v *gin.RouterGroup, photos *gin.RouterGroup, handler handler.SaveHandler, authMiddleware middleware.AuthorizationMiddleware) SaveRouter {
+	return &saveRouterImpl{v: v, photos: photos, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *saveRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+	u.photos.Use(u.authMiddleware.Authentication)
+
+	// /photos/:id/save
+	u.photos.POST("/:id/save", u.handler.Save)
+	u.photos.DELETE("/:id/save", u.handler.Unsave)
+
+	// /saved?collection_id=&cursor=&limit=
+	u.v.GET("", u.handler.GetSaved)
+	u.v.GET("/collections", u.handler.GetCollections)
+	u.v.POST("/collections", u.handler.CreateCollection)
+	u.v.PUT("/collections/:id", u.handler.RenameCollection)
+	u.v.DELETE("/collections/:id", u.handler.DeleteCollection)
+	u.v.POST("/collections/:id/photos", u.handler.AddToCollection)
+	u.v.DELETE("/collections/:id/photos/:photo_id", u.handler.RemoveFromCollection)
+}
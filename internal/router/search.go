@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchRouter interface {
+	Mount()
+}
+
+// searchRouterImpl mounts /search on v and nearby search on photos.
+type searchRouterImpl struct {
+	v              *gin.RouterGroup
+	photos         *gin.RouterGroup
+	handler        handler.SearchHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewSearchRouter(v *gin.RouterGroup, photos *gin.RouterGroup, handler handler.SearchHandler, authMiddleware middleware.AuthorizationMiddleware) SearchRouter {
+	return &searchRouterImpl{v: v, photos: photos, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *searchRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+	u.photos.Use(u.authMiddleware.Authentication)
+
+	// /search/photos?q=&cursor=&limit=
+	u.v.GET("/photos", u.handler.SearchPhotos)
+	// /photos/nearby?lat=&lng=&radius=&cursor=&limit=
+	u.photos.GET("/nearby", u.handler.NearbyPhotos)
+}
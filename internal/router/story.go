@@ -0,0 +1,52 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type StoryRouter interface {
+	Mount()
+}
+
+// storyRouterImpl mounts /stories on v, /highlights on highlights and the
+// stories and highlights of a user on users.
+type storyRouterImpl struct {
+	v              *gin.RouterGroup
+	highlights     *gin.RouterGroup
+	users          *gin.RouterGroup
+	handler        handler.StoryHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewStoryRouter(v *gin.RouterGroup, highlights *gin.RouterGroup, users *gin.RouterGroup, handler handler.StoryHandler, authMiddleware middleware.AuthorizationMiddleware) StoryRouter {
+	return &storyRouterImpl{v: v, highlights: highlights, users: users, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *storyRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+	u.highlights.Use(u.authMiddleware.Authentication)
+	u.users.Use(u.authMiddleware.Authentication)
+
+	// the author is checked by the service, anyone else gets a 404
+	u.v.POST("", u.handler.PostStory)
+	u.v.GET("", u.handler.GetStoryTray)
+	u.v.DELETE("/:id", u.handler.DeleteStory)
+	u.v.POST("/:id/seen", u.handler.MarkSeen)
+	// /stories/:id/viewers?cursor=&limit=
+	u.v.GET("/:id/viewers", u.handler.GetStoryViewers)
+
+	u.highlights.POST("", u.handler.CreateHighlight)
+	u.highlights.GET("/:id", u.handler.GetHighlight)
+	u.highlights.PUT("/:id", u.handler.EditHighlight)
+	u.highlights.DELETE("/:id", u.handler.DeleteHighlight)
+	u.highlights.POST("/:id/stories", u.handler.AddHighlightStory)
+	u.highlights.PUT("/:id/stories", u.handler.ReorderHighlightStories)
+	u.highlights.DELETE("/:id/stories/:story_id", u.handler.RemoveHighlightStory)
+
+	u.users.GET("/:id/stories", u.handler.GetUserStories)
+	// /users/:id/highlights?cursor=&limit=
+	u.users.GET("/:id/highlights", u.handler.GetUserHighlights)
+}
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/handler"
+	"github.com/MidnightHelix/MyGram/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type TagRouter interface {
+	Mount()
+}
+
+type tagRouterImpl struct {
+	v              *gin.RouterGroup
+	handler        handler.TagHandler
+	authMiddleware middleware.AuthorizationMiddleware
+}
+
+func NewTagRouter(v *gin.RouterGroup, handler handler.TagHandler, authMiddleware middleware.AuthorizationMiddleware) TagRouter {
+	return &tagRouterImpl{v: v, handler: handler, authMiddleware: authMiddleware}
+}
+
+func (u *tagRouterImpl) Mount() {
+
+	u.v.Use(u.authMiddleware.Authentication)
+
+	// /tags?q=&limit=
+	u.v.GET("", u.handler.SearchTags)
+	// /tags/:name?cursor=&limit=
+	u.v.GET("/:name", u.handler.GetTag)
+}
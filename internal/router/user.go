@@ -26,6 +26,10 @@ func (u *userRouterImpl) Mount() {
 	u.v.POST("/register", u.handler.UserSignUp)
 	// /users/login
 	u.v.POST("/login", u.handler.UserLogin)
+	// /users/password
+	u.v.POST("/password", u.handler.ResetPassword)
+	// /users/availability?username=
+	u.v.GET("/availability", u.handler.CheckUsername)
 
 	// users
 	u.v.Use(u.authMiddleware.Authentication)
@@ -33,6 +37,12 @@ func (u *userRouterImpl) Mount() {
 	u.v.GET("", u.handler.GetUsers)
 	// /users/:id
 	u.v.GET("/:id", u.handler.GetUsersById)
+	// /users/by-username/:username
+	u.v.GET("/by-username/:username", u.handler.GetUserByUsername)
+	// PUT /users/username
+	u.v.PUT("/username", u.handler.ChangeUsername)
+	// PUT /users/privacy
+	u.v.PUT("/privacy", u.handler.UpdatePrivacy)
 	// PUT /users
 	u.v.PUT("/:id", u.authMiddleware.UserAuthorization, u.handler.EditUser)
 	// DELETE /users
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+type AdminService interface {
+	SuspendUser(ctx context.Context, id uint64, duration time.Duration, reason string) (model.User, error)
+	BanUser(ctx context.Context, id uint64, reason string) (model.User, error)
+	ReinstateUser(ctx context.Context, id uint64) (model.User, error)
+	RestoreUser(ctx context.Context, id uint64) (model.User, error)
+	ForcePasswordReset(ctx context.Context, id uint64) (token string, err error)
+	RevokeSessions(ctx context.Context, id uint64) error
+
+	// FindNearDuplicates finds photos of any user within maxDistance bits
+	// of the photo's PHash, zero uses DuplicateDistance.
+	FindNearDuplicates(ctx context.Context, viewerID uint64, photoID uint64, maxDistance int) ([]NearDuplicate, error)
+}
+
+type adminServiceImpl struct {
+	userRepo  repository.UserQuery
+	photoRepo repository.PhotoQuery
+	likeRepo  repository.LikeQuery
+	saveRepo  repository.SaveQuery
+}
+
+func NewAdminService(userRepo repository.UserQuery, photoRepo repository.PhotoQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery) AdminService {
+	return &adminServiceImpl{userRepo: userRepo, photoRepo: photoRepo, likeRepo: likeRepo, saveRepo: saveRepo}
+}
+
+func (u *adminServiceImpl) SuspendUser(ctx context.Context, id uint64, duration time.Duration, reason string) (model.User, error) {
+	if duration <= 0 {
+		return model.User{}, ErrInvalidDuration
+	}
+	if _, err := u.getUser(ctx, id); err != nil {
+		return model.User{}, err
+	}
+
+	until := time.Now().Add(duration)
+	if err := u.userRepo.SetSuspension(ctx, id, &until, reason); err != nil {
+		return model.User{}, err
+	}
+	return u.getUser(ctx, id)
+}
+
+func (u *adminServiceImpl) BanUser(ctx context.Context, id uint64, reason string) (model.User, error) {
+	if _, err := u.getUser(ctx, id); err != nil {
+		return model.User{}, err
+	}
+
+	now := time.Now()
+	if err := u.userRepo.SetBan(ctx, id, &now, reason); err != nil {
+		return model.User{}, err
+	}
+	return u.getUser(ctx, id)
+}
+
+// ReinstateUser lifts both a suspension and a ban.
+func (u *adminServiceImpl) ReinstateUser(ctx context.Context, id uint64) (model.User, error) {
+	if _, err := u.getUser(ctx, id); err != nil {
+		return model.User{}, err
+	}
+
+	if err := u.userRepo.SetSuspension(ctx, id, nil, ""); err != nil {
+		return model.User{}, err
+	}
+	if err := u.userRepo.SetBan(ctx, id, nil, ""); err != nil {
+		return model.User{}, err
+	}
+	return u.getUser(ctx, id)
+}
+
+func (u *adminServiceImpl) RestoreUser(ctx context.Context, id uint64) (model.User, error) {
+	user, err := u.userRepo.GetUsersByIDUnscoped(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID == 0 {
+		return model.User{}, ErrUserNotFound
+	}
+	if !user.DeletedAt.Valid {
+		return model.User{}, ErrUserNotDeleted
+	}
+
+	if err := u.userRepo.RestoreUser(ctx, id); err != nil {
+		return model.User{}, err
+	}
+	return u.getUser(ctx, id)
+}
+
+// ForcePasswordReset signs the user out everywhere and makes the next login
+// go through a password change. The returned token is the only way to
+// complete it and has to reach the user out of band, it works once.
+func (u *adminServiceImpl) ForcePasswordReset(ctx context.Context, id uint64) (string, error) {
+	if _, err := u.getUser(ctx, id); err != nil {
+		return "", err
+	}
+	token, hash, err := newResetToken()
+	if err != nil {
+		return "", err
+	}
+	if err := u.userRepo.SetPasswordResetToken(ctx, id, hash); err != nil {
+		return "", err
+	}
+	if err := u.userRepo.RevokeTokens(ctx, id, time.Now()); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (u *adminServiceImpl) RevokeSessions(ctx context.Context, id uint64) error {
+	if _, err := u.getUser(ctx, id); err != nil {
+		return err
+	}
+	return u.userRepo.RevokeTokens(ctx, id, time.Now())
+}
+
+func (u *adminServiceImpl) FindNearDuplicates(ctx context.Context, viewerID uint64, photoID uint64, maxDistance int) ([]NearDuplicate, error) {
+	if maxDistance == 0 {
+		maxDistance = DuplicateDistance
+	}
+	if maxDistance < 0 || maxDistance > imaging.MaxProbeDistance {
+		return nil, ErrInvalidDistance
+	}
+
+	photo, err := u.photoRepo.GetPhotosByID(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if photo.ID == 0 {
+		return nil, ErrPhotoNotFound
+	}
+	hashes, ok := photoHashes(photo)
+	if !ok {
+		return []NearDuplicate{}, nil
+	}
+	duplicates, err := findNearDuplicates(ctx, u.photoRepo, hashes, 0, photo.ID, maxDistance)
+	if err != nil {
+		return nil, err
+	}
+
+	photos := make([]model.Photo, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		photos = append(photos, duplicate.Photo)
+	}
+	if err := markLiked(ctx, u.likeRepo, viewerID, photos); err != nil {
+		return nil, err
+	}
+	if err := markSaved(ctx, u.saveRepo, viewerID, photos); err != nil {
+		return nil, err
+	}
+	for i := range duplicates {
+		duplicates[i].Photo = photos[i]
+	}
+	return duplicates, nil
+}
+
+func (u *adminServiceImpl) getUser(ctx context.Context, id uint64) (model.User, error) {
+	user, err := u.userRepo.GetUsersByID(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID == 0 {
+		return model.User{}, ErrUserNotFound
+	}
+	return user, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSuspendUser(t *testing.T) {
+	t.Run("error invalid duration", func(t *testing.T) {
+		svc := adminServiceImpl{userRepo: mocks.NewUserQuery(t)}
+
+		_, err := svc.SuspendUser(context.Background(), 1, 0, "spam")
+		assert.ErrorIs(t, err, ErrInvalidDuration)
+	})
+
+	t.Run("error user not found", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{}, nil)
+		svc := adminServiceImpl{userRepo: userMock}
+
+		_, err := svc.SuspendUser(context.Background(), 1, time.Hour, "spam")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("success suspend until now plus duration", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		var until *time.Time
+		userMock.On("SetSuspension", context.Background(), uint64(1), mock.AnythingOfType("*time.Time"), "spam").
+			Run(func(args mock.Arguments) { until = args.Get(2).(*time.Time) }).
+			Return(nil)
+		svc := adminServiceImpl{userRepo: userMock}
+
+		_, err := svc.SuspendUser(context.Background(), 1, time.Hour, "spam")
+		assert.Nil(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *until, time.Minute)
+	})
+}
+
+func TestRestoreUser(t *testing.T) {
+	t.Run("error user not deleted", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByIDUnscoped", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		svc := adminServiceImpl{userRepo: userMock}
+
+		_, err := svc.RestoreUser(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrUserNotDeleted)
+	})
+
+	t.Run("success restore deleted user", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		deleted := model.User{ID: 1, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+		userMock.On("GetUsersByIDUnscoped", context.Background(), uint64(1)).Return(deleted, nil)
+		userMock.On("RestoreUser", context.Background(), uint64(1)).Return(nil)
+		userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+		svc := adminServiceImpl{userRepo: userMock}
+
+		res, err := svc.RestoreUser(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1), res.ID)
+	})
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	userMock := mocks.NewUserQuery(t)
+	userMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1}, nil)
+	var stored string
+	userMock.On("SetPasswordResetToken", context.Background(), uint64(1), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { stored = args.String(2) }).
+		Return(nil)
+	userMock.On("RevokeTokens", context.Background(), uint64(1), mock.AnythingOfType("time.Time")).Return(nil)
+	svc := adminServiceImpl{userRepo: userMock}
+
+	token, err := svc.ForcePasswordReset(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+	// only the hash is stored
+	assert.NotEqual(t, token, stored)
+	assert.Equal(t, hashResetToken(token), stored)
+}
+
+func TestFindNearDuplicates(t *testing.T) {
+	t.Run("error invalid distance", func(t *testing.T) {
+		svc := adminServiceImpl{photoRepo: mocks.NewPhotoQuery(t)}
+
+		_, err := svc.FindNearDuplicates(context.Background(), 9, 1, 40)
+		assert.ErrorIs(t, err, ErrInvalidDistance)
+	})
+
+	t.Run("error photo not found", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(1)).Return(model.Photo{}, nil)
+		svc := adminServiceImpl{photoRepo: photoMock}
+
+		_, err := svc.FindNearDuplicates(context.Background(), 9, 1, 0)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+
+	t.Run("success across users, closest first", func(t *testing.T) {
+		photo := model.Photo{ID: 1, UserID: 1}
+		setPhotoHashes(&photo, imaging.Hashes{PHash: 0xff00ff00ff00ff00, DHash: 0x0f0f0f0f0f0f0f0f})
+		far := model.Photo{ID: 2, UserID: 2}
+		setPhotoHashes(&far, imaging.Hashes{PHash: 0xff00ff00ff00ff00 ^ 0b11111, DHash: 0x0f0f0f0f0f0f0f0f})
+		near := model.Photo{ID: 3, UserID: 3}
+		setPhotoHashes(&near, imaging.Hashes{PHash: 0xff00ff00ff00ff00 ^ 0b1, DHash: 0x0f0f0f0f0f0f0f0f ^ 0b11})
+		tooFar := model.Photo{ID: 4, UserID: 4}
+		setPhotoHashes(&tooFar, imaging.Hashes{PHash: 0x00ff00ff00ff00ff})
+
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(1)).Return(photo, nil)
+		photoMock.On("FindByHashBands", context.Background(), mock.Anything, uint64(0), uint64(1), duplicateCandidates).
+			Return([]model.Photo{far, near, tooFar}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(9), []uint64{3, 2}).Return([]uint64{2}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(9), []uint64{3, 2}).Return([]uint64{}, nil)
+		svc := adminServiceImpl{photoRepo: photoMock, likeRepo: likeMock, saveRepo: saveMock}
+
+		res, err := svc.FindNearDuplicates(context.Background(), 9, 1, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, uint64(3), res[0].Photo.ID)
+		assert.Equal(t, 1, res[0].PHashDistance)
+		assert.Equal(t, 2, res[0].DHashDistance)
+		assert.Equal(t, uint64(2), res[1].Photo.ID)
+		assert.False(t, res[0].Photo.LikedByMe)
+		assert.True(t, res[1].Photo.LikedByMe)
+	})
+}
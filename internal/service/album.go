@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+// AlbumService manages albums. Routes that change an album are guarded by
+// the album authorization middleware, so the caller here is already known
+// to be its owner, or a collaborator where collaborators are allowed.
+type AlbumService interface {
+	// GetAlbum returns an album and a page of its photos in album order.
+	GetAlbum(ctx context.Context, viewerID uint64, id uint64, cursor uint64, limit int) (model.Album, []model.AlbumPhoto, error)
+	GetUserAlbums(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Album, error)
+
+	CreateAlbum(ctx context.Context, album model.Album, userID uint64) (model.Album, error)
+	EditAlbum(ctx context.Context, album model.Album, id uint64) (model.Album, error)
+	DeleteAlbum(ctx context.Context, id uint64) error
+
+	AddPhoto(ctx context.Context, userID uint64, albumID uint64, photoID uint64) error
+	RemovePhoto(ctx context.Context, userID uint64, albumID uint64, photoID uint64) error
+	ReorderPhotos(ctx context.Context, albumID uint64, photoIDs []uint64) error
+
+	AddCollaborator(ctx context.Context, ownerID uint64, albumID uint64, userID uint64) error
+	RemoveCollaborator(ctx context.Context, callerID uint64, albumID uint64, userID uint64) error
+}
+
+type albumServiceImpl struct {
+	repo      repository.AlbumQuery
+	photoRepo repository.PhotoQuery
+	userRepo  repository.UserQuery
+	blockRepo repository.BlockQuery
+	likeRepo  repository.LikeQuery
+	saveRepo  repository.SaveQuery
+}
+
+func NewAlbumService(repo repository.AlbumQuery, photoRepo repository.PhotoQuery, userRepo repository.UserQuery, blockRepo repository.BlockQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery) AlbumService {
+	return &albumServiceImpl{repo: repo, photoRepo: photoRepo, userRepo: userRepo, blockRepo: blockRepo, likeRepo: likeRepo, saveRepo: saveRepo}
+}
+
+func (u *albumServiceImpl) GetAlbum(ctx context.Context, viewerID uint64, id uint64, cursor uint64, limit int) (model.Album, []model.AlbumPhoto, error) {
+	album, err := u.repo.GetAlbum(ctx, viewerID, id)
+	if err != nil {
+		return model.Album{}, nil, err
+	}
+	if album.ID == 0 {
+		return model.Album{}, nil, ErrAlbumNotFound
+	}
+
+	items, err := u.repo.GetAlbumPhotos(ctx, viewerID, id, cursor, normalizeLimit(limit))
+	if err != nil {
+		return model.Album{}, nil, err
+	}
+	photos := make([]model.Photo, 0, len(items))
+	for _, item := range items {
+		photos = append(photos, *item.Photo)
+	}
+	if err := markLiked(ctx, u.likeRepo, viewerID, photos); err != nil {
+		return model.Album{}, nil, err
+	}
+	if err := markSaved(ctx, u.saveRepo, viewerID, photos); err != nil {
+		return model.Album{}, nil, err
+	}
+	for i := range items {
+		items[i].Photo = &photos[i]
+	}
+	return album, items, nil
+}
+
+func (u *albumServiceImpl) GetUserAlbums(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Album, error) {
+	return u.repo.GetUserAlbums(ctx, viewerID, userID, cursor, normalizeLimit(limit))
+}
+
+// CreateAlbum makes an empty album, its cover becomes the first photo added.
+func (u *albumServiceImpl) CreateAlbum(ctx context.Context, album model.Album, userID uint64) (model.Album, error) {
+	album.UserID = userID
+	album.CoverPhotoID = nil
+	if album.Visibility == "" {
+		album.Visibility = model.AlbumVisibilityPublic
+	}
+	return u.repo.CreateAlbum(ctx, album)
+}
+
+// EditAlbum keeps the current visibility and cover when album leaves them
+// empty. A new cover must be one of the album's photos.
+func (u *albumServiceImpl) EditAlbum(ctx context.Context, album model.Album, id uint64) (model.Album, error) {
+	existing, err := u.repo.GetAlbumByID(ctx, id)
+	if err != nil {
+		return model.Album{}, err
+	}
+	if existing.ID == 0 {
+		return model.Album{}, ErrAlbumNotFound
+	}
+
+	album.ID = existing.ID
+	album.UserID = existing.UserID
+	if album.Visibility == "" {
+		album.Visibility = existing.Visibility
+	}
+	if album.CoverPhotoID == nil {
+		album.CoverPhotoID = existing.CoverPhotoID
+	} else {
+		item, err := u.repo.GetAlbumPhoto(ctx, id, *album.CoverPhotoID)
+		if err != nil {
+			return model.Album{}, err
+		}
+		if item.PhotoID == 0 {
+			return model.Album{}, ErrPhotoNotInAlbum
+		}
+	}
+	return u.repo.UpdateAlbum(ctx, album)
+}
+
+func (u *albumServiceImpl) DeleteAlbum(ctx context.Context, id uint64) error {
+	return u.repo.DeleteAlbum(ctx, id)
+}
+
+// AddPhoto appends one of the caller's own photos to the album, adding a
+// photo that is already in it does nothing.
+func (u *albumServiceImpl) AddPhoto(ctx context.Context, userID uint64, albumID uint64, photoID uint64) error {
+	photo, err := u.photoRepo.GetPhotosByID(ctx, photoID)
+	if err != nil {
+		return err
+	}
+	if photo.ID == 0 {
+		return ErrPhotoNotFound
+	}
+	if photo.UserID != userID {
+		return ErrPhotoNotOwned
+	}
+	_, err = u.repo.AddAlbumPhoto(ctx, albumID, photoID, userID)
+	return err
+}
+
+// RemovePhoto lets the owner remove any photo and collaborators only the
+// photos they added.
+func (u *albumServiceImpl) RemovePhoto(ctx context.Context, userID uint64, albumID uint64, photoID uint64) error {
+	album, err := u.repo.GetAlbumByID(ctx, albumID)
+	if err != nil {
+		return err
+	}
+	item, err := u.repo.GetAlbumPhoto(ctx, albumID, photoID)
+	if err != nil {
+		return err
+	}
+	if item.PhotoID == 0 {
+		return ErrPhotoNotInAlbum
+	}
+	if album.UserID != userID && item.AddedBy != userID {
+		return ErrAlbumPermission
+	}
+	_, err = u.repo.RemoveAlbumPhoto(ctx, albumID, photoID)
+	return err
+}
+
+// ReorderPhotos takes the complete new order, so a photo added meanwhile by
+// a collaborator is never silently moved.
+func (u *albumServiceImpl) ReorderPhotos(ctx context.Context, albumID uint64, photoIDs []uint64) error {
+	current, err := u.repo.GetAlbumPhotoIDs(ctx, albumID)
+	if err != nil {
+		return err
+	}
+	if len(current) != len(photoIDs) {
+		return ErrInvalidAlbumOrder
+	}
+	remaining := make(map[uint64]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range photoIDs {
+		if !remaining[id] {
+			return ErrInvalidAlbumOrder
+		}
+		delete(remaining, id)
+	}
+	return u.repo.ReorderAlbumPhotos(ctx, albumID, photoIDs)
+}
+
+func (u *albumServiceImpl) AddCollaborator(ctx context.Context, ownerID uint64, albumID uint64, userID uint64) error {
+	if ownerID == userID {
+		return ErrCollaboratorSelf
+	}
+	user, err := u.userRepo.GetUsersByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 || checkAccountStatus(user) != nil {
+		return ErrUserNotFound
+	}
+	blocked, err := u.blockRepo.IsBlocked(ctx, ownerID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrUserNotFound
+	}
+	return u.repo.AddCollaborator(ctx, albumID, userID)
+}
+
+// RemoveCollaborator lets the owner remove anyone and a collaborator leave.
+// Photos they added stay in the album.
+func (u *albumServiceImpl) RemoveCollaborator(ctx context.Context, callerID uint64, albumID uint64, userID uint64) error {
+	album, err := u.repo.GetAlbumByID(ctx, albumID)
+	if err != nil {
+		return err
+	}
+	if album.UserID != callerID && userID != callerID {
+		return ErrAlbumPermission
+	}
+	return u.repo.RemoveCollaborator(ctx, albumID, userID)
+}
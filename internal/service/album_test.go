@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAlbum(t *testing.T) {
+	t.Run("success marks liked photos", func(t *testing.T) {
+		albumMock := mocks.NewAlbumQuery(t)
+		albumMock.On("GetAlbum", context.Background(), uint64(1), uint64(4)).Return(model.Album{ID: 4, UserID: 2}, nil)
+		albumMock.On("GetAlbumPhotos", context.Background(), uint64(1), uint64(4), uint64(0), 20).
+			Return([]model.AlbumPhoto{{AlbumID: 4, PhotoID: 7, Position: 1, Photo: &model.Photo{ID: 7}}, {AlbumID: 4, PhotoID: 8, Position: 2, Photo: &model.Photo{ID: 8}}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{7, 8}).Return([]uint64{8}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{7, 8}).Return([]uint64{}, nil)
+		svc := albumServiceImpl{repo: albumMock, likeRepo: likeMock, saveRepo: saveMock}
+
+		_, items, err := svc.GetAlbum(context.Background(), 1, 4, 0, 0)
+		assert.Nil(t, err)
+		assert.False(t, items[0].Photo.LikedByMe)
+		assert.True(t, items[1].Photo.LikedByMe)
+	})
+
+	t.Run("error not visible", func(t *testing.T) {
+		albumMock := mocks.NewAlbumQuery(t)
+		albumMock.On("GetAlbum", context.Background(), uint64(1), uint64(4)).Return(model.Album{}, nil)
+		svc := albumServiceImpl{repo: albumMock}
+
+		_, _, err := svc.GetAlbum(context.Background(), 1, 4, 0, 0)
+		assert.ErrorIs(t, err, ErrAlbumNotFound)
+	})
+}
+
+func TestEditAlbum(t *testing.T) {
+	t.Run("success keeps visibility and cover", func(t *testing.T) {
+		cover := uint64(7)
+		albumMock := mocks.NewAlbumQuery(t)
+		albumMock.On("GetAlbumByID", context.Background(), uint64(4)).
+			Return(model.Album{ID: 4, UserID: 2, Visibility: model.AlbumVisibilityFollowers, CoverPhotoID: &cover}, nil)
+		albumMock.On("UpdateAlbum", context.Background(), model.Album{ID: 4, UserID: 2, Title: "Trip", Visibility: model.AlbumVisibilityFollowers, CoverPhotoID: &cover}).
+			Return(func(ctx context.Context, album model.Album) (model.Album, error) { return album, nil })
+		svc := albumServiceImpl{repo: albumMock}
+
+		res, err := svc.EditAlbum(context.Background(), model.Album{Title: "Trip"}, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, model.AlbumVisibilityFollowers, res.Visibility)
+	})
+
+	t.Run("error cover not in album", func(t *testing.T) {
+		cover := uint64(9)
+		albumMock := mocks.NewAlbumQuery(t)
+		albumMock.On("GetAlbumByID", context.Background(), uint64(4)).Return(model.Album{ID: 4, UserID: 2}, nil)
+		albumMock.On("GetAlbumPhoto", context.Background(), uint64(4), uint64(9)).Return(model.AlbumPhoto{}, nil)
+		svc := albumServiceImpl{repo: albumMock}
+
+		_, err := svc.EditAlbum(context.Background(), model.Album{Title: "Trip", CoverPhotoID: &cover}, 4)
+		assert.ErrorIs(t, err, ErrPhotoNotInAlbum)
+	})
+}
+
+func TestAddAlbumPhoto(t *testing.T) {
+	t.Run("success own photo", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 3}, nil)
+		albumMock := mocks.NewAlbumQuery(t)
+		albumMock.On("AddAlbumPhoto", context.Background(), uint64(4), uint64(7), uint64(3)).Return(true, nil)
+		svc := albumServiceImpl{repo: albumMock, photoRepo: photoMock}
+
+		assert.Nil(t, svc.AddPhoto(context.Background(), 3, 4, 7))
+	})
+
+	t.Run("error someone else's photo", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+		svc := albumServiceImpl{repo: mocks.NewAlbumQuery(t), photoRepo: photoMock}
+
+		assert.ErrorIs(t, svc.AddPhoto(context.Background(), 3, 4, 7), ErrPhotoNotOwned)
+	})
+}
+
+func TestRemoveAlbumPhoto(t *testing.T) {
+	t.Run("error collaborator removing a photo they did not add", func(t *testing.T) {
+		albumMock := mocks.NewAlbumQuery(t)
+		albumMock.On("GetAlbumByID", context.Background(), uint64(4)).Return(model.Album{ID: 4, UserID: 2}, nil)
+		albumMock.On("GetAlbumPhoto", context.Background(), uint64(4), uint64(7)).Return(model.AlbumPhoto{AlbumID: 4, PhotoID: 7, AddedBy: 2}, nil)
+		svc := albumServiceImpl{repo: albumMock}
+
+		assert.ErrorIs(t, svc.RemovePhoto(context.Background(), 3, 4, 7), ErrAlbumPermission)
+	})
+
+	t.Run("success owner removes a collaborator's photo", func(t *testing.T) {
+		albumMock := mocks.NewAlbumQuery(t)
+		albumMock.On("GetAlbumByID", context.Background(), uint64(4)).Return(model.Album{ID: 4, UserID: 2}, nil)
+		albumMock.On("GetAlbumPhoto", context.Background(), uint64(4), uint64(7)).Return(model.AlbumPhoto{AlbumID: 4, PhotoID: 7, AddedBy: 3}, nil)
+		albumMock.On("RemoveAlbumPhoto", context.Background(), uint64(4), uint64(7)).Return(true, nil)
+		svc := albumServiceImpl{repo: albumMock}
+
+		assert.Nil(t, svc.RemovePhoto(context.Background(), 2, 4, 7))
+	})
+}
+
+func TestReorderAlbumPhotos(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []uint64
+		err   error
+	}{
+		{"success", []uint64{9, 7, 8}, nil},
+		{"error missing photo", []uint64{9, 7}, ErrInvalidAlbumOrder},
+		{"error repeated photo", []uint64{9, 7, 7}, ErrInvalidAlbumOrder},
+		{"error unknown photo", []uint64{9, 7, 5}, ErrInvalidAlbumOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			albumMock := mocks.NewAlbumQuery(t)
+			albumMock.On("GetAlbumPhotoIDs", context.Background(), uint64(4)).Return([]uint64{7, 8, 9}, nil)
+			if tt.err == nil {
+				albumMock.On("ReorderAlbumPhotos", context.Background(), uint64(4), tt.order).Return(nil)
+			}
+			svc := albumServiceImpl{repo: albumMock}
+
+			assert.ErrorIs(t, svc.ReorderPhotos(context.Background(), 4, tt.order), tt.err)
+		})
+	}
+}
+
+func TestAddAlbumCollaborator(t *testing.T) {
+	t.Run("error self", func(t *testing.T) {
+		svc := albumServiceImpl{repo: mocks.NewAlbumQuery(t)}
+
+		assert.ErrorIs(t, svc.AddCollaborator(context.Background(), 2, 4, 2), ErrCollaboratorSelf)
+	})
+
+	t.Run("error blocked", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(3)).Return(model.User{ID: 3}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(2), uint64(3)).Return(true, nil)
+		svc := albumServiceImpl{repo: mocks.NewAlbumQuery(t), userRepo: userMock, blockRepo: blockMock}
+
+		assert.ErrorIs(t, svc.AddCollaborator(context.Background(), 2, 4, 3), ErrUserNotFound)
+	})
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+)
+
+// DefaultGCGrace keeps objects of recently deleted photos and of uploads
+// still in flight.
+const DefaultGCGrace = 7 * 24 * time.Hour
+
+const gcBatch = 100
+
+type GCOptions struct {
+	// Grace is how long a blob must have been unreferenced, photos deleted
+	// within it still hold their blob.
+	Grace  time.Duration
+	DryRun bool
+	// Limit stops after this many blobs, zero collects all.
+	Limit int
+}
+
+// GCReport lists what a collection removed, or would remove on a dry run.
+type GCReport struct {
+	DryRun    bool
+	Collected []model.Blob
+	Bytes     int64
+	// Drifted are collected blobs whose RefCount was not zero, a sign of
+	// references that were never released.
+	Drifted int
+	Errors  []string
+}
+
+type BlobGC interface {
+	Collect(ctx context.Context, opts GCOptions) (GCReport, error)
+}
+
+type blobGCImpl struct {
+	repo  repository.BlobQuery
+	store storage.Storage
+}
+
+func NewBlobGC(repo repository.BlobQuery, store storage.Storage) BlobGC {
+	return &blobGCImpl{repo: repo, store: store}
+}
+
+// Collect deletes blobs no live or recently deleted photo references,
+// together with the variants rendered from them. Failures to delete single
+// blobs are reported and skipped.
+func (g *blobGCImpl) Collect(ctx context.Context, opts GCOptions) (GCReport, error) {
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultGCGrace
+	}
+	cutoff := time.Now().Add(-opts.Grace)
+	report := GCReport{DryRun: opts.DryRun, Collected: []model.Blob{}}
+
+	afterID := uint64(0)
+	for opts.Limit == 0 || len(report.Collected) < opts.Limit {
+		blobs, err := g.repo.GetUnreferencedBlobs(ctx, cutoff, afterID, gcBatch)
+		if err != nil {
+			return report, err
+		}
+		if len(blobs) == 0 {
+			break
+		}
+		for _, blob := range blobs {
+			afterID = blob.ID
+			if opts.Limit > 0 && len(report.Collected) >= opts.Limit {
+				break
+			}
+
+			if !opts.DryRun {
+				deleted, err := g.repo.DeleteUnreferencedBlob(ctx, blob.ID, cutoff, func(blob model.Blob) error {
+					return g.deleteObjects(ctx, blob)
+				})
+				if err != nil {
+					report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", blob.Key, err))
+					continue
+				}
+				// referenced again since it was listed
+				if !deleted {
+					continue
+				}
+			}
+			report.Collected = append(report.Collected, blob)
+			report.Bytes += blob.Size
+			if blob.RefCount != 0 {
+				report.Drifted++
+			}
+		}
+	}
+	return report, nil
+}
+
+// deleteObjects removes a blob and the variants the photo processor may
+// have rendered next to it.
+func (g *blobGCImpl) deleteObjects(ctx context.Context, blob model.Blob) error {
+	keys := []string{blob.Key}
+	for _, size := range imaging.Sizes {
+		for _, format := range imaging.Formats {
+			keys = append(keys, variantKey(blob.Key, size.Name, format))
+		}
+	}
+	for _, key := range keys {
+		if err := g.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	storageMocks "github.com/MidnightHelix/MyGram/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCollectBlobs(t *testing.T) {
+	blobs := []model.Blob{
+		{ID: 1, Key: "blobs/aa/aa.png", Size: 100},
+		{ID: 2, Key: "blobs/bb/bb.jpg", Size: 50, RefCount: 1},
+	}
+
+	t.Run("dry run deletes nothing", func(t *testing.T) {
+		repoMock := mocks.NewBlobQuery(t)
+		repoMock.On("GetUnreferencedBlobs", context.Background(), mock.AnythingOfType("time.Time"), uint64(0), gcBatch).Return(blobs, nil)
+		repoMock.On("GetUnreferencedBlobs", context.Background(), mock.AnythingOfType("time.Time"), uint64(2), gcBatch).Return([]model.Blob{}, nil)
+		gc := blobGCImpl{repo: repoMock, store: storageMocks.NewStorage(t)}
+
+		report, err := gc.Collect(context.Background(), GCOptions{DryRun: true})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(report.Collected))
+		assert.Equal(t, int64(150), report.Bytes)
+		assert.Equal(t, 1, report.Drifted)
+	})
+
+	t.Run("success deletes blobs and variants", func(t *testing.T) {
+		var cutoff time.Time
+		repoMock := mocks.NewBlobQuery(t)
+		repoMock.On("GetUnreferencedBlobs", context.Background(), mock.AnythingOfType("time.Time"), uint64(0), gcBatch).
+			Run(func(args mock.Arguments) { cutoff = args.Get(1).(time.Time) }).
+			Return(blobs[:1], nil)
+		repoMock.On("GetUnreferencedBlobs", context.Background(), mock.AnythingOfType("time.Time"), uint64(1), gcBatch).Return([]model.Blob{}, nil)
+		repoMock.On("DeleteUnreferencedBlob", context.Background(), uint64(1), mock.AnythingOfType("time.Time"), mock.Anything).
+			Return(func(ctx context.Context, id uint64, cutoff time.Time, deleteObjects func(model.Blob) error) (bool, error) {
+				return true, deleteObjects(blobs[0])
+			})
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Delete", context.Background(), "blobs/aa/aa.png").Return(nil).Once()
+		storeMock.On("Delete", context.Background(), "blobs/aa/aa_thumb.jpg").Return(nil).Once()
+		storeMock.On("Delete", context.Background(), mock.AnythingOfType("string")).Return(nil).Times(5)
+		gc := blobGCImpl{repo: repoMock, store: storeMock}
+
+		report, err := gc.Collect(context.Background(), GCOptions{Grace: time.Hour})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(report.Collected))
+		assert.WithinDuration(t, time.Now().Add(-time.Hour), cutoff, time.Minute)
+	})
+
+	t.Run("error deleting is reported and skipped", func(t *testing.T) {
+		repoMock := mocks.NewBlobQuery(t)
+		repoMock.On("GetUnreferencedBlobs", context.Background(), mock.AnythingOfType("time.Time"), uint64(0), gcBatch).Return(blobs, nil)
+		repoMock.On("GetUnreferencedBlobs", context.Background(), mock.AnythingOfType("time.Time"), uint64(2), gcBatch).Return([]model.Blob{}, nil)
+		repoMock.On("DeleteUnreferencedBlob", context.Background(), uint64(1), mock.AnythingOfType("time.Time"), mock.Anything).Return(false, errors.New("some error"))
+		// referenced again after it was listed
+		repoMock.On("DeleteUnreferencedBlob", context.Background(), uint64(2), mock.AnythingOfType("time.Time"), mock.Anything).Return(false, nil)
+		gc := blobGCImpl{repo: repoMock, store: storageMocks.NewStorage(t)}
+
+		report, err := gc.Collect(context.Background(), GCOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(report.Collected))
+		assert.Equal(t, 1, len(report.Errors))
+	})
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+type BlockService interface {
+	Block(ctx context.Context, userID uint64, targetID uint64) (model.Block, error)
+	Unblock(ctx context.Context, userID uint64, targetID uint64) error
+	GetBlocks(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Block, error)
+
+	Mute(ctx context.Context, userID uint64, targetID uint64) (model.Mute, error)
+	Unmute(ctx context.Context, userID uint64, targetID uint64) error
+	GetMutes(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Mute, error)
+}
+
+type blockServiceImpl struct {
+	repo     repository.BlockQuery
+	userRepo repository.UserQuery
+}
+
+func NewBlockService(repo repository.BlockQuery, userRepo repository.UserQuery) BlockService {
+	return &blockServiceImpl{repo: repo, userRepo: userRepo}
+}
+
+func (u *blockServiceImpl) Block(ctx context.Context, userID uint64, targetID uint64) (model.Block, error) {
+	if err := u.checkTarget(ctx, userID, targetID); err != nil {
+		return model.Block{}, err
+	}
+
+	res, err := u.repo.CreateBlock(ctx, model.Block{BlockerID: userID, BlockedID: targetID})
+	if err != nil {
+		return model.Block{}, err
+	}
+	return res, nil
+}
+
+func (u *blockServiceImpl) Unblock(ctx context.Context, userID uint64, targetID uint64) error {
+	return u.repo.DeleteBlock(ctx, userID, targetID)
+}
+
+func (u *blockServiceImpl) GetBlocks(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Block, error) {
+	return u.repo.GetBlocks(ctx, userID, cursor, normalizeLimit(limit))
+}
+
+func (u *blockServiceImpl) Mute(ctx context.Context, userID uint64, targetID uint64) (model.Mute, error) {
+	if err := u.checkTarget(ctx, userID, targetID); err != nil {
+		return model.Mute{}, err
+	}
+
+	res, err := u.repo.CreateMute(ctx, model.Mute{MuterID: userID, MutedID: targetID})
+	if err != nil {
+		return model.Mute{}, err
+	}
+	return res, nil
+}
+
+func (u *blockServiceImpl) Unmute(ctx context.Context, userID uint64, targetID uint64) error {
+	return u.repo.DeleteMute(ctx, userID, targetID)
+}
+
+func (u *blockServiceImpl) GetMutes(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Mute, error) {
+	return u.repo.GetMutes(ctx, userID, cursor, normalizeLimit(limit))
+}
+
+func (u *blockServiceImpl) checkTarget(ctx context.Context, userID uint64, targetID uint64) error {
+	if userID == targetID {
+		return ErrBlockSelf
+	}
+	target, err := u.userRepo.GetUsersByID(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	if target.ID == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
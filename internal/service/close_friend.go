@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+// CloseFriendService keeps each user's close friends list, the people who
+// see their close friends photos. The list is private to its owner.
+type CloseFriendService interface {
+	AddCloseFriend(ctx context.Context, userID uint64, friendID uint64) (model.CloseFriend, error)
+	RemoveCloseFriend(ctx context.Context, userID uint64, friendID uint64) error
+	GetCloseFriends(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.CloseFriend, error)
+}
+
+type closeFriendServiceImpl struct {
+	repo     repository.CloseFriendQuery
+	userRepo repository.UserQuery
+}
+
+func NewCloseFriendService(repo repository.CloseFriendQuery, userRepo repository.UserQuery) CloseFriendService {
+	return &closeFriendServiceImpl{repo: repo, userRepo: userRepo}
+}
+
+func (u *closeFriendServiceImpl) AddCloseFriend(ctx context.Context, userID uint64, friendID uint64) (model.CloseFriend, error) {
+	if userID == friendID {
+		return model.CloseFriend{}, ErrCloseFriendSelf
+	}
+	friend, err := u.userRepo.GetUsersByID(ctx, friendID)
+	if err != nil {
+		return model.CloseFriend{}, err
+	}
+	if friend.ID == 0 {
+		return model.CloseFriend{}, ErrUserNotFound
+	}
+	return u.repo.CreateCloseFriend(ctx, model.CloseFriend{UserID: userID, FriendID: friendID})
+}
+
+func (u *closeFriendServiceImpl) RemoveCloseFriend(ctx context.Context, userID uint64, friendID uint64) error {
+	return u.repo.DeleteCloseFriend(ctx, userID, friendID)
+}
+
+func (u *closeFriendServiceImpl) GetCloseFriends(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.CloseFriend, error) {
+	return u.repo.GetCloseFriends(ctx, userID, cursor, normalizeLimit(limit))
+}
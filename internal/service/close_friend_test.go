@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCloseFriend(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		repoMock := mocks.NewCloseFriendQuery(t)
+		repoMock.On("CreateCloseFriend", context.Background(), model.CloseFriend{UserID: 1, FriendID: 2}).
+			Return(model.CloseFriend{ID: 3, UserID: 1, FriendID: 2}, nil)
+		svc := closeFriendServiceImpl{repo: repoMock, userRepo: userMock}
+
+		res, err := svc.AddCloseFriend(context.Background(), 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(3), res.ID)
+	})
+
+	t.Run("error self", func(t *testing.T) {
+		svc := closeFriendServiceImpl{repo: mocks.NewCloseFriendQuery(t), userRepo: mocks.NewUserQuery(t)}
+
+		_, err := svc.AddCloseFriend(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrCloseFriendSelf)
+	})
+
+	t.Run("error user not found", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{}, nil)
+		svc := closeFriendServiceImpl{repo: mocks.NewCloseFriendQuery(t), userRepo: userMock}
+
+		_, err := svc.AddCloseFriend(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
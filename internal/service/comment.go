@@ -2,33 +2,100 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
 
 	"github.com/MidnightHelix/MyGram/internal/model"
 	"github.com/MidnightHelix/MyGram/internal/repository"
 )
 
+// CommentConfig tunes how comments may be changed.
+type CommentConfig struct {
+	// EditWindow is how long after posting a comment may still be edited,
+	// 0 allows edits forever.
+	EditWindow time.Duration
+}
+
+var DefaultCommentConfig = CommentConfig{}
+
+// CommentConfigFromEnv starts from DefaultCommentConfig and overrides it
+// with COMMENT_EDIT_WINDOW when it is set.
+func CommentConfigFromEnv() (CommentConfig, error) {
+	cfg := DefaultCommentConfig
+	if v := os.Getenv("COMMENT_EDIT_WINDOW"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return CommentConfig{}, fmt.Errorf("COMMENT_EDIT_WINDOW: %w", err)
+		}
+		cfg.EditWindow = d
+	}
+	if cfg.EditWindow < 0 {
+		return CommentConfig{}, fmt.Errorf("comment: edit window must not be negative")
+	}
+	return cfg, nil
+}
+
 type CommentService interface {
 	GetComments(ctx context.Context, userID uint64) ([]model.Comment, error)
 	GetCommentsById(ctx context.Context, id uint64) (model.Comment, error)
 	PostComment(ctx context.Context, comment model.Comment, userID uint64) (model.Comment, error)
 
-	EditComment(ctx context.Context, comment model.Comment, id uint64) (model.Comment, error)
+	// EditComment replaces the message of a comment by editorID, keeping
+	// the one it had as a revision. Once the EditWindow has passed the
+	// comment can no longer be edited.
+	EditComment(ctx context.Context, comment model.Comment, id uint64, editorID uint64) (model.Comment, error)
+	// GetCommentRevisions lists the earlier messages of a comment newest
+	// first. Only its author and moderators may see them, anyone else is
+	// told the comment does not exist.
+	GetCommentRevisions(ctx context.Context, viewerID uint64, moderator bool, id uint64, cursor uint64, limit int) ([]model.Revision, error)
 	DeleteComment(ctx context.Context, id uint64) error
 }
 
 type commentServiceImpl struct {
-	repo repository.CommentQuery
+	repo      repository.CommentQuery
+	photoRepo repository.PhotoQuery
+	userRepo  repository.UserQuery
+	blockRepo repository.BlockQuery
+	likeRepo  repository.LikeQuery
+	saveRepo  repository.SaveQuery
+	mentions  MentionService
+	cfg       CommentConfig
 }
 
-func NewCommentService(repo repository.CommentQuery) CommentService {
-	return &commentServiceImpl{repo: repo}
+func NewCommentService(repo repository.CommentQuery, photoRepo repository.PhotoQuery, userRepo repository.UserQuery, blockRepo repository.BlockQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery, mentions MentionService, cfg CommentConfig) CommentService {
+	return &commentServiceImpl{repo: repo, photoRepo: photoRepo, userRepo: userRepo, blockRepo: blockRepo, likeRepo: likeRepo, saveRepo: saveRepo, mentions: mentions, cfg: cfg}
 }
 
+// GetComments lists the comments of userID with their photos marked like
+// any other photo listing.
 func (u *commentServiceImpl) GetComments(ctx context.Context, userID uint64) ([]model.Comment, error) {
 	comments, err := u.repo.GetComments(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
+
+	photos := []model.Photo{}
+	for _, comment := range comments {
+		if comment.Photo != nil {
+			photos = append(photos, *comment.Photo)
+		}
+	}
+	if err := markLiked(ctx, u.likeRepo, userID, photos); err != nil {
+		return nil, err
+	}
+	if err := markSaved(ctx, u.saveRepo, userID, photos); err != nil {
+		return nil, err
+	}
+	i := 0
+	for _, comment := range comments {
+		if comment.Photo != nil {
+			comment.Photo.LikedByMe = photos[i].LikedByMe
+			comment.Photo.SavedByMe = photos[i].SavedByMe
+			i++
+		}
+	}
 	return comments, err
 }
 
@@ -41,6 +108,11 @@ func (u *commentServiceImpl) GetCommentsById(ctx context.Context, id uint64) (mo
 }
 
 func (u *commentServiceImpl) PostComment(ctx context.Context, comment model.Comment, userID uint64) (model.Comment, error) {
+	// only a photo the commenter may see can be commented on
+	photo, err := visiblePhoto(ctx, u.photoRepo, u.userRepo, u.blockRepo, userID, comment.PhotoID)
+	if err != nil {
+		return model.Comment{}, err
+	}
 
 	user := model.Comment{
 		Message: comment.Message,
@@ -53,15 +125,66 @@ func (u *commentServiceImpl) PostComment(ctx context.Context, comment model.Comm
 	if err != nil {
 		return model.Comment{}, err
 	}
+
+	mentions, err := u.mentions.SyncMentions(ctx, commentMentionSource(res, photo), res.Message)
+	if err != nil {
+		log.Printf("mentions of comment %d not saved: %v", res.ID, err)
+	}
+	res.Mentions = mentions
 	return res, err
 }
 
-func (u *commentServiceImpl) EditComment(ctx context.Context, comment model.Comment, id uint64) (model.Comment, error) {
-	res, err := u.repo.EditComment(ctx, comment, id)
+func (u *commentServiceImpl) EditComment(ctx context.Context, comment model.Comment, id uint64, editorID uint64) (model.Comment, error) {
+	existing, err := u.repo.GetCommentsByID(ctx, id)
 	if err != nil {
 		return model.Comment{}, err
 	}
-	return res, err
+	if existing.ID == 0 {
+		return model.Comment{}, ErrCommentNotFound
+	}
+	if u.cfg.EditWindow > 0 && time.Since(existing.CreatedAt) > u.cfg.EditWindow {
+		return model.Comment{}, ErrCommentEditWindow
+	}
+	photo, err := u.photoRepo.GetPhotosByID(ctx, existing.PhotoID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	res, err := u.repo.EditComment(ctx, comment, id, editorID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	existing.Message = res.Message
+	if res.EditedAt != nil {
+		existing.EditedAt = res.EditedAt
+	}
+	existing.Mentions, err = u.mentions.SyncMentions(ctx, commentMentionSource(existing, photo), comment.Message)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	return existing, nil
+}
+
+func (u *commentServiceImpl) GetCommentRevisions(ctx context.Context, viewerID uint64, moderator bool, id uint64, cursor uint64, limit int) ([]model.Revision, error) {
+	comment, err := u.repo.GetCommentsByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ID == 0 || (comment.UserID != viewerID && !moderator) {
+		return nil, ErrCommentNotFound
+	}
+	return u.repo.GetCommentRevisions(ctx, id, cursor, normalizeLimit(limit))
+}
+
+func commentMentionSource(comment model.Comment, photo model.Photo) model.MentionSource {
+	return model.MentionSource{
+		Type:      model.MentionSourceComment,
+		ID:        comment.ID,
+		AuthorID:  comment.UserID,
+		PhotoID:   photo.ID,
+		CommentID: &comment.ID,
+	}
 }
 
 func (u *commentServiceImpl) DeleteComment(ctx context.Context, id uint64) error {
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	serviceMocks "github.com/MidnightHelix/MyGram/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetComments(t *testing.T) {
+	repoMock := mocks.NewCommentQuery(t)
+	repoMock.On("GetComments", context.Background(), uint64(1)).Return([]model.Comment{
+		{ID: 3, PhotoID: 7, Photo: &model.Photo{ID: 7, LikeCount: 2}},
+		{ID: 4, PhotoID: 8, Photo: &model.Photo{ID: 8}},
+	}, nil)
+	likeMock := mocks.NewLikeQuery(t)
+	likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{7, 8}).Return([]uint64{7}, nil)
+	saveMock := mocks.NewSaveQuery(t)
+	saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{7, 8}).Return([]uint64{8}, nil)
+	svc := commentServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock}
+
+	res, err := svc.GetComments(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.True(t, res[0].Photo.LikedByMe)
+	assert.False(t, res[0].Photo.SavedByMe)
+	assert.False(t, res[1].Photo.LikedByMe)
+	assert.True(t, res[1].Photo.SavedByMe)
+}
+
+func TestPostComment(t *testing.T) {
+	t.Run("error photo not found", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{}, nil)
+		svc := commentServiceImpl{repo: mocks.NewCommentQuery(t), photoRepo: photoMock, blockRepo: mocks.NewBlockQuery(t)}
+
+		_, err := svc.PostComment(context.Background(), model.Comment{PhotoID: 7, Message: "hi"}, 1)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+
+	t.Run("error commenter is blocked by photo owner", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, Visibility: model.PhotoVisibilityPublic}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		svc := commentServiceImpl{repo: mocks.NewCommentQuery(t), photoRepo: photoMock, userRepo: userMock, blockRepo: mocks.NewBlockQuery(t)}
+
+		_, err := svc.PostComment(context.Background(), model.Comment{PhotoID: 7, Message: "hi"}, 1)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+
+	t.Run("success post comment", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+		repoMock := mocks.NewCommentQuery(t)
+		repoMock.On("CreateComment", context.Background(), model.Comment{PhotoID: 7, Message: "hi @ann", UserID: 1}).
+			Return(model.Comment{ID: 3, PhotoID: 7, Message: "hi @ann", UserID: 1}, nil)
+		commentID := uint64(3)
+		mentionMock := serviceMocks.NewMentionService(t)
+		mentionMock.On("SyncMentions", context.Background(), model.MentionSource{Type: model.MentionSourceComment, ID: 3, AuthorID: 1, PhotoID: 7, CommentID: &commentID}, "hi @ann").
+			Return([]model.Mention{{UserID: 5, Offset: 3, Length: 4}}, nil)
+		svc := commentServiceImpl{repo: repoMock, photoRepo: photoMock, mentions: mentionMock}
+
+		res, err := svc.PostComment(context.Background(), model.Comment{PhotoID: 7, Message: "hi @ann"}, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(3), res.ID)
+		assert.Equal(t, []model.Mention{{UserID: 5, Offset: 3, Length: 4}}, res.Mentions)
+	})
+
+	t.Run("error private photo of someone else", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, Visibility: model.PhotoVisibilityPrivate}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+		svc := commentServiceImpl{repo: mocks.NewCommentQuery(t), photoRepo: photoMock}
+
+		_, err := svc.PostComment(context.Background(), model.Comment{PhotoID: 7, Message: "hi"}, 1)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
+
+func TestEditComment(t *testing.T) {
+	t.Run("error edit window has passed", func(t *testing.T) {
+		repoMock := mocks.NewCommentQuery(t)
+		repoMock.On("GetCommentsByID", context.Background(), uint64(3)).
+			Return(model.Comment{ID: 3, PhotoID: 7, Message: "hi", UserID: 1, CreatedAt: time.Now().Add(-20 * time.Minute)}, nil)
+		svc := commentServiceImpl{repo: repoMock, photoRepo: mocks.NewPhotoQuery(t), cfg: CommentConfig{EditWindow: 15 * time.Minute}}
+
+		_, err := svc.EditComment(context.Background(), model.Comment{Message: "hi @ann"}, 3, 1)
+		assert.Equal(t, ErrCommentEditWindow, err)
+	})
+
+	t.Run("success edit comment", func(t *testing.T) {
+		editedAt := time.Now()
+		repoMock := mocks.NewCommentQuery(t)
+		repoMock.On("GetCommentsByID", context.Background(), uint64(3)).
+			Return(model.Comment{ID: 3, PhotoID: 7, Message: "hi", UserID: 1, CreatedAt: time.Now().Add(-10 * time.Minute)}, nil)
+		repoMock.On("EditComment", context.Background(), model.Comment{Message: "hi @ann"}, uint64(3), uint64(1)).
+			Return(model.Comment{Message: "hi @ann", EditedAt: &editedAt}, nil)
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+		commentID := uint64(3)
+		mentionMock := serviceMocks.NewMentionService(t)
+		mentionMock.On("SyncMentions", context.Background(), model.MentionSource{Type: model.MentionSourceComment, ID: 3, AuthorID: 1, PhotoID: 7, CommentID: &commentID}, "hi @ann").
+			Return([]model.Mention{{UserID: 5, Offset: 3, Length: 4}}, nil)
+		svc := commentServiceImpl{repo: repoMock, photoRepo: photoMock, mentions: mentionMock, cfg: CommentConfig{EditWindow: 15 * time.Minute}}
+
+		res, err := svc.EditComment(context.Background(), model.Comment{Message: "hi @ann"}, 3, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(3), res.ID)
+		assert.Equal(t, "hi @ann", res.Message)
+		assert.Equal(t, &editedAt, res.EditedAt)
+		assert.Equal(t, 1, len(res.Mentions))
+	})
+}
+
+func TestGetCommentRevisions(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		viewerID  uint64
+		moderator bool
+		err       error
+	}{
+		{desc: "author", viewerID: 1},
+		{desc: "moderator", viewerID: 9, moderator: true},
+		{desc: "error someone else", viewerID: 9, err: ErrCommentNotFound},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			repoMock := mocks.NewCommentQuery(t)
+			repoMock.On("GetCommentsByID", context.Background(), uint64(3)).Return(model.Comment{ID: 3, UserID: 1}, nil)
+			if tC.err == nil {
+				repoMock.On("GetCommentRevisions", context.Background(), uint64(3), uint64(0), 20).
+					Return([]model.Revision{{ID: 4, SourceType: model.RevisionSourceComment, SourceID: 3, EditorID: 1, Text: "hi"}}, nil)
+			}
+			svc := commentServiceImpl{repo: repoMock}
+
+			res, err := svc.GetCommentRevisions(context.Background(), tC.viewerID, tC.moderator, 3, 0, 0)
+			assert.Equal(t, tC.err, err)
+			if tC.err == nil {
+				assert.Equal(t, "hi", res[0].Text)
+			}
+		})
+	}
+}
+
+func TestCommentConfigFromEnv(t *testing.T) {
+	t.Setenv("COMMENT_EDIT_WINDOW", "")
+	cfg, err := CommentConfigFromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultCommentConfig, cfg)
+
+	t.Setenv("COMMENT_EDIT_WINDOW", "15m")
+	cfg, err = CommentConfigFromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, 15*time.Minute, cfg.EditWindow)
+
+	t.Setenv("COMMENT_EDIT_WINDOW", "-1m")
+	_, err = CommentConfigFromEnv()
+	assert.NotNil(t, err)
+}
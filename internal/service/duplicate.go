@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+const (
+	// DuplicateDistance is the PHash distance up to which an upload is
+	// reported as a repost of the uploader's own photo. dHash must agree
+	// within duplicateDHashDistance as well, to keep false positives down.
+	DuplicateDistance      = 7
+	duplicateDHashDistance = 10
+
+	// duplicateCandidates bounds how many band matches are compared.
+	duplicateCandidates = 200
+)
+
+// NearDuplicate is a photo that looks like another, with the number of bits
+// their hashes differ in.
+type NearDuplicate struct {
+	Photo         model.Photo
+	PHashDistance int
+	DHashDistance int
+}
+
+// findNearDuplicates returns the photos within maxDistance of hashes,
+// closest first. A zero userID searches across users.
+func findNearDuplicates(ctx context.Context, repo repository.PhotoQuery, hashes imaging.Hashes, userID uint64, excludeID uint64, maxDistance int) ([]NearDuplicate, error) {
+	probes := [][]int32{}
+	for _, values := range imaging.BandProbes(hashes.PHash, maxDistance) {
+		band := []int32{}
+		for _, v := range values {
+			band = append(band, int32(v))
+		}
+		probes = append(probes, band)
+	}
+
+	candidates, err := repo.FindByHashBands(ctx, probes, userID, excludeID, duplicateCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []NearDuplicate{}
+	for _, photo := range candidates {
+		if photo.PHash == nil || photo.DHash == nil {
+			continue
+		}
+		dup := NearDuplicate{
+			Photo:         photo,
+			PHashDistance: imaging.Distance(hashes.PHash, uint64(*photo.PHash)),
+			DHashDistance: imaging.Distance(hashes.DHash, uint64(*photo.DHash)),
+		}
+		if dup.PHashDistance <= maxDistance {
+			res = append(res, dup)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].PHashDistance < res[j].PHashDistance })
+	return res, nil
+}
+
+// photoHashes returns the hashes stored on photo, ok is false for photos
+// uploaded before hashing.
+func photoHashes(photo model.Photo) (imaging.Hashes, bool) {
+	if photo.PHash == nil || photo.DHash == nil {
+		return imaging.Hashes{}, false
+	}
+	return imaging.Hashes{PHash: uint64(*photo.PHash), DHash: uint64(*photo.DHash)}, true
+}
+
+// setPhotoHashes stores hashes on photo along with its band index rows.
+func setPhotoHashes(photo *model.Photo, hashes imaging.Hashes) {
+	phash, dhash := int64(hashes.PHash), int64(hashes.DHash)
+	photo.PHash, photo.DHash = &phash, &dhash
+	photo.HashBands = nil
+	for band, value := range imaging.Bands(hashes.PHash) {
+		photo.HashBands = append(photo.HashBands, model.PhotoHashBand{Band: int16(band), Value: int32(value)})
+	}
+}
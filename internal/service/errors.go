@@ -0,0 +1,53 @@
+package service
+
+import "errors"
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrFollowSelf            = errors.New("you cannot follow yourself")
+	ErrPrivateAccount        = errors.New("this account is private")
+	ErrFollowRequestNotFound = errors.New("follow request not found")
+	ErrBlockSelf             = errors.New("you cannot block or mute yourself")
+	ErrPhotoNotFound         = errors.New("photo not found")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrAccountBanned         = errors.New("your account has been banned")
+	ErrAccountSuspended      = errors.New("your account is suspended")
+	ErrPasswordResetRequired = errors.New("a password reset is required before logging in")
+	ErrInvalidResetToken     = errors.New("invalid or already used password reset token")
+	ErrInvalidDuration       = errors.New("suspension duration must be positive")
+	ErrUserNotDeleted        = errors.New("user is not deleted")
+	ErrInvalidUsername       = errors.New("username may only contain letters, digits, '_' and '.'")
+	ErrUsernameReserved      = errors.New("username is reserved")
+	ErrUsernameTaken         = errors.New("username is already taken")
+	ErrEmailTaken            = errors.New("email is already registered")
+	ErrUsernameCooldown      = errors.New("username was changed too recently")
+	ErrPhotoTooLarge         = errors.New("photo is larger than 10 MB")
+	ErrPhotoTooManyPixels    = errors.New("photo is larger than 40 megapixels")
+	ErrUnsupportedMediaType  = errors.New("only jpeg, png, gif and webp images are accepted")
+	ErrProcessingQueueFull   = errors.New("photo processing queue is full")
+	ErrInvalidTransform      = errors.New("invalid width, height, fit, format or quality")
+	ErrInvalidSignature      = errors.New("invalid media signature")
+	ErrMediaNotFound         = errors.New("media not found")
+	ErrInvalidDistance       = errors.New("distance must be between 0 and 11 bits")
+	ErrTagNotFound           = errors.New("tag not found")
+	ErrAlbumNotFound         = errors.New("album not found")
+	ErrPhotoNotInAlbum       = errors.New("photo is not in this album")
+	ErrPhotoNotOwned         = errors.New("only your own photos can be added to an album")
+	ErrAlbumPermission       = errors.New("you are not allowed to change this in the album")
+	ErrInvalidAlbumOrder     = errors.New("the new order must list every photo of the album once")
+	ErrCollaboratorSelf      = errors.New("you cannot add yourself as a collaborator")
+	ErrCollectionNotFound    = errors.New("collection not found")
+	ErrCloseFriendSelf       = errors.New("you cannot add yourself to your close friends")
+	ErrMediaItemNotFound     = errors.New("media item not found")
+	ErrTooManyMediaItems     = errors.New("a post holds at most 10 images")
+	ErrLastMediaItem         = errors.New("a post needs at least one image")
+	ErrInvalidMediaOrder     = errors.New("the new order must list every image of the post once")
+	ErrStoryNotFound         = errors.New("story not found")
+	ErrHighlightNotFound     = errors.New("highlight not found")
+	ErrStoryNotOwned         = errors.New("only your own stories can be added to a highlight")
+	ErrInvalidHighlightOrder = errors.New("the new order must list every story of the highlight once")
+	ErrInvalidLocation       = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	ErrInvalidRadius         = errors.New("the radius must be at most 50 km")
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrCommentEditWindow     = errors.New("this comment can no longer be edited")
+)
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+// ExploreConfig tunes the explore ranking, see repository.ExploreWeights
+// for how the weights are applied.
+type ExploreConfig struct {
+	Weights repository.ExploreWeights
+	// Window is how old a photo can be and still be ranked.
+	Window time.Duration
+	// Interval is how often scores are recomputed.
+	Interval time.Duration
+	// MaxPerAuthor caps the photos of a single account in the ranking.
+	MaxPerAuthor int
+}
+
+var DefaultExploreConfig = ExploreConfig{
+	Weights:      repository.ExploreWeights{Comments: 2, Likes: 1, Saves: 3, Gravity: 1.5},
+	Window:       7 * 24 * time.Hour,
+	Interval:     10 * time.Minute,
+	MaxPerAuthor: 3,
+}
+
+// ExploreConfigFromEnv starts from DefaultExploreConfig and overrides it
+// with EXPLORE_COMMENT_WEIGHT, EXPLORE_LIKE_WEIGHT, EXPLORE_SAVE_WEIGHT,
+// EXPLORE_GRAVITY, EXPLORE_WINDOW, EXPLORE_INTERVAL and
+// EXPLORE_MAX_PER_AUTHOR when they are set.
+func ExploreConfigFromEnv() (ExploreConfig, error) {
+	cfg := DefaultExploreConfig
+	floats := map[string]*float64{
+		"EXPLORE_COMMENT_WEIGHT": &cfg.Weights.Comments,
+		"EXPLORE_LIKE_WEIGHT":    &cfg.Weights.Likes,
+		"EXPLORE_SAVE_WEIGHT":    &cfg.Weights.Saves,
+		"EXPLORE_GRAVITY":        &cfg.Weights.Gravity,
+	}
+	for name, dst := range floats {
+		if v := os.Getenv(name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return ExploreConfig{}, fmt.Errorf("%s: %w", name, err)
+			}
+			*dst = f
+		}
+	}
+	durations := map[string]*time.Duration{
+		"EXPLORE_WINDOW":   &cfg.Window,
+		"EXPLORE_INTERVAL": &cfg.Interval,
+	}
+	for name, dst := range durations {
+		if v := os.Getenv(name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return ExploreConfig{}, fmt.Errorf("%s: %w", name, err)
+			}
+			*dst = d
+		}
+	}
+	if v := os.Getenv("EXPLORE_MAX_PER_AUTHOR"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return ExploreConfig{}, fmt.Errorf("EXPLORE_MAX_PER_AUTHOR: %w", err)
+		}
+		cfg.MaxPerAuthor = n
+	}
+	if cfg.Interval <= 0 || cfg.Window <= 0 || cfg.MaxPerAuthor <= 0 {
+		return ExploreConfig{}, fmt.Errorf("explore: window, interval and max per author must be positive")
+	}
+	return cfg, nil
+}
+
+// ExploreService ranks recent public photos by engagement decayed over
+// time. Scores are precomputed in the background, so a page only reads
+// them and filters out what the viewer must not see.
+type ExploreService interface {
+	// Start recomputes scores right away and then every Interval until ctx
+	// is done.
+	Start(ctx context.Context)
+	Recompute(ctx context.Context) error
+	GetExplore(ctx context.Context, viewerID uint64, cursor string, limit int) (photos []model.Photo, nextCursor string, err error)
+}
+
+type exploreServiceImpl struct {
+	repo     repository.ExploreQuery
+	likeRepo repository.LikeQuery
+	saveRepo repository.SaveQuery
+	cfg      ExploreConfig
+}
+
+func NewExploreService(repo repository.ExploreQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery, cfg ExploreConfig) ExploreService {
+	return &exploreServiceImpl{repo: repo, likeRepo: likeRepo, saveRepo: saveRepo, cfg: cfg}
+}
+
+type exploreCursor struct {
+	Score float64 `json:"s"`
+	ID    uint64  `json:"i"`
+}
+
+func (u *exploreServiceImpl) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(u.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			if err := u.Recompute(ctx); err != nil {
+				log.Printf("explore: recompute: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (u *exploreServiceImpl) Recompute(ctx context.Context) error {
+	now := time.Now()
+	return u.repo.RecomputeScores(ctx, u.cfg.Weights, now.Add(-u.cfg.Window), now)
+}
+
+// GetExplore pages through the ranking. Scores change on every recompute,
+// a cursor from before one continues below the same score and may skip or
+// repeat a few photos.
+func (u *exploreServiceImpl) GetExplore(ctx context.Context, viewerID uint64, cursor string, limit int) (photos []model.Photo, nextCursor string, err error) {
+	after := exploreCursor{}
+	if err = decodeCursor(cursor, &after); err != nil {
+		return nil, "", err
+	}
+
+	limit = normalizeLimit(limit)
+	scores, err := u.repo.GetExplore(ctx, viewerID, repository.ExplorePage{
+		AfterScore:   after.Score,
+		AfterID:      after.ID,
+		MaxPerAuthor: u.cfg.MaxPerAuthor,
+		Limit:        limit + 1,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// one extra row was fetched to know whether another page exists
+	if len(scores) > limit {
+		scores = scores[:limit]
+		last := scores[limit-1]
+		nextCursor = encodeCursor(exploreCursor{Score: last.Score, ID: last.PhotoID})
+	}
+	photos = []model.Photo{}
+	for _, score := range scores {
+		if score.Photo != nil {
+			photos = append(photos, *score.Photo)
+		}
+	}
+	if err = markLiked(ctx, u.likeRepo, viewerID, photos); err != nil {
+		return nil, "", err
+	}
+	if err = markSaved(ctx, u.saveRepo, viewerID, photos); err != nil {
+		return nil, "", err
+	}
+	return photos, nextCursor, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetExplore(t *testing.T) {
+	t.Run("pages below the cursor", func(t *testing.T) {
+		repoMock := mocks.NewExploreQuery(t)
+		repoMock.On("GetExplore", context.Background(), uint64(1), repository.ExplorePage{AfterScore: 0.75, AfterID: 40, MaxPerAuthor: 3, Limit: 3}).
+			Return([]model.ExploreScore{
+				{PhotoID: 12, Score: 0.7, Photo: &model.Photo{ID: 12}},
+				{PhotoID: 30, Score: 0.5, Photo: &model.Photo{ID: 30}},
+				{PhotoID: 8, Score: 0.5, Photo: &model.Photo{ID: 8}},
+			}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{12, 30}).Return([]uint64{30}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{12, 30}).Return([]uint64{}, nil)
+		svc := exploreServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock, cfg: DefaultExploreConfig}
+
+		res, next, err := svc.GetExplore(context.Background(), 1, encodeCursor(exploreCursor{Score: 0.75, ID: 40}), 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{12, 30}, photoIDs(res))
+		assert.False(t, res[0].LikedByMe)
+		assert.True(t, res[1].LikedByMe)
+		after := exploreCursor{}
+		assert.Nil(t, decodeCursor(next, &after))
+		assert.Equal(t, exploreCursor{Score: 0.5, ID: 30}, after)
+	})
+
+	t.Run("last page has no cursor", func(t *testing.T) {
+		repoMock := mocks.NewExploreQuery(t)
+		repoMock.On("GetExplore", context.Background(), uint64(1), repository.ExplorePage{MaxPerAuthor: 3, Limit: 21}).
+			Return([]model.ExploreScore{{PhotoID: 12, Score: 0.7, Photo: &model.Photo{ID: 12}}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{12}).Return([]uint64{}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{12}).Return([]uint64{}, nil)
+		svc := exploreServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock, cfg: DefaultExploreConfig}
+
+		res, next, err := svc.GetExplore(context.Background(), 1, "", 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{12}, photoIDs(res))
+		assert.Equal(t, "", next)
+	})
+
+	t.Run("error invalid cursor", func(t *testing.T) {
+		svc := exploreServiceImpl{repo: mocks.NewExploreQuery(t), cfg: DefaultExploreConfig}
+
+		_, _, err := svc.GetExplore(context.Background(), 1, "not a cursor", 20)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestRecompute(t *testing.T) {
+	cfg := DefaultExploreConfig
+	cfg.Window = 48 * time.Hour
+	repoMock := mocks.NewExploreQuery(t)
+	repoMock.On("RecomputeScores", context.Background(), cfg.Weights, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			since, now := args.Get(2).(time.Time), args.Get(3).(time.Time)
+			assert.Equal(t, cfg.Window, now.Sub(since))
+		}).
+		Return(nil)
+	svc := exploreServiceImpl{repo: repoMock, cfg: cfg}
+
+	assert.Nil(t, svc.Recompute(context.Background()))
+}
+
+func TestExploreConfigFromEnv(t *testing.T) {
+	t.Setenv("EXPLORE_COMMENT_WEIGHT", "4")
+	t.Setenv("EXPLORE_SAVE_WEIGHT", "5")
+	t.Setenv("EXPLORE_INTERVAL", "1m")
+	cfg, err := ExploreConfigFromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, 4.0, cfg.Weights.Comments)
+	assert.Equal(t, 5.0, cfg.Weights.Saves)
+	assert.Equal(t, DefaultExploreConfig.Weights.Likes, cfg.Weights.Likes)
+	assert.Equal(t, DefaultExploreConfig.Weights.Gravity, cfg.Weights.Gravity)
+	assert.Equal(t, time.Minute, cfg.Interval)
+
+	t.Setenv("EXPLORE_MAX_PER_AUTHOR", "0")
+	_, err = ExploreConfigFromEnv()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+const (
+	// FanoutThreshold is the follower count from which an account's photos
+	// are merged into feeds when they are read instead of being pushed into
+	// every follower's timeline when they are posted.
+	FanoutThreshold = 5000
+
+	// backfillPhotos is how many recent photos of a newly followed account
+	// are pushed into the follower's timeline.
+	backfillPhotos = 50
+)
+
+// FeedService builds home feeds with a hybrid fan-out: photos of regular
+// accounts are pushed into follower timelines on post, photos of accounts
+// with many followers are pulled in when the feed is read.
+type FeedService interface {
+	GetFeed(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error)
+
+	// Distribute pushes a photo into its author's followers' timelines, or
+	// switches the author to fan-out on read past FanoutThreshold.
+	Distribute(ctx context.Context, photo model.Photo) error
+	AddFollow(ctx context.Context, userID uint64, authorID uint64) error
+	RemoveFollow(ctx context.Context, userID uint64, authorID uint64) error
+}
+
+type feedServiceImpl struct {
+	repo       repository.TimelineQuery
+	followRepo repository.FollowQuery
+	likeRepo   repository.LikeQuery
+	saveRepo   repository.SaveQuery
+}
+
+func NewFeedService(repo repository.TimelineQuery, followRepo repository.FollowQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery) FeedService {
+	return &feedServiceImpl{repo: repo, followRepo: followRepo, likeRepo: likeRepo, saveRepo: saveRepo}
+}
+
+func (u *feedServiceImpl) GetFeed(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	limit = normalizeLimit(limit)
+	pushed, err := u.repo.GetTimeline(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	pulled, err := u.repo.GetFanoutOnReadPhotos(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	photos := mergePhotos(limit, pushed, pulled)
+	if err := markLiked(ctx, u.likeRepo, userID, photos); err != nil {
+		return nil, err
+	}
+	if err := markSaved(ctx, u.saveRepo, userID, photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (u *feedServiceImpl) Distribute(ctx context.Context, photo model.Photo) error {
+	followers, err := u.followRepo.CountFollowers(ctx, photo.UserID)
+	if err != nil {
+		return err
+	}
+	if followers >= FanoutThreshold {
+		return u.repo.SetFanoutOnRead(ctx, photo.UserID)
+	}
+	return u.repo.FanOut(ctx, photo)
+}
+
+func (u *feedServiceImpl) AddFollow(ctx context.Context, userID uint64, authorID uint64) error {
+	return u.repo.Backfill(ctx, userID, authorID, backfillPhotos)
+}
+
+func (u *feedServiceImpl) RemoveFollow(ctx context.Context, userID uint64, authorID uint64) error {
+	return u.repo.RemoveAuthor(ctx, userID, authorID)
+}
+
+// mergePhotos merges lists sorted by descending id into one, dropping
+// photos present in both, up to limit photos.
+func mergePhotos(limit int, a []model.Photo, b []model.Photo) []model.Photo {
+	res := []model.Photo{}
+	for len(res) < limit && (len(a) > 0 || len(b) > 0) {
+		var next model.Photo
+		switch {
+		case len(b) == 0 || (len(a) > 0 && a[0].ID >= b[0].ID):
+			next, a = a[0], a[1:]
+		default:
+			next, b = b[0], b[1:]
+		}
+		if len(res) > 0 && res[len(res)-1].ID == next.ID {
+			continue
+		}
+		res = append(res, next)
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func photoIDs(photos []model.Photo) []uint64 {
+	ids := []uint64{}
+	for _, photo := range photos {
+		ids = append(ids, photo.ID)
+	}
+	return ids
+}
+
+func TestGetFeed(t *testing.T) {
+	t.Run("merges pushed and pulled photos newest first", func(t *testing.T) {
+		repoMock := mocks.NewTimelineQuery(t)
+		repoMock.On("GetTimeline", context.Background(), uint64(1), uint64(100), 4).
+			Return([]model.Photo{{ID: 90}, {ID: 70}, {ID: 60}, {ID: 20}}, nil)
+		// 70 was pushed before its author switched to fan-out on read
+		repoMock.On("GetFanoutOnReadPhotos", context.Background(), uint64(1), uint64(100), 4).
+			Return([]model.Photo{{ID: 80}, {ID: 70}, {ID: 10}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{90, 80, 70, 60}).Return([]uint64{70}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{90, 80, 70, 60}).Return([]uint64{60}, nil)
+		svc := feedServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock}
+
+		res, err := svc.GetFeed(context.Background(), 1, 100, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{90, 80, 70, 60}, photoIDs(res))
+		assert.True(t, res[2].LikedByMe)
+		assert.False(t, res[2].SavedByMe)
+		assert.True(t, res[3].SavedByMe)
+	})
+
+	t.Run("default limit", func(t *testing.T) {
+		repoMock := mocks.NewTimelineQuery(t)
+		repoMock.On("GetTimeline", context.Background(), uint64(1), uint64(0), 20).Return([]model.Photo{}, nil)
+		repoMock.On("GetFanoutOnReadPhotos", context.Background(), uint64(1), uint64(0), 20).Return([]model.Photo{{ID: 3}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{3}).Return([]uint64{}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{3}).Return([]uint64{}, nil)
+		svc := feedServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock}
+
+		res, err := svc.GetFeed(context.Background(), 1, 0, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{3}, photoIDs(res))
+	})
+}
+
+func TestDistribute(t *testing.T) {
+	t.Run("pushes to followers", func(t *testing.T) {
+		photo := model.Photo{ID: 5, UserID: 2}
+		followMock := mocks.NewFollowQuery(t)
+		followMock.On("CountFollowers", context.Background(), uint64(2)).Return(int64(FanoutThreshold-1), nil)
+		repoMock := mocks.NewTimelineQuery(t)
+		repoMock.On("FanOut", context.Background(), photo).Return(nil)
+		svc := feedServiceImpl{repo: repoMock, followRepo: followMock}
+
+		assert.Nil(t, svc.Distribute(context.Background(), photo))
+	})
+
+	t.Run("large accounts switch to fan-out on read", func(t *testing.T) {
+		photo := model.Photo{ID: 5, UserID: 2}
+		followMock := mocks.NewFollowQuery(t)
+		followMock.On("CountFollowers", context.Background(), uint64(2)).Return(int64(FanoutThreshold), nil)
+		repoMock := mocks.NewTimelineQuery(t)
+		repoMock.On("SetFanoutOnRead", context.Background(), uint64(2)).Return(nil)
+		svc := feedServiceImpl{repo: repoMock, followRepo: followMock}
+
+		assert.Nil(t, svc.Distribute(context.Background(), photo))
+	})
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+type FollowService interface {
+	Follow(ctx context.Context, followerID uint64, followingID uint64) (model.Follow, error)
+	Unfollow(ctx context.Context, followerID uint64, followingID uint64) error
+	GetFollowers(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error)
+	GetFollowing(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error)
+	GetFollowStatus(ctx context.Context, viewerID uint64, userID uint64) (following bool, followedBy bool, requested bool, err error)
+	CountFollows(ctx context.Context, userID uint64) (followers int64, following int64, err error)
+
+	GetFollowRequests(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Follow, error)
+	AcceptFollowRequest(ctx context.Context, userID uint64, requestID uint64) error
+	RejectFollowRequest(ctx context.Context, userID uint64, requestID uint64) error
+	AcceptAllFollowRequests(ctx context.Context, userID uint64) error
+}
+
+type followServiceImpl struct {
+	repo      repository.FollowQuery
+	userRepo  repository.UserQuery
+	blockRepo repository.BlockQuery
+	feed      FeedService
+}
+
+func NewFollowService(repo repository.FollowQuery, userRepo repository.UserQuery, blockRepo repository.BlockQuery, feed FeedService) FollowService {
+	return &followServiceImpl{repo: repo, userRepo: userRepo, blockRepo: blockRepo, feed: feed}
+}
+
+func (u *followServiceImpl) Follow(ctx context.Context, followerID uint64, followingID uint64) (model.Follow, error) {
+	if followerID == followingID {
+		return model.Follow{}, ErrFollowSelf
+	}
+
+	target, err := u.userRepo.GetUsersByID(ctx, followingID)
+	if err != nil {
+		return model.Follow{}, err
+	}
+	if target.ID == 0 {
+		return model.Follow{}, ErrUserNotFound
+	}
+	blocked, err := u.blockRepo.IsBlocked(ctx, followerID, followingID)
+	if err != nil {
+		return model.Follow{}, err
+	}
+	if blocked {
+		return model.Follow{}, ErrUserNotFound
+	}
+
+	follow := model.Follow{
+		FollowerID:  followerID,
+		FollowingID: followingID,
+		Status:      model.FollowStatusAccepted,
+	}
+	if target.IsPrivate {
+		follow.Status = model.FollowStatusPending
+	}
+
+	// following twice is a no-op, the existing edge (or pending request) is returned
+	res, created, err := u.repo.CreateFollow(ctx, follow)
+	if err != nil {
+		return model.Follow{}, err
+	}
+	if created && res.Status == model.FollowStatusAccepted {
+		u.addToFeed(ctx, followerID, followingID)
+	}
+	return res, nil
+}
+
+func (u *followServiceImpl) Unfollow(ctx context.Context, followerID uint64, followingID uint64) error {
+	if err := u.repo.DeleteFollow(ctx, followerID, followingID); err != nil {
+		return err
+	}
+	// feeds already skip unfollowed accounts, this only frees the entries
+	if err := u.feed.RemoveFollow(ctx, followerID, followingID); err != nil {
+		log.Printf("timeline of user %d not cleaned up: %v", followerID, err)
+	}
+	return nil
+}
+
+func (u *followServiceImpl) GetFollowers(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error) {
+	if err := u.checkGraphVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+	return u.repo.GetFollowers(ctx, viewerID, userID, model.FollowStatusAccepted, cursor, normalizeLimit(limit))
+}
+
+func (u *followServiceImpl) GetFollowing(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Follow, error) {
+	if err := u.checkGraphVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+	return u.repo.GetFollowing(ctx, viewerID, userID, cursor, normalizeLimit(limit))
+}
+
+func (u *followServiceImpl) GetFollowStatus(ctx context.Context, viewerID uint64, userID uint64) (following bool, followedBy bool, requested bool, err error) {
+	outgoing, err := u.repo.GetFollow(ctx, viewerID, userID)
+	if err != nil {
+		return
+	}
+	incoming, err := u.repo.GetFollow(ctx, userID, viewerID)
+	if err != nil {
+		return
+	}
+	following = outgoing.Status == model.FollowStatusAccepted
+	requested = outgoing.Status == model.FollowStatusPending
+	followedBy = incoming.Status == model.FollowStatusAccepted
+	return
+}
+
+func (u *followServiceImpl) CountFollows(ctx context.Context, userID uint64) (followers int64, following int64, err error) {
+	followers, err = u.repo.CountFollowers(ctx, userID)
+	if err != nil {
+		return
+	}
+	following, err = u.repo.CountFollowing(ctx, userID)
+	return
+}
+
+func (u *followServiceImpl) GetFollowRequests(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Follow, error) {
+	return u.repo.GetFollowers(ctx, userID, userID, model.FollowStatusPending, cursor, normalizeLimit(limit))
+}
+
+func (u *followServiceImpl) AcceptFollowRequest(ctx context.Context, userID uint64, requestID uint64) error {
+	request, err := u.getFollowRequest(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.UpdateFollowStatus(ctx, requestID, model.FollowStatusAccepted); err != nil {
+		return err
+	}
+	u.addToFeed(ctx, request.FollowerID, request.FollowingID)
+	return nil
+}
+
+// AcceptAllFollowRequests is run when a private account goes public, its
+// pending requesters become followers like anyone following it from now on.
+func (u *followServiceImpl) AcceptAllFollowRequests(ctx context.Context, userID uint64) error {
+	follows, err := u.repo.AcceptFollowRequests(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, follow := range follows {
+		u.addToFeed(ctx, follow.FollowerID, follow.FollowingID)
+	}
+	return nil
+}
+
+func (u *followServiceImpl) RejectFollowRequest(ctx context.Context, userID uint64, requestID uint64) error {
+	request, err := u.getFollowRequest(ctx, userID, requestID)
+	if err != nil {
+		return err
+	}
+	return u.repo.DeleteFollow(ctx, request.FollowerID, request.FollowingID)
+}
+
+// addToFeed fills the follower's feed with recent photos of the account, a
+// failure only delays them until the account posts again.
+func (u *followServiceImpl) addToFeed(ctx context.Context, followerID uint64, followingID uint64) {
+	if err := u.feed.AddFollow(ctx, followerID, followingID); err != nil {
+		log.Printf("timeline of user %d not backfilled: %v", followerID, err)
+	}
+}
+
+// getFollowRequest loads a pending request addressed to userID.
+func (u *followServiceImpl) getFollowRequest(ctx context.Context, userID uint64, requestID uint64) (model.Follow, error) {
+	request, err := u.repo.GetFollowByID(ctx, requestID)
+	if err != nil {
+		return model.Follow{}, err
+	}
+	if request.ID == 0 || request.FollowingID != userID || request.Status != model.FollowStatusPending {
+		return model.Follow{}, ErrFollowRequestNotFound
+	}
+	return request, nil
+}
+
+// checkGraphVisible hides the follower lists of a private account from
+// everyone except the owner and its accepted followers, and hides them
+// entirely across a block.
+func (u *followServiceImpl) checkGraphVisible(ctx context.Context, viewerID uint64, userID uint64) error {
+	user, err := u.userRepo.GetUsersByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		return ErrUserNotFound
+	}
+	if viewerID == userID {
+		return nil
+	}
+	blocked, err := u.blockRepo.IsBlocked(ctx, viewerID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrUserNotFound
+	}
+	if !user.IsPrivate {
+		return nil
+	}
+	follow, err := u.repo.GetFollow(ctx, viewerID, userID)
+	if err != nil {
+		return err
+	}
+	if follow.Status != model.FollowStatusAccepted {
+		return ErrPrivateAccount
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	serviceMocks "github.com/MidnightHelix/MyGram/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFollow(t *testing.T) {
+	t.Run("error follow self", func(t *testing.T) {
+		svc := followServiceImpl{repo: mocks.NewFollowQuery(t), userRepo: mocks.NewUserQuery(t), blockRepo: mocks.NewBlockQuery(t)}
+
+		_, err := svc.Follow(context.Background(), 1, 1)
+		assert.ErrorIs(t, err, ErrFollowSelf)
+	})
+
+	t.Run("error user not found", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{}, nil)
+		svc := followServiceImpl{repo: mocks.NewFollowQuery(t), userRepo: userMock, blockRepo: mocks.NewBlockQuery(t)}
+
+		_, err := svc.Follow(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("error blocked user", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(true, nil)
+		svc := followServiceImpl{repo: mocks.NewFollowQuery(t), userRepo: userMock, blockRepo: blockMock}
+
+		_, err := svc.Follow(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("already following returns existing follow", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(false, nil)
+		repoMock := mocks.NewFollowQuery(t)
+		existing := model.Follow{ID: 5, FollowerID: 1, FollowingID: 2, Status: model.FollowStatusAccepted}
+		repoMock.On("CreateFollow", context.Background(), mock.AnythingOfType("model.Follow")).Return(existing, false, nil)
+		// the feed was filled by the first follow, AddFollow is not expected again
+		svc := followServiceImpl{repo: repoMock, userRepo: userMock, blockRepo: blockMock, feed: serviceMocks.NewFeedService(t)}
+
+		res, err := svc.Follow(context.Background(), 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, existing, res)
+	})
+
+	testCases := []struct {
+		desc      string
+		isPrivate bool
+		status    string
+	}{
+		{desc: "public account is followed directly", isPrivate: false, status: model.FollowStatusAccepted},
+		{desc: "private account gets a follow request", isPrivate: true, status: model.FollowStatusPending},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			userMock := mocks.NewUserQuery(t)
+			userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2, IsPrivate: tC.isPrivate}, nil)
+			blockMock := mocks.NewBlockQuery(t)
+			blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(false, nil)
+			repoMock := mocks.NewFollowQuery(t)
+			repoMock.On("CreateFollow", context.Background(), mock.AnythingOfType("model.Follow")).
+				Return(func(ctx context.Context, follow model.Follow) (model.Follow, bool, error) {
+					follow.ID = 10
+					return follow, true, nil
+				})
+			feedMock := serviceMocks.NewFeedService(t)
+			if tC.status == model.FollowStatusAccepted {
+				feedMock.On("AddFollow", context.Background(), uint64(1), uint64(2)).Return(nil)
+			}
+			svc := followServiceImpl{repo: repoMock, userRepo: userMock, blockRepo: blockMock, feed: feedMock}
+
+			res, err := svc.Follow(context.Background(), 1, 2)
+			assert.Nil(t, err)
+			assert.Equal(t, tC.status, res.Status)
+		})
+	}
+}
+
+func TestAcceptAllFollowRequests(t *testing.T) {
+	repoMock := mocks.NewFollowQuery(t)
+	repoMock.On("AcceptFollowRequests", context.Background(), uint64(2)).Return([]model.Follow{
+		{ID: 3, FollowerID: 4, FollowingID: 2, Status: model.FollowStatusAccepted},
+		{ID: 5, FollowerID: 6, FollowingID: 2, Status: model.FollowStatusAccepted},
+	}, nil)
+	feedMock := serviceMocks.NewFeedService(t)
+	feedMock.On("AddFollow", context.Background(), uint64(4), uint64(2)).Return(nil)
+	feedMock.On("AddFollow", context.Background(), uint64(6), uint64(2)).Return(nil)
+	svc := followServiceImpl{repo: repoMock, feed: feedMock}
+
+	err := svc.AcceptAllFollowRequests(context.Background(), 2)
+	assert.Nil(t, err)
+}
+
+func TestGetFollowersPrivateAccount(t *testing.T) {
+	t.Run("error not an accepted follower", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2, IsPrivate: true}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(false, nil)
+		repoMock := mocks.NewFollowQuery(t)
+		repoMock.On("GetFollow", context.Background(), uint64(1), uint64(2)).
+			Return(model.Follow{ID: 3, Status: model.FollowStatusPending}, nil)
+		svc := followServiceImpl{repo: repoMock, userRepo: userMock, blockRepo: blockMock}
+
+		_, err := svc.GetFollowers(context.Background(), 1, 2, 0, 0)
+		assert.ErrorIs(t, err, ErrPrivateAccount)
+	})
+
+	t.Run("success owner sees own followers", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2, IsPrivate: true}, nil)
+		repoMock := mocks.NewFollowQuery(t)
+		repoMock.On("GetFollowers", context.Background(), uint64(2), uint64(2), model.FollowStatusAccepted, uint64(0), 20).
+			Return([]model.Follow{{ID: 1}}, nil)
+		svc := followServiceImpl{repo: repoMock, userRepo: userMock, blockRepo: mocks.NewBlockQuery(t)}
+
+		res, err := svc.GetFollowers(context.Background(), 2, 2, 0, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+	})
+}
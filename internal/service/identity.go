@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+)
+
+// usernameChangeCooldown is how long a user has to wait between username
+// changes, so handles cannot be cycled to dodge mentions or blocks.
+const usernameChangeCooldown = 30 * 24 * time.Hour
+
+// reservedUsernames cannot be registered by anyone. They are matched by
+// canonical key so look-alikes such as "Αdmin" are caught as well.
+var reservedUsernames = newReservedSet(
+	"admin", "administrator", "root", "system", "sysadmin", "moderator", "mod", "staff",
+	"support", "help", "helpdesk", "security", "abuse", "noreply", "postmaster", "webmaster",
+	"api", "www", "mail", "static", "media", "cdn", "status", "docs", "swagger",
+	"mygram", "official", "team", "about", "terms", "privacy", "settings", "account",
+	"login", "logout", "register", "signup", "password", "explore", "feed", "search",
+	"users", "photos", "comments", "socialmedias", "tags", "stories", "me", "null", "undefined",
+)
+
+func newReservedSet(names ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[helper.UsernameKey(name)] = struct{}{}
+	}
+	return set
+}
+
+func isReservedUsername(username string) bool {
+	_, ok := reservedUsernames[helper.UsernameKey(username)]
+	return ok
+}
+
+// checkUsername returns why username cannot be used by userID, nil when it
+// is free. userID is 0 for someone who has not signed up yet.
+func (u *userServiceImpl) checkUsername(ctx context.Context, username string, userID uint64) error {
+	if !helper.IsValidUsername(username) {
+		return ErrInvalidUsername
+	}
+	if isReservedUsername(username) {
+		return ErrUsernameReserved
+	}
+	taken, err := u.repo.IsUsernameTaken(ctx, username, userID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrUsernameTaken
+	}
+	return nil
+}
+
+func (u *userServiceImpl) checkEmail(ctx context.Context, email string, userID uint64) error {
+	taken, err := u.repo.IsEmailTaken(ctx, email, userID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// usernameCooldown returns ErrUsernameCooldown with the time of the next
+// allowed change when changedAt is too recent.
+func usernameCooldown(changedAt *time.Time, now time.Time) error {
+	if changedAt == nil {
+		return nil
+	}
+	if next := changedAt.Add(usernameChangeCooldown); next.After(now) {
+		return fmt.Errorf("%w, next change allowed after %s", ErrUsernameCooldown, next.Format(time.RFC3339))
+	}
+	return nil
+}
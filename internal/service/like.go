@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+// LikeService likes and unlikes photos. Both are idempotent, liking twice
+// or unliking a photo that was never liked succeeds without changing the
+// count.
+type LikeService interface {
+	// Like and Unlike return the photo with its like count after the
+	// change.
+	Like(ctx context.Context, userID uint64, photoID uint64) (model.Photo, error)
+	Unlike(ctx context.Context, userID uint64, photoID uint64) (model.Photo, error)
+	GetLikers(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Like, error)
+}
+
+type likeServiceImpl struct {
+	repo      repository.LikeQuery
+	photoRepo repository.PhotoQuery
+	userRepo  repository.UserQuery
+	blockRepo repository.BlockQuery
+}
+
+func NewLikeService(repo repository.LikeQuery, photoRepo repository.PhotoQuery, userRepo repository.UserQuery, blockRepo repository.BlockQuery) LikeService {
+	return &likeServiceImpl{repo: repo, photoRepo: photoRepo, userRepo: userRepo, blockRepo: blockRepo}
+}
+
+func (u *likeServiceImpl) Like(ctx context.Context, userID uint64, photoID uint64) (model.Photo, error) {
+	if _, err := u.visiblePhoto(ctx, userID, photoID); err != nil {
+		return model.Photo{}, err
+	}
+	if _, err := u.repo.CreateLike(ctx, userID, photoID); err != nil {
+		return model.Photo{}, err
+	}
+	return u.likedPhoto(ctx, photoID, true)
+}
+
+// Unlike does not check the photo is still visible, a like can always be
+// taken back.
+func (u *likeServiceImpl) Unlike(ctx context.Context, userID uint64, photoID uint64) (model.Photo, error) {
+	if _, err := u.repo.DeleteLike(ctx, userID, photoID); err != nil {
+		return model.Photo{}, err
+	}
+	return u.likedPhoto(ctx, photoID, false)
+}
+
+func (u *likeServiceImpl) GetLikers(ctx context.Context, viewerID uint64, photoID uint64, cursor uint64, limit int) ([]model.Like, error) {
+	if _, err := u.visiblePhoto(ctx, viewerID, photoID); err != nil {
+		return nil, err
+	}
+	return u.repo.GetLikes(ctx, viewerID, photoID, cursor, normalizeLimit(limit))
+}
+
+func (u *likeServiceImpl) likedPhoto(ctx context.Context, photoID uint64, liked bool) (model.Photo, error) {
+	photo, err := u.photoRepo.GetPhotosByID(ctx, photoID)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	if photo.ID == 0 {
+		return model.Photo{}, ErrPhotoNotFound
+	}
+	photo.LikedByMe = liked
+	return photo, nil
+}
+
+func (u *likeServiceImpl) visiblePhoto(ctx context.Context, viewerID uint64, photoID uint64) (model.Photo, error) {
+	return visiblePhoto(ctx, u.photoRepo, u.userRepo, u.blockRepo, viewerID, photoID)
+}
+
+// visiblePhoto loads a photo the viewer may see, whether they may is decided
+// by the photo repository. A public photo of a private account the viewer
+// does not follow is reported as such, any other photo they may not see
+// looks like it does not exist.
+func visiblePhoto(ctx context.Context, photoRepo repository.PhotoQuery, userRepo repository.UserQuery, blockRepo repository.BlockQuery, viewerID uint64, photoID uint64) (model.Photo, error) {
+	photo, err := photoRepo.GetPhotosByID(ctx, photoID)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	if photo.ID == 0 {
+		return model.Photo{}, ErrPhotoNotFound
+	}
+	visible, err := photoRepo.IsPhotoVisible(ctx, viewerID, photoID)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	if visible {
+		return photo, nil
+	}
+	if photo.Visibility != model.PhotoVisibilityPublic {
+		return model.Photo{}, ErrPhotoNotFound
+	}
+
+	author, err := userRepo.GetUsersByID(ctx, photo.UserID)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	if !author.IsPrivate || checkAccountStatus(author) != nil {
+		return model.Photo{}, ErrPhotoNotFound
+	}
+	blocked, err := blockRepo.IsBlocked(ctx, viewerID, photo.UserID)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	if blocked {
+		return model.Photo{}, ErrPhotoNotFound
+	}
+	return model.Photo{}, ErrPrivateAccount
+}
+
+// markSaved sets SavedByMe on the photos the viewer has saved with a single
+// lookup for the whole list.
+func markSaved(ctx context.Context, repo repository.SaveQuery, viewerID uint64, photos []model.Photo) error {
+	ids := make([]uint64, 0, len(photos))
+	for _, photo := range photos {
+		ids = append(ids, photo.ID)
+	}
+	saved, err := repo.GetSavedPhotoIDs(ctx, viewerID, ids)
+	if err != nil {
+		return err
+	}
+	set := make(map[uint64]bool, len(saved))
+	for _, id := range saved {
+		set[id] = true
+	}
+	for i := range photos {
+		photos[i].SavedByMe = set[photos[i].ID]
+	}
+	return nil
+}
+
+// markLiked sets LikedByMe on the photos the viewer has liked with a single
+// lookup for the whole list.
+func markLiked(ctx context.Context, repo repository.LikeQuery, viewerID uint64, photos []model.Photo) error {
+	ids := make([]uint64, 0, len(photos))
+	for _, photo := range photos {
+		ids = append(ids, photo.ID)
+	}
+	liked, err := repo.GetLikedPhotoIDs(ctx, viewerID, ids)
+	if err != nil {
+		return err
+	}
+	set := make(map[uint64]bool, len(liked))
+	for _, id := range liked {
+		set[id] = true
+	}
+	for i := range photos {
+		photos[i].LikedByMe = set[photos[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLike(t *testing.T) {
+	t.Run("success on a visible photo", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, LikeCount: 4, Visibility: model.PhotoVisibilityPublic}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("CreateLike", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+		svc := likeServiceImpl{repo: likeMock, photoRepo: photoMock}
+
+		res, err := svc.Like(context.Background(), 1, 7)
+		assert.Nil(t, err)
+		assert.True(t, res.LikedByMe)
+		assert.Equal(t, int64(4), res.LikeCount)
+	})
+
+	t.Run("error blocked", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, Visibility: model.PhotoVisibilityPublic}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2, IsPrivate: true}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(true, nil)
+		svc := likeServiceImpl{repo: mocks.NewLikeQuery(t), photoRepo: photoMock, userRepo: userMock, blockRepo: blockMock}
+
+		_, err := svc.Like(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+
+	t.Run("error private account not followed", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, Visibility: model.PhotoVisibilityPublic}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2, IsPrivate: true}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(false, nil)
+		svc := likeServiceImpl{repo: mocks.NewLikeQuery(t), photoRepo: photoMock, userRepo: userMock, blockRepo: blockMock}
+
+		_, err := svc.Like(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrPrivateAccount)
+	})
+
+	t.Run("error restricted photo looks missing", func(t *testing.T) {
+		// a followers-only or close friends photo does not say why it is hidden
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, Visibility: model.PhotoVisibilityCloseFriends}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+		svc := likeServiceImpl{repo: mocks.NewLikeQuery(t), photoRepo: photoMock}
+
+		_, err := svc.Like(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+
+	t.Run("error photo not found", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{}, nil)
+		svc := likeServiceImpl{repo: mocks.NewLikeQuery(t), photoRepo: photoMock}
+
+		_, err := svc.Like(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
+
+func TestUnlike(t *testing.T) {
+	// unliking a photo that was not liked is not an error
+	likeMock := mocks.NewLikeQuery(t)
+	likeMock.On("DeleteLike", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+	photoMock := mocks.NewPhotoQuery(t)
+	photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, LikeCount: 3}, nil)
+	svc := likeServiceImpl{repo: likeMock, photoRepo: photoMock}
+
+	res, err := svc.Unlike(context.Background(), 1, 7)
+	assert.Nil(t, err)
+	assert.False(t, res.LikedByMe)
+	assert.Equal(t, int64(3), res.LikeCount)
+}
+
+func TestGetLikers(t *testing.T) {
+	photoMock := mocks.NewPhotoQuery(t)
+	photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 1}, nil)
+	photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+	likeMock := mocks.NewLikeQuery(t)
+	likeMock.On("GetLikes", context.Background(), uint64(1), uint64(7), uint64(0), 20).Return([]model.Like{{ID: 4, UserID: 3}}, nil)
+	svc := likeServiceImpl{repo: likeMock, photoRepo: photoMock}
+
+	res, err := svc.GetLikers(context.Background(), 1, 7, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res))
+}
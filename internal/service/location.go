@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+)
+
+// locationDecimals are the decimals of a coordinate kept at each precision,
+// about 11 m, 1.1 km and 11 km.
+var locationDecimals = map[string]int{
+	model.LocationPrecisionExact:       4,
+	model.LocationPrecisionApproximate: 2,
+	model.LocationPrecisionCity:        1,
+}
+
+func validLocation(latitude float64, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// roundLocation checks a location given by the author and rounds it to
+// precision, approximate when none is given. Rounding happens before the
+// location is stored so the author's exact position never is.
+func roundLocation(latitude *float64, longitude *float64, precision string) (*float64, *float64, error) {
+	if latitude == nil && longitude == nil {
+		return nil, nil, nil
+	}
+	if latitude == nil || longitude == nil || !validLocation(*latitude, *longitude) {
+		return nil, nil, ErrInvalidLocation
+	}
+	if precision == "" {
+		precision = model.LocationPrecisionApproximate
+	}
+	decimals, ok := locationDecimals[precision]
+	if !ok {
+		return nil, nil, ErrInvalidLocation
+	}
+	lat := helper.RoundCoordinate(*latitude, decimals)
+	lng := helper.RoundCoordinate(*longitude, decimals)
+	return &lat, &lng, nil
+}
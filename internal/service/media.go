@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/media"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+	"golang.org/x/sync/singleflight"
+)
+
+// MediaBaseURL is where GET /media is mounted, signed urls point below it.
+const MediaBaseURL = "/api/v1/media"
+
+// Media is a rendered image ready to be served.
+type Media struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+}
+
+type MediaService interface {
+	// Render returns key transformed by opts, signature and expires must be
+	// the ones SignURL put on the url and expires must not have passed.
+	Render(ctx context.Context, key string, opts imaging.Options, expires time.Time, signature string) (Media, error)
+	// SignURL signs renditions of the images of photos the viewer may see,
+	// any other key is reported as not found.
+	SignURL(ctx context.Context, viewerID uint64, key string, opts imaging.Options) (string, error)
+}
+
+// MediaCache stores rendered images by name, implemented by media.DiskCache.
+type MediaCache interface {
+	Get(name string) ([]byte, bool)
+	Put(name string, data []byte) error
+}
+
+type mediaServiceImpl struct {
+	store     storage.Storage
+	signer    *media.Signer
+	cache     MediaCache
+	photoRepo repository.PhotoQuery
+	group     singleflight.Group
+}
+
+func NewMediaService(store storage.Storage, signer *media.Signer, cache MediaCache, photoRepo repository.PhotoQuery) MediaService {
+	return &mediaServiceImpl{store: store, signer: signer, cache: cache, photoRepo: photoRepo}
+}
+
+func (u *mediaServiceImpl) Render(ctx context.Context, key string, opts imaging.Options, expires time.Time, signature string) (Media, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return Media{}, ErrInvalidTransform
+	}
+	if !u.signer.Verify(key, opts, expires, signature) {
+		return Media{}, ErrInvalidSignature
+	}
+
+	name := renditionName(key, opts)
+	result := Media{ContentType: "image/" + opts.Format, ETag: `"` + name + `"`}
+	if data, ok := u.cache.Get(name); ok {
+		result.Data = data
+		return result, nil
+	}
+
+	// concurrent requests for the same rendition share one render
+	data, err, _ := u.group.Do(name, func() (any, error) {
+		data, err := u.render(ctx, key, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.cache.Put(name, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	})
+	if err != nil {
+		return Media{}, err
+	}
+	result.Data = data.([]byte)
+	return result, nil
+}
+
+func (u *mediaServiceImpl) SignURL(ctx context.Context, viewerID uint64, key string, opts imaging.Options) (string, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return "", ErrInvalidTransform
+	}
+	visible, err := u.photoRepo.IsObjectVisible(ctx, viewerID, key)
+	if err != nil {
+		return "", err
+	}
+	if !visible {
+		return "", ErrMediaNotFound
+	}
+	return u.signer.URL(MediaBaseURL, key, opts), nil
+}
+
+func (u *mediaServiceImpl) render(ctx context.Context, key string, opts imaging.Options) ([]byte, error) {
+	r, err := u.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxPhotoSize))
+	if err != nil {
+		return nil, err
+	}
+	src, err := imaging.Decode(data)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return imaging.Encode(imaging.Transform(src, opts), opts)
+}
+
+// renditionName identifies a rendition, it doubles as the cache file name
+// and the ETag since stored objects never change under the same key.
+func renditionName(key string, opts imaging.Options) string {
+	sum := sha256.Sum256([]byte(key + "?" + media.Query(opts).Encode()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/media"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+	storageMocks "github.com/MidnightHelix/MyGram/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSignURL(t *testing.T) {
+	signer, _ := media.NewSigner([]byte("secret"))
+
+	t.Run("error photo not visible", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("IsObjectVisible", context.Background(), uint64(1), "photos/2/a.png").Return(false, nil)
+		svc := &mediaServiceImpl{signer: signer, photoRepo: photoMock}
+
+		_, err := svc.SignURL(context.Background(), 1, "photos/2/a.png", imaging.Options{Width: 100})
+		assert.ErrorIs(t, err, ErrMediaNotFound)
+	})
+
+	t.Run("success signs a visible image", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("IsObjectVisible", context.Background(), uint64(1), "photos/2/a.png").Return(true, nil)
+		svc := &mediaServiceImpl{signer: signer, photoRepo: photoMock}
+
+		url, err := svc.SignURL(context.Background(), 1, "photos/2/a.png", imaging.Options{Width: 100})
+		assert.Nil(t, err)
+		assert.Contains(t, url, MediaBaseURL+"/photos/2/a.png?")
+	})
+}
+
+func TestRenderMedia(t *testing.T) {
+	signer, _ := media.NewSigner([]byte("secret"))
+	opts := imaging.Options{Width: 15, Format: imaging.FormatPNG}
+	normalized, _ := opts.Normalize()
+	expires := time.Now().Add(media.URLLifetime)
+	sig := signer.Sign("photos/1/a.png", normalized, expires)
+
+	t.Run("error invalid signature", func(t *testing.T) {
+		cache, _ := media.NewDiskCache(t.TempDir(), 1<<20)
+		svc := &mediaServiceImpl{store: storageMocks.NewStorage(t), signer: signer, cache: cache}
+
+		_, err := svc.Render(context.Background(), "photos/1/a.png", imaging.Options{Width: 2000}, expires, sig)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("error expired signature", func(t *testing.T) {
+		cache, _ := media.NewDiskCache(t.TempDir(), 1<<20)
+		svc := &mediaServiceImpl{store: storageMocks.NewStorage(t), signer: signer, cache: cache}
+		expired := time.Now().Add(-time.Minute)
+
+		_, err := svc.Render(context.Background(), "photos/1/a.png", opts, expired, signer.Sign("photos/1/a.png", normalized, expired))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("error invalid options", func(t *testing.T) {
+		cache, _ := media.NewDiskCache(t.TempDir(), 1<<20)
+		svc := &mediaServiceImpl{store: storageMocks.NewStorage(t), signer: signer, cache: cache}
+
+		_, err := svc.Render(context.Background(), "photos/1/a.png", imaging.Options{Fit: "stretch"}, expires, sig)
+		assert.ErrorIs(t, err, ErrInvalidTransform)
+	})
+
+	t.Run("error not found", func(t *testing.T) {
+		cache, _ := media.NewDiskCache(t.TempDir(), 1<<20)
+		store := storageMocks.NewStorage(t)
+		store.On("Get", mock.Anything, "photos/1/a.png").Return(nil, storage.ErrNotFound)
+		svc := &mediaServiceImpl{store: store, signer: signer, cache: cache}
+
+		_, err := svc.Render(context.Background(), "photos/1/a.png", opts, expires, sig)
+		assert.ErrorIs(t, err, ErrMediaNotFound)
+	})
+
+	t.Run("success renders once then serves the cache", func(t *testing.T) {
+		cache, _ := media.NewDiskCache(t.TempDir(), 1<<20)
+		store := storageMocks.NewStorage(t)
+		store.On("Get", mock.Anything, "photos/1/a.png").Return(io.NopCloser(bytes.NewReader(testPNG(t))), nil).Once()
+		svc := &mediaServiceImpl{store: store, signer: signer, cache: cache}
+
+		res, err := svc.Render(context.Background(), "photos/1/a.png", opts, expires, sig)
+		assert.Nil(t, err)
+		assert.Equal(t, "image/png", res.ContentType)
+		img, _, err := image.Decode(bytes.NewReader(res.Data))
+		assert.Nil(t, err)
+		assert.Equal(t, 15, img.Bounds().Dx())
+		assert.Equal(t, 10, img.Bounds().Dy())
+
+		cached, err := svc.Render(context.Background(), "photos/1/a.png", opts, expires, sig)
+		assert.Nil(t, err)
+		assert.Equal(t, res, cached)
+	})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+)
+
+// MentionService resolves @mentions when a caption or comment is written
+// and notifies the users they name.
+type MentionService interface {
+	// SyncMentions replaces the mentions of source with the ones in text
+	// and returns them. Users mentioned for the first time are notified
+	// when they can see the photo. Names that do not resolve, or resolve to
+	// someone blocked from or by the author, stay plain text.
+	SyncMentions(ctx context.Context, source model.MentionSource, text string) ([]model.Mention, error)
+}
+
+type mentionServiceImpl struct {
+	repo             repository.MentionQuery
+	notificationRepo repository.NotificationQuery
+	userRepo         repository.UserQuery
+	photoRepo        repository.PhotoQuery
+	blockRepo        repository.BlockQuery
+}
+
+func NewMentionService(repo repository.MentionQuery, notificationRepo repository.NotificationQuery, userRepo repository.UserQuery, photoRepo repository.PhotoQuery, blockRepo repository.BlockQuery) MentionService {
+	return &mentionServiceImpl{repo: repo, notificationRepo: notificationRepo, userRepo: userRepo, photoRepo: photoRepo, blockRepo: blockRepo}
+}
+
+func (u *mentionServiceImpl) SyncMentions(ctx context.Context, source model.MentionSource, text string) ([]model.Mention, error) {
+	resolved := map[string]uint64{}
+	mentions := []model.Mention{}
+	for _, token := range helper.ParseMentions(text) {
+		key := helper.UsernameKey(token.Username)
+		userID, ok := resolved[key]
+		if !ok {
+			var err error
+			if userID, err = u.resolve(ctx, source.AuthorID, token.Username); err != nil {
+				return nil, err
+			}
+			resolved[key] = userID
+		}
+		if userID == 0 {
+			continue
+		}
+		mentions = append(mentions, model.Mention{UserID: userID, Offset: token.Offset, Length: token.Length})
+	}
+
+	previous, err := u.repo.ReplaceMentions(ctx, source.Type, source.ID, mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	notified := map[uint64]bool{source.AuthorID: true}
+	for _, id := range previous {
+		notified[id] = true
+	}
+	notifications := []model.Notification{}
+	for _, mention := range mentions {
+		if notified[mention.UserID] {
+			continue
+		}
+		notified[mention.UserID] = true
+		visible, err := u.photoRepo.IsPhotoVisible(ctx, mention.UserID, source.PhotoID)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+		notifications = append(notifications, model.Notification{
+			UserID:    mention.UserID,
+			ActorID:   source.AuthorID,
+			Type:      model.NotificationTypeMention,
+			PhotoID:   source.PhotoID,
+			CommentID: source.CommentID,
+		})
+	}
+	if err := u.notificationRepo.CreateNotifications(ctx, notifications); err != nil {
+		return nil, err
+	}
+	return mentions, nil
+}
+
+// resolve returns the id of the user a username names, or 0 when there is
+// no such active user or a block stands between them and the author.
+func (u *mentionServiceImpl) resolve(ctx context.Context, authorID uint64, username string) (uint64, error) {
+	user, err := u.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+	if user.ID == 0 || user.DeletedAt.Valid || checkAccountStatus(user) != nil {
+		return 0, nil
+	}
+	if user.ID == authorID {
+		return user.ID, nil
+	}
+	blocked, err := u.blockRepo.IsBlocked(ctx, authorID, user.ID)
+	if err != nil {
+		return 0, err
+	}
+	if blocked {
+		return 0, nil
+	}
+	return user.ID, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMentions(t *testing.T) {
+	source := model.MentionSource{Type: model.MentionSourcePhoto, ID: 7, AuthorID: 1, PhotoID: 7}
+
+	t.Run("success resolves each name once and notifies", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByUsername", context.Background(), "ann").Return(model.User{ID: 5}, nil).Once()
+		userMock.On("FindByUsername", context.Background(), "nobody").Return(model.User{}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(5)).Return(false, nil)
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(5), uint64(7)).Return(true, nil)
+		repoMock := mocks.NewMentionQuery(t)
+		mentions := []model.Mention{{UserID: 5, Offset: 4, Length: 4}, {UserID: 5, Offset: 13, Length: 4}}
+		repoMock.On("ReplaceMentions", context.Background(), model.MentionSourcePhoto, uint64(7), mentions).Return([]uint64{}, nil)
+		notificationMock := mocks.NewNotificationQuery(t)
+		notificationMock.On("CreateNotifications", context.Background(), []model.Notification{
+			{UserID: 5, ActorID: 1, Type: model.NotificationTypeMention, PhotoID: 7},
+		}).Return(nil)
+		svc := mentionServiceImpl{repo: repoMock, notificationRepo: notificationMock, userRepo: userMock, photoRepo: photoMock, blockRepo: blockMock}
+
+		res, err := svc.SyncMentions(context.Background(), source, "hey @ann and @ann, @nobody")
+		assert.Nil(t, err)
+		assert.Equal(t, mentions, res)
+	})
+
+	t.Run("success blocked user stays plain text", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByUsername", context.Background(), "ann").Return(model.User{ID: 5}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(5)).Return(true, nil)
+		repoMock := mocks.NewMentionQuery(t)
+		repoMock.On("ReplaceMentions", context.Background(), model.MentionSourcePhoto, uint64(7), []model.Mention{}).Return([]uint64{}, nil)
+		notificationMock := mocks.NewNotificationQuery(t)
+		notificationMock.On("CreateNotifications", context.Background(), []model.Notification{}).Return(nil)
+		svc := mentionServiceImpl{repo: repoMock, notificationRepo: notificationMock, userRepo: userMock, blockRepo: blockMock}
+
+		res, err := svc.SyncMentions(context.Background(), source, "hey @ann")
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("success user who cannot see the photo is not notified", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByUsername", context.Background(), "ann").Return(model.User{ID: 5}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(5)).Return(false, nil)
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(5), uint64(7)).Return(false, nil)
+		repoMock := mocks.NewMentionQuery(t)
+		repoMock.On("ReplaceMentions", context.Background(), model.MentionSourcePhoto, uint64(7), []model.Mention{{UserID: 5, Offset: 4, Length: 4}}).Return([]uint64{}, nil)
+		notificationMock := mocks.NewNotificationQuery(t)
+		notificationMock.On("CreateNotifications", context.Background(), []model.Notification{}).Return(nil)
+		svc := mentionServiceImpl{repo: repoMock, notificationRepo: notificationMock, userRepo: userMock, photoRepo: photoMock, blockRepo: blockMock}
+
+		res, err := svc.SyncMentions(context.Background(), source, "hey @ann")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+	})
+
+	t.Run("success edit does not notify again", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByUsername", context.Background(), "ann").Return(model.User{ID: 5}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(5)).Return(false, nil)
+		repoMock := mocks.NewMentionQuery(t)
+		repoMock.On("ReplaceMentions", context.Background(), model.MentionSourcePhoto, uint64(7), []model.Mention{{UserID: 5, Offset: 10, Length: 4}}).Return([]uint64{5}, nil)
+		notificationMock := mocks.NewNotificationQuery(t)
+		notificationMock.On("CreateNotifications", context.Background(), []model.Notification{}).Return(nil)
+		svc := mentionServiceImpl{repo: repoMock, notificationRepo: notificationMock, userRepo: userMock, blockRepo: blockMock}
+
+		_, err := svc.SyncMentions(context.Background(), source, "edited to @ann")
+		assert.Nil(t, err)
+	})
+}
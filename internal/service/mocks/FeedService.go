@@ -0,0 +1,113 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FeedService is an autogenerated mock type for the FeedService type
+type FeedService struct {
+	mock.Mock
+}
+
+// AddFollow provides a mock function with given fields: ctx, userID, authorID
+func (_m *FeedService) AddFollow(ctx context.Context, userID uint64, authorID uint64) error {
+	ret := _m.Called(ctx, userID, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddFollow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, userID, authorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Distribute provides a mock function with given fields: ctx, photo
+func (_m *FeedService) Distribute(ctx context.Context, photo model.Photo) error {
+	ret := _m.Called(ctx, photo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Distribute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Photo) error); ok {
+		r0 = rf(ctx, photo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFeed provides a mock function with given fields: ctx, userID, cursor, limit
+func (_m *FeedService) GetFeed(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	ret := _m.Called(ctx, userID, cursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeed")
+	}
+
+	var r0 []model.Photo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]model.Photo, error)); ok {
+		return rf(ctx, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []model.Photo); ok {
+		r0 = rf(ctx, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Photo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = rf(ctx, userID, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveFollow provides a mock function with given fields: ctx, userID, authorID
+func (_m *FeedService) RemoveFollow(ctx context.Context, userID uint64, authorID uint64) error {
+	ret := _m.Called(ctx, userID, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFollow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, userID, authorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFeedService creates a new instance of FeedService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFeedService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FeedService {
+	mock := &FeedService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
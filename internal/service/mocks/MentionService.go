@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MentionService is an autogenerated mock type for the MentionService type
+type MentionService struct {
+	mock.Mock
+}
+
+// SyncMentions provides a mock function with given fields: ctx, source, text
+func (_m *MentionService) SyncMentions(ctx context.Context, source model.MentionSource, text string) ([]model.Mention, error) {
+	ret := _m.Called(ctx, source, text)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncMentions")
+	}
+
+	var r0 []model.Mention
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.MentionSource, string) ([]model.Mention, error)); ok {
+		return rf(ctx, source, text)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.MentionSource, string) []model.Mention); ok {
+		r0 = rf(ctx, source, text)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Mention)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.MentionSource, string) error); ok {
+		r1 = rf(ctx, source, text)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMentionService creates a new instance of MentionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMentionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MentionService {
+	mock := &MentionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,51 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhotoProcessor is an autogenerated mock type for the PhotoProcessor type
+type PhotoProcessor struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: ctx, photoID
+func (_m *PhotoProcessor) Enqueue(ctx context.Context, photoID uint64) error {
+	ret := _m.Called(ctx, photoID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, photoID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Start provides a mock function with given fields: ctx
+func (_m *PhotoProcessor) Start(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// NewPhotoProcessor creates a new instance of PhotoProcessor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhotoProcessor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhotoProcessor {
+	mock := &PhotoProcessor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,12 +1,14 @@
-// Code generated by mockery v2.42.1. DO NOT EDIT.
+// Code generated by mockery v2.53.3. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
 
-	model "github.com/MidnightHelix/MyGram/internal/model"
+	dto "github.com/MidnightHelix/MyGram/pkg/dto"
 	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/MidnightHelix/MyGram/internal/model"
 )
 
 // UserService is an autogenerated mock type for the UserService type
@@ -14,6 +16,108 @@ type UserService struct {
 	mock.Mock
 }
 
+// ChangeUsername provides a mock function with given fields: ctx, id, username
+func (_m *UserService) ChangeUsername(ctx context.Context, id uint64, username string) (model.User, error) {
+	ret := _m.Called(ctx, id, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeUsername")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) (model.User, error)); ok {
+		return rf(ctx, id, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) model.User); ok {
+		r0 = rf(ctx, id, username)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string) error); ok {
+		r1 = rf(ctx, id, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckUsername provides a mock function with given fields: ctx, username
+func (_m *UserService) CheckUsername(ctx context.Context, username string) (dto.UsernameAvailability, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckUsername")
+	}
+
+	var r0 dto.UsernameAvailability
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dto.UsernameAvailability, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) dto.UsernameAvailability); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(dto.UsernameAvailability)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *UserService) DeleteUser(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EditUser provides a mock function with given fields: ctx, editUser, id
+func (_m *UserService) EditUser(ctx context.Context, editUser model.User, id uint64) (model.User, error) {
+	ret := _m.Called(ctx, editUser, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditUser")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.User, uint64) (model.User, error)); ok {
+		return rf(ctx, editUser, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.User, uint64) model.User); ok {
+		r0 = rf(ctx, editUser, id)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.User, uint64) error); ok {
+		r1 = rf(ctx, editUser, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateUserAccessToken provides a mock function with given fields: ctx, user
 func (_m *UserService) GenerateUserAccessToken(ctx context.Context, user model.User) (string, error) {
 	ret := _m.Called(ctx, user)
@@ -42,34 +146,104 @@ func (_m *UserService) GenerateUserAccessToken(ctx context.Context, user model.U
 	return r0, r1
 }
 
-// GetUsers provides a mock function with given fields: ctx
-func (_m *UserService) GetUsers(ctx context.Context) ([]model.User, error) {
-	ret := _m.Called(ctx)
+// GetProfile provides a mock function with given fields: ctx, viewerID, id
+func (_m *UserService) GetProfile(ctx context.Context, viewerID uint64, id uint64) (model.User, error) {
+	ret := _m.Called(ctx, viewerID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (model.User, error)); ok {
+		return rf(ctx, viewerID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) model.User); ok {
+		r0 = rf(ctx, viewerID, id)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, viewerID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileByUsername provides a mock function with given fields: ctx, viewerID, username
+func (_m *UserService) GetProfileByUsername(ctx context.Context, viewerID uint64, username string) (model.User, bool, error) {
+	ret := _m.Called(ctx, viewerID, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfileByUsername")
+	}
+
+	var r0 model.User
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) (model.User, bool, error)); ok {
+		return rf(ctx, viewerID, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string) model.User); ok {
+		r0 = rf(ctx, viewerID, username)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string) bool); ok {
+		r1 = rf(ctx, viewerID, username)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, string) error); ok {
+		r2 = rf(ctx, viewerID, username)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetUsers provides a mock function with given fields: ctx, viewerID, directory
+func (_m *UserService) GetUsers(ctx context.Context, viewerID uint64, directory dto.UserDirectory) ([]model.User, string, error) {
+	ret := _m.Called(ctx, viewerID, directory)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUsers")
 	}
 
 	var r0 []model.User
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]model.User, error)); ok {
-		return rf(ctx)
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, dto.UserDirectory) ([]model.User, string, error)); ok {
+		return rf(ctx, viewerID, directory)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []model.User); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, dto.UserDirectory) []model.User); ok {
+		r0 = rf(ctx, viewerID, directory)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, dto.UserDirectory) string); ok {
+		r1 = rf(ctx, viewerID, directory)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(string)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, dto.UserDirectory) error); ok {
+		r2 = rf(ctx, viewerID, directory)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetUsersById provides a mock function with given fields: ctx, id
@@ -100,8 +274,64 @@ func (_m *UserService) GetUsersById(ctx context.Context, id uint64) (model.User,
 	return r0, r1
 }
 
+// Login provides a mock function with given fields: ctx, userLogin
+func (_m *UserService) Login(ctx context.Context, userLogin dto.UserLogin) (model.User, error) {
+	ret := _m.Called(ctx, userLogin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UserLogin) (model.User, error)); ok {
+		return rf(ctx, userLogin)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UserLogin) model.User); ok {
+		r0 = rf(ctx, userLogin)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.UserLogin) error); ok {
+		r1 = rf(ctx, userLogin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: ctx, reset
+func (_m *UserService) ResetPassword(ctx context.Context, reset dto.PasswordReset) (model.User, error) {
+	ret := _m.Called(ctx, reset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PasswordReset) (model.User, error)); ok {
+		return rf(ctx, reset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PasswordReset) model.User); ok {
+		r0 = rf(ctx, reset)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PasswordReset) error); ok {
+		r1 = rf(ctx, reset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SignUp provides a mock function with given fields: ctx, userSignUp
-func (_m *UserService) SignUp(ctx context.Context, userSignUp model.UserSignUp) (model.User, error) {
+func (_m *UserService) SignUp(ctx context.Context, userSignUp dto.UserSignUp) (model.User, error) {
 	ret := _m.Called(ctx, userSignUp)
 
 	if len(ret) == 0 {
@@ -110,16 +340,16 @@ func (_m *UserService) SignUp(ctx context.Context, userSignUp model.UserSignUp)
 
 	var r0 model.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.UserSignUp) (model.User, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UserSignUp) (model.User, error)); ok {
 		return rf(ctx, userSignUp)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, model.UserSignUp) model.User); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, dto.UserSignUp) model.User); ok {
 		r0 = rf(ctx, userSignUp)
 	} else {
 		r0 = ret.Get(0).(model.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, model.UserSignUp) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, dto.UserSignUp) error); ok {
 		r1 = rf(ctx, userSignUp)
 	} else {
 		r1 = ret.Error(1)
@@ -128,6 +358,24 @@ func (_m *UserService) SignUp(ctx context.Context, userSignUp model.UserSignUp)
 	return r0, r1
 }
 
+// UpdatePrivacy provides a mock function with given fields: ctx, id, isPrivate
+func (_m *UserService) UpdatePrivacy(ctx context.Context, id uint64, isPrivate bool) error {
+	ret := _m.Called(ctx, id, isPrivate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePrivacy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, bool) error); ok {
+		r0 = rf(ctx, id, isPrivate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserService(t interface {
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+type NotificationService interface {
+	GetNotifications(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Notification, error)
+	MarkRead(ctx context.Context, userID uint64) error
+}
+
+type notificationServiceImpl struct {
+	repo repository.NotificationQuery
+}
+
+func NewNotificationService(repo repository.NotificationQuery) NotificationService {
+	return &notificationServiceImpl{repo: repo}
+}
+
+func (u *notificationServiceImpl) GetNotifications(ctx context.Context, userID uint64, cursor uint64, limit int) ([]model.Notification, error) {
+	return u.repo.GetNotifications(ctx, userID, cursor, normalizeLimit(limit))
+}
+
+func (u *notificationServiceImpl) MarkRead(ctx context.Context, userID uint64) error {
+	return u.repo.MarkNotificationsRead(ctx, userID, time.Now())
+}
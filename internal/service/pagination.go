@@ -0,0 +1,38 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+)
+
+// normalizeLimit clamps a client supplied page size, see dto.Page.
+func normalizeLimit(limit int) int {
+	return dto.Page{Limit: limit}.Size()
+}
+
+// encodeCursor turns a keyset into the opaque string handed to clients.
+func encodeCursor(keyset any) string {
+	b, err := json.Marshal(keyset)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor reads a cursor produced by encodeCursor, an empty cursor
+// leaves keyset untouched.
+func decodeCursor(cursor string, keyset any) error {
+	if cursor == "" {
+		return nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, keyset); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
@@ -1,27 +1,96 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"strings"
 
+	"github.com/MidnightHelix/MyGram/internal/imaging"
 	"github.com/MidnightHelix/MyGram/internal/model"
 	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
 )
 
+// MaxPhotoSize is the largest image accepted by PostPhoto and AddMediaItem.
+const MaxPhotoSize = 10 << 20
+
+// MaxMediaItems is the most images a post holds.
+const MaxMediaItems = 10
+
+// photoTypes are the sniffed content types accepted for upload and the file
+// extension their objects get.
+var photoTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
 type PhotoService interface {
 	GetPhotos(ctx context.Context, userID uint64) ([]model.Photo, error)
 	GetPhotosById(ctx context.Context, id uint64) (model.Photo, error)
-	PostPhoto(ctx context.Context, photo model.Photo, userID uint64) (model.Photo, error)
+	// GetPhoto loads a photo the viewer may see with its author, counts and
+	// viewer flags, and a page of its comments oldest first in Comments
+	// starting after the comment id cursor.
+	GetPhoto(ctx context.Context, viewerID uint64, id uint64, cursor uint64, limit int) (model.Photo, error)
+	// GetUserPhotos is the profile grid of userID, newest first. A private
+	// account's grid is empty to viewers who do not follow it.
+	GetUserPhotos(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Photo, error)
+	// PostPhoto creates a post of the files in order, the first being its
+	// cover. It also returns the uploader's earlier photos that look the
+	// same as the cover, the upload is not rejected because of them.
+	PostPhoto(ctx context.Context, upload dto.PhotoUpload, files []io.Reader, userID uint64) (model.Photo, []model.Photo, error)
 
-	EditPhoto(ctx context.Context, photo model.Photo, id uint64) (model.Photo, error)
+	// EditPhoto replaces the title, caption and visibility of a photo by
+	// editorID, keeping the title and caption it had as a revision.
+	EditPhoto(ctx context.Context, photo model.Photo, id uint64, editorID uint64) (model.Photo, error)
+	// GetPhotoRevisions lists the earlier titles and captions of a photo
+	// newest first. Only its author and moderators may see them, anyone
+	// else is told the photo does not exist.
+	GetPhotoRevisions(ctx context.Context, viewerID uint64, moderator bool, id uint64, cursor uint64, limit int) ([]model.Revision, error)
+	// SetPhotoLocation replaces the location of a photo, leaving out both
+	// the coordinates and the place name removes it.
+	SetPhotoLocation(ctx context.Context, id uint64, location dto.PhotoLocationInput) (model.Photo, error)
 	DeletePhoto(ctx context.Context, id uint64) error
+
+	// AddMediaItem appends an image to a post.
+	AddMediaItem(ctx context.Context, photoID uint64, file io.Reader, altText string) (model.MediaItem, error)
+	EditMediaItem(ctx context.Context, photoID uint64, id uint64, altText string) (model.MediaItem, error)
+	// DeleteMediaItem removes an image from a post, the last one cannot be
+	// removed, the post is deleted instead.
+	DeleteMediaItem(ctx context.Context, photoID uint64, id uint64) error
+	// ReorderMediaItems puts the images of a post in the order of ids and
+	// returns them. A new first image becomes the cover and gets its
+	// variants rendered in the background.
+	ReorderMediaItems(ctx context.Context, photoID uint64, ids []uint64) ([]model.MediaItem, error)
 }
 
 type photoServiceImpl struct {
-	repo repository.PhotoQuery
+	repo        repository.PhotoQuery
+	blobRepo    repository.BlobQuery
+	likeRepo    repository.LikeQuery
+	saveRepo    repository.SaveQuery
+	tagRepo     repository.TagQuery
+	commentRepo repository.CommentQuery
+	userRepo    repository.UserQuery
+	blockRepo   repository.BlockQuery
+	mentions    MentionService
+	store       storage.Storage
+	processor   PhotoProcessor
 }
 
-func NewPhotoService(repo repository.PhotoQuery) PhotoService {
-	return &photoServiceImpl{repo: repo}
+func NewPhotoService(repo repository.PhotoQuery, blobRepo repository.BlobQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery, tagRepo repository.TagQuery, commentRepo repository.CommentQuery, userRepo repository.UserQuery, blockRepo repository.BlockQuery, mentions MentionService, store storage.Storage, processor PhotoProcessor) PhotoService {
+	return &photoServiceImpl{repo: repo, blobRepo: blobRepo, likeRepo: likeRepo, saveRepo: saveRepo, tagRepo: tagRepo, commentRepo: commentRepo, userRepo: userRepo, blockRepo: blockRepo, mentions: mentions, store: store, processor: processor}
 }
 
 func (u *photoServiceImpl) GetPhotos(ctx context.Context, userID uint64) ([]model.Photo, error) {
@@ -29,6 +98,12 @@ func (u *photoServiceImpl) GetPhotos(ctx context.Context, userID uint64) ([]mode
 	if err != nil {
 		return nil, err
 	}
+	if err := markLiked(ctx, u.likeRepo, userID, photos); err != nil {
+		return nil, err
+	}
+	if err := markSaved(ctx, u.saveRepo, userID, photos); err != nil {
+		return nil, err
+	}
 	return photos, err
 }
 
@@ -40,35 +115,483 @@ func (u *photoServiceImpl) GetPhotosById(ctx context.Context, id uint64) (model.
 	return photo, err
 }
 
-func (u *photoServiceImpl) PostPhoto(ctx context.Context, photo model.Photo, userID uint64) (model.Photo, error) {
+// GetPhoto tells a viewer who may not see the photo the same as the likes
+// and comments of it do, see visiblePhoto.
+func (u *photoServiceImpl) GetPhoto(ctx context.Context, viewerID uint64, id uint64, cursor uint64, limit int) (model.Photo, error) {
+	if _, err := visiblePhoto(ctx, u.repo, u.userRepo, u.blockRepo, viewerID, id); err != nil {
+		return model.Photo{}, err
+	}
+	photo, err := u.repo.GetPhoto(ctx, viewerID, id)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	if photo.ID == 0 {
+		return model.Photo{}, ErrPhotoNotFound
+	}
+
+	photos := []model.Photo{photo}
+	if err := markLiked(ctx, u.likeRepo, viewerID, photos); err != nil {
+		return model.Photo{}, err
+	}
+	if err := markSaved(ctx, u.saveRepo, viewerID, photos); err != nil {
+		return model.Photo{}, err
+	}
+	photo = photos[0]
+	photo.Comments, err = u.commentRepo.GetPhotoComments(ctx, viewerID, id, cursor, normalizeLimit(limit))
+	if err != nil {
+		return model.Photo{}, err
+	}
+	return photo, nil
+}
+
+func (u *photoServiceImpl) GetUserPhotos(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.Photo, error) {
+	if _, err := visibleUser(ctx, u.userRepo, u.blockRepo, viewerID, userID); err != nil {
+		return nil, err
+	}
+	photos, err := u.repo.GetUserPhotos(ctx, viewerID, userID, cursor, normalizeLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	if err := markLiked(ctx, u.likeRepo, viewerID, photos); err != nil {
+		return nil, err
+	}
+	if err := markSaved(ctx, u.saveRepo, viewerID, photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// PostPhoto stores the uploaded images and creates the photo pointing at
+// them. The content types are sniffed from the bytes, the client's claim is
+// ignored, and metadata is stripped before anything is stored. The photo is
+// returned processing, the variants of its cover are rendered in the
+// background.
+func (u *photoServiceImpl) PostPhoto(ctx context.Context, upload dto.PhotoUpload, files []io.Reader, userID uint64) (model.Photo, []model.Photo, error) {
+	if len(files) > MaxMediaItems {
+		return model.Photo{}, nil, ErrTooManyMediaItems
+	}
+	latitude, longitude, err := roundLocation(upload.Latitude, upload.Longitude, upload.LocationPrecision)
+	if err != nil {
+		return model.Photo{}, nil, err
+	}
+	media := make([]mediaUpload, 0, len(files))
+	for _, file := range files {
+		m, err := readMedia(file)
+		if err != nil {
+			return model.Photo{}, nil, err
+		}
+		media = append(media, m)
+	}
+	if len(media) == 0 {
+		return model.Photo{}, nil, fmt.Errorf("%w: no image", ErrUnsupportedMediaType)
+	}
+
+	hashes := imaging.Hash(media[0].img)
+	near, err := findNearDuplicates(ctx, u.repo, hashes, userID, 0, DuplicateDistance)
+	if err != nil {
+		return model.Photo{}, nil, err
+	}
+	duplicates := []model.Photo{}
+	for _, dup := range near {
+		if dup.DHashDistance <= duplicateDHashDistance {
+			duplicates = append(duplicates, dup.Photo)
+		}
+	}
+
+	items := make([]model.MediaItem, 0, len(media))
+	for i, m := range media {
+		altText := ""
+		if i < len(upload.AltText) {
+			altText = upload.AltText[i]
+		}
+		item, err := u.storeMedia(ctx, m, altText)
+		if err != nil {
+			u.releaseMedia(ctx, items)
+			return model.Photo{}, nil, err
+		}
+		item.Position = i + 1
+		items = append(items, item)
+	}
 
+	visibility := upload.Visibility
+	if visibility == "" {
+		visibility = model.PhotoVisibilityPublic
+	}
+	cover := items[0]
 	user := model.Photo{
-		Title:   photo.Title,
-		Caption: photo.Caption,
-		Url:     photo.Url,
-		UserID:  userID,
+		Title:       upload.Title,
+		Caption:     upload.Caption,
+		Visibility:  visibility,
+		Url:         cover.Url,
+		ObjectKey:   cover.ObjectKey,
+		ContentType: cover.ContentType,
+		Size:        cover.Size,
+		Status:      model.PhotoStatusProcessing,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		PlaceName:   upload.PlaceName,
+		UserID:      userID,
+		Media:       items,
+	}
+	setPhotoHashes(&user, hashes)
+	if upload.ShareMetadata {
+		user.CameraModel = cameraName(media[0].meta.CameraMake, media[0].meta.CameraModel)
+		user.TakenAt = media[0].meta.TakenAt
 	}
 
 	// store to db
 	res, err := u.repo.CreatePhoto(ctx, user)
+	if err != nil {
+		u.releaseMedia(ctx, items)
+		return model.Photo{}, nil, err
+	}
+	if err := u.tagRepo.SetPhotoTags(ctx, res.ID, helper.ParseHashtags(res.Caption)); err != nil {
+		log.Printf("tags of photo %d not saved: %v", res.ID, err)
+	}
+	mentions, err := u.mentions.SyncMentions(ctx, photoMentionSource(res), res.Caption)
+	if err != nil {
+		log.Printf("mentions of photo %d not saved: %v", res.ID, err)
+	}
+	res.Mentions = mentions
+
+	if err := u.processor.Enqueue(ctx, res.ID); err != nil {
+		log.Printf("photo %d not queued for processing: %v", res.ID, err)
+	}
+	return res, duplicates, nil
+}
+
+func (u *photoServiceImpl) EditPhoto(ctx context.Context, photo model.Photo, id uint64, editorID uint64) (model.Photo, error) {
+	existing, err := u.repo.GetPhotosByID(ctx, id)
+	if err != nil {
+		return model.Photo{}, err
+	}
+
+	res, err := u.repo.EditPhoto(ctx, photo, id, editorID)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	if err := u.tagRepo.SetPhotoTags(ctx, id, helper.ParseHashtags(photo.Caption)); err != nil {
+		return model.Photo{}, err
+	}
+
+	existing.Title = res.Title
+	existing.Caption = res.Caption
+	if res.Visibility != "" {
+		existing.Visibility = res.Visibility
+	}
+	if res.EditedAt != nil {
+		existing.EditedAt = res.EditedAt
+	}
+	existing.Mentions, err = u.mentions.SyncMentions(ctx, photoMentionSource(existing), photo.Caption)
 	if err != nil {
 		return model.Photo{}, err
 	}
-	return res, err
+	return existing, nil
+}
+
+func (u *photoServiceImpl) GetPhotoRevisions(ctx context.Context, viewerID uint64, moderator bool, id uint64, cursor uint64, limit int) ([]model.Revision, error) {
+	photo, err := u.repo.GetPhotosByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if photo.ID == 0 || (photo.UserID != viewerID && !moderator) {
+		return nil, ErrPhotoNotFound
+	}
+	return u.repo.GetPhotoRevisions(ctx, id, cursor, normalizeLimit(limit))
 }
 
-func (u *photoServiceImpl) EditPhoto(ctx context.Context, photo model.Photo, id uint64) (model.Photo, error) {
-	res, err := u.repo.EditPhoto(ctx, photo, id)
+func (u *photoServiceImpl) SetPhotoLocation(ctx context.Context, id uint64, location dto.PhotoLocationInput) (model.Photo, error) {
+	latitude, longitude, err := roundLocation(location.Latitude, location.Longitude, location.Precision)
+	if err != nil {
+		return model.Photo{}, err
+	}
+	photo, err := u.repo.GetPhotosByID(ctx, id)
 	if err != nil {
 		return model.Photo{}, err
 	}
-	return res, err
+	if err := u.repo.SetPhotoLocation(ctx, id, latitude, longitude, location.PlaceName); err != nil {
+		return model.Photo{}, err
+	}
+	photo.Latitude, photo.Longitude, photo.PlaceName = latitude, longitude, location.PlaceName
+	return photo, nil
+}
+
+func photoMentionSource(photo model.Photo) model.MentionSource {
+	return model.MentionSource{
+		Type:     model.MentionSourcePhoto,
+		ID:       photo.ID,
+		AuthorID: photo.UserID,
+		PhotoID:  photo.ID,
+	}
 }
 
+// DeletePhoto soft deletes the photo and releases the blobs of its images.
+// The objects and variants stay until the garbage collector removes
+// unreferenced blobs, other photos may share them and a restore within the
+// grace period still finds them.
 func (u *photoServiceImpl) DeletePhoto(ctx context.Context, id uint64) error {
-	err := u.repo.DeletePhoto(ctx, id)
+	photo, err := u.repo.GetPhotosByID(ctx, id)
 	if err != nil {
 		return err
 	}
+
+	err = u.repo.DeletePhoto(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := u.tagRepo.SetPhotoTags(ctx, id, nil); err != nil {
+		return err
+	}
+
+	for _, item := range photo.Media {
+		if item.ObjectKey == "" {
+			continue
+		}
+		if err := u.blobRepo.ReleaseBlob(ctx, item.ObjectKey); err != nil {
+			return err
+		}
+	}
+	// photos from before uploads existed only have a url and nothing stored
+	if len(photo.Media) == 0 && photo.ObjectKey != "" {
+		return u.blobRepo.ReleaseBlob(ctx, photo.ObjectKey)
+	}
+	return nil
+}
+
+func (u *photoServiceImpl) AddMediaItem(ctx context.Context, photoID uint64, file io.Reader, altText string) (model.MediaItem, error) {
+	m, err := readMedia(file)
+	if err != nil {
+		return model.MediaItem{}, err
+	}
+	item, err := u.storeMedia(ctx, m, altText)
+	if err != nil {
+		return model.MediaItem{}, err
+	}
+	item.PhotoID = photoID
+	res, added, err := u.repo.AddMediaItem(ctx, item, MaxMediaItems)
+	if err != nil || !added {
+		releaseBlob(ctx, u.blobRepo, item.ObjectKey)
+	}
+	if err != nil {
+		return model.MediaItem{}, err
+	}
+	if !added {
+		return model.MediaItem{}, ErrTooManyMediaItems
+	}
+	return res, nil
+}
+
+func (u *photoServiceImpl) EditMediaItem(ctx context.Context, photoID uint64, id uint64, altText string) (model.MediaItem, error) {
+	item, err := u.repo.GetMediaItem(ctx, photoID, id)
+	if err != nil {
+		return model.MediaItem{}, err
+	}
+	if item.ID == 0 {
+		return model.MediaItem{}, ErrMediaItemNotFound
+	}
+	if err := u.repo.EditMediaItem(ctx, id, altText); err != nil {
+		return model.MediaItem{}, err
+	}
+	item.AltText = altText
+	return item, nil
+}
+
+func (u *photoServiceImpl) DeleteMediaItem(ctx context.Context, photoID uint64, id uint64) error {
+	photo, err := u.repo.GetPhotosByID(ctx, photoID)
+	if err != nil {
+		return err
+	}
+	item := model.MediaItem{}
+	for _, m := range photo.Media {
+		if m.ID == id {
+			item = m
+		}
+	}
+	if item.ID == 0 {
+		return ErrMediaItemNotFound
+	}
+	if len(photo.Media) == 1 {
+		return ErrLastMediaItem
+	}
+
+	coverChanged, err := u.repo.DeleteMediaItem(ctx, photoID, id)
+	if err != nil {
+		return err
+	}
+	if item.ObjectKey != "" {
+		releaseBlob(ctx, u.blobRepo, item.ObjectKey)
+	}
+	if coverChanged {
+		u.reprocess(ctx, photoID)
+	}
 	return nil
 }
+
+func (u *photoServiceImpl) ReorderMediaItems(ctx context.Context, photoID uint64, ids []uint64) ([]model.MediaItem, error) {
+	photo, err := u.repo.GetPhotosByID(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if len(photo.Media) != len(ids) {
+		return nil, ErrInvalidMediaOrder
+	}
+	remaining := make(map[uint64]bool, len(photo.Media))
+	for _, item := range photo.Media {
+		remaining[item.ID] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return nil, ErrInvalidMediaOrder
+		}
+		delete(remaining, id)
+	}
+
+	coverChanged, err := u.repo.ReorderMediaItems(ctx, photoID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if coverChanged {
+		u.reprocess(ctx, photoID)
+	}
+	byID := make(map[uint64]model.MediaItem, len(photo.Media))
+	for _, item := range photo.Media {
+		byID[item.ID] = item
+	}
+	items := make([]model.MediaItem, 0, len(ids))
+	for i, id := range ids {
+		item := byID[id]
+		item.Position = i + 1
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// reprocess renders the variants of a new cover. The photo stays ready
+// meanwhile, clients fall back to its url until the variants are there. A
+// ready photo is not swept, so when the queue is full the old variants stay
+// until the cover changes again.
+func (u *photoServiceImpl) reprocess(ctx context.Context, photoID uint64) {
+	if err := u.processor.Enqueue(ctx, photoID); err != nil {
+		log.Printf("photo %d not queued for processing: %v", photoID, err)
+	}
+}
+
+// mediaUpload is an uploaded image that passed the checks, stripped of
+// metadata.
+type mediaUpload struct {
+	data        []byte
+	contentType string
+	ext         string
+	meta        imaging.Metadata
+	img         image.Image
+}
+
+func readMedia(file io.Reader) (mediaUpload, error) {
+	data, err := io.ReadAll(io.LimitReader(file, MaxPhotoSize+1))
+	if err != nil {
+		return mediaUpload{}, err
+	}
+	if len(data) > MaxPhotoSize {
+		return mediaUpload{}, ErrPhotoTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := photoTypes[contentType]
+	if !ok {
+		return mediaUpload{}, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+	data, meta, err := imaging.Sanitize(data, contentType)
+	if err != nil {
+		return mediaUpload{}, decodeError(err)
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return mediaUpload{}, decodeError(err)
+	}
+	return mediaUpload{data: data, contentType: contentType, ext: ext, meta: meta, img: img}, nil
+}
+
+// decodeError tells images with too many pixels apart from files that are
+// not images at all.
+func decodeError(err error) error {
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return ErrPhotoTooManyPixels
+	}
+	return fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+}
+
+// storeMedia stores an uploaded image and returns the item pointing at it.
+func (u *photoServiceImpl) storeMedia(ctx context.Context, m mediaUpload, altText string) (model.MediaItem, error) {
+	key, err := storeBlob(ctx, u.blobRepo, u.store, m.data, m.contentType, m.ext)
+	if err != nil {
+		return model.MediaItem{}, err
+	}
+	bounds := m.img.Bounds()
+	return model.MediaItem{
+		Url:         u.store.URL(key),
+		ObjectKey:   key,
+		ContentType: m.contentType,
+		Size:        int64(len(m.data)),
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		AltText:     altText,
+	}, nil
+}
+
+func (u *photoServiceImpl) releaseMedia(ctx context.Context, items []model.MediaItem) {
+	for _, item := range items {
+		releaseBlob(ctx, u.blobRepo, item.ObjectKey)
+	}
+}
+
+// storeBlob stores data under a key derived from its sha256 and takes a
+// reference to it. Content that is already stored is not uploaded again.
+func storeBlob(ctx context.Context, blobRepo repository.BlobQuery, store storage.Storage, data []byte, contentType string, ext string) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	blob, err := blobRepo.AcquireBlob(ctx, model.Blob{
+		Key:         blobKey(hash, ext),
+		Hash:        &hash,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if blob.Uploaded {
+		return blob.Key, nil
+	}
+
+	// another reference does not mean the object exists, its upload may
+	// still be running or may have failed. The content is equal, so every
+	// holder of a reference that was not uploaded yet stores it itself.
+	if err := store.Put(ctx, blob.Key, bytes.NewReader(data), blob.Size, contentType); err != nil {
+		releaseBlob(ctx, blobRepo, blob.Key)
+		return "", err
+	}
+	if err := blobRepo.MarkBlobUploaded(ctx, blob.Key); err != nil {
+		releaseBlob(ctx, blobRepo, blob.Key)
+		return "", err
+	}
+	return blob.Key, nil
+}
+
+func releaseBlob(ctx context.Context, blobRepo repository.BlobQuery, key string) {
+	if err := blobRepo.ReleaseBlob(ctx, key); err != nil {
+		log.Printf("blob %s not released: %v", key, err)
+	}
+}
+
+// cameraName joins make and model, most vendors already repeat the make in
+// the model ("Canon" / "Canon EOS 5D") so it is only prefixed when missing.
+func cameraName(cameraMake string, cameraModel string) string {
+	if cameraMake == "" || strings.HasPrefix(strings.ToLower(cameraModel), strings.ToLower(cameraMake)) {
+		return cameraModel
+	}
+	return strings.TrimSpace(cameraMake + " " + cameraModel)
+}
+
+// blobKey spreads blobs over directories by the first byte of their hash,
+// e.g. blobs/9f/9f86d0...08.png.
+func blobKey(hash string, ext string) string {
+	return fmt.Sprintf("blobs/%s/%s%s", hash[:2], hash, ext)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+)
+
+// PhotoProcessor renders the variants of uploaded photos in the background
+// so uploads return as soon as the original is stored.
+type PhotoProcessor interface {
+	// Start launches the workers and the sweeper, which queues photos left
+	// processing by a previous run or by a full queue. Both stop when ctx is
+	// done.
+	Start(ctx context.Context)
+	// Enqueue never waits, it fails with ErrProcessingQueueFull when there
+	// is no room.
+	Enqueue(ctx context.Context, photoID uint64) error
+}
+
+// processingSweepInterval is how often photos still processing are queued
+// again, and processingSweepBatch how many are read per query.
+const (
+	processingSweepInterval = time.Minute
+	processingSweepBatch    = 500
+)
+
+type photoProcessorImpl struct {
+	repo    repository.PhotoQuery
+	store   storage.Storage
+	feed    FeedService
+	workers int
+	jobs    chan uint64
+
+	// queued holds the photos waiting in jobs, so the sweeper does not queue
+	// them twice. A photo leaves it when a worker takes it, a cover changed
+	// during the render is queued again.
+	mu     sync.Mutex
+	queued map[uint64]bool
+}
+
+// NewPhotoProcessor hands photos to feed once they are ready, so feeds
+// never show a photo without its variants.
+func NewPhotoProcessor(repo repository.PhotoQuery, store storage.Storage, feed FeedService, workers int) PhotoProcessor {
+	return &photoProcessorImpl{
+		repo:    repo,
+		store:   store,
+		feed:    feed,
+		workers: workers,
+		jobs:    make(chan uint64, workers*64),
+		queued:  map[uint64]bool{},
+	}
+}
+
+func (p *photoProcessorImpl) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		go p.work(ctx)
+	}
+	go func() {
+		ticker := time.NewTicker(processingSweepInterval)
+		defer ticker.Stop()
+		for {
+			p.sweep(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Enqueue never holds up the upload. A new photo that finds the queue full
+// stays processing and is picked up by the sweeper.
+func (p *photoProcessorImpl) Enqueue(ctx context.Context, photoID uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queued[photoID] {
+		return nil
+	}
+	select {
+	case p.jobs <- photoID:
+		p.queued[photoID] = true
+		return nil
+	default:
+		return ErrProcessingQueueFull
+	}
+}
+
+// sweep pages through every photo still processing and queues them until
+// the queue is full, the rest wait for the next sweep.
+func (p *photoProcessorImpl) sweep(ctx context.Context) {
+	var after uint64
+	for {
+		photos, err := p.repo.GetPhotosByStatus(ctx, model.PhotoStatusProcessing, after, processingSweepBatch)
+		if err != nil {
+			log.Printf("photo processor: sweep: %v", err)
+			return
+		}
+		for _, photo := range photos {
+			if err := p.Enqueue(ctx, photo.ID); err != nil {
+				return
+			}
+		}
+		if len(photos) < processingSweepBatch {
+			return
+		}
+		after = photos[len(photos)-1].ID
+	}
+}
+
+func (p *photoProcessorImpl) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-p.jobs:
+			p.mu.Lock()
+			delete(p.queued, id)
+			p.mu.Unlock()
+			if err := p.process(ctx, id); err != nil {
+				log.Printf("photo processor: photo %d: %v", id, err)
+				// a re-render of a ready photo keeps its status and variants
+				if err := p.repo.MarkPhotoFailed(ctx, id); err != nil {
+					log.Printf("photo processor: photo %d: %v", id, err)
+				}
+			}
+		}
+	}
+}
+
+func (p *photoProcessorImpl) process(ctx context.Context, id uint64) error {
+	photo, err := p.repo.GetPhotosByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// deleted before its turn came
+	if photo.ID == 0 {
+		return nil
+	}
+
+	r, err := p.store.Get(ctx, photo.ObjectKey)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+
+	res, err := imaging.Process(data)
+	if err != nil {
+		return err
+	}
+
+	variants := []model.PhotoVariant{}
+	for _, rendition := range res.Renditions {
+		key := variantKey(photo.ObjectKey, rendition.Name, rendition.Format)
+		if err := p.store.Put(ctx, key, bytes.NewReader(rendition.Data), int64(len(rendition.Data)), rendition.ContentType); err != nil {
+			return err
+		}
+		variants = append(variants, model.PhotoVariant{
+			PhotoID:   id,
+			Name:      rendition.Name,
+			Format:    rendition.Format,
+			ObjectKey: key,
+			Url:       p.store.URL(key),
+			Width:     rendition.Width,
+			Height:    rendition.Height,
+			Size:      int64(len(rendition.Data)),
+		})
+	}
+	if err := p.repo.SaveVariants(ctx, id, res.Width, res.Height, variants); err != nil {
+		return err
+	}
+	if err := p.feed.Distribute(ctx, photo); err != nil {
+		log.Printf("photo processor: photo %d not distributed to feeds: %v", id, err)
+	}
+	return nil
+}
+
+// variantKey places renditions next to their original, photos/1/ab12.png
+// becomes photos/1/ab12_thumb.jpg.
+func variantKey(key string, name string, format string) string {
+	ext := ".jpg"
+	if format == imaging.FormatPNG {
+		ext = ".png"
+	}
+	return strings.TrimSuffix(key, path.Ext(key)) + "_" + name + ext
+}
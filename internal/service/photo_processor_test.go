@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnqueue(t *testing.T) {
+	p := &photoProcessorImpl{jobs: make(chan uint64, 1), queued: map[uint64]bool{}}
+
+	assert.Nil(t, p.Enqueue(context.Background(), 1))
+	// already waiting in the queue
+	assert.Nil(t, p.Enqueue(context.Background(), 1))
+	// the queue is full, the upload does not wait for room
+	assert.ErrorIs(t, p.Enqueue(context.Background(), 2), ErrProcessingQueueFull)
+	assert.Equal(t, 1, len(p.jobs))
+}
+
+func TestSweep(t *testing.T) {
+	t.Run("pages through every processing photo", func(t *testing.T) {
+		first := make([]model.Photo, processingSweepBatch)
+		for i := range first {
+			first[i].ID = uint64(i + 1)
+		}
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByStatus", context.Background(), model.PhotoStatusProcessing, uint64(0), processingSweepBatch).Return(first, nil)
+		repoMock.On("GetPhotosByStatus", context.Background(), model.PhotoStatusProcessing, uint64(processingSweepBatch), processingSweepBatch).
+			Return([]model.Photo{{ID: processingSweepBatch + 1}}, nil)
+		p := &photoProcessorImpl{repo: repoMock, jobs: make(chan uint64, processingSweepBatch+1), queued: map[uint64]bool{}}
+
+		p.sweep(context.Background())
+		assert.Equal(t, processingSweepBatch+1, len(p.jobs))
+	})
+
+	t.Run("stops when the queue is full", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByStatus", context.Background(), model.PhotoStatusProcessing, uint64(0), processingSweepBatch).
+			Return([]model.Photo{{ID: 1}, {ID: 2}}, nil)
+		p := &photoProcessorImpl{repo: repoMock, jobs: make(chan uint64, 1), queued: map[uint64]bool{}}
+
+		p.sweep(context.Background())
+		assert.Equal(t, uint64(1), <-p.jobs)
+	})
+}
@@ -0,0 +1,561 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/imaging"
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	serviceMocks "github.com/MidnightHelix/MyGram/internal/service/mocks"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+	storageMocks "github.com/MidnightHelix/MyGram/internal/storage/mocks"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testPNG is a small but complete png image.
+func testPNG(t *testing.T) []byte {
+	buf := bytes.Buffer{}
+	assert.Nil(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 30, 20))))
+	return buf.Bytes()
+}
+
+func TestPostPhoto(t *testing.T) {
+	t.Run("error unsupported content", func(t *testing.T) {
+		svc := photoServiceImpl{repo: mocks.NewPhotoQuery(t), store: storageMocks.NewStorage(t)}
+
+		_, _, err := svc.PostPhoto(context.Background(), dto.PhotoUpload{Title: "t"}, []io.Reader{strings.NewReader("<html>not an image</html>")}, 1)
+		assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+	})
+
+	t.Run("error too large", func(t *testing.T) {
+		svc := photoServiceImpl{repo: mocks.NewPhotoQuery(t), store: storageMocks.NewStorage(t)}
+		data := append(testPNG(t), make([]byte, MaxPhotoSize)...)
+
+		_, _, err := svc.PostPhoto(context.Background(), dto.PhotoUpload{Title: "t"}, []io.Reader{bytes.NewReader(data)}, 1)
+		assert.ErrorIs(t, err, ErrPhotoTooLarge)
+	})
+
+	t.Run("success stores object and references its key", func(t *testing.T) {
+		pngData := testPNG(t)
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "blobs/") && strings.HasSuffix(key, ".png")
+		}), mock.Anything, int64(len(pngData)), "image/png").Return(nil)
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 1
+				return blob, nil
+			})
+		blobMock.On("MarkBlobUploaded", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		storeMock.On("URL", mock.AnythingOfType("string")).Return(func(key string) string { return "/uploads/" + key })
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("FindByHashBands", context.Background(), mock.Anything, uint64(1), uint64(0), duplicateCandidates).Return([]model.Photo{}, nil)
+		repoMock.On("CreatePhoto", context.Background(), mock.AnythingOfType("model.Photo")).
+			Return(func(ctx context.Context, photo model.Photo) (model.Photo, error) {
+				photo.ID = 7
+				return photo, nil
+			})
+		processorMock := serviceMocks.NewPhotoProcessor(t)
+		processorMock.On("Enqueue", context.Background(), uint64(7)).Return(nil)
+		tagMock := mocks.NewTagQuery(t)
+		tagMock.On("SetPhotoTags", context.Background(), uint64(7), []string{"sunset", "beach"}).Return(nil)
+		mentionMock := serviceMocks.NewMentionService(t)
+		mentionMock.On("SyncMentions", context.Background(), model.MentionSource{Type: model.MentionSourcePhoto, ID: 7, AuthorID: 1, PhotoID: 7}, "#Sunset at the #beach").
+			Return([]model.Mention{}, nil)
+		svc := photoServiceImpl{repo: repoMock, blobRepo: blobMock, tagRepo: tagMock, mentions: mentionMock, store: storeMock, processor: processorMock}
+
+		res, _, err := svc.PostPhoto(context.Background(), dto.PhotoUpload{Title: "t", Caption: "#Sunset at the #beach"}, []io.Reader{bytes.NewReader(pngData)}, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, model.PhotoStatusProcessing, res.Status)
+		assert.Equal(t, "image/png", res.ContentType)
+		assert.Equal(t, "/uploads/"+res.ObjectKey, res.Url)
+		assert.NotNil(t, res.PHash)
+		assert.Equal(t, 4, len(res.HashBands))
+		assert.Equal(t, 1, len(res.Media))
+		assert.Equal(t, res.ObjectKey, res.Media[0].ObjectKey)
+	})
+
+	t.Run("success carousel keeps the order and alt texts", func(t *testing.T) {
+		first := testPNG(t)
+		buf := bytes.Buffer{}
+		assert.Nil(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 10, 40))))
+		second := buf.Bytes()
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").Return(nil)
+		storeMock.On("URL", mock.AnythingOfType("string")).Return(func(key string) string { return "/uploads/" + key })
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 1
+				return blob, nil
+			})
+		blobMock.On("MarkBlobUploaded", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("FindByHashBands", context.Background(), mock.Anything, uint64(1), uint64(0), duplicateCandidates).Return([]model.Photo{}, nil)
+		repoMock.On("CreatePhoto", context.Background(), mock.AnythingOfType("model.Photo")).
+			Return(func(ctx context.Context, photo model.Photo) (model.Photo, error) {
+				photo.ID = 7
+				return photo, nil
+			})
+		processorMock := serviceMocks.NewPhotoProcessor(t)
+		processorMock.On("Enqueue", context.Background(), uint64(7)).Return(nil)
+		tagMock := mocks.NewTagQuery(t)
+		tagMock.On("SetPhotoTags", context.Background(), uint64(7), []string{}).Return(nil)
+		mentionMock := serviceMocks.NewMentionService(t)
+		mentionMock.On("SyncMentions", context.Background(), mock.AnythingOfType("model.MentionSource"), "").Return([]model.Mention{}, nil)
+		svc := photoServiceImpl{repo: repoMock, blobRepo: blobMock, tagRepo: tagMock, mentions: mentionMock, store: storeMock, processor: processorMock}
+
+		upload := dto.PhotoUpload{Title: "t", AltText: []string{"wide", "tall"}}
+		res, _, err := svc.PostPhoto(context.Background(), upload, []io.Reader{bytes.NewReader(first), bytes.NewReader(second)}, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res.Media))
+		assert.Equal(t, 1, res.Media[0].Position)
+		assert.Equal(t, "wide", res.Media[0].AltText)
+		assert.Equal(t, 30, res.Media[0].Width)
+		assert.Equal(t, 2, res.Media[1].Position)
+		assert.Equal(t, "tall", res.Media[1].AltText)
+		assert.Equal(t, 40, res.Media[1].Height)
+		assert.Equal(t, res.Media[0].ObjectKey, res.ObjectKey)
+	})
+
+	t.Run("error latitude without longitude", func(t *testing.T) {
+		svc := photoServiceImpl{repo: mocks.NewPhotoQuery(t), store: storageMocks.NewStorage(t)}
+		latitude := -6.2
+
+		_, _, err := svc.PostPhoto(context.Background(), dto.PhotoUpload{Title: "t", Latitude: &latitude}, []io.Reader{bytes.NewReader(testPNG(t))}, 1)
+		assert.ErrorIs(t, err, ErrInvalidLocation)
+	})
+
+	t.Run("error too many images", func(t *testing.T) {
+		svc := photoServiceImpl{repo: mocks.NewPhotoQuery(t), store: storageMocks.NewStorage(t)}
+		files := []io.Reader{}
+		for i := 0; i <= MaxMediaItems; i++ {
+			files = append(files, bytes.NewReader(testPNG(t)))
+		}
+
+		_, _, err := svc.PostPhoto(context.Background(), dto.PhotoUpload{Title: "t"}, files, 1)
+		assert.ErrorIs(t, err, ErrTooManyMediaItems)
+	})
+
+	t.Run("success warns about a repost", func(t *testing.T) {
+		pngData := testPNG(t)
+		img, _, _ := image.Decode(bytes.NewReader(pngData))
+		earlier := model.Photo{ID: 3, UserID: 1}
+		setPhotoHashes(&earlier, imaging.Hash(img))
+		unrelated := model.Photo{ID: 4, UserID: 1}
+		setPhotoHashes(&unrelated, imaging.Hashes{PHash: ^uint64(0), DHash: ^uint64(0)})
+
+		// the same bytes were stored before, they are not uploaded again
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("URL", mock.AnythingOfType("string")).Return("")
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 2
+				blob.Uploaded = true
+				return blob, nil
+			})
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("FindByHashBands", context.Background(), mock.Anything, uint64(1), uint64(0), duplicateCandidates).Return([]model.Photo{unrelated, earlier}, nil)
+		repoMock.On("CreatePhoto", context.Background(), mock.AnythingOfType("model.Photo")).Return(model.Photo{ID: 7, UserID: 1}, nil)
+		processorMock := serviceMocks.NewPhotoProcessor(t)
+		processorMock.On("Enqueue", context.Background(), uint64(7)).Return(nil)
+		tagMock := mocks.NewTagQuery(t)
+		tagMock.On("SetPhotoTags", context.Background(), uint64(7), []string{}).Return(nil)
+		mentionMock := serviceMocks.NewMentionService(t)
+		mentionMock.On("SyncMentions", context.Background(), mock.AnythingOfType("model.MentionSource"), "").Return([]model.Mention{}, nil)
+		svc := photoServiceImpl{repo: repoMock, blobRepo: blobMock, tagRepo: tagMock, mentions: mentionMock, store: storeMock, processor: processorMock}
+
+		_, duplicates, err := svc.PostPhoto(context.Background(), dto.PhotoUpload{Title: "t"}, []io.Reader{bytes.NewReader(pngData)}, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(duplicates))
+		assert.Equal(t, uint64(3), duplicates[0].ID)
+	})
+
+	t.Run("error create releases the blob", func(t *testing.T) {
+		pngData := testPNG(t)
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").Return(nil)
+		storeMock.On("URL", mock.AnythingOfType("string")).Return("")
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 1
+				return blob, nil
+			})
+		blobMock.On("MarkBlobUploaded", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		blobMock.On("ReleaseBlob", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("FindByHashBands", context.Background(), mock.Anything, uint64(1), uint64(0), duplicateCandidates).Return([]model.Photo{}, nil)
+		repoMock.On("CreatePhoto", context.Background(), mock.AnythingOfType("model.Photo")).Return(model.Photo{}, errors.New("some error"))
+		svc := photoServiceImpl{repo: repoMock, blobRepo: blobMock, store: storeMock}
+
+		_, _, err := svc.PostPhoto(context.Background(), dto.PhotoUpload{Title: "t"}, []io.Reader{bytes.NewReader(pngData)}, 1)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestDeletePhoto(t *testing.T) {
+	repoMock := mocks.NewPhotoQuery(t)
+	repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, ObjectKey: "blobs/ab/ab.png"}, nil)
+	repoMock.On("DeletePhoto", context.Background(), uint64(7)).Return(nil)
+	blobMock := mocks.NewBlobQuery(t)
+	blobMock.On("ReleaseBlob", context.Background(), "blobs/ab/ab.png").Return(nil)
+	tagMock := mocks.NewTagQuery(t)
+	tagMock.On("SetPhotoTags", context.Background(), uint64(7), []string(nil)).Return(nil)
+	// nothing is deleted from storage, that is left to the garbage collector
+	svc := photoServiceImpl{repo: repoMock, blobRepo: blobMock, tagRepo: tagMock, store: storageMocks.NewStorage(t)}
+
+	assert.Nil(t, svc.DeletePhoto(context.Background(), 7))
+}
+
+func TestEditPhoto(t *testing.T) {
+	photo := model.Photo{Title: "t", Caption: "now #Summer with @ann, was #winter"}
+	repoMock := mocks.NewPhotoQuery(t)
+	repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+	repoMock.On("EditPhoto", context.Background(), photo, uint64(7), uint64(2)).Return(photo, nil)
+	tagMock := mocks.NewTagQuery(t)
+	tagMock.On("SetPhotoTags", context.Background(), uint64(7), []string{"summer", "winter"}).Return(nil)
+	mentionMock := serviceMocks.NewMentionService(t)
+	mentionMock.On("SyncMentions", context.Background(), model.MentionSource{Type: model.MentionSourcePhoto, ID: 7, AuthorID: 2, PhotoID: 7}, photo.Caption).
+		Return([]model.Mention{{UserID: 5, Offset: 17, Length: 4}}, nil)
+	svc := photoServiceImpl{repo: repoMock, tagRepo: tagMock, mentions: mentionMock}
+
+	res, err := svc.EditPhoto(context.Background(), photo, 7, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), res.Mentions[0].UserID)
+	assert.Equal(t, uint64(7), res.ID)
+	assert.Equal(t, uint64(2), res.UserID)
+	assert.Equal(t, photo.Caption, res.Caption)
+}
+
+func TestGetPhoto(t *testing.T) {
+	t.Run("error photo not visible", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, Visibility: model.PhotoVisibilityPrivate}, nil)
+		repoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+		svc := photoServiceImpl{repo: repoMock}
+
+		_, err := svc.GetPhoto(context.Background(), 1, 7, 0, 0)
+		assert.Equal(t, ErrPhotoNotFound, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+		repoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+		repoMock.On("GetPhoto", context.Background(), uint64(1), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, LikeCount: 3, CommentCount: 1}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{7}).Return([]uint64{7}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{7}).Return([]uint64{}, nil)
+		commentMock := mocks.NewCommentQuery(t)
+		commentMock.On("GetPhotoComments", context.Background(), uint64(1), uint64(7), uint64(0), 20).Return([]model.Comment{{ID: 11, PhotoID: 7, UserID: 3}}, nil)
+		svc := photoServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock, commentRepo: commentMock}
+
+		res, err := svc.GetPhoto(context.Background(), 1, 7, 0, 0)
+		assert.Nil(t, err)
+		assert.True(t, res.LikedByMe)
+		assert.False(t, res.SavedByMe)
+		assert.Equal(t, 1, len(res.Comments))
+	})
+}
+
+func TestGetUserPhotos(t *testing.T) {
+	t.Run("error blocked user", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(true, nil)
+		svc := photoServiceImpl{repo: mocks.NewPhotoQuery(t), userRepo: userMock, blockRepo: blockMock}
+
+		_, err := svc.GetUserPhotos(context.Background(), 1, 2, 0, 0)
+		assert.Equal(t, ErrUserNotFound, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("GetUsersByID", context.Background(), uint64(2)).Return(model.User{ID: 2}, nil)
+		blockMock := mocks.NewBlockQuery(t)
+		blockMock.On("IsBlocked", context.Background(), uint64(1), uint64(2)).Return(false, nil)
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetUserPhotos", context.Background(), uint64(1), uint64(2), uint64(40), 20).Return([]model.Photo{{ID: 39, UserID: 2}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{39}).Return([]uint64{}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{39}).Return([]uint64{39}, nil)
+		svc := photoServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock, userRepo: userMock, blockRepo: blockMock}
+
+		res, err := svc.GetUserPhotos(context.Background(), 1, 2, 40, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.True(t, res[0].SavedByMe)
+	})
+}
+
+func TestGetPhotoRevisions(t *testing.T) {
+	t.Run("error someone else", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+		svc := photoServiceImpl{repo: repoMock}
+
+		_, err := svc.GetPhotoRevisions(context.Background(), 1, false, 7, 0, 0)
+		assert.Equal(t, ErrPhotoNotFound, err)
+	})
+
+	t.Run("success moderator", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+		repoMock.On("GetPhotoRevisions", context.Background(), uint64(7), uint64(9), 20).
+			Return([]model.Revision{{ID: 8, SourceType: model.RevisionSourcePhoto, SourceID: 7, EditorID: 2, Title: "t", Text: "before"}}, nil)
+		svc := photoServiceImpl{repo: repoMock}
+
+		res, err := svc.GetPhotoRevisions(context.Background(), 1, true, 7, 9, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "before", res[0].Text)
+	})
+}
+
+func TestSetPhotoLocation(t *testing.T) {
+	coordinate := func(v float64) *float64 { return &v }
+	testCases := []struct {
+		desc      string
+		location  dto.PhotoLocationInput
+		latitude  *float64
+		longitude *float64
+	}{
+		{
+			desc:      "approximate by default",
+			location:  dto.PhotoLocationInput{Latitude: coordinate(-6.175392), Longitude: coordinate(106.827153), PlaceName: "Monas"},
+			latitude:  coordinate(-6.18),
+			longitude: coordinate(106.83),
+		},
+		{
+			desc:      "exact",
+			location:  dto.PhotoLocationInput{Latitude: coordinate(-6.175392), Longitude: coordinate(106.827153), Precision: model.LocationPrecisionExact},
+			latitude:  coordinate(-6.1754),
+			longitude: coordinate(106.8272),
+		},
+		{
+			desc:      "city",
+			location:  dto.PhotoLocationInput{Latitude: coordinate(-6.175392), Longitude: coordinate(106.827153), Precision: model.LocationPrecisionCity},
+			latitude:  coordinate(-6.2),
+			longitude: coordinate(106.8),
+		},
+		{
+			desc:     "place name only",
+			location: dto.PhotoLocationInput{PlaceName: "Jakarta"},
+		},
+		{
+			desc: "removed",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			repoMock := mocks.NewPhotoQuery(t)
+			repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 1}, nil)
+			repoMock.On("SetPhotoLocation", context.Background(), uint64(7), tC.latitude, tC.longitude, tC.location.PlaceName).Return(nil)
+			svc := photoServiceImpl{repo: repoMock}
+
+			photo, err := svc.SetPhotoLocation(context.Background(), 7, tC.location)
+			assert.Nil(t, err)
+			assert.Equal(t, tC.latitude, photo.Latitude)
+			assert.Equal(t, tC.longitude, photo.Longitude)
+			assert.Equal(t, tC.location.PlaceName, photo.PlaceName)
+		})
+	}
+
+	t.Run("error out of range", func(t *testing.T) {
+		svc := photoServiceImpl{repo: mocks.NewPhotoQuery(t)}
+
+		_, err := svc.SetPhotoLocation(context.Background(), 7, dto.PhotoLocationInput{Latitude: coordinate(95), Longitude: coordinate(0)})
+		assert.ErrorIs(t, err, ErrInvalidLocation)
+	})
+}
+
+func TestAddMediaItem(t *testing.T) {
+	t.Run("error post is full releases the blob", func(t *testing.T) {
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").Return(nil)
+		storeMock.On("URL", mock.AnythingOfType("string")).Return("")
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 1
+				return blob, nil
+			})
+		blobMock.On("MarkBlobUploaded", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		blobMock.On("ReleaseBlob", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("AddMediaItem", context.Background(), mock.MatchedBy(func(item model.MediaItem) bool {
+			return item.PhotoID == 7 && item.AltText == "a dog" && item.Width == 30 && item.Height == 20
+		}), MaxMediaItems).Return(model.MediaItem{}, false, nil)
+		svc := photoServiceImpl{repo: repoMock, blobRepo: blobMock, store: storeMock}
+
+		_, err := svc.AddMediaItem(context.Background(), 7, bytes.NewReader(testPNG(t)), "a dog")
+		assert.ErrorIs(t, err, ErrTooManyMediaItems)
+	})
+}
+
+func TestDeleteMediaItem(t *testing.T) {
+	t.Run("error last item", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).
+			Return(model.Photo{ID: 7, Media: []model.MediaItem{{ID: 1, Position: 1}}}, nil)
+		svc := photoServiceImpl{repo: repoMock}
+
+		assert.ErrorIs(t, svc.DeleteMediaItem(context.Background(), 7, 1), ErrLastMediaItem)
+	})
+
+	t.Run("error not in post", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).
+			Return(model.Photo{ID: 7, Media: []model.MediaItem{{ID: 1, Position: 1}, {ID: 2, Position: 2}}}, nil)
+		svc := photoServiceImpl{repo: repoMock}
+
+		assert.ErrorIs(t, svc.DeleteMediaItem(context.Background(), 7, 3), ErrMediaItemNotFound)
+	})
+
+	t.Run("success deleting the cover reprocesses", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).
+			Return(model.Photo{ID: 7, Media: []model.MediaItem{{ID: 1, Position: 1, ObjectKey: "blobs/ab/ab.png"}, {ID: 2, Position: 2}}}, nil)
+		repoMock.On("DeleteMediaItem", context.Background(), uint64(7), uint64(1)).Return(true, nil)
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("ReleaseBlob", context.Background(), "blobs/ab/ab.png").Return(nil)
+		processorMock := serviceMocks.NewPhotoProcessor(t)
+		processorMock.On("Enqueue", context.Background(), uint64(7)).Return(nil)
+		svc := photoServiceImpl{repo: repoMock, blobRepo: blobMock, processor: processorMock}
+
+		assert.Nil(t, svc.DeleteMediaItem(context.Background(), 7, 1))
+	})
+}
+
+func TestReorderMediaItems(t *testing.T) {
+	photo := model.Photo{ID: 7, Media: []model.MediaItem{{ID: 1, Position: 1}, {ID: 2, Position: 2}, {ID: 3, Position: 3}}}
+
+	tests := []struct {
+		name string
+		ids  []uint64
+	}{
+		{"missing item", []uint64{3, 1}},
+		{"repeated item", []uint64{3, 1, 1}},
+		{"item of another post", []uint64{3, 1, 4}},
+	}
+	for _, tt := range tests {
+		t.Run("error "+tt.name, func(t *testing.T) {
+			repoMock := mocks.NewPhotoQuery(t)
+			repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(photo, nil)
+			svc := photoServiceImpl{repo: repoMock}
+
+			_, err := svc.ReorderMediaItems(context.Background(), 7, tt.ids)
+			assert.ErrorIs(t, err, ErrInvalidMediaOrder)
+		})
+	}
+
+	t.Run("success new cover reprocesses", func(t *testing.T) {
+		repoMock := mocks.NewPhotoQuery(t)
+		repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(photo, nil)
+		repoMock.On("ReorderMediaItems", context.Background(), uint64(7), []uint64{3, 1, 2}).Return(true, nil)
+		processorMock := serviceMocks.NewPhotoProcessor(t)
+		processorMock.On("Enqueue", context.Background(), uint64(7)).Return(nil)
+		svc := photoServiceImpl{repo: repoMock, processor: processorMock}
+
+		res, err := svc.ReorderMediaItems(context.Background(), 7, []uint64{3, 1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []model.MediaItem{{ID: 3, Position: 1}, {ID: 1, Position: 2}, {ID: 2, Position: 3}}, res)
+	})
+}
+
+func TestProcessPhoto(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	buf := bytes.Buffer{}
+	assert.Nil(t, png.Encode(&buf, src))
+
+	store := storage.NewLocalStorage(t.TempDir(), "/uploads")
+	assert.Nil(t, store.Put(context.Background(), "photos/1/ab.png", bytes.NewReader(buf.Bytes()), int64(buf.Len()), "image/png"))
+
+	repoMock := mocks.NewPhotoQuery(t)
+	repoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, ObjectKey: "photos/1/ab.png"}, nil)
+	var saved []model.PhotoVariant
+	repoMock.On("SaveVariants", context.Background(), uint64(7), 300, 200, mock.AnythingOfType("[]model.PhotoVariant")).
+		Run(func(args mock.Arguments) { saved = args.Get(4).([]model.PhotoVariant) }).
+		Return(nil)
+	feedMock := serviceMocks.NewFeedService(t)
+	feedMock.On("Distribute", context.Background(), mock.AnythingOfType("model.Photo")).Return(nil)
+	p := photoProcessorImpl{repo: repoMock, store: store, feed: feedMock}
+
+	assert.Nil(t, p.process(context.Background(), 7))
+	assert.Equal(t, 6, len(saved))
+	for _, variant := range saved {
+		r, err := store.Get(context.Background(), variant.ObjectKey)
+		assert.Nil(t, err)
+		r.Close()
+	}
+	assert.Equal(t, "photos/1/ab_thumb.jpg", saved[0].ObjectKey)
+	assert.Equal(t, "/uploads/photos/1/ab_thumb.jpg", saved[0].Url)
+}
+
+func TestStoreBlob(t *testing.T) {
+	data := testPNG(t)
+
+	t.Run("success already uploaded is not stored again", func(t *testing.T) {
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 2
+				blob.Uploaded = true
+				return blob, nil
+			})
+
+		key, err := storeBlob(context.Background(), blobMock, storageMocks.NewStorage(t), data, "image/png", ".png")
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(key, "blobs/"))
+	})
+
+	t.Run("success held by another upload that is not done stores it too", func(t *testing.T) {
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 2
+				return blob, nil
+			})
+		blobMock.On("MarkBlobUploaded", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.AnythingOfType("string"), mock.Anything, int64(len(data)), "image/png").Return(nil)
+
+		_, err := storeBlob(context.Background(), blobMock, storeMock, data, "image/png", ".png")
+		assert.Nil(t, err)
+	})
+
+	t.Run("error put releases the reference", func(t *testing.T) {
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 1
+				return blob, nil
+			})
+		blobMock.On("ReleaseBlob", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.AnythingOfType("string"), mock.Anything, int64(len(data)), "image/png").Return(errors.New("some error"))
+
+		_, err := storeBlob(context.Background(), blobMock, storeMock, data, "image/png", ".png")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCameraName(t *testing.T) {
+	assert.Equal(t, "Canon EOS 5D", cameraName("Canon", "Canon EOS 5D"))
+	assert.Equal(t, "Apple iPhone 15", cameraName("Apple", "iPhone 15"))
+	assert.Equal(t, "iPhone 15", cameraName("", "iPhone 15"))
+}
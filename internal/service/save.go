@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+)
+
+// SaveService saves photos for later and sorts them into collections. Saves
+// and collections are private, nobody but their owner ever sees them.
+type SaveService interface {
+	// Save and Unsave are idempotent. Only a photo the user may see can be
+	// saved, unsaving always works.
+	Save(ctx context.Context, userID uint64, photoID uint64) error
+	Unsave(ctx context.Context, userID uint64, photoID uint64) error
+	// GetSaved lists the user's saved photos, or those of one of their
+	// collections when collectionID is not zero.
+	GetSaved(ctx context.Context, userID uint64, collectionID uint64, cursor uint64, limit int) ([]model.Save, error)
+
+	GetCollections(ctx context.Context, userID uint64) ([]model.SaveCollection, error)
+	CreateCollection(ctx context.Context, userID uint64, name string) (model.SaveCollection, error)
+	RenameCollection(ctx context.Context, userID uint64, id uint64, name string) (model.SaveCollection, error)
+	DeleteCollection(ctx context.Context, userID uint64, id uint64) error
+	// AddToCollection saves the photo if it is not saved yet.
+	AddToCollection(ctx context.Context, userID uint64, id uint64, photoID uint64) error
+	RemoveFromCollection(ctx context.Context, userID uint64, id uint64, photoID uint64) error
+}
+
+type saveServiceImpl struct {
+	repo      repository.SaveQuery
+	photoRepo repository.PhotoQuery
+	userRepo  repository.UserQuery
+	blockRepo repository.BlockQuery
+	likeRepo  repository.LikeQuery
+}
+
+func NewSaveService(repo repository.SaveQuery, photoRepo repository.PhotoQuery, userRepo repository.UserQuery, blockRepo repository.BlockQuery, likeRepo repository.LikeQuery) SaveService {
+	return &saveServiceImpl{repo: repo, photoRepo: photoRepo, userRepo: userRepo, blockRepo: blockRepo, likeRepo: likeRepo}
+}
+
+func (u *saveServiceImpl) Save(ctx context.Context, userID uint64, photoID uint64) error {
+	if _, err := visiblePhoto(ctx, u.photoRepo, u.userRepo, u.blockRepo, userID, photoID); err != nil {
+		return err
+	}
+	_, err := u.repo.CreateSave(ctx, userID, photoID)
+	return err
+}
+
+func (u *saveServiceImpl) Unsave(ctx context.Context, userID uint64, photoID uint64) error {
+	_, err := u.repo.DeleteSave(ctx, userID, photoID)
+	return err
+}
+
+func (u *saveServiceImpl) GetSaved(ctx context.Context, userID uint64, collectionID uint64, cursor uint64, limit int) ([]model.Save, error) {
+	if collectionID > 0 {
+		if _, err := u.collection(ctx, userID, collectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	saves, err := u.repo.GetSaves(ctx, userID, collectionID, cursor, normalizeLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	photos := make([]model.Photo, 0, len(saves))
+	for _, save := range saves {
+		photos = append(photos, *save.Photo)
+	}
+	if err := markLiked(ctx, u.likeRepo, userID, photos); err != nil {
+		return nil, err
+	}
+	for i := range saves {
+		photos[i].SavedByMe = true
+		saves[i].Photo = &photos[i]
+	}
+	return saves, nil
+}
+
+func (u *saveServiceImpl) GetCollections(ctx context.Context, userID uint64) ([]model.SaveCollection, error) {
+	return u.repo.GetCollections(ctx, userID)
+}
+
+func (u *saveServiceImpl) CreateCollection(ctx context.Context, userID uint64, name string) (model.SaveCollection, error) {
+	return u.repo.CreateCollection(ctx, model.SaveCollection{UserID: userID, Name: name})
+}
+
+func (u *saveServiceImpl) RenameCollection(ctx context.Context, userID uint64, id uint64, name string) (model.SaveCollection, error) {
+	collection, err := u.collection(ctx, userID, id)
+	if err != nil {
+		return model.SaveCollection{}, err
+	}
+	if err := u.repo.RenameCollection(ctx, id, name); err != nil {
+		return model.SaveCollection{}, err
+	}
+	collection.Name = name
+	return collection, nil
+}
+
+func (u *saveServiceImpl) DeleteCollection(ctx context.Context, userID uint64, id uint64) error {
+	if _, err := u.collection(ctx, userID, id); err != nil {
+		return err
+	}
+	return u.repo.DeleteCollection(ctx, id)
+}
+
+func (u *saveServiceImpl) AddToCollection(ctx context.Context, userID uint64, id uint64, photoID uint64) error {
+	if _, err := u.collection(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := u.Save(ctx, userID, photoID); err != nil {
+		return err
+	}
+	return u.repo.AddToCollection(ctx, id, photoID)
+}
+
+func (u *saveServiceImpl) RemoveFromCollection(ctx context.Context, userID uint64, id uint64, photoID uint64) error {
+	if _, err := u.collection(ctx, userID, id); err != nil {
+		return err
+	}
+	return u.repo.RemoveFromCollection(ctx, id, photoID)
+}
+
+// collection loads a collection of userID. Someone else's collection is
+// reported as not found so its existence does not leak.
+func (u *saveServiceImpl) collection(ctx context.Context, userID uint64, id uint64) (model.SaveCollection, error) {
+	collection, err := u.repo.GetCollection(ctx, userID, id)
+	if err != nil {
+		return model.SaveCollection{}, err
+	}
+	if collection.ID == 0 {
+		return model.SaveCollection{}, ErrCollectionNotFound
+	}
+	return collection, nil
+}
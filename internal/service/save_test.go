@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSave(t *testing.T) {
+	t.Run("success on a visible photo", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("CreateSave", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+		svc := saveServiceImpl{repo: saveMock, photoRepo: photoMock}
+
+		err := svc.Save(context.Background(), 1, 7)
+		assert.Nil(t, err)
+	})
+
+	t.Run("error private photo", func(t *testing.T) {
+		photoMock := mocks.NewPhotoQuery(t)
+		photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 2, Visibility: model.PhotoVisibilityPrivate}, nil)
+		photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(false, nil)
+		svc := saveServiceImpl{repo: mocks.NewSaveQuery(t), photoRepo: photoMock}
+
+		err := svc.Save(context.Background(), 1, 7)
+		assert.ErrorIs(t, err, ErrPhotoNotFound)
+	})
+}
+
+func TestGetSaved(t *testing.T) {
+	t.Run("success marks saved and liked", func(t *testing.T) {
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSaves", context.Background(), uint64(1), uint64(0), uint64(0), 20).
+			Return([]model.Save{{ID: 5, PhotoID: 7, Photo: &model.Photo{ID: 7}}, {ID: 4, PhotoID: 3, Photo: &model.Photo{ID: 3}}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{7, 3}).Return([]uint64{3}, nil)
+		svc := saveServiceImpl{repo: saveMock, likeRepo: likeMock}
+
+		res, err := svc.GetSaved(context.Background(), 1, 0, 0, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.True(t, res[0].Photo.SavedByMe)
+		assert.False(t, res[0].Photo.LikedByMe)
+		assert.True(t, res[1].Photo.LikedByMe)
+	})
+
+	t.Run("error collection of someone else", func(t *testing.T) {
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetCollection", context.Background(), uint64(1), uint64(3)).Return(model.SaveCollection{}, nil)
+		svc := saveServiceImpl{repo: saveMock}
+
+		_, err := svc.GetSaved(context.Background(), 1, 3, 0, 0)
+		assert.ErrorIs(t, err, ErrCollectionNotFound)
+	})
+}
+
+func TestAddToCollection(t *testing.T) {
+	photoMock := mocks.NewPhotoQuery(t)
+	photoMock.On("GetPhotosByID", context.Background(), uint64(7)).Return(model.Photo{ID: 7, UserID: 1}, nil)
+	photoMock.On("IsPhotoVisible", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+	saveMock := mocks.NewSaveQuery(t)
+	saveMock.On("GetCollection", context.Background(), uint64(1), uint64(3)).Return(model.SaveCollection{ID: 3, UserID: 1}, nil)
+	saveMock.On("CreateSave", context.Background(), uint64(1), uint64(7)).Return(true, nil)
+	saveMock.On("AddToCollection", context.Background(), uint64(3), uint64(7)).Return(nil)
+	svc := saveServiceImpl{repo: saveMock, photoRepo: photoMock}
+
+	err := svc.AddToCollection(context.Background(), 1, 3, 7)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+)
+
+// SearchConfig tunes photo search ranking. The text search language belongs
+// to the database connection, see infrastructure.GormPostgres.
+type SearchConfig struct {
+	// HalfLife is the age at which a photo's relevance counts half.
+	HalfLife time.Duration
+}
+
+var DefaultSearchConfig = SearchConfig{
+	HalfLife: 30 * 24 * time.Hour,
+}
+
+// SearchConfigFromEnv starts from DefaultSearchConfig and overrides it with
+// SEARCH_HALF_LIFE when it is set.
+func SearchConfigFromEnv() (SearchConfig, error) {
+	cfg := DefaultSearchConfig
+	if v := os.Getenv("SEARCH_HALF_LIFE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return SearchConfig{}, fmt.Errorf("SEARCH_HALF_LIFE: %w", err)
+		}
+		cfg.HalfLife = d
+	}
+	if cfg.HalfLife <= 0 {
+		return SearchConfig{}, fmt.Errorf("search: half life must be positive")
+	}
+	return cfg, nil
+}
+
+// Nearby search looks DefaultNearbyRadiusKm around a point unless asked
+// for another radius up to MaxNearbyRadiusKm.
+const (
+	DefaultNearbyRadiusKm = 5.0
+	MaxNearbyRadiusKm     = 50.0
+)
+
+// SearchService finds photos by the words of their title and caption, or by
+// where they were taken.
+type SearchService interface {
+	SearchPhotos(ctx context.Context, viewerID uint64, query string, cursor string, limit int) (matches []model.PhotoMatch, nextCursor string, err error)
+	// NearbyPhotos finds photos within radiusKm of a point, nearest first.
+	// A radius of zero means DefaultNearbyRadiusKm.
+	NearbyPhotos(ctx context.Context, viewerID uint64, latitude float64, longitude float64, radiusKm float64, cursor string, limit int) (photos []model.NearbyPhoto, nextCursor string, err error)
+}
+
+type searchServiceImpl struct {
+	repo     repository.SearchQuery
+	likeRepo repository.LikeQuery
+	saveRepo repository.SaveQuery
+	cfg      SearchConfig
+}
+
+func NewSearchService(repo repository.SearchQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery, cfg SearchConfig) SearchService {
+	return &searchServiceImpl{repo: repo, likeRepo: likeRepo, saveRepo: saveRepo, cfg: cfg}
+}
+
+// searchCursor keeps the time the first page was ranked at, so later pages
+// continue the same ranking instead of one that has decayed meanwhile.
+type searchCursor struct {
+	Now  time.Time `json:"n"`
+	Rank float64   `json:"r"`
+	ID   uint64    `json:"i"`
+}
+
+func (u *searchServiceImpl) SearchPhotos(ctx context.Context, viewerID uint64, query string, cursor string, limit int) (matches []model.PhotoMatch, nextCursor string, err error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []model.PhotoMatch{}, "", nil
+	}
+	after := searchCursor{}
+	if err = decodeCursor(cursor, &after); err != nil {
+		return nil, "", err
+	}
+	if after.Now.IsZero() {
+		after.Now = time.Now()
+	}
+
+	limit = normalizeLimit(limit)
+	matches, err = u.repo.SearchPhotos(ctx, viewerID, repository.PhotoSearch{
+		Query:     query,
+		HalfLife:  u.cfg.HalfLife,
+		Now:       after.Now,
+		AfterRank: after.Rank,
+		AfterID:   after.ID,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// one extra row was fetched to know whether another page exists
+	if len(matches) > limit {
+		matches = matches[:limit]
+		last := matches[limit-1]
+		nextCursor = encodeCursor(searchCursor{Now: after.Now, Rank: last.Rank, ID: last.PhotoID})
+	}
+	found := []model.PhotoMatch{}
+	photos := []model.Photo{}
+	for _, match := range matches {
+		if match.Photo != nil {
+			found = append(found, match)
+			photos = append(photos, *match.Photo)
+		}
+	}
+	if err = markLiked(ctx, u.likeRepo, viewerID, photos); err != nil {
+		return nil, "", err
+	}
+	if err = markSaved(ctx, u.saveRepo, viewerID, photos); err != nil {
+		return nil, "", err
+	}
+	for i := range found {
+		found[i].Photo = &photos[i]
+	}
+	return found, nextCursor, nil
+}
+
+type nearbyCursor struct {
+	Distance float64 `json:"d"`
+	ID       uint64  `json:"i"`
+}
+
+func (u *searchServiceImpl) NearbyPhotos(ctx context.Context, viewerID uint64, latitude float64, longitude float64, radiusKm float64, cursor string, limit int) (photos []model.NearbyPhoto, nextCursor string, err error) {
+	if !validLocation(latitude, longitude) {
+		return nil, "", ErrInvalidLocation
+	}
+	if radiusKm < 0 || radiusKm > MaxNearbyRadiusKm {
+		return nil, "", ErrInvalidRadius
+	}
+	if radiusKm == 0 {
+		radiusKm = DefaultNearbyRadiusKm
+	}
+	after := nearbyCursor{}
+	if err = decodeCursor(cursor, &after); err != nil {
+		return nil, "", err
+	}
+
+	limit = normalizeLimit(limit)
+	photos, err = u.repo.NearbyPhotos(ctx, viewerID, repository.PhotoNearby{
+		Latitude:      latitude,
+		Longitude:     longitude,
+		RadiusKm:      radiusKm,
+		AfterDistance: after.Distance,
+		AfterID:       after.ID,
+		Limit:         limit + 1,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// one extra row was fetched to know whether another page exists
+	if len(photos) > limit {
+		photos = photos[:limit]
+		last := photos[limit-1]
+		nextCursor = encodeCursor(nearbyCursor{Distance: last.Distance, ID: last.PhotoID})
+	}
+	found := []model.NearbyPhoto{}
+	listed := []model.Photo{}
+	for _, item := range photos {
+		if item.Photo == nil || item.Photo.Latitude == nil || item.Photo.Longitude == nil {
+			continue
+		}
+		item.DistanceKm = helper.DistanceKm(latitude, longitude, *item.Photo.Latitude, *item.Photo.Longitude)
+		found = append(found, item)
+		listed = append(listed, *item.Photo)
+	}
+	if err = markLiked(ctx, u.likeRepo, viewerID, listed); err != nil {
+		return nil, "", err
+	}
+	if err = markSaved(ctx, u.saveRepo, viewerID, listed); err != nil {
+		return nil, "", err
+	}
+	for i := range found {
+		found[i].Photo = &listed[i]
+	}
+	return found, nextCursor, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchPhotos(t *testing.T) {
+	t.Run("pages below the cursor as of its time", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		repoMock := mocks.NewSearchQuery(t)
+		repoMock.On("SearchPhotos", context.Background(), uint64(1), repository.PhotoSearch{
+			Query: "beach", HalfLife: DefaultSearchConfig.HalfLife, Now: now, AfterRank: 0.75, AfterID: 40, Limit: 3,
+		}).Return([]model.PhotoMatch{
+			{PhotoID: 12, Rank: 0.7, Photo: &model.Photo{ID: 12}, TitleHighlights: []model.Highlight{{Offset: 0, Length: 5}}},
+			{PhotoID: 30, Rank: 0.5, Photo: &model.Photo{ID: 30}},
+			{PhotoID: 8, Rank: 0.5, Photo: &model.Photo{ID: 8}},
+		}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{12, 30}).Return([]uint64{30}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{12, 30}).Return([]uint64{12}, nil)
+		svc := searchServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock, cfg: DefaultSearchConfig}
+
+		res, next, err := svc.SearchPhotos(context.Background(), 1, " beach ", encodeCursor(searchCursor{Now: now, Rank: 0.75, ID: 40}), 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, []model.Highlight{{Offset: 0, Length: 5}}, res[0].TitleHighlights)
+		assert.True(t, res[0].Photo.SavedByMe)
+		assert.True(t, res[1].Photo.LikedByMe)
+		after := searchCursor{}
+		assert.Nil(t, decodeCursor(next, &after))
+		assert.Equal(t, searchCursor{Now: now, Rank: 0.5, ID: 30}, after)
+	})
+
+	t.Run("first page is ranked as of now", func(t *testing.T) {
+		repoMock := mocks.NewSearchQuery(t)
+		repoMock.On("SearchPhotos", context.Background(), uint64(1), mock.MatchedBy(func(search repository.PhotoSearch) bool {
+			return search.AfterID == 0 && search.Limit == 21 && time.Since(search.Now) < time.Minute
+		})).Return([]model.PhotoMatch{{PhotoID: 12, Rank: 0.7, Photo: &model.Photo{ID: 12}}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{12}).Return([]uint64{}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{12}).Return([]uint64{}, nil)
+		svc := searchServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock, cfg: DefaultSearchConfig}
+
+		res, next, err := svc.SearchPhotos(context.Background(), 1, "beach", "", 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, "", next)
+	})
+
+	t.Run("blank query finds nothing", func(t *testing.T) {
+		svc := searchServiceImpl{repo: mocks.NewSearchQuery(t), cfg: DefaultSearchConfig}
+
+		res, _, err := svc.SearchPhotos(context.Background(), 1, "  ", "", 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("error invalid cursor", func(t *testing.T) {
+		svc := searchServiceImpl{repo: mocks.NewSearchQuery(t), cfg: DefaultSearchConfig}
+
+		_, _, err := svc.SearchPhotos(context.Background(), 1, "beach", "not a cursor", 20)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestNearbyPhotos(t *testing.T) {
+	coordinate := func(v float64) *float64 { return &v }
+
+	t.Run("pages after the cursor with distances", func(t *testing.T) {
+		repoMock := mocks.NewSearchQuery(t)
+		repoMock.On("NearbyPhotos", context.Background(), uint64(1), repository.PhotoNearby{
+			Latitude: -6.2, Longitude: 106.8, RadiusKm: DefaultNearbyRadiusKm, AfterDistance: 0.5, AfterID: 40, Limit: 3,
+		}).Return([]model.NearbyPhoto{
+			{PhotoID: 12, Distance: 1, Photo: &model.Photo{ID: 12, Latitude: coordinate(-6.2), Longitude: coordinate(106.81)}},
+			{PhotoID: 30, Distance: 4, Photo: &model.Photo{ID: 30, Latitude: coordinate(-6.22), Longitude: coordinate(106.8)}},
+			{PhotoID: 8, Distance: 9, Photo: &model.Photo{ID: 8, Latitude: coordinate(-6.2), Longitude: coordinate(106.83)}},
+		}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{12, 30}).Return([]uint64{30}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{12, 30}).Return([]uint64{}, nil)
+		svc := searchServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock, cfg: DefaultSearchConfig}
+
+		res, next, err := svc.NearbyPhotos(context.Background(), 1, -6.2, 106.8, 0, encodeCursor(nearbyCursor{Distance: 0.5, ID: 40}), 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.InDelta(t, 1.1, res[0].DistanceKm, 0.01)
+		assert.InDelta(t, 2.22, res[1].DistanceKm, 0.01)
+		assert.True(t, res[1].Photo.LikedByMe)
+		after := nearbyCursor{}
+		assert.Nil(t, decodeCursor(next, &after))
+		assert.Equal(t, nearbyCursor{Distance: 4, ID: 30}, after)
+	})
+
+	testCases := []struct {
+		desc      string
+		latitude  float64
+		longitude float64
+		radius    float64
+		err       error
+	}{
+		{desc: "latitude out of range", latitude: -91, longitude: 0, err: ErrInvalidLocation},
+		{desc: "longitude out of range", latitude: 0, longitude: 180.5, err: ErrInvalidLocation},
+		{desc: "radius too large", latitude: 0, longitude: 0, radius: MaxNearbyRadiusKm + 1, err: ErrInvalidRadius},
+	}
+	for _, tC := range testCases {
+		t.Run("error "+tC.desc, func(t *testing.T) {
+			svc := searchServiceImpl{repo: mocks.NewSearchQuery(t), cfg: DefaultSearchConfig}
+
+			_, _, err := svc.NearbyPhotos(context.Background(), 1, tC.latitude, tC.longitude, tC.radius, "", 20)
+			assert.ErrorIs(t, err, tC.err)
+		})
+	}
+}
+
+func TestSearchConfigFromEnv(t *testing.T) {
+	t.Setenv("SEARCH_HALF_LIFE", "72h")
+	cfg, err := SearchConfigFromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, 72*time.Hour, cfg.HalfLife)
+
+	t.Setenv("SEARCH_HALF_LIFE", "-1h")
+	_, err = SearchConfigFromEnv()
+	assert.NotNil(t, err)
+}
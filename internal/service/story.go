@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/internal/storage"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+)
+
+// StoryLifetime is how long a story is shown after it is posted.
+const StoryLifetime = 24 * time.Hour
+
+// StoryConfig tunes the story reaper. Expired stories are hidden as soon as
+// they expire, the reaper only deletes them and frees their images.
+type StoryConfig struct {
+	// ReapInterval is how often expired stories are deleted.
+	ReapInterval time.Duration
+}
+
+var DefaultStoryConfig = StoryConfig{
+	ReapInterval: time.Minute,
+}
+
+// StoryConfigFromEnv starts from DefaultStoryConfig and overrides it with
+// STORY_REAP_INTERVAL when it is set.
+func StoryConfigFromEnv() (StoryConfig, error) {
+	cfg := DefaultStoryConfig
+	if v := os.Getenv("STORY_REAP_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return StoryConfig{}, fmt.Errorf("STORY_REAP_INTERVAL: %w", err)
+		}
+		cfg.ReapInterval = d
+	}
+	if cfg.ReapInterval <= 0 {
+		return StoryConfig{}, fmt.Errorf("story: reap interval must be positive")
+	}
+	return cfg, nil
+}
+
+const reapBatch = 100
+
+// StoryService manages stories and the highlights keeping them. Stories are
+// seen by the author and their followers until they expire, highlights by
+// everyone who sees the author's profile. Changes are only allowed to the
+// author, anyone else is told the story or highlight does not exist.
+type StoryService interface {
+	// Start reaps expired stories right away and then every ReapInterval
+	// until ctx is done.
+	Start(ctx context.Context)
+	// Reap deletes the expired stories no highlight keeps and releases
+	// their images, it returns how many it deleted.
+	Reap(ctx context.Context) (int, error)
+
+	PostStory(ctx context.Context, upload dto.StoryUpload, file io.Reader, userID uint64) (model.Story, error)
+	// GetStoryTray groups the live stories of the viewer and the accounts
+	// they follow by account. The viewer's own come first, then accounts
+	// with stories the viewer has not seen, each group by its latest story.
+	GetStoryTray(ctx context.Context, viewerID uint64) ([]model.StoryTray, error)
+	GetUserStories(ctx context.Context, viewerID uint64, userID uint64) ([]model.Story, error)
+	// MarkSeen records that the viewer has seen a live story, seeing it
+	// again or seeing one's own story changes nothing.
+	MarkSeen(ctx context.Context, viewerID uint64, id uint64) error
+	GetStoryViewers(ctx context.Context, userID uint64, id uint64, cursor uint64, limit int) ([]model.StoryView, error)
+	DeleteStory(ctx context.Context, userID uint64, id uint64) error
+
+	GetHighlight(ctx context.Context, viewerID uint64, id uint64) (model.StoryHighlight, []model.StoryHighlightItem, error)
+	GetUserHighlights(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.StoryHighlight, error)
+	CreateHighlight(ctx context.Context, userID uint64, title string, storyIDs []uint64) (model.StoryHighlight, error)
+	EditHighlight(ctx context.Context, userID uint64, id uint64, title string) (model.StoryHighlight, error)
+	DeleteHighlight(ctx context.Context, userID uint64, id uint64) error
+	AddHighlightStory(ctx context.Context, userID uint64, id uint64, storyID uint64) error
+	RemoveHighlightStory(ctx context.Context, userID uint64, id uint64, storyID uint64) error
+	ReorderHighlightStories(ctx context.Context, userID uint64, id uint64, storyIDs []uint64) error
+}
+
+type storyServiceImpl struct {
+	repo     repository.StoryQuery
+	blobRepo repository.BlobQuery
+	store    storage.Storage
+	cfg      StoryConfig
+}
+
+func NewStoryService(repo repository.StoryQuery, blobRepo repository.BlobQuery, store storage.Storage, cfg StoryConfig) StoryService {
+	return &storyServiceImpl{repo: repo, blobRepo: blobRepo, store: store, cfg: cfg}
+}
+
+func (u *storyServiceImpl) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(u.cfg.ReapInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := u.Reap(ctx); err != nil {
+				log.Printf("stories: reap: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (u *storyServiceImpl) Reap(ctx context.Context) (int, error) {
+	now := time.Now()
+	reaped := 0
+	for {
+		stories, err := u.repo.ExpireStories(ctx, now, reapBatch)
+		if err != nil {
+			return reaped, err
+		}
+		for _, story := range stories {
+			releaseBlob(ctx, u.blobRepo, story.ObjectKey)
+		}
+		reaped += len(stories)
+		if len(stories) < reapBatch {
+			return reaped, nil
+		}
+	}
+}
+
+// PostStory stores the image the same way PostPhoto does, stories are shown
+// as uploaded and get no variants.
+func (u *storyServiceImpl) PostStory(ctx context.Context, upload dto.StoryUpload, file io.Reader, userID uint64) (model.Story, error) {
+	m, err := readMedia(file)
+	if err != nil {
+		return model.Story{}, err
+	}
+	key, err := storeBlob(ctx, u.blobRepo, u.store, m.data, m.contentType, m.ext)
+	if err != nil {
+		return model.Story{}, err
+	}
+	bounds := m.img.Bounds()
+	story, err := u.repo.CreateStory(ctx, model.Story{
+		UserID:      userID,
+		Url:         u.store.URL(key),
+		ObjectKey:   key,
+		ContentType: m.contentType,
+		Size:        int64(len(m.data)),
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Caption:     upload.Caption,
+		AltText:     upload.AltText,
+		ExpiresAt:   time.Now().Add(StoryLifetime),
+	})
+	if err != nil {
+		releaseBlob(ctx, u.blobRepo, key)
+		return model.Story{}, err
+	}
+	return story, nil
+}
+
+func (u *storyServiceImpl) GetStoryTray(ctx context.Context, viewerID uint64) ([]model.StoryTray, error) {
+	stories, err := u.repo.GetStoryTray(ctx, viewerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := u.markSeen(ctx, viewerID, stories); err != nil {
+		return nil, err
+	}
+
+	trays := []model.StoryTray{}
+	for _, story := range stories {
+		if len(trays) == 0 || trays[len(trays)-1].User.ID != story.UserID {
+			tray := model.StoryTray{User: model.User{ID: story.UserID}, Seen: true}
+			if story.User != nil {
+				tray.User = *story.User
+			}
+			trays = append(trays, tray)
+		}
+		tray := &trays[len(trays)-1]
+		tray.Stories = append(tray.Stories, story)
+		tray.Seen = tray.Seen && story.SeenByMe
+	}
+	latest := func(tray model.StoryTray) uint64 {
+		return tray.Stories[len(tray.Stories)-1].ID
+	}
+	sort.SliceStable(trays, func(i, j int) bool {
+		a, b := trays[i], trays[j]
+		if (a.User.ID == viewerID) != (b.User.ID == viewerID) {
+			return a.User.ID == viewerID
+		}
+		if a.Seen != b.Seen {
+			return !a.Seen
+		}
+		return latest(a) > latest(b)
+	})
+	return trays, nil
+}
+
+func (u *storyServiceImpl) GetUserStories(ctx context.Context, viewerID uint64, userID uint64) ([]model.Story, error) {
+	stories, err := u.repo.GetUserStories(ctx, viewerID, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := u.markSeen(ctx, viewerID, stories); err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+// markSeen fills in SeenByMe, the author has always seen their own stories.
+func (u *storyServiceImpl) markSeen(ctx context.Context, viewerID uint64, stories []model.Story) error {
+	ids := make([]uint64, 0, len(stories))
+	for _, story := range stories {
+		if story.UserID != viewerID {
+			ids = append(ids, story.ID)
+		}
+	}
+	seen, err := u.repo.GetSeenStoryIDs(ctx, viewerID, ids)
+	if err != nil {
+		return err
+	}
+	set := make(map[uint64]bool, len(seen))
+	for _, id := range seen {
+		set[id] = true
+	}
+	for i := range stories {
+		stories[i].SeenByMe = stories[i].UserID == viewerID || set[stories[i].ID]
+	}
+	return nil
+}
+
+func (u *storyServiceImpl) MarkSeen(ctx context.Context, viewerID uint64, id uint64) error {
+	story, err := u.repo.GetStory(ctx, viewerID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if story.ID == 0 {
+		return ErrStoryNotFound
+	}
+	if story.UserID == viewerID {
+		return nil
+	}
+	_, err = u.repo.MarkSeen(ctx, id, viewerID)
+	return err
+}
+
+// GetStoryViewers is only for the author, the viewers of a story kept in a
+// highlight stay listed after it expires.
+func (u *storyServiceImpl) GetStoryViewers(ctx context.Context, userID uint64, id uint64, cursor uint64, limit int) ([]model.StoryView, error) {
+	if _, err := u.ownStory(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return u.repo.GetStoryViewers(ctx, id, cursor, normalizeLimit(limit))
+}
+
+func (u *storyServiceImpl) DeleteStory(ctx context.Context, userID uint64, id uint64) error {
+	story, err := u.ownStory(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.DeleteStory(ctx, id); err != nil {
+		return err
+	}
+	releaseBlob(ctx, u.blobRepo, story.ObjectKey)
+	return nil
+}
+
+func (u *storyServiceImpl) ownStory(ctx context.Context, userID uint64, id uint64) (model.Story, error) {
+	story, err := u.repo.GetStoryByID(ctx, id)
+	if err != nil {
+		return model.Story{}, err
+	}
+	if story.ID == 0 || story.UserID != userID {
+		return model.Story{}, ErrStoryNotFound
+	}
+	return story, nil
+}
+
+func (u *storyServiceImpl) GetHighlight(ctx context.Context, viewerID uint64, id uint64) (model.StoryHighlight, []model.StoryHighlightItem, error) {
+	highlight, err := u.repo.GetHighlight(ctx, viewerID, id)
+	if err != nil {
+		return model.StoryHighlight{}, nil, err
+	}
+	if highlight.ID == 0 {
+		return model.StoryHighlight{}, nil, ErrHighlightNotFound
+	}
+	items, err := u.repo.GetHighlightItems(ctx, id)
+	if err != nil {
+		return model.StoryHighlight{}, nil, err
+	}
+	return highlight, items, nil
+}
+
+func (u *storyServiceImpl) GetUserHighlights(ctx context.Context, viewerID uint64, userID uint64, cursor uint64, limit int) ([]model.StoryHighlight, error) {
+	return u.repo.GetUserHighlights(ctx, viewerID, userID, cursor, normalizeLimit(limit))
+}
+
+// CreateHighlight makes a highlight of the caller's stories in the order
+// given, every story is checked before the highlight is created.
+func (u *storyServiceImpl) CreateHighlight(ctx context.Context, userID uint64, title string, storyIDs []uint64) (model.StoryHighlight, error) {
+	for _, storyID := range storyIDs {
+		if err := u.checkHighlightStory(ctx, userID, storyID); err != nil {
+			return model.StoryHighlight{}, err
+		}
+	}
+	highlight, err := u.repo.CreateHighlight(ctx, model.StoryHighlight{UserID: userID, Title: title})
+	if err != nil {
+		return model.StoryHighlight{}, err
+	}
+	for _, storyID := range storyIDs {
+		added, err := u.repo.AddHighlightStory(ctx, highlight.ID, storyID)
+		if err != nil {
+			return model.StoryHighlight{}, err
+		}
+		if added && highlight.CoverStoryID == nil {
+			cover := storyID
+			highlight.CoverStoryID = &cover
+		}
+	}
+	return highlight, nil
+}
+
+func (u *storyServiceImpl) EditHighlight(ctx context.Context, userID uint64, id uint64, title string) (model.StoryHighlight, error) {
+	highlight, err := u.ownHighlight(ctx, userID, id)
+	if err != nil {
+		return model.StoryHighlight{}, err
+	}
+	if err := u.repo.UpdateHighlight(ctx, id, title); err != nil {
+		return model.StoryHighlight{}, err
+	}
+	highlight.Title = title
+	return highlight, nil
+}
+
+// DeleteHighlight leaves its stories to expire as usual, those that already
+// have go with the next reap unless another highlight keeps them.
+func (u *storyServiceImpl) DeleteHighlight(ctx context.Context, userID uint64, id uint64) error {
+	if _, err := u.ownHighlight(ctx, userID, id); err != nil {
+		return err
+	}
+	return u.repo.DeleteHighlight(ctx, id)
+}
+
+// AddHighlightStory appends one of the caller's stories, adding a story
+// that is already in the highlight does nothing.
+func (u *storyServiceImpl) AddHighlightStory(ctx context.Context, userID uint64, id uint64, storyID uint64) error {
+	if _, err := u.ownHighlight(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := u.checkHighlightStory(ctx, userID, storyID); err != nil {
+		return err
+	}
+	_, err := u.repo.AddHighlightStory(ctx, id, storyID)
+	return err
+}
+
+func (u *storyServiceImpl) RemoveHighlightStory(ctx context.Context, userID uint64, id uint64, storyID uint64) error {
+	if _, err := u.ownHighlight(ctx, userID, id); err != nil {
+		return err
+	}
+	removed, err := u.repo.RemoveHighlightStory(ctx, id, storyID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrStoryNotFound
+	}
+	return nil
+}
+
+// ReorderHighlightStories takes the complete new order like
+// AlbumService.ReorderPhotos.
+func (u *storyServiceImpl) ReorderHighlightStories(ctx context.Context, userID uint64, id uint64, storyIDs []uint64) error {
+	if _, err := u.ownHighlight(ctx, userID, id); err != nil {
+		return err
+	}
+	items, err := u.repo.GetHighlightItems(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(items) != len(storyIDs) {
+		return ErrInvalidHighlightOrder
+	}
+	remaining := make(map[uint64]bool, len(items))
+	for _, item := range items {
+		remaining[item.StoryID] = true
+	}
+	for _, storyID := range storyIDs {
+		if !remaining[storyID] {
+			return ErrInvalidHighlightOrder
+		}
+		delete(remaining, storyID)
+	}
+	return u.repo.ReorderHighlightStories(ctx, id, storyIDs)
+}
+
+func (u *storyServiceImpl) ownHighlight(ctx context.Context, userID uint64, id uint64) (model.StoryHighlight, error) {
+	highlight, err := u.repo.GetHighlightByID(ctx, id)
+	if err != nil {
+		return model.StoryHighlight{}, err
+	}
+	if highlight.ID == 0 || highlight.UserID != userID {
+		return model.StoryHighlight{}, ErrHighlightNotFound
+	}
+	return highlight, nil
+}
+
+func (u *storyServiceImpl) checkHighlightStory(ctx context.Context, userID uint64, storyID uint64) error {
+	story, err := u.repo.GetStoryByID(ctx, storyID)
+	if err != nil {
+		return err
+	}
+	if story.ID == 0 {
+		return ErrStoryNotFound
+	}
+	if story.UserID != userID {
+		return ErrStoryNotOwned
+	}
+	return nil
+}
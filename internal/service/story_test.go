@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	storageMocks "github.com/MidnightHelix/MyGram/internal/storage/mocks"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReap(t *testing.T) {
+	full := make([]model.Story, reapBatch)
+	for i := range full {
+		full[i] = model.Story{ID: uint64(i + 1), ObjectKey: "blobs/aa/aa.png"}
+	}
+	repoMock := mocks.NewStoryQuery(t)
+	repoMock.On("ExpireStories", context.Background(), mock.AnythingOfType("time.Time"), reapBatch).Return(full, nil).Once()
+	repoMock.On("ExpireStories", context.Background(), mock.AnythingOfType("time.Time"), reapBatch).
+		Return([]model.Story{{ID: 200, ObjectKey: "blobs/bb/bb.png"}}, nil).Once()
+	blobMock := mocks.NewBlobQuery(t)
+	blobMock.On("ReleaseBlob", context.Background(), "blobs/aa/aa.png").Return(nil).Times(reapBatch)
+	blobMock.On("ReleaseBlob", context.Background(), "blobs/bb/bb.png").Return(nil).Once()
+	svc := storyServiceImpl{repo: repoMock, blobRepo: blobMock}
+
+	reaped, err := svc.Reap(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, reapBatch+1, reaped)
+}
+
+func TestPostStory(t *testing.T) {
+	t.Run("error create releases the blob", func(t *testing.T) {
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").Return(nil)
+		storeMock.On("URL", mock.AnythingOfType("string")).Return("")
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 1
+				return blob, nil
+			})
+		blobMock.On("MarkBlobUploaded", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		blobMock.On("ReleaseBlob", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("CreateStory", context.Background(), mock.AnythingOfType("model.Story")).Return(model.Story{}, errors.New("some error"))
+		svc := storyServiceImpl{repo: repoMock, blobRepo: blobMock, store: storeMock}
+
+		_, err := svc.PostStory(context.Background(), dto.StoryUpload{}, bytes.NewReader(testPNG(t)), 1)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("success expires after a day", func(t *testing.T) {
+		storeMock := storageMocks.NewStorage(t)
+		storeMock.On("Put", context.Background(), mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), "image/png").Return(nil)
+		storeMock.On("URL", mock.AnythingOfType("string")).Return("http://cdn/story.png")
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("AcquireBlob", context.Background(), mock.AnythingOfType("model.Blob")).
+			Return(func(ctx context.Context, blob model.Blob) (model.Blob, error) {
+				blob.RefCount = 1
+				return blob, nil
+			})
+		blobMock.On("MarkBlobUploaded", context.Background(), mock.AnythingOfType("string")).Return(nil)
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("CreateStory", context.Background(), mock.AnythingOfType("model.Story")).
+			Return(func(ctx context.Context, story model.Story) (model.Story, error) {
+				story.ID = 4
+				return story, nil
+			})
+		svc := storyServiceImpl{repo: repoMock, blobRepo: blobMock, store: storeMock}
+
+		before := time.Now()
+		story, err := svc.PostStory(context.Background(), dto.StoryUpload{Caption: "hi"}, bytes.NewReader(testPNG(t)), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 30, story.Width)
+		assert.Equal(t, "hi", story.Caption)
+		assert.False(t, story.ExpiresAt.Before(before.Add(StoryLifetime)))
+	})
+}
+
+func TestGetStoryTray(t *testing.T) {
+	repoMock := mocks.NewStoryQuery(t)
+	repoMock.On("GetStoryTray", context.Background(), uint64(1), mock.AnythingOfType("time.Time")).Return([]model.Story{
+		{ID: 2, UserID: 1},
+		{ID: 3, UserID: 2},
+		{ID: 9, UserID: 2},
+		{ID: 5, UserID: 3},
+		{ID: 8, UserID: 4},
+	}, nil)
+	// the viewer's own story is never asked for
+	repoMock.On("GetSeenStoryIDs", context.Background(), uint64(1), []uint64{3, 9, 5, 8}).Return([]uint64{3, 9, 8}, nil)
+	svc := storyServiceImpl{repo: repoMock}
+
+	trays, err := svc.GetStoryTray(context.Background(), 1)
+	assert.Nil(t, err)
+	users := []uint64{}
+	for _, tray := range trays {
+		users = append(users, tray.User.ID)
+	}
+	// own first, then unseen, then by latest story
+	assert.Equal(t, []uint64{1, 3, 2, 4}, users)
+	assert.True(t, trays[0].Seen)
+	assert.False(t, trays[1].Seen)
+	assert.True(t, trays[2].Seen)
+	assert.Equal(t, 2, len(trays[2].Stories))
+}
+
+func TestMarkSeen(t *testing.T) {
+	t.Run("error expired or hidden", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetStory", context.Background(), uint64(1), uint64(4), mock.AnythingOfType("time.Time")).Return(model.Story{}, nil)
+		svc := storyServiceImpl{repo: repoMock}
+
+		assert.ErrorIs(t, svc.MarkSeen(context.Background(), 1, 4), ErrStoryNotFound)
+	})
+
+	t.Run("success own story is not recorded", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetStory", context.Background(), uint64(1), uint64(4), mock.AnythingOfType("time.Time")).Return(model.Story{ID: 4, UserID: 1}, nil)
+		svc := storyServiceImpl{repo: repoMock}
+
+		assert.Nil(t, svc.MarkSeen(context.Background(), 1, 4))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetStory", context.Background(), uint64(1), uint64(4), mock.AnythingOfType("time.Time")).Return(model.Story{ID: 4, UserID: 2}, nil)
+		repoMock.On("MarkSeen", context.Background(), uint64(4), uint64(1)).Return(true, nil)
+		svc := storyServiceImpl{repo: repoMock}
+
+		assert.Nil(t, svc.MarkSeen(context.Background(), 1, 4))
+	})
+}
+
+func TestDeleteStory(t *testing.T) {
+	t.Run("error not the author", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetStoryByID", context.Background(), uint64(4)).Return(model.Story{ID: 4, UserID: 2}, nil)
+		svc := storyServiceImpl{repo: repoMock}
+
+		assert.ErrorIs(t, svc.DeleteStory(context.Background(), 1, 4), ErrStoryNotFound)
+	})
+
+	t.Run("success releases the blob", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetStoryByID", context.Background(), uint64(4)).Return(model.Story{ID: 4, UserID: 1, ObjectKey: "blobs/aa/aa.png"}, nil)
+		repoMock.On("DeleteStory", context.Background(), uint64(4)).Return(nil)
+		blobMock := mocks.NewBlobQuery(t)
+		blobMock.On("ReleaseBlob", context.Background(), "blobs/aa/aa.png").Return(nil)
+		svc := storyServiceImpl{repo: repoMock, blobRepo: blobMock}
+
+		assert.Nil(t, svc.DeleteStory(context.Background(), 1, 4))
+	})
+}
+
+func TestCreateHighlight(t *testing.T) {
+	t.Run("error story of someone else", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetStoryByID", context.Background(), uint64(4)).Return(model.Story{ID: 4, UserID: 1}, nil)
+		repoMock.On("GetStoryByID", context.Background(), uint64(5)).Return(model.Story{ID: 5, UserID: 2}, nil)
+		svc := storyServiceImpl{repo: repoMock}
+
+		_, err := svc.CreateHighlight(context.Background(), 1, "trip", []uint64{4, 5})
+		assert.ErrorIs(t, err, ErrStoryNotOwned)
+	})
+
+	t.Run("success first story is the cover", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetStoryByID", context.Background(), uint64(4)).Return(model.Story{ID: 4, UserID: 1}, nil)
+		repoMock.On("GetStoryByID", context.Background(), uint64(5)).Return(model.Story{ID: 5, UserID: 1}, nil)
+		repoMock.On("CreateHighlight", context.Background(), model.StoryHighlight{UserID: 1, Title: "trip"}).
+			Return(model.StoryHighlight{ID: 3, UserID: 1, Title: "trip"}, nil)
+		repoMock.On("AddHighlightStory", context.Background(), uint64(3), uint64(4)).Return(true, nil)
+		repoMock.On("AddHighlightStory", context.Background(), uint64(3), uint64(5)).Return(true, nil)
+		svc := storyServiceImpl{repo: repoMock}
+
+		highlight, err := svc.CreateHighlight(context.Background(), 1, "trip", []uint64{4, 5})
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(4), *highlight.CoverStoryID)
+	})
+}
+
+func TestReorderHighlightStories(t *testing.T) {
+	items := []model.StoryHighlightItem{{HighlightID: 3, StoryID: 4}, {HighlightID: 3, StoryID: 5}}
+	for name, order := range map[string][]uint64{
+		"missing story":   {4},
+		"duplicate story": {4, 4},
+		"unknown story":   {4, 6},
+	} {
+		t.Run("error "+name, func(t *testing.T) {
+			repoMock := mocks.NewStoryQuery(t)
+			repoMock.On("GetHighlightByID", context.Background(), uint64(3)).Return(model.StoryHighlight{ID: 3, UserID: 1}, nil)
+			repoMock.On("GetHighlightItems", context.Background(), uint64(3)).Return(items, nil)
+			svc := storyServiceImpl{repo: repoMock}
+
+			assert.ErrorIs(t, svc.ReorderHighlightStories(context.Background(), 1, 3, order), ErrInvalidHighlightOrder)
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		repoMock := mocks.NewStoryQuery(t)
+		repoMock.On("GetHighlightByID", context.Background(), uint64(3)).Return(model.StoryHighlight{ID: 3, UserID: 1}, nil)
+		repoMock.On("GetHighlightItems", context.Background(), uint64(3)).Return(items, nil)
+		repoMock.On("ReorderHighlightStories", context.Background(), uint64(3), []uint64{5, 4}).Return(nil)
+		svc := storyServiceImpl{repo: repoMock}
+
+		assert.Nil(t, svc.ReorderHighlightStories(context.Background(), 1, 3, []uint64{5, 4}))
+	})
+}
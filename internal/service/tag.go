@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
+)
+
+// TagService serves hashtag pages and autocomplete. Tags themselves are
+// kept in sync with captions by PhotoService.
+type TagService interface {
+	GetTagPhotos(ctx context.Context, viewerID uint64, name string, cursor uint64, limit int) (model.Tag, []model.Photo, error)
+	SearchTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error)
+}
+
+type tagServiceImpl struct {
+	repo     repository.TagQuery
+	likeRepo repository.LikeQuery
+	saveRepo repository.SaveQuery
+}
+
+func NewTagService(repo repository.TagQuery, likeRepo repository.LikeQuery, saveRepo repository.SaveQuery) TagService {
+	return &tagServiceImpl{repo: repo, likeRepo: likeRepo, saveRepo: saveRepo}
+}
+
+// GetTagPhotos accepts the tag in any form a caption could contain it, with
+// or without '#' and in any case.
+func (u *tagServiceImpl) GetTagPhotos(ctx context.Context, viewerID uint64, name string, cursor uint64, limit int) (model.Tag, []model.Photo, error) {
+	tag, err := u.repo.GetTag(ctx, helper.NormalizeHashtag(name))
+	if err != nil {
+		return model.Tag{}, nil, err
+	}
+	if tag.ID == 0 {
+		return model.Tag{}, nil, ErrTagNotFound
+	}
+
+	photos, err := u.repo.GetTagPhotos(ctx, viewerID, tag.ID, cursor, normalizeLimit(limit))
+	if err != nil {
+		return model.Tag{}, nil, err
+	}
+	if err := markLiked(ctx, u.likeRepo, viewerID, photos); err != nil {
+		return model.Tag{}, nil, err
+	}
+	if err := markSaved(ctx, u.saveRepo, viewerID, photos); err != nil {
+		return model.Tag{}, nil, err
+	}
+	return tag, photos, nil
+}
+
+func (u *tagServiceImpl) SearchTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error) {
+	prefix = helper.NormalizeHashtag(prefix)
+	if prefix == "" {
+		return []model.Tag{}, nil
+	}
+	return u.repo.SearchTags(ctx, prefix, normalizeLimit(limit))
+}
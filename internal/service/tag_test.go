@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTagPhotos(t *testing.T) {
+	t.Run("success normalizes the name", func(t *testing.T) {
+		repoMock := mocks.NewTagQuery(t)
+		repoMock.On("GetTag", context.Background(), "café").Return(model.Tag{ID: 3, Name: "café", PhotoCount: 2}, nil)
+		repoMock.On("GetTagPhotos", context.Background(), uint64(1), uint64(3), uint64(0), 20).Return([]model.Photo{{ID: 9}, {ID: 4}}, nil)
+		likeMock := mocks.NewLikeQuery(t)
+		likeMock.On("GetLikedPhotoIDs", context.Background(), uint64(1), []uint64{9, 4}).Return([]uint64{4}, nil)
+		saveMock := mocks.NewSaveQuery(t)
+		saveMock.On("GetSavedPhotoIDs", context.Background(), uint64(1), []uint64{9, 4}).Return([]uint64{}, nil)
+		svc := tagServiceImpl{repo: repoMock, likeRepo: likeMock, saveRepo: saveMock}
+
+		tag, photos, err := svc.GetTagPhotos(context.Background(), 1, "#CAFÉ", 0, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "café", tag.Name)
+		assert.Equal(t, []uint64{9, 4}, photoIDs(photos))
+		assert.True(t, photos[1].LikedByMe)
+	})
+
+	t.Run("error tag not found", func(t *testing.T) {
+		repoMock := mocks.NewTagQuery(t)
+		repoMock.On("GetTag", context.Background(), "nothing").Return(model.Tag{}, nil)
+		svc := tagServiceImpl{repo: repoMock}
+
+		_, _, err := svc.GetTagPhotos(context.Background(), 1, "nothing", 0, 0)
+		assert.ErrorIs(t, err, ErrTagNotFound)
+	})
+}
+
+func TestSearchTags(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repoMock := mocks.NewTagQuery(t)
+		repoMock.On("SearchTags", context.Background(), "sun", 5).Return([]model.Tag{{Name: "sunset", PhotoCount: 8}}, nil)
+		svc := tagServiceImpl{repo: repoMock}
+
+		res, err := svc.SearchTags(context.Background(), "#Sun", 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		svc := tagServiceImpl{repo: mocks.NewTagQuery(t)}
+
+		res, err := svc.SearchTags(context.Background(), "#", 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+	})
+}
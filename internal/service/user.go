@@ -2,7 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/MidnightHelix/MyGram/internal/model"
@@ -12,30 +18,72 @@ import (
 )
 
 type UserService interface {
-	GetUsers(ctx context.Context) ([]model.User, error)
+	GetUsers(ctx context.Context, viewerID uint64, directory dto.UserDirectory) (users []model.User, nextCursor string, err error)
 	GetUsersById(ctx context.Context, id uint64) (model.User, error)
+	GetProfile(ctx context.Context, viewerID uint64, id uint64) (model.User, error)
+	GetProfileByUsername(ctx context.Context, viewerID uint64, username string) (user model.User, redirected bool, err error)
+	CheckUsername(ctx context.Context, username string) (dto.UsernameAvailability, error)
 	SignUp(ctx context.Context, userSignUp dto.UserSignUp) (model.User, error)
 	Login(ctx context.Context, userLogin dto.UserLogin) (model.User, error)
+	ResetPassword(ctx context.Context, reset dto.PasswordReset) (model.User, error)
 	EditUser(ctx context.Context, editUser model.User, id uint64) (model.User, error)
+	ChangeUsername(ctx context.Context, id uint64, username string) (model.User, error)
+	UpdatePrivacy(ctx context.Context, id uint64, isPrivate bool) error
 	DeleteUser(ctx context.Context, id uint64) error
 	// misc
 	GenerateUserAccessToken(ctx context.Context, user model.User) (token string, err error)
 }
 
 type userServiceImpl struct {
-	repo repository.UserQuery
+	repo      repository.UserQuery
+	blockRepo repository.BlockQuery
 }
 
-func NewUserService(repo repository.UserQuery) UserService {
-	return &userServiceImpl{repo: repo}
+func NewUserService(repo repository.UserQuery, blockRepo repository.BlockQuery) UserService {
+	return &userServiceImpl{repo: repo, blockRepo: blockRepo}
 }
 
-func (u *userServiceImpl) GetUsers(ctx context.Context) ([]model.User, error) {
-	users, err := u.repo.GetUsers(ctx)
+type userCursor struct {
+	Username string `json:"u,omitempty"`
+	ID       uint64 `json:"i"`
+}
+
+// GetUsers pages through the directory, optionally narrowed to usernames or
+// display names starting with directory.Query.
+func (u *userServiceImpl) GetUsers(ctx context.Context, viewerID uint64, directory dto.UserDirectory) (users []model.User, nextCursor string, err error) {
+	after := userCursor{}
+	if err = decodeCursor(directory.Cursor, &after); err != nil {
+		return nil, "", err
+	}
+
+	limit := normalizeLimit(directory.Limit)
+	search := repository.UserSearch{
+		Prefix:        strings.TrimSpace(strings.TrimPrefix(directory.Query, "@")),
+		Sort:          directory.Sort,
+		AfterUsername: after.Username,
+		AfterID:       after.ID,
+		Limit:         limit + 1,
+	}
+	if search.Sort == "" {
+		search.Sort = repository.UserSortUsername
+	}
+
+	users, err = u.repo.GetUsers(ctx, viewerID, search)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return users, err
+
+	// one extra row was fetched to know whether another page exists
+	if len(users) > limit {
+		users = users[:limit]
+		last := users[limit-1]
+		next := userCursor{ID: last.ID}
+		if search.Sort == repository.UserSortUsername {
+			next.Username = strings.ToLower(last.Username)
+		}
+		nextCursor = encodeCursor(next)
+	}
+	return users, nextCursor, nil
 }
 
 func (u *userServiceImpl) GetUsersById(ctx context.Context, id uint64) (model.User, error) {
@@ -46,12 +94,98 @@ func (u *userServiceImpl) GetUsersById(ctx context.Context, id uint64) (model.Us
 	return user, err
 }
 
+// GetProfile loads a user as seen by viewerID, users on either side of a
+// block and sanctioned users look like they do not exist.
+func (u *userServiceImpl) GetProfile(ctx context.Context, viewerID uint64, id uint64) (model.User, error) {
+	return visibleUser(ctx, u.repo, u.blockRepo, viewerID, id)
+}
+
+// visibleUser is GetProfile for services listing what is on a profile.
+func visibleUser(ctx context.Context, userRepo repository.UserQuery, blockRepo repository.BlockQuery, viewerID uint64, id uint64) (model.User, error) {
+	user, err := userRepo.GetUsersByID(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID == 0 {
+		return model.User{}, ErrUserNotFound
+	}
+
+	if viewerID == id {
+		return user, nil
+	}
+	// sanctioned accounts are hidden from everyone else
+	if checkAccountStatus(user) != nil {
+		return model.User{}, ErrUserNotFound
+	}
+
+	blocked, err := blockRepo.IsBlocked(ctx, viewerID, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if blocked {
+		return model.User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// GetProfileByUsername resolves a username, including ones the user has
+// since changed away from, redirected reports that an old handle was used.
+func (u *userServiceImpl) GetProfileByUsername(ctx context.Context, viewerID uint64, username string) (user model.User, redirected bool, err error) {
+	user, err = u.repo.FindByUsername(ctx, username)
+	if err != nil {
+		return model.User{}, false, err
+	}
+
+	id := user.ID
+	if id == 0 {
+		redirect, err := u.repo.GetUsernameRedirect(ctx, username)
+		if err != nil {
+			return model.User{}, false, err
+		}
+		if redirect.UserID == 0 {
+			return model.User{}, false, ErrUserNotFound
+		}
+		id, redirected = redirect.UserID, true
+	}
+
+	user, err = u.GetProfile(ctx, viewerID, id)
+	if err != nil {
+		return model.User{}, false, err
+	}
+	return user, redirected, nil
+}
+
+func (u *userServiceImpl) CheckUsername(ctx context.Context, username string) (dto.UsernameAvailability, error) {
+	username = helper.NormalizeUsername(username)
+	res := dto.UsernameAvailability{Username: username, Available: true}
+
+	err := u.checkUsername(ctx, username, 0)
+	switch {
+	case errors.Is(err, ErrInvalidUsername), errors.Is(err, ErrUsernameReserved), errors.Is(err, ErrUsernameTaken):
+		res.Available = false
+		res.Reason = err.Error()
+	case err != nil:
+		return dto.UsernameAvailability{}, err
+	}
+	return res, nil
+}
+
 func (u *userServiceImpl) SignUp(ctx context.Context, userSignUp dto.UserSignUp) (model.User, error) {
+	userSignUp.Username = helper.NormalizeUsername(userSignUp.Username)
+	if err := u.checkUsername(ctx, userSignUp.Username, 0); err != nil {
+		return model.User{}, err
+	}
+	if err := u.checkEmail(ctx, userSignUp.Email, 0); err != nil {
+		return model.User{}, err
+	}
+
 	// assumption: semua user adalah user baru
 	user := model.User{
-		Username: userSignUp.Username,
-		Email:    userSignUp.Email,
-		Age:      userSignUp.Age,
+		Username:    userSignUp.Username,
+		Email:       userSignUp.Email,
+		DisplayName: userSignUp.DisplayName,
+		Age:         userSignUp.Age,
+		Role:        model.RoleUser,
 	}
 
 	// encryption password
@@ -81,12 +215,97 @@ func (u *userServiceImpl) Login(ctx context.Context, userLogin dto.UserLogin) (m
 		return model.User{}, err
 	}
 
+	if err := checkAccountStatus(user); err != nil {
+		return model.User{}, err
+	}
+	if user.MustResetPassword {
+		return model.User{}, ErrPasswordResetRequired
+	}
+
 	return user, err
 }
 
+// ResetPassword changes the password of a user who knows the current one.
+// After a forced reset the current password no longer counts, only the
+// one-time token issued by ForcePasswordReset does.
+func (u *userServiceImpl) ResetPassword(ctx context.Context, reset dto.PasswordReset) (model.User, error) {
+	user, err := u.repo.FindByEmail(ctx, reset.Email)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if user.MustResetPassword {
+		if reset.Token == "" {
+			return model.User{}, ErrInvalidResetToken
+		}
+	} else if err := helper.CompareHashAndPassword(user.Password, reset.Password); err != nil {
+		return model.User{}, err
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return model.User{}, err
+	}
+
+	pass, err := helper.GenerateHash(reset.NewPassword)
+	if err != nil {
+		return model.User{}, err
+	}
+	now := time.Now()
+	if user.MustResetPassword {
+		ok, err := u.repo.ResetPasswordWithToken(ctx, user.ID, hashResetToken(reset.Token), pass, now)
+		if err != nil {
+			return model.User{}, err
+		}
+		if !ok {
+			return model.User{}, ErrInvalidResetToken
+		}
+	} else if err := u.repo.UpdatePassword(ctx, user.ID, pass, now); err != nil {
+		return model.User{}, err
+	}
+
+	user.Password = pass
+	user.MustResetPassword = false
+	user.PasswordResetToken = ""
+	user.TokensValidAfter = &now
+	return user, nil
+}
+
+// newResetToken returns a random token for a forced password reset, only its
+// hash is stored.
+func newResetToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashResetToken(token), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkAccountStatus rejects banned and currently suspended accounts.
+func checkAccountStatus(user model.User) error {
+	if user.IsBanned() {
+		return ErrAccountBanned
+	}
+	if user.IsSuspended(time.Now()) {
+		return fmt.Errorf("%w until %s", ErrAccountSuspended, user.SuspendedUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
 func (u *userServiceImpl) GenerateUserAccessToken(ctx context.Context, user model.User) (token string, err error) {
 	// generate claim
 	now := time.Now()
+	// Authentication rejects tokens issued in the second of the last
+	// revocation, one minted right after a password change counts from the
+	// next second instead
+	issuedAt := uint64(now.Unix())
+	if user.TokensValidAfter != nil && issuedAt <= uint64(user.TokensValidAfter.Unix()) {
+		issuedAt = uint64(user.TokensValidAfter.Unix()) + 1
+	}
 
 	claim := model.StandardClaim{
 		Jti: fmt.Sprintf("%v", time.Now().UnixNano()),
@@ -94,7 +313,7 @@ func (u *userServiceImpl) GenerateUserAccessToken(ctx context.Context, user mode
 		Aud: "golang-006",
 		Sub: "access-token",
 		Exp: uint64(now.Add(time.Hour).Unix()),
-		Iat: uint64(now.Unix()),
+		Iat: issuedAt,
 		Nbf: uint64(now.Unix()),
 	}
 
@@ -102,6 +321,7 @@ func (u *userServiceImpl) GenerateUserAccessToken(ctx context.Context, user mode
 		StandardClaim: claim,
 		UserID:        user.ID,
 		Username:      user.Username,
+		Role:          user.Role,
 		Dob:           user.DoB,
 	}
 
@@ -115,7 +335,36 @@ func (u *userServiceImpl) GenerateUserAccessToken(ctx context.Context, user mode
 	return
 }
 
+// EditUser updates the profile fields. A username that differs by more than
+// letter case is a rename and goes through ChangeUsername.
 func (u *userServiceImpl) EditUser(ctx context.Context, editUser model.User, id uint64) (model.User, error) {
+	user, err := u.repo.GetUsersByID(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID == 0 {
+		return model.User{}, ErrUserNotFound
+	}
+
+	editUser.Username = helper.NormalizeUsername(editUser.Username)
+	if editUser.Username == "" {
+		editUser.Username = user.Username
+	}
+	if helper.UsernameKey(editUser.Username) != helper.UsernameKey(user.Username) {
+		if _, err := u.ChangeUsername(ctx, id, editUser.Username); err != nil {
+			return model.User{}, err
+		}
+	} else if !helper.IsValidUsername(editUser.Username) {
+		return model.User{}, ErrInvalidUsername
+	}
+
+	if editUser.Email == "" {
+		editUser.Email = user.Email
+	}
+	if err := u.checkEmail(ctx, editUser.Email, id); err != nil {
+		return model.User{}, err
+	}
+
 	res, err := u.repo.EditUser(ctx, editUser, id)
 	if err != nil {
 		return model.User{}, err
@@ -123,6 +372,47 @@ func (u *userServiceImpl) EditUser(ctx context.Context, editUser model.User, id
 	return res, err
 }
 
+// ChangeUsername renames a user at most once per cooldown period, the old
+// username keeps redirecting to them. Changing only the letter case is not
+// a rename and is always allowed.
+func (u *userServiceImpl) ChangeUsername(ctx context.Context, id uint64, username string) (model.User, error) {
+	username = helper.NormalizeUsername(username)
+	user, err := u.repo.GetUsersByID(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID == 0 {
+		return model.User{}, ErrUserNotFound
+	}
+
+	if helper.UsernameKey(username) == helper.UsernameKey(user.Username) {
+		if !helper.IsValidUsername(username) {
+			return model.User{}, ErrInvalidUsername
+		}
+		user.Username = username
+		return u.repo.EditUser(ctx, user, id)
+	}
+
+	now := time.Now()
+	if err := usernameCooldown(user.UsernameChangedAt, now); err != nil {
+		return model.User{}, err
+	}
+	if err := u.checkUsername(ctx, username, id); err != nil {
+		return model.User{}, err
+	}
+	if err := u.repo.ChangeUsername(ctx, id, username, now); err != nil {
+		return model.User{}, err
+	}
+
+	user.Username = username
+	user.UsernameChangedAt = &now
+	return user, nil
+}
+
+func (u *userServiceImpl) UpdatePrivacy(ctx context.Context, id uint64, isPrivate bool) error {
+	return u.repo.UpdatePrivacy(ctx, id, isPrivate)
+}
+
 func (u *userServiceImpl) DeleteUser(ctx context.Context, id uint64) error {
 	err := u.repo.DeleteUser(ctx, id)
 	if err != nil {
@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	// "github.com/Calmantara/go-kominfo-2024/go-middleware/internal/model"
 	// "github.com/Calmantara/go-kominfo-2024/go-middleware/internal/repository/mocks"
 	"github.com/MidnightHelix/MyGram/internal/model"
+	"github.com/MidnightHelix/MyGram/internal/repository"
 	"github.com/MidnightHelix/MyGram/internal/repository/mocks"
+	"github.com/MidnightHelix/MyGram/pkg/dto"
+	"github.com/MidnightHelix/MyGram/pkg/helper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetUsers(t *testing.T) {
@@ -20,10 +25,10 @@ func TestGetUsers(t *testing.T) {
 		svc := userServiceImpl{
 			repo: repoMock,
 		}
-		repoMock.On("GetUsers", context.Background()).Return([]model.User{}, errors.New("some error"))
+		repoMock.On("GetUsers", context.Background(), uint64(1), repository.UserSearch{Sort: repository.UserSortUsername, Limit: 21}).Return([]model.User{}, errors.New("some error"))
 
 		// call method
-		usr, err := svc.GetUsers(context.Background())
+		usr, _, err := svc.GetUsers(context.Background(), 1, dto.UserDirectory{})
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(usr))
 	})
@@ -33,10 +38,10 @@ func TestGetUsers(t *testing.T) {
 		svc := userServiceImpl{
 			repo: repoMock,
 		}
-		repoMock.On("GetUsers", context.Background()).Return([]model.User{{ID: 1, Username: "user1"}}, nil)
+		repoMock.On("GetUsers", context.Background(), uint64(1), repository.UserSearch{Sort: repository.UserSortUsername, Limit: 21}).Return([]model.User{{ID: 1, Username: "user1"}}, nil)
 
 		// call method
-		usr, err := svc.GetUsers(context.Background())
+		usr, _, err := svc.GetUsers(context.Background(), 1, dto.UserDirectory{})
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(usr))
 	})
@@ -106,3 +111,196 @@ func TestGetUserById(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUsersDirectoryPaging(t *testing.T) {
+	t.Run("next cursor resumes after the last username", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("GetUsers", context.Background(), uint64(1), repository.UserSearch{Prefix: "al", Sort: repository.UserSortUsername, Limit: 3}).
+			Return([]model.User{{ID: 4, Username: "Alba"}, {ID: 2, Username: "alex"}, {ID: 9, Username: "alice"}}, nil)
+		repoMock.On("GetUsers", context.Background(), uint64(1), repository.UserSearch{Prefix: "al", Sort: repository.UserSortUsername, AfterUsername: "alex", AfterID: 2, Limit: 3}).
+			Return([]model.User{{ID: 9, Username: "alice"}}, nil)
+		svc := userServiceImpl{repo: repoMock}
+
+		users, next, err := svc.GetUsers(context.Background(), 1, dto.UserDirectory{Query: "@al", Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(users))
+		assert.NotEqual(t, "", next)
+
+		users, next, err = svc.GetUsers(context.Background(), 1, dto.UserDirectory{Query: "al", Limit: 2, Cursor: next})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(users))
+		assert.Equal(t, "", next)
+	})
+
+	t.Run("error invalid cursor", func(t *testing.T) {
+		svc := userServiceImpl{repo: mocks.NewUserQuery(t)}
+
+		_, _, err := svc.GetUsers(context.Background(), 1, dto.UserDirectory{Cursor: "not a cursor"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestSignUpUsername(t *testing.T) {
+	t.Run("error reserved look-alike", func(t *testing.T) {
+		svc := userServiceImpl{repo: mocks.NewUserQuery(t)}
+
+		_, err := svc.SignUp(context.Background(), dto.UserSignUp{Username: "Αdmin", Email: "a@example.com"})
+		assert.ErrorIs(t, err, ErrUsernameReserved)
+	})
+
+	t.Run("error invalid characters", func(t *testing.T) {
+		svc := userServiceImpl{repo: mocks.NewUserQuery(t)}
+
+		_, err := svc.SignUp(context.Background(), dto.UserSignUp{Username: "al ice", Email: "a@example.com"})
+		assert.ErrorIs(t, err, ErrInvalidUsername)
+	})
+
+	t.Run("error username taken", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("IsUsernameTaken", context.Background(), "Alice", uint64(0)).Return(true, nil)
+		svc := userServiceImpl{repo: repoMock}
+
+		_, err := svc.SignUp(context.Background(), dto.UserSignUp{Username: " Alice ", Email: "a@example.com"})
+		assert.ErrorIs(t, err, ErrUsernameTaken)
+	})
+
+	t.Run("error email taken", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("IsUsernameTaken", context.Background(), "alice", uint64(0)).Return(false, nil)
+		repoMock.On("IsEmailTaken", context.Background(), "A@Example.com", uint64(0)).Return(true, nil)
+		svc := userServiceImpl{repo: repoMock}
+
+		_, err := svc.SignUp(context.Background(), dto.UserSignUp{Username: "alice", Email: "A@Example.com"})
+		assert.ErrorIs(t, err, ErrEmailTaken)
+	})
+}
+
+func TestChangeUsername(t *testing.T) {
+	t.Run("error within cooldown", func(t *testing.T) {
+		changedAt := time.Now().Add(-24 * time.Hour)
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).
+			Return(model.User{ID: 1, Username: "alice", UsernameChangedAt: &changedAt}, nil)
+		svc := userServiceImpl{repo: repoMock}
+
+		_, err := svc.ChangeUsername(context.Background(), 1, "alicia")
+		assert.ErrorIs(t, err, ErrUsernameCooldown)
+	})
+
+	t.Run("case only change skips cooldown", func(t *testing.T) {
+		changedAt := time.Now().Add(-24 * time.Hour)
+		user := model.User{ID: 1, Username: "alice", UsernameChangedAt: &changedAt}
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(user, nil)
+		repoMock.On("EditUser", context.Background(), mock.AnythingOfType("model.User"), uint64(1)).
+			Return(func(ctx context.Context, user model.User, id uint64) (model.User, error) { return user, nil })
+		svc := userServiceImpl{repo: repoMock}
+
+		res, err := svc.ChangeUsername(context.Background(), 1, "Alice")
+		assert.Nil(t, err)
+		assert.Equal(t, "Alice", res.Username)
+	})
+
+	t.Run("success rename", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1, Username: "alice"}, nil)
+		repoMock.On("IsUsernameTaken", context.Background(), "alicia", uint64(1)).Return(false, nil)
+		repoMock.On("ChangeUsername", context.Background(), uint64(1), "alicia", mock.AnythingOfType("time.Time")).Return(nil)
+		svc := userServiceImpl{repo: repoMock}
+
+		res, err := svc.ChangeUsername(context.Background(), 1, "alicia")
+		assert.Nil(t, err)
+		assert.Equal(t, "alicia", res.Username)
+		assert.NotNil(t, res.UsernameChangedAt)
+	})
+}
+
+func TestGetProfileByUsername(t *testing.T) {
+	t.Run("old username redirects", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("FindByUsername", context.Background(), "alice").Return(model.User{}, nil)
+		repoMock.On("GetUsernameRedirect", context.Background(), "alice").Return(model.UsernameRedirect{UserID: 1}, nil)
+		repoMock.On("GetUsersByID", context.Background(), uint64(1)).Return(model.User{ID: 1, Username: "alicia"}, nil)
+		svc := userServiceImpl{repo: repoMock}
+
+		user, redirected, err := svc.GetProfileByUsername(context.Background(), 1, "alice")
+		assert.Nil(t, err)
+		assert.True(t, redirected)
+		assert.Equal(t, "alicia", user.Username)
+	})
+
+	t.Run("error unknown username", func(t *testing.T) {
+		repoMock := mocks.NewUserQuery(t)
+		repoMock.On("FindByUsername", context.Background(), "nobody").Return(model.User{}, nil)
+		repoMock.On("GetUsernameRedirect", context.Background(), "nobody").Return(model.UsernameRedirect{}, nil)
+		svc := userServiceImpl{repo: repoMock}
+
+		_, _, err := svc.GetProfileByUsername(context.Background(), 1, "nobody")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	current, err := helper.GenerateHash("secret1")
+	assert.Nil(t, err)
+
+	t.Run("error forced reset with the current password", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByEmail", context.Background(), "a@b.c").
+			Return(model.User{ID: 1, Password: current, MustResetPassword: true}, nil)
+		svc := userServiceImpl{repo: userMock}
+
+		_, err := svc.ResetPassword(context.Background(), dto.PasswordReset{Email: "a@b.c", Password: "secret1", NewPassword: "secret2"})
+		assert.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+
+	t.Run("error forced reset with a used token", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByEmail", context.Background(), "a@b.c").
+			Return(model.User{ID: 1, Password: current, MustResetPassword: true}, nil)
+		userMock.On("ResetPasswordWithToken", context.Background(), uint64(1), hashResetToken("tok"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+			Return(false, nil)
+		svc := userServiceImpl{repo: userMock}
+
+		_, err := svc.ResetPassword(context.Background(), dto.PasswordReset{Email: "a@b.c", Token: "tok", NewPassword: "secret2"})
+		assert.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+
+	t.Run("success forced reset with the token", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByEmail", context.Background(), "a@b.c").
+			Return(model.User{ID: 1, Password: current, MustResetPassword: true}, nil)
+		userMock.On("ResetPasswordWithToken", context.Background(), uint64(1), hashResetToken("tok"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+			Return(true, nil)
+		svc := userServiceImpl{repo: userMock}
+
+		res, err := svc.ResetPassword(context.Background(), dto.PasswordReset{Email: "a@b.c", Token: "tok", NewPassword: "secret2"})
+		assert.Nil(t, err)
+		assert.False(t, res.MustResetPassword)
+		assert.NotNil(t, res.TokensValidAfter)
+	})
+
+	t.Run("success with the current password", func(t *testing.T) {
+		userMock := mocks.NewUserQuery(t)
+		userMock.On("FindByEmail", context.Background(), "a@b.c").
+			Return(model.User{ID: 1, Password: current}, nil)
+		userMock.On("UpdatePassword", context.Background(), uint64(1), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+			Return(nil)
+		svc := userServiceImpl{repo: userMock}
+
+		_, err := svc.ResetPassword(context.Background(), dto.PasswordReset{Email: "a@b.c", Password: "secret1", NewPassword: "secret2"})
+		assert.Nil(t, err)
+	})
+}
+
+func TestGenerateUserAccessTokenAfterRevocation(t *testing.T) {
+	now := time.Now()
+	svc := userServiceImpl{}
+
+	token, err := svc.GenerateUserAccessToken(context.Background(), model.User{ID: 1, TokensValidAfter: &now})
+	assert.Nil(t, err)
+	claims, err := helper.ValidateToken(token)
+	assert.Nil(t, err)
+	// a token minted in the second of the revocation must still authenticate
+	assert.Greater(t, int64(claims["iat"].(float64)), now.Unix())
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), "/uploads/")
+	ctx := context.Background()
+
+	t.Run("put get delete", func(t *testing.T) {
+		err := s.Put(ctx, "photos/1/a.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+		assert.Nil(t, err)
+
+		r, err := s.Get(ctx, "photos/1/a.jpg")
+		assert.Nil(t, err)
+		body, _ := io.ReadAll(r)
+		r.Close()
+		assert.Equal(t, "jpeg", string(body))
+		assert.Equal(t, "/uploads/photos/1/a.jpg", s.URL("photos/1/a.jpg"))
+
+		assert.Nil(t, s.Delete(ctx, "photos/1/a.jpg"))
+		_, err = s.Get(ctx, "photos/1/a.jpg")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("error key escaping the root", func(t *testing.T) {
+		for _, key := range []string{"../a.jpg", "/etc/passwd", "photos//a.jpg", "photos/./a.jpg", ""} {
+			err := s.Put(ctx, key, strings.NewReader("x"), 1, "image/jpeg")
+			assert.ErrorIs(t, err, ErrInvalidKey, key)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal S3 stand-in that checks request signatures and keeps
+// objects in memory.
+type fakeS3 struct {
+	cfg     S3Config
+	mu      sync.Mutex
+	objects map[string]string
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	check := r.Clone(context.Background())
+	check.URL.Host = r.Host
+	signV4(check, f.cfg, t)
+	if check.Header.Get("Authorization") != r.Header.Get("Authorization") {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "SignatureDoesNotMatch")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := r.URL.Path
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[path] = string(body)
+		f.types[path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, body)
+	case http.MethodDelete:
+		delete(f.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	cfg := S3Config{Region: "us-east-1", Bucket: "mygram", AccessKey: "AKID", SecretKey: "secret"}
+	fake := &fakeS3{cfg: cfg, objects: map[string]string{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	cfg.Endpoint = server.URL
+	s := NewS3Storage(cfg, server.Client())
+	ctx := context.Background()
+
+	t.Run("put get delete", func(t *testing.T) {
+		err := s.Put(ctx, "photos/1/a b.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+		assert.Nil(t, err)
+		assert.Equal(t, "jpeg", fake.objects["/mygram/photos/1/a b.jpg"])
+		assert.Equal(t, "image/jpeg", fake.types["/mygram/photos/1/a b.jpg"])
+
+		r, err := s.Get(ctx, "photos/1/a b.jpg")
+		assert.Nil(t, err)
+		body, _ := io.ReadAll(r)
+		r.Close()
+		assert.Equal(t, "jpeg", string(body))
+		assert.Equal(t, server.URL+"/mygram/photos/1/a%20b.jpg", s.URL("photos/1/a b.jpg"))
+
+		assert.Nil(t, s.Delete(ctx, "photos/1/a b.jpg"))
+		_, err = s.Get(ctx, "photos/1/a b.jpg")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("error bad credentials", func(t *testing.T) {
+		bad := cfg
+		bad.SecretKey = "wrong"
+		err := NewS3Storage(bad, server.Client()).Put(ctx, "photos/1/b.jpg", strings.NewReader("x"), 1, "image/jpeg")
+		assert.ErrorContains(t, err, "403")
+	})
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Storage keeps uploaded media as objects addressed by key, e.g.
+// "photos/42/9f86d081.jpg". Keys are relative, slash separated and never
+// contain "." or ".." segments.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL is where clients can fetch the object from.
+	URL(key string) string
+}
+
+// LocalBaseURL is the path the api serves locally stored objects under.
+const LocalBaseURL = "/uploads"
+
+// NewStorage picks the backend from the environment. STORAGE_DRIVER=s3 uses
+// an S3-compatible bucket configured by the S3_* variables, anything else
+// stores files under STORAGE_DIR (default "uploads").
+func NewStorage() Storage {
+	if os.Getenv("STORAGE_DRIVER") == "s3" {
+		return NewS3Storage(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    getenv("S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		}, nil)
+	}
+	return NewLocalStorage(getenv("STORAGE_DIR", "uploads"), LocalBaseURL)
+}
+
+// LocalRoot returns the directory a local backend keeps its files in, ok is
+// false for remote backends which serve objects themselves.
+func LocalRoot(s Storage) (root string, ok bool) {
+	local, ok := s.(*localStorageImpl)
+	if !ok {
+		return "", false
+	}
+	return local.root, true
+}
+
+func getenv(name string, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type Suspension struct {
+	Duration string `json:"duration" binding:"required" validate:"required"`
+	Reason   string `json:"reason" validate:"max=255"`
+}
+
+type Ban struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type ModeratedUser struct {
+	ID               uint64     `json:"id"`
+	Username         string     `json:"username"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	BannedAt         *time.Time `json:"banned_at,omitempty"`
+	ModerationReason string     `json:"moderation_reason,omitempty"`
+}
+
+// NearDuplicate is a photo that looks like the one being moderated, the
+// distances count the bits their perceptual hashes differ in.
+type NearDuplicate struct {
+	Photo         Photo `json:"photo"`
+	PHashDistance int   `json:"phash_distance"`
+	DHashDistance int   `json:"dhash_distance"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+type Album struct {
+	ID            uint64        `json:"id"`
+	Title         string        `json:"title"`
+	Description   string        `json:"description"`
+	Visibility    string        `json:"visibility"`
+	CoverPhoto    *Photo        `json:"cover_photo,omitempty"`
+	UserID        uint64        `json:"user_id"`
+	User          *UserDefault  `json:"user,omitempty"`
+	Collaborators []UserDefault `json:"collaborators,omitempty"`
+	CreatedAt     *time.Time    `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time    `json:"updated_at,omitempty"`
+}
+
+// AlbumPage is an album with a page of its photos in album order, the
+// cursor of the next page is the position of the last photo.
+type AlbumPage struct {
+	Album  Album   `json:"album"`
+	Photos []Photo `json:"photos"`
+}
+
+// AlbumInput creates or edits an album. Visibility is public, followers or
+// private, CoverPhotoID must be a photo in the album and is ignored on
+// create.
+type AlbumInput struct {
+	Title        string  `json:"title" binding:"required" validate:"required,max=100"`
+	Description  string  `json:"description" validate:"max=1000"`
+	Visibility   string  `json:"visibility" validate:"omitempty,oneof=public followers private"`
+	CoverPhotoID *uint64 `json:"cover_photo_id"`
+}
+
+type AlbumPhotoInput struct {
+	PhotoID uint64 `json:"photo_id" binding:"required" validate:"required"`
+}
+
+// AlbumOrder lists every photo of an album in its new order.
+type AlbumOrder struct {
+	PhotoIDs []uint64 `json:"photo_ids" binding:"required" validate:"required,min=1"`
+}
+
+type AlbumCollaboratorInput struct {
+	UserID uint64 `json:"user_id" binding:"required" validate:"required"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type BlockedUser struct {
+	ID        uint64     `json:"id"`
+	UserID    uint64     `json:"user_id"`
+	Username  string     `json:"username"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// CloseFriend is a user on the caller's close friends list, ID is the
+// cursor for the next page.
+type CloseFriend struct {
+	ID        uint64     `json:"id"`
+	UserID    uint64     `json:"user_id"`
+	Username  string     `json:"username"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
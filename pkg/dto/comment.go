@@ -11,8 +11,11 @@ type Comment struct {
 	Message   string          `json:"message"`
 	PhotoID   uint64          `json:"photo_id"`
 	UserID    uint64          `json:"user_id"`
+	Mentions  []Mention       `json:"mentions,omitempty"`
 	CreatedAt *time.Time      `json:"created_at,omitempty"`
 	UpdatedAt *time.Time      `json:"updated_at,omitempty"`
+	Edited    bool            `json:"edited"`
+	EditedAt  *time.Time      `json:"edited_at,omitempty"`
 	DeletedAt *gorm.DeletedAt `json:"deleted_at,omitempty"`
 	User      *UserDefault    `json:"user,omitempty"`
 	Photo     *Photo          `json:"photo,omitempty"`
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type Follow struct {
+	ID          uint64     `json:"id"`
+	FollowerID  uint64     `json:"follower_id"`
+	FollowingID uint64     `json:"following_id"`
+	Status      string     `json:"status"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+type FollowUser struct {
+	FollowID   uint64     `json:"follow_id"`
+	UserID     uint64     `json:"user_id"`
+	Username   string     `json:"username"`
+	FollowedAt *time.Time `json:"followed_at,omitempty"`
+}
+
+type FollowStatus struct {
+	Following  bool `json:"following"`
+	FollowedBy bool `json:"followed_by"`
+	Mutual     bool `json:"mutual"`
+	Requested  bool `json:"requested"`
+}
+
+type UserPrivacy struct {
+	IsPrivate *bool `json:"is_private" binding:"required" validate:"required"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+// MediaTransform are the rendition parameters of GET /media, as query
+// string or as the body of POST /media/sign.
+type MediaTransform struct {
+	Width   int    `json:"width" form:"w"`
+	Height  int    `json:"height" form:"h"`
+	Fit     string `json:"fit" form:"fit"`
+	Format  string `json:"format" form:"fm"`
+	Quality int    `json:"quality" form:"q"`
+}
+
+type MediaSignRequest struct {
+	Key string `json:"key" binding:"required" validate:"required"`
+	MediaTransform
+}
+
+type SignedMedia struct {
+	Url string `json:"url"`
+}
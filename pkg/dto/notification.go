@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// Notification is an activity the caller was involved in, Actor is who did
+// it. CommentID is set when it happened in a comment.
+type Notification struct {
+	ID        uint64       `json:"id"`
+	Type      string       `json:"type"`
+	Actor     *UserDefault `json:"actor,omitempty"`
+	PhotoID   uint64       `json:"photo_id"`
+	CommentID *uint64      `json:"comment_id,omitempty"`
+	Read      bool         `json:"read"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+}
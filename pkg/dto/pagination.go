@@ -0,0 +1,39 @@
+package dto
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type Page struct {
+	Cursor uint64 `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// Size clamps the requested limit into [1, MaxPageLimit].
+func (p Page) Size() int {
+	if p.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return p.Limit
+}
+
+// CursorPage requests a page of a listing with an opaque cursor, see
+// CursorInfo.
+type CursorPage struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+type PageInfo struct {
+	NextCursor *uint64 `json:"next_cursor,omitempty"`
+}
+
+// CursorInfo carries an opaque cursor for listings whose keyset is more
+// than a single id.
+type CursorInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -7,14 +7,137 @@ import (
 )
 
 type Photo struct {
-	ID        uint64          `json:"id"`
-	Title     string          `json:"title"`
-	Caption   string          `json:"caption"`
-	Url       string          `json:"photo_url"`
-	UserID    uint64          `json:"user_id"`
-	CreatedAt *time.Time      `json:"created_at,omitempty"`
-	UpdatedAt *time.Time      `json:"updated_at,omitempty"`
-	DeletedAt *gorm.DeletedAt `json:"deleted_at,omitempty"`
-	Comments  []Comment       `json:"comments,omitempty"`
-	User      *UserDefault    `json:"user,omitempty"`
+	ID      uint64 `json:"id"`
+	Title   string `json:"title"`
+	Caption string `json:"caption"`
+	Url     string `json:"photo_url"`
+	Status  string `json:"status,omitempty"`
+	// Visibility is only shown to the photo's author.
+	Visibility string         `json:"visibility,omitempty"`
+	Width      int            `json:"width,omitempty"`
+	Height     int            `json:"height,omitempty"`
+	Camera     string         `json:"camera_model,omitempty"`
+	TakenAt    *time.Time     `json:"taken_at,omitempty"`
+	Location   *PhotoLocation `json:"location,omitempty"`
+	Variants   []PhotoVariant `json:"variants,omitempty"`
+	// Media are the images of the post in order, the first one is the one
+	// Url and Variants show.
+	Media    []MediaItem `json:"media,omitempty"`
+	Mentions []Mention   `json:"mentions,omitempty"`
+	// DuplicateOf lists the uploader's photos that look the same as a new
+	// upload, only set in the response to POST /photos.
+	DuplicateOf []uint64 `json:"duplicate_of,omitempty"`
+	// LikeCount, CommentCount, LikedByMe and SavedByMe are set in photo
+	// listings.
+	LikeCount    *int64          `json:"like_count,omitempty"`
+	CommentCount *int64          `json:"comment_count,omitempty"`
+	LikedByMe    *bool           `json:"liked_by_me,omitempty"`
+	SavedByMe    *bool           `json:"saved_by_me,omitempty"`
+	UserID       uint64          `json:"user_id"`
+	CreatedAt    *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time      `json:"updated_at,omitempty"`
+	Edited       bool            `json:"edited"`
+	EditedAt     *time.Time      `json:"edited_at,omitempty"`
+	DeletedAt    *gorm.DeletedAt `json:"deleted_at,omitempty"`
+	// Comments is a page of the photo's comments oldest first, only set by
+	// GET /photos/:id.
+	Comments []Comment    `json:"comments,omitempty"`
+	User     *UserDefault `json:"user,omitempty"`
+}
+
+// PhotoVariant is a resized rendition, clients pick the smallest one that
+// covers the space they display the photo in.
+type PhotoVariant struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Url    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// PhotoLocation is where a photo was taken as far as the author shares it,
+// the coordinates are rounded to the precision they picked and left out
+// when only a place name was given.
+type PhotoLocation struct {
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	PlaceName string   `json:"place_name,omitempty"`
+}
+
+// PhotoLocationInput replaces the location of a photo, leaving out both the
+// coordinates and the place name removes it. Precision is exact (about
+// 11 m), approximate (about 1 km) or city (about 11 km), approximate when
+// left out.
+type PhotoLocationInput struct {
+	Latitude  *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+	PlaceName string   `json:"place_name" validate:"max=100"`
+	Precision string   `json:"precision" validate:"omitempty,oneof=exact approximate city"`
+}
+
+// MediaItem is one image of a post.
+type MediaItem struct {
+	ID       uint64 `json:"id"`
+	Position int    `json:"position"`
+	Url      string `json:"url"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	AltText  string `json:"alt_text"`
+}
+
+// MediaItemInput changes the alt text of an image, an empty one removes it.
+type MediaItemInput struct {
+	AltText string `json:"alt_text" validate:"max=1000"`
+}
+
+// MediaOrder lists every image of a post in its new order.
+type MediaOrder struct {
+	MediaIDs []uint64 `json:"media_ids" binding:"required" validate:"required,min=1"`
+}
+
+// Mention marks the users named in a caption or comment. Offset and Length
+// count characters of the text, '@' included, so clients can link the
+// name without parsing it again.
+type Mention struct {
+	UserID uint64 `json:"user_id"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+// PhotoLikes is the state of a photo's likes after a like or unlike.
+type PhotoLikes struct {
+	PhotoID   uint64 `json:"photo_id"`
+	LikeCount int64  `json:"like_count"`
+	LikedByMe bool   `json:"liked_by_me"`
+}
+
+// Liker is a user who liked a photo, LikeID is the cursor for the next
+// page.
+type Liker struct {
+	LikeID   uint64     `json:"like_id"`
+	UserID   uint64     `json:"user_id"`
+	Username string     `json:"username"`
+	LikedAt  *time.Time `json:"liked_at,omitempty"`
+}
+
+// PhotoUpload is the multipart form of POST /photos, the images themselves
+// are sent in order in up to 10 "photo" file fields.
+type PhotoUpload struct {
+	Title   string `form:"title" binding:"required" validate:"required"`
+	Caption string `form:"caption"`
+	// ShareMetadata keeps the camera model and capture time from EXIF on
+	// the photo. Location and other EXIF data are always removed, a
+	// location is only kept when given below.
+	ShareMetadata bool `form:"share_metadata"`
+	// Visibility is public, followers, close_friends or private, public
+	// when left out.
+	Visibility string `form:"visibility" validate:"omitempty,oneof=public followers close_friends private"`
+	// AltText describes the images in the same order.
+	AltText []string `form:"alt_text" validate:"max=10,dive,max=1000"`
+	// Latitude, Longitude, PlaceName and LocationPrecision are the location
+	// of the photo like PhotoLocationInput.
+	Latitude          *float64 `form:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude         *float64 `form:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+	PlaceName         string   `form:"place_name" validate:"max=100"`
+	LocationPrecision string   `form:"location_precision" validate:"omitempty,oneof=exact approximate city"`
 }
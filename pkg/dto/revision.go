@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// Revision is the text a photo or comment had before an edit, EditedAt is
+// when it was replaced. Title is only set for photos.
+type Revision struct {
+	ID       uint64       `json:"id"`
+	Title    string       `json:"title,omitempty"`
+	Text     string       `json:"text"`
+	EditorID uint64       `json:"editor_id"`
+	Editor   *UserDefault `json:"editor,omitempty"`
+	EditedAt time.Time    `json:"edited_at"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// PhotoSaved is whether a photo is saved after a save or unsave.
+type PhotoSaved struct {
+	PhotoID   uint64 `json:"photo_id"`
+	SavedByMe bool   `json:"saved_by_me"`
+}
+
+// SavedPage selects a page of saved photos, CollectionID narrows it to one
+// collection. Cursor is the save id of the last photo of the previous page.
+type SavedPage struct {
+	CollectionID uint64 `form:"collection_id"`
+	Page
+}
+
+type SaveCollection struct {
+	ID        uint64     `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type SaveCollectionInput struct {
+	Name string `json:"name" binding:"required" validate:"required,max=100"`
+}
+
+type SaveCollectionPhotoInput struct {
+	PhotoID uint64 `json:"photo_id" binding:"required" validate:"required"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+// PhotoSearch is the query of photo search, Query takes quoted phrases, "or"
+// and "-" before a word to exclude it.
+type PhotoSearch struct {
+	Query  string `form:"q" validate:"required,max=200"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// PhotoMatch is a photo found by search with the words of its title and
+// caption that matched the query.
+type PhotoMatch struct {
+	Photo
+	TitleHighlights   []Highlight `json:"title_highlights"`
+	CaptionHighlights []Highlight `json:"caption_highlights"`
+}
+
+// Highlight marks a matched word. Offset and Length count characters of the
+// text like those of a Mention.
+type Highlight struct {
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+// PhotoNearby is the query of nearby search, Radius is in km and 5 when
+// left out.
+type PhotoNearby struct {
+	Latitude  *float64 `form:"lat" validate:"required,gte=-90,lte=90"`
+	Longitude *float64 `form:"lng" validate:"required,gte=-180,lte=180"`
+	Radius    float64  `form:"radius" validate:"omitempty,gt=0,lte=50"`
+	Cursor    string   `form:"cursor"`
+	Limit     int      `form:"limit"`
+}
+
+// NearbyPhoto is a photo found by nearby search with its distance from the
+// point searched.
+type NearbyPhoto struct {
+	Photo
+	DistanceKm float64 `json:"distance_km"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+import "time"
+
+// Story is shown until ExpiresAt unless it is kept in a highlight.
+// ViewCount is only returned to the author.
+type Story struct {
+	ID        uint64       `json:"id"`
+	Url       string       `json:"url"`
+	Width     int          `json:"width,omitempty"`
+	Height    int          `json:"height,omitempty"`
+	Caption   string       `json:"caption"`
+	AltText   string       `json:"alt_text"`
+	ViewCount *int64       `json:"view_count,omitempty"`
+	Seen      *bool        `json:"seen,omitempty"`
+	UserID    uint64       `json:"user_id"`
+	User      *UserDefault `json:"user,omitempty"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+}
+
+// StoryTray holds the live stories of one account oldest first, Seen is
+// set once the viewer has seen all of them.
+type StoryTray struct {
+	User    UserDefault `json:"user"`
+	Seen    bool        `json:"seen"`
+	Stories []Story     `json:"stories"`
+}
+
+// StoryUpload is the multipart form of POST /stories, the image is sent in
+// the "photo" file field.
+type StoryUpload struct {
+	Caption string `form:"caption" validate:"max=2200"`
+	AltText string `form:"alt_text" validate:"max=1000"`
+}
+
+type StoryViewer struct {
+	ID       uint64    `json:"id"`
+	UserID   uint64    `json:"user_id"`
+	Username string    `json:"username"`
+	ViewedAt time.Time `json:"viewed_at"`
+}
+
+type StoryHighlight struct {
+	ID         uint64       `json:"id"`
+	Title      string       `json:"title"`
+	CoverStory *Story       `json:"cover_story,omitempty"`
+	UserID     uint64       `json:"user_id"`
+	User       *UserDefault `json:"user,omitempty"`
+	CreatedAt  *time.Time   `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time   `json:"updated_at,omitempty"`
+}
+
+// StoryHighlightPage is a highlight with all its stories in highlight order.
+type StoryHighlightPage struct {
+	Highlight StoryHighlight `json:"highlight"`
+	Stories   []Story        `json:"stories"`
+}
+
+// StoryHighlightInput creates a highlight, StoryIDs are added in order.
+// Only the title is used to edit one.
+type StoryHighlightInput struct {
+	Title    string   `json:"title" binding:"required" validate:"required,max=100"`
+	StoryIDs []uint64 `json:"story_ids" validate:"max=100"`
+}
+
+type StoryHighlightItemInput struct {
+	StoryID uint64 `json:"story_id" binding:"required" validate:"required"`
+}
+
+// StoryHighlightOrder lists every story of a highlight in its new order.
+type StoryHighlightOrder struct {
+	StoryIDs []uint64 `json:"story_ids" binding:"required" validate:"required,min=1"`
+}
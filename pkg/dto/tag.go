@@ -0,0 +1,18 @@
+package dto
+
+type Tag struct {
+	Name       string `json:"name"`
+	PhotoCount int64  `json:"photo_count"`
+}
+
+// TagPage is a tag with a page of its photos.
+type TagPage struct {
+	Tag    Tag     `json:"tag"`
+	Photos []Photo `json:"photos"`
+}
+
+// TagSearch is the query of tag autocomplete, Query may start with '#'.
+type TagSearch struct {
+	Query string `form:"q" validate:"max=100"`
+	Limit int    `form:"limit"`
+}
@@ -10,8 +10,12 @@ type User struct {
 	ID           uint64          `json:"id,omitempty"`
 	Email        string          `json:"email,omitempty"`
 	Username     string          `json:"username,omitempty"`
+	DisplayName  string          `json:"display_name,omitempty"`
 	DoB          *time.Time      `json:"dob,omitempty"`
 	Age          *uint8          `json:"age,omitempty"`
+	IsPrivate    bool            `json:"is_private"`
+	Followers    *int64          `json:"followers_count,omitempty"`
+	Following    *int64          `json:"following_count,omitempty"`
 	CreatedAt    *time.Time      `json:"created_at,omitempty"`
 	UpdatedAt    *time.Time      `json:"updated_at,omitempty"`
 	DeletedAt    *gorm.DeletedAt `json:"deleted_at,omitempty"`
@@ -22,17 +26,44 @@ type User struct {
 
 type UserDefault struct {
 	ID       *uint64 `json:"id,omitempty"`
-	Email    string  `json:"email"`
+	Email    string  `json:"email,omitempty"`
 	Username string  `json:"username"`
 }
 type UserSignUp struct {
-	Username string `json:"username" binding:"required" validate:"required,min=3,max=50"`
-	Email    string `json:"email" binding:"required" validate:"required,email"`
-	Password string `json:"password" binding:"required" validate:"required,min=6"`
-	Age      uint8  `json:"age" binding:"required" validate:"required,min=9"`
+	Username    string `json:"username" binding:"required" validate:"required,min=3,max=50"`
+	Email       string `json:"email" binding:"required" validate:"required,email"`
+	DisplayName string `json:"display_name" validate:"max=50"`
+	Password    string `json:"password" binding:"required" validate:"required,min=6"`
+	Age         uint8  `json:"age" binding:"required" validate:"required,min=9"`
 }
 
 type UserLogin struct {
 	Email    string `json:"email" binding:"required"  validate:"required,email"`
 	Password string `json:"password" binding:"required"`
 }
+
+type UserDirectory struct {
+	Query  string `form:"q" validate:"max=50"`
+	Sort   string `form:"sort" validate:"omitempty,oneof=username newest"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// PasswordReset proves the caller with the current password, or with the
+// token handed out when an admin forced the reset.
+type PasswordReset struct {
+	Email       string `json:"email" binding:"required" validate:"required,email"`
+	Password    string `json:"password,omitempty" validate:"required_without=Token"`
+	Token       string `json:"token,omitempty" validate:"required_without=Password"`
+	NewPassword string `json:"new_password" binding:"required" validate:"required,min=6"`
+}
+
+type UsernameChange struct {
+	Username string `json:"username" binding:"required" validate:"required,min=3,max=50"`
+}
+
+type UsernameAvailability struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
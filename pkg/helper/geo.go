@@ -0,0 +1,74 @@
+package helper
+
+import "math"
+
+// EarthRadiusKm is the mean radius of the earth.
+const EarthRadiusKm = 6371.0
+
+// KmPerDegree is the length of a degree of latitude, and of a degree of
+// longitude at the equator.
+const KmPerDegree = EarthRadiusKm * math.Pi / 180
+
+// RoundCoordinate rounds a latitude or longitude to decimals places. One
+// decimal is about 11 km, two about 1.1 km and four about 11 m.
+func RoundCoordinate(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
+
+// DistanceKm is the great circle distance between two points.
+func DistanceKm(lat1 float64, lng1 float64, lat2 float64, lng2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	dPhi, dLambda := radians(lat2-lat1), radians(lng2-lng1)
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox holds every point within some distance of a center. MinLng is
+// greater than MaxLng when the box crosses the antimeridian, a point is then
+// inside when its longitude is at least MinLng or at most MaxLng.
+type BoundingBox struct {
+	MinLat float64
+	MaxLat float64
+	MinLng float64
+	MaxLng float64
+}
+
+// CrossesAntimeridian reports whether the box wraps around from 180 to -180.
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// NearbyBox is the smallest box of latitudes and longitudes holding every
+// point within radiusKm of lat, lng. Near a pole it spans all longitudes.
+func NearbyBox(lat float64, lng float64, radiusKm float64) BoundingBox {
+	dLat := radiusKm / KmPerDegree
+	box := BoundingBox{MinLat: lat - dLat, MaxLat: lat + dLat, MinLng: -180, MaxLng: 180}
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat, box.MaxLat = math.Max(box.MinLat, -90), math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	// the widest point of a circle on the sphere is not at its center's
+	// latitude, this is the exact half width in longitude
+	dLng := math.Asin(math.Sin(radiusKm/EarthRadiusKm)/math.Cos(radians(lat))) * 180 / math.Pi
+	if math.IsNaN(dLng) || dLng >= 180 {
+		return box
+	}
+	box.MinLng, box.MaxLng = wrapLongitude(lng-dLng), wrapLongitude(lng+dLng)
+	return box
+}
+
+func wrapLongitude(lng float64) float64 {
+	if lng < -180 {
+		return lng + 360
+	}
+	if lng > 180 {
+		return lng - 360
+	}
+	return lng
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
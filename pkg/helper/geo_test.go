@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundCoordinate(t *testing.T) {
+	assert.Equal(t, -6.18, RoundCoordinate(-6.175392, 2))
+	assert.Equal(t, 106.8272, RoundCoordinate(106.827153, 4))
+	assert.Equal(t, 180.0, RoundCoordinate(179.96, 1))
+}
+
+func TestDistanceKm(t *testing.T) {
+	// Jakarta to Bandung
+	assert.InDelta(t, 116, DistanceKm(-6.2088, 106.8456, -6.9175, 107.6191), 1)
+	// across the antimeridian
+	assert.InDelta(t, 22.2, DistanceKm(0, 179.9, 0, -179.9), 0.1)
+	assert.Equal(t, 0.0, DistanceKm(51.5, -0.12, 51.5, -0.12))
+}
+
+func TestNearbyBox(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		lat    float64
+		lng    float64
+		radius float64
+		wraps  bool
+		all    bool
+	}{
+		{desc: "equator", lat: 0, lng: 0, radius: 10},
+		{desc: "high latitude is wider", lat: 60, lng: 10, radius: 10},
+		{desc: "antimeridian", lat: -17, lng: 179.95, radius: 20, wraps: true},
+		{desc: "pole", lat: 89.99, lng: 30, radius: 5, all: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			box := NearbyBox(tC.lat, tC.lng, tC.radius)
+			assert.Equal(t, tC.wraps, box.CrossesAntimeridian())
+			if tC.all {
+				assert.Equal(t, BoundingBox{MinLat: box.MinLat, MaxLat: 90, MinLng: -180, MaxLng: 180}, box)
+				return
+			}
+			// every point on the circle is inside the box
+			for bearing := 0; bearing < 360; bearing += 15 {
+				lat, lng := destination(tC.lat, tC.lng, tC.radius, float64(bearing))
+				assert.InDelta(t, tC.radius, DistanceKm(tC.lat, tC.lng, lat, lng), 1e-6)
+				assert.True(t, lat >= box.MinLat-1e-9 && lat <= box.MaxLat+1e-9, "lat %f", lat)
+				if box.CrossesAntimeridian() {
+					assert.True(t, lng >= box.MinLng-1e-9 || lng <= box.MaxLng+1e-9, "lng %f", lng)
+				} else {
+					assert.True(t, lng >= box.MinLng-1e-9 && lng <= box.MaxLng+1e-9, "lng %f", lng)
+				}
+			}
+		})
+	}
+}
+
+// destination is the point distanceKm away from lat, lng in the direction
+// of bearing, in degrees clockwise from north.
+func destination(lat float64, lng float64, distanceKm float64, bearing float64) (float64, float64) {
+	phi, lambda, theta := radians(lat), radians(lng), radians(bearing)
+	delta := distanceKm / EarthRadiusKm
+	phi2 := math.Asin(math.Sin(phi)*math.Cos(delta) + math.Cos(phi)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi), math.Cos(delta)-math.Sin(phi)*math.Sin(phi2))
+	return phi2 * 180 / math.Pi, wrapLongitude(lambda2 * 180 / math.Pi)
+}
@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxHashtagLength is the longest tag kept, longer ones are ignored
+	// rather than cut so they cannot collide with a real shorter tag.
+	MaxHashtagLength = 100
+	// MaxHashtags is how many distinct tags a caption can carry.
+	MaxHashtags = 30
+)
+
+// ParseHashtags returns the distinct normalized hashtags of text in order
+// of first use. A tag is '#' followed by letters, digits, combining marks
+// and '_', with at least one letter, so "#1" or "a#b" are not tags.
+func ParseHashtags(text string) []string {
+	runes := []rune(norm.NFKC.String(text))
+	tags := []string{}
+	seen := map[string]bool{}
+	for i := 0; i < len(runes) && len(tags) < MaxHashtags; i++ {
+		if runes[i] != '#' || (i > 0 && (isHashtagRune(runes[i-1]) || runes[i-1] == '&')) {
+			continue
+		}
+		end := i + 1
+		letter := false
+		for end < len(runes) && isHashtagRune(runes[end]) {
+			letter = letter || unicode.IsLetter(runes[end])
+			end++
+		}
+		tag := runes[i+1 : end]
+		i = end - 1
+		if !letter || len(tag) > MaxHashtagLength {
+			continue
+		}
+		name := NormalizeHashtag(string(tag))
+		if !seen[name] {
+			seen[name] = true
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// NormalizeHashtag is the stored form of a tag: NFKC and case folded, so
+// "#Café" and "#CAFÉ" are the same tag. Diacritics are kept, in many
+// languages they tell different words apart.
+func NormalizeHashtag(tag string) string {
+	return norm.NFC.String(folder.String(norm.NFKC.String(strings.TrimPrefix(strings.TrimSpace(tag), "#"))))
+}
+
+func isHashtagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
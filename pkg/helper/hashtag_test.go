@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHashtags(t *testing.T) {
+	testCases := []struct {
+		desc string
+		text string
+		tags []string
+	}{
+		{desc: "words", text: "sunset at the #Beach with #friends!", tags: []string{"beach", "friends"}},
+		{desc: "duplicates keep first use", text: "#Travel #travel #TRAVEL #food", tags: []string{"travel", "food"}},
+		{desc: "unicode letters and marks", text: "#Café #東京 #नमस्ते", tags: []string{"café", "東京", "नमस्ते"}},
+		{desc: "full-width hash", text: "＃Ｔｏｋｙｏ", tags: []string{"tokyo"}},
+		{desc: "adjacent tags", text: "#one#two", tags: []string{"one"}},
+		{desc: "digits only", text: "we're #1 and #2024", tags: []string{}},
+		{desc: "digits with letters", text: "#2024goals", tags: []string{"2024goals"}},
+		{desc: "inside words and entities", text: "issue#12 a#b &#39;", tags: []string{}},
+		{desc: "underscore", text: "#throwback_thursday.", tags: []string{"throwback_thursday"}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.tags, ParseHashtags(tC.text))
+		})
+	}
+
+	t.Run("too long is ignored", func(t *testing.T) {
+		assert.Equal(t, []string{}, ParseHashtags("#"+strings.Repeat("a", MaxHashtagLength+1)))
+	})
+
+	t.Run("at most MaxHashtags", func(t *testing.T) {
+		text := strings.Repeat("#a #b #c #d #e #f #g #h #i #j #k #l #m #n #o #p #q #r #s #t #u #v #w #x #y #z ", 2) + "#aa #bb #cc #dd #ee #ff"
+		assert.Equal(t, MaxHashtags, len(ParseHashtags(text)))
+	})
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	assert.Equal(t, "café", NormalizeHashtag("#CAFÉ"))
+	assert.Equal(t, NormalizeHashtag("café"), NormalizeHashtag("café"))
+}
@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps characters that render like a basic latin letter to that
+// letter. It covers the Cyrillic and Greek look-alikes people actually use
+// for impersonation, not the full Unicode confusables table.
+var confusables = map[rune]rune{
+	// cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'һ': 'h', 'і': 'i', 'ї': 'i', 'ј': 'j',
+	'к': 'k', 'ӏ': 'l', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's',
+	'т': 't', 'с': 'c', 'у': 'y', 'ԝ': 'w', 'х': 'x', 'ԁ': 'd', 'ɡ': 'g',
+	// greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w',
+	// latin
+	'ı': 'i', 'ȷ': 'j', 'ł': 'l', 'ø': 'o', 'đ': 'd', 'ħ': 'h',
+}
+
+// usernameConfusables folds sequences that only look alike once rendered,
+// they are applied to usernames but not to emails where "rn" and "m" are
+// genuinely different mailboxes.
+var usernameConfusables = strings.NewReplacer("rn", "m", "0", "o", "1", "l", "5", "s")
+
+var folder = cases.Fold()
+
+// NormalizeUsername is the form a username is stored and displayed in:
+// NFKC so full-width and ligature variants collapse, and trimmed.
+func NormalizeUsername(username string) string {
+	return norm.NFKC.String(strings.TrimSpace(username))
+}
+
+// IsValidUsername allows letters, digits, '_' and '.', with at least one
+// letter or digit so a name cannot be only punctuation.
+func IsValidUsername(username string) bool {
+	alnum := false
+	for _, r := range username {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			alnum = true
+		case r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return alnum
+}
+
+// UsernameKey is the identity of a username: two names with the same key
+// look the same to a reader and cannot both be registered.
+func UsernameKey(username string) string {
+	return usernameConfusables.Replace(skeleton(username))
+}
+
+// EmailKey is the identity of an email address, compared case-insensitively
+// and with look-alike characters folded.
+func EmailKey(email string) string {
+	return skeleton(strings.TrimSpace(email))
+}
+
+// skeleton case-folds s, drops diacritics and maps look-alike characters to
+// the latin letter they imitate.
+func skeleton(s string) string {
+	s = norm.NFD.String(folder.String(norm.NFKC.String(s)))
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
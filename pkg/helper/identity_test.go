@@ -0,0 +1,46 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsernameKey(t *testing.T) {
+	testCases := []struct {
+		desc string
+		a    string
+		b    string
+	}{
+		{desc: "case", a: "Alice", b: "alice"},
+		{desc: "cyrillic look-alike", a: "аlice", b: "alice"},
+		{desc: "full-width", a: "ａｌｉｃｅ", b: "alice"},
+		{desc: "diacritics", a: "alicé", b: "alice"},
+		{desc: "digits for letters", a: "g00gle", b: "google"},
+		{desc: "rn for m", a: "rnygram", b: "mygram"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, UsernameKey(tC.b), UsernameKey(tC.a))
+		})
+	}
+
+	t.Run("different names keep different keys", func(t *testing.T) {
+		assert.NotEqual(t, UsernameKey("alice"), UsernameKey("alicia"))
+	})
+}
+
+func TestEmailKey(t *testing.T) {
+	assert.Equal(t, EmailKey("alice@example.com"), EmailKey(" Alice@Example.COM"))
+	assert.Equal(t, EmailKey("alice@example.com"), EmailKey("аlice@example.com"))
+	// unlike usernames, look-alike ascii sequences are distinct mailboxes
+	assert.NotEqual(t, EmailKey("barn@example.com"), EmailKey("bam@example.com"))
+}
+
+func TestIsValidUsername(t *testing.T) {
+	assert.True(t, IsValidUsername("alice_01"))
+	assert.True(t, IsValidUsername("jürgen.k"))
+	assert.False(t, IsValidUsername("alice smith"))
+	assert.False(t, IsValidUsername("a/b"))
+	assert.False(t, IsValidUsername("__"))
+}
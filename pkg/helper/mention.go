@@ -0,0 +1,46 @@
+package helper
+
+import "unicode"
+
+// MaxMentions is how many @mentions in one text are resolved.
+const MaxMentions = 20
+
+// MentionToken is an @username in a text. Offset and Length count runes
+// and include the '@'.
+type MentionToken struct {
+	Offset   int
+	Length   int
+	Username string
+}
+
+// ParseMentions finds the @usernames in text in order. A mention is '@'
+// followed by the characters IsValidUsername allows, not preceded by one of
+// them so "mail@example.com" is not a mention, and a trailing '.' is left to
+// the sentence.
+func ParseMentions(text string) []MentionToken {
+	runes := []rune(text)
+	mentions := []MentionToken{}
+	for i := 0; i < len(runes) && len(mentions) < MaxMentions; i++ {
+		if runes[i] != '@' || (i > 0 && isUsernameRune(runes[i-1])) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && isUsernameRune(runes[end]) {
+			end++
+		}
+		next := end
+		for end > i+1 && runes[end-1] == '.' {
+			end--
+		}
+		name := string(runes[i+1 : end])
+		if IsValidUsername(name) {
+			mentions = append(mentions, MentionToken{Offset: i, Length: end - i, Username: name})
+		}
+		i = next - 1
+	}
+	return mentions
+}
+
+func isUsernameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
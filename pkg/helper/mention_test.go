@@ -0,0 +1,27 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		mentions []MentionToken
+	}{
+		{desc: "start of text", text: "@alice hi", mentions: []MentionToken{{Offset: 0, Length: 6, Username: "alice"}}},
+		{desc: "end of sentence", text: "thanks @bob.smith.", mentions: []MentionToken{{Offset: 7, Length: 10, Username: "bob.smith"}}},
+		{desc: "several", text: "@a, @b_c!", mentions: []MentionToken{{Offset: 0, Length: 2, Username: "a"}, {Offset: 4, Length: 4, Username: "b_c"}}},
+		{desc: "offsets count runes", text: "café @zoë", mentions: []MentionToken{{Offset: 5, Length: 4, Username: "zoë"}}},
+		{desc: "email is not a mention", text: "mail me@example.com", mentions: []MentionToken{}},
+		{desc: "lone at", text: "meet @ 5 or @.", mentions: []MentionToken{}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.mentions, ParseMentions(tC.text))
+		})
+	}
+}